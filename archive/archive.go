@@ -0,0 +1,84 @@
+package archive
+
+import (
+	"time"
+
+	"github.com/livestockz/api/domain/feed"
+)
+
+const (
+	Format  string = "livestock-archive"
+	Version int    = 1
+	//every date and time in the archive, as the database reads it back
+	timeLayout = "2006-01-02 15:04:05"
+)
+
+//Header is the first line of an archive
+type Header struct {
+	Format  string    `json:"format"`
+	Version int       `json:"version"`
+	Created time.Time `json:"created"`
+}
+
+//Line is every line after the header, a row of a table, the last line only
+//carries the number of rows written for each table so a cut file is noticed
+type Line struct {
+	Table  string                 `json:"table,omitempty"`
+	Row    map[string]interface{} `json:"row,omitempty"`
+	Counts map[string]int         `json:"counts,omitempty"`
+}
+
+type table struct {
+	name  string
+	order string
+	//reference data shipped with the schema, replaced on restore
+	seeded     bool
+	references []reference
+}
+
+//reference is a column holding the id of a row of another table
+type reference struct {
+	column string
+	table  string
+}
+
+//tables of both domains, parents first so a restore can insert in this order
+var tables = []table{
+	{name: "growth_species", order: "id"},
+	{name: "growth_species_curve", order: "growth_species_id, day", references: []reference{{"growth_species_id", "growth_species"}}},
+	{name: "growth_death_cause", order: "id", seeded: true},
+	{name: "growth_water_quality_range", order: "parameter", seeded: true},
+	{name: "growth_hatchery", order: "id"},
+	{name: "growth_seed_source", order: "id", references: []reference{{"growth_hatchery_id", "growth_hatchery"}}},
+	{name: "growth_batch", order: "id", references: []reference{{"growth_species_id", "growth_species"}}},
+	{name: "growth_pool", order: "id"},
+	{name: "growth_pool_device", order: "id", references: []reference{{"growth_pool_id", "growth_pool"}}},
+	{name: "growth_water_quality", order: "id", references: []reference{{"growth_pool_id", "growth_pool"}}},
+	{name: "feed_type", order: "id"},
+	{name: "feed_type_unit", order: "id", references: []reference{{"feed_type_id", "feed_type"}}},
+	{name: "feed_supplier", order: "id"},
+	{name: "feed_incoming", order: "id", references: []reference{{"feed_type_id", "feed_type"}, {"feed_supplier_id", "feed_supplier"}}},
+	{name: "feed_type_cost", order: "id", references: []reference{{"feed_type_id", "feed_type"}, {"feed_incoming_id", "feed_incoming"}}},
+	{name: "feed_lot", order: "id", references: []reference{{"feed_type_id", "feed_type"}, {"feed_incoming_id", "feed_incoming"}}},
+	{name: "feed_stocktake", order: "id"},
+	{name: "feed_stocktake_detail", order: "id", references: []reference{{"feed_stocktake_id", "feed_stocktake"}, {"feed_type_id", "feed_type"}}},
+	{name: "feed_adjustment", order: "id", references: []reference{{"feed_type_id", "feed_type"}, {"feed_stocktake_id", "feed_stocktake"}}},
+	{name: "growth_batch_cycle", order: "id", references: []reference{{"growth_batch_id", "growth_batch"}, {"growth_pool_id", "growth_pool"}, {"growth_seed_source_id", "growth_seed_source"}}},
+	{name: "growth_death", order: "id", references: []reference{{"growth_batch_cycle_id", "growth_batch_cycle"}, {"growth_death_cause_id", "growth_death_cause"}}},
+	{name: "growth_feeding", order: "id", references: []reference{{"growth_batch_cycle_id", "growth_batch_cycle"}, {"feed_type_id", "feed_type"}}},
+	{name: "growth_sampling", order: "id", references: []reference{{"growth_batch_cycle_id", "growth_batch_cycle"}}},
+	{name: "growth_cost", order: "id", references: []reference{{"growth_batch_cycle_id", "growth_batch_cycle"}}},
+	{name: "growth_treatment", order: "id", references: []reference{{"growth_batch_cycle_id", "growth_batch_cycle"}}},
+	{name: "growth_treatment_override", order: "id", references: []reference{{"growth_batch_cycle_id", "growth_batch_cycle"}, {"growth_treatment_id", "growth_treatment"}}},
+	{name: "growth_summary", order: "id", references: []reference{{"growth_batch_cycle_id", "growth_batch_cycle"}}},
+	{name: "growth_sales", order: "id"},
+	{name: "growth_sales_detail", order: "id", references: []reference{{"sales_id", "growth_sales"}, {"growth_batch_cycle_id", "growth_batch_cycle"}}},
+	{name: "feed_lot_usage", order: "id", references: []reference{{"feed_lot_id", "feed_lot"}}},
+}
+
+//lotUsageReferences tells which table a feed lot usage reference_id points at
+var lotUsageReferences = map[string]string{
+	feed.Lot_Usage_Feeding:    "growth_feeding",
+	feed.Lot_Usage_Adjustment: "feed_adjustment",
+	feed.Lot_Usage_Incoming:   "feed_incoming",
+}
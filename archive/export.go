@@ -0,0 +1,81 @@
+package archive
+
+import (
+	"database/sql"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+//Export writes every table to w as json lines, all tables are read in one
+//transaction so the archive is a consistent snapshot of the farm
+func Export(db *sql.DB, w io.Writer) (map[string]int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	//read only, nothing to keep
+	defer tx.Rollback()
+
+	out := json.NewEncoder(w)
+	if err := out.Encode(Header{Format: Format, Version: Version, Created: time.Now()}); err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, t := range tables {
+		if count, err := exportTable(tx, out, t); err != nil {
+			return nil, err
+		} else {
+			counts[t.name] = count
+		}
+	}
+	if err := out.Encode(Line{Counts: counts}); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+func exportTable(tx *sql.Tx, out *json.Encoder, t table) (int, error) {
+	rows, err := tx.Query("SELECT * FROM " + t.name + " ORDER BY " + t.order)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	count := 0
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return count, err
+		}
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			row[column] = exportValue(values[i])
+		}
+		if err := out.Encode(Line{Table: t.name, Row: row}); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, rows.Err()
+}
+
+//exportValue keeps text and decimals as written by the database, dates in
+//the layout it accepts back
+func exportValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(timeLayout)
+	default:
+		return v
+	}
+}
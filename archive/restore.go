@@ -0,0 +1,208 @@
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ncrypthic/dbmapper"
+	. "github.com/ncrypthic/dbmapper/dialects/mysql"
+)
+
+const (
+	//the longest line accepted, a row with a full text column fits easily
+	maxLineSize = 16 * 1024 * 1024
+	//broken references listed before giving up
+	maxProblems = 20
+)
+
+//column names come from the file, only plain identifiers go into the sql
+var identifier = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+//Restore reads an archive written by Export into an empty database, the file is
+//read and every relationship checked before anything is written, then all rows
+//are inserted in a single transaction
+func Restore(db *sql.DB, r io.Reader) (map[string]int, error) {
+	rows, err := readArchive(r)
+	if err != nil {
+		return nil, err
+	} else if err := verify(rows); err != nil {
+		return nil, err
+	} else if err := ensureEmpty(db); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tables {
+		//seeded reference data gives way to the archived one
+		if t.seeded && len(rows[t.name]) > 0 {
+			if _, err := tx.Exec("DELETE FROM " + t.name); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+		for _, row := range rows[t.name] {
+			if err := insertRow(tx, t.name, row); err != nil {
+				tx.Rollback()
+				return nil, fmt.Errorf("%s: %s", t.name, err.Error())
+			}
+		}
+		counts[t.name] = len(rows[t.name])
+	}
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return counts, nil
+}
+
+//readArchive checks the header and the trailer and returns the rows by table
+func readArchive(r io.Reader) (map[string][]map[string]interface{}, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("The archive is empty.")
+	}
+	var header Header
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil || header.Format != Format {
+		return nil, fmt.Errorf("Not a %s file.", Format)
+	} else if header.Version < 1 || header.Version > Version {
+		return nil, fmt.Errorf("Archive version %d is not supported, this build reads up to version %d.", header.Version, Version)
+	}
+
+	known := make(map[string]bool)
+	for _, t := range tables {
+		known[t.name] = true
+	}
+	rows := make(map[string][]map[string]interface{})
+	var counts map[string]int
+	for number := 2; scanner.Scan(); number++ {
+		if counts != nil {
+			return nil, fmt.Errorf("Line %d comes after the end of the archive.", number)
+		}
+		var line Line
+		decoder := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		//numbers stay as written, ids and decimals must not go through float64
+		decoder.UseNumber()
+		if err := decoder.Decode(&line); err != nil {
+			return nil, fmt.Errorf("Line %d: %s", number, err.Error())
+		} else if line.Counts != nil {
+			counts = line.Counts
+		} else if !known[line.Table] {
+			return nil, fmt.Errorf("Line %d: unknown table %s.", number, line.Table)
+		} else {
+			rows[line.Table] = append(rows[line.Table], line.Row)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	} else if counts == nil {
+		return nil, fmt.Errorf("The archive is incomplete, it has no row counts at the end.")
+	}
+	for _, t := range tables {
+		if counts[t.name] != len(rows[t.name]) {
+			return nil, fmt.Errorf("The archive is incomplete, %s has %d of %d rows.", t.name, len(rows[t.name]), counts[t.name])
+		}
+	}
+	return rows, nil
+}
+
+//verify checks every id a row points at is in the archive
+func verify(rows map[string][]map[string]interface{}) error {
+	ids := make(map[string]map[string]bool)
+	for _, t := range tables {
+		ids[t.name] = make(map[string]bool)
+		for _, row := range rows[t.name] {
+			if id, ok := row["id"]; ok && id != nil {
+				ids[t.name][fmt.Sprint(id)] = true
+			}
+		}
+	}
+
+	problems := make([]string, 0)
+	missing := func(t string, row map[string]interface{}, column string, target string) {
+		if value, ok := row[column]; ok && value != nil && !ids[target][fmt.Sprint(value)] {
+			problems = append(problems, fmt.Sprintf("%s %v: %s %v not found in %s", t, row["id"], column, value, target))
+		}
+	}
+	for _, t := range tables {
+		for _, row := range rows[t.name] {
+			for _, ref := range t.references {
+				missing(t.name, row, ref.column, ref.table)
+			}
+			if t.name == "feed_lot_usage" {
+				if target, ok := lotUsageReferences[fmt.Sprint(row["reference_type"])]; !ok {
+					problems = append(problems, fmt.Sprintf("feed_lot_usage %v: unknown reference_type %v", row["id"], row["reference_type"]))
+				} else {
+					missing(t.name, row, "reference_id", target)
+				}
+			}
+		}
+	}
+	if len(problems) > maxProblems {
+		problems = append(problems[:maxProblems], fmt.Sprintf("and %d more", len(problems)-maxProblems))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("The archive is not consistent:\n%s", strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+//ensureEmpty refuses to restore over existing farm data, only the seeded
+//reference tables may hold rows
+func ensureEmpty(db *sql.DB) error {
+	for _, t := range tables {
+		if t.seeded {
+			continue
+		}
+		summary := dbmapper.Prepare("SELECT COUNT(*) AS total FROM " + t.name)
+		total := make([]int32, 0)
+		if err := summary.Error(); err != nil {
+			return err
+		} else if err := Parse(db.Query(summary.SQL())).Map(dbmapper.Int32("total", &total)); err != nil {
+			return err
+		} else if len(total) > 0 && total[0] > 0 {
+			return fmt.Errorf("The database is not empty, %s has %d rows.", t.name, total[0])
+		}
+	}
+	return nil
+}
+
+func insertRow(tx *sql.Tx, name string, row map[string]interface{}) error {
+	columns := make([]string, 0, len(row))
+	for column := range row {
+		if !identifier.MatchString(column) {
+			return fmt.Errorf("invalid column name %q", column)
+		}
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	params := make([]*dbmapper.QueryParam, len(columns))
+	for i, column := range columns {
+		value := row[column]
+		if number, ok := value.(json.Number); ok {
+			value = number.String()
+		}
+		params[i] = dbmapper.Param(column, value)
+	}
+	insert := dbmapper.Prepare(fmt.Sprintf("INSERT INTO %s(%s) VALUES (:%s)", name, strings.Join(columns, ", "), strings.Join(columns, ", :"))).With(params...)
+	if err := insert.Error(); err != nil {
+		return err
+	} else if _, err := tx.Exec(insert.SQL(), insert.Params()...); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/livestockz/api/archive"
+	"github.com/livestockz/api/database"
+)
+
+const usage = `usage:
+  livestock                  start the api
+  livestock export <file>    write every table to a json lines archive, - for stdout
+  livestock import <file>    restore an archive into an empty database, - for stdin
+  livestock migrate up       apply every pending schema migration
+  livestock migrate down [n] revert the last n applied migrations, 1 by default
+  livestock migrate status   list the migrations and when they were applied
+  livestock migrate baseline <version>
+                             record migrations up to version as applied, for a
+                             database created before the migrations existed`
+
+//runCommand runs the command given on the command line instead of the api
+func runCommand(db *sql.DB, dialect string, args []string) error {
+	if args[0] == "migrate" {
+		return migrate(db, dialect, args[1:])
+	} else if len(args) != 2 {
+		return errors.New(usage)
+	}
+	switch args[0] {
+	case "export":
+		return exportArchive(db, args[1])
+	case "import":
+		return importArchive(db, args[1])
+	default:
+		return errors.New(usage)
+	}
+}
+
+func migrate(db *sql.DB, dialect string, args []string) error {
+	if len(args) < 1 {
+		return errors.New(usage)
+	}
+	var migrations []database.Migration
+	var err error
+	switch {
+	case args[0] == "up" && len(args) == 1:
+		migrations, err = database.Up(db, dialect)
+		printMigrations("applied", migrations)
+	case args[0] == "down" && len(args) <= 2:
+		steps := 1
+		if len(args) == 2 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("down takes a number of migrations, got %s", args[1])
+			}
+		}
+		migrations, err = database.Down(db, dialect, steps)
+		printMigrations("reverted", migrations)
+	case args[0] == "baseline" && len(args) == 2:
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("baseline takes a migration version, got %s", args[1])
+		}
+		migrations, err = database.Baseline(db, dialect, version)
+		printMigrations("recorded", migrations)
+		return err
+	case args[0] == "status" && len(args) == 1:
+		statuses, err := database.Status(db, dialect)
+		for _, status := range statuses {
+			applied := "pending"
+			if status.Applied.Valid {
+				applied = status.Applied.Time.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d %-32s %s\n", status.Version, status.Name, applied)
+		}
+		return err
+	default:
+		return errors.New(usage)
+	}
+	return err
+}
+
+func exportArchive(db *sql.DB, path string) error {
+	var out io.Writer = os.Stdout
+	if path != "-" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+	counts, err := archive.Export(db, out)
+	if err != nil {
+		//do not leave a half written archive behind
+		if path != "-" {
+			os.Remove(path)
+		}
+		return err
+	}
+	printCounts("exported", counts)
+	return nil
+}
+
+func importArchive(db *sql.DB, path string) error {
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		in = file
+	}
+	counts, err := archive.Restore(db, in)
+	if err != nil {
+		return err
+	}
+	printCounts("imported", counts)
+	return nil
+}
+
+//printMigrations goes to stderr like the counts, status is the only output
+func printMigrations(action string, migrations []database.Migration) {
+	for _, migration := range migrations {
+		fmt.Fprintf(os.Stderr, "%s %04d %s\n", action, migration.Version, migration.Name)
+	}
+}
+
+//printCounts goes to stderr so an export to stdout stays a clean archive
+func printCounts(action string, counts map[string]int) {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "%s %d rows of %s\n", action, counts[name], name)
+	}
+}
@@ -2,26 +2,69 @@ package config
 
 import (
 	"fmt"
+	"net/url"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 )
 
 type Config struct {
-	DatabaseHost string `envconfig:"ls_db_host" default:"localhost"`
-	DatabasePort string `envconfig:"ls_db_port" default:"3306"`
-	DatabaseName string `envconfig:"ls_db_name" default:"livestock"`
-	DatabaseUser string `envconfig:"ls_db_user" default:"root"`
-	DatabasePass string `envconfig:"ls_db_pass" default:""`
-	DebugMode    bool   `envconfig:"ls_debug" default:"true"`
+	//mysql, postgres, sqlite or memory, the port defaults to the dialect's own
+	DatabaseDialect string `envconfig:"ls_db_dialect" default:"mysql"`
+	//the database file of the sqlite dialect, host and credentials are unused
+	DatabasePath    string `envconfig:"ls_db_path" default:"livestock.db"`
+	DatabaseHost    string `envconfig:"ls_db_host" default:"localhost"`
+	DatabasePort    string `envconfig:"ls_db_port" default:""`
+	DatabaseName    string `envconfig:"ls_db_name" default:"livestock"`
+	DatabaseUser    string `envconfig:"ls_db_user" default:"root"`
+	DatabasePass    string `envconfig:"ls_db_pass" default:""`
+	DatabaseSSLMode string `envconfig:"ls_db_sslmode" default:"disable"`
+	DebugMode       bool   `envconfig:"ls_debug" default:"true"`
+	//apply pending schema migrations before serving the api
+	AutoMigrate bool `envconfig:"ls_auto_migrate" default:"false"`
+	//feed alert, days of stock to look ahead and days of feeding to average
+	FeedAlertHorizon int `envconfig:"ls_feed_alert_horizon" default:"14"`
+	FeedAlertWindow  int `envconfig:"ls_feed_alert_window" default:"14"`
+	//sensor telemetry over mqtt, disabled unless ls_mqtt_enabled is set
+	MQTTEnabled       bool          `envconfig:"ls_mqtt_enabled" default:"false"`
+	MQTTBroker        string        `envconfig:"ls_mqtt_broker" default:"tcp://localhost:1883"`
+	MQTTClientID      string        `envconfig:"ls_mqtt_client_id" default:"livestock-api"`
+	MQTTUsername      string        `envconfig:"ls_mqtt_username" default:""`
+	MQTTPassword      string        `envconfig:"ls_mqtt_password" default:""`
+	MQTTTopics        []string      `envconfig:"ls_mqtt_topics" default:"livestock/+/telemetry"`
+	MQTTBatchSize     int           `envconfig:"ls_mqtt_batch_size" default:"50"`
+	MQTTFlushInterval time.Duration `envconfig:"ls_mqtt_flush_interval" default:"30s"`
 }
 
 func (cfg *Config) DatabaseDSN() string {
+	if cfg.DatabaseDialect == "sqlite" {
+		//foreign keys are off unless asked for, writers wait for the lock
+		//instead of failing and transactions take it when they begin
+		return "file:" + cfg.DatabasePath + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate&_time_format=sqlite"
+	} else if cfg.DatabaseDialect == "postgres" {
+		port := cfg.DatabasePort
+		if port == "" {
+			port = "5432"
+		}
+		dsn := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(cfg.DatabaseUser, cfg.DatabasePass),
+			Host:     cfg.DatabaseHost + ":" + port,
+			Path:     cfg.DatabaseName,
+			RawQuery: url.Values{"sslmode": {cfg.DatabaseSSLMode}}.Encode(),
+		}
+		return dsn.String()
+	}
+	port := cfg.DatabasePort
+	if port == "" {
+		port = "3306"
+	}
 	return fmt.Sprintf(
 		"%s:%s@tcp(%s:%s)/%s?parseTime=true",
 		cfg.DatabaseUser,
 		cfg.DatabasePass,
 		cfg.DatabaseHost,
-		cfg.DatabasePort,
+		port,
 		cfg.DatabaseName,
 	)
 }
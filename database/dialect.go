@@ -0,0 +1,71 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	_ "modernc.org/sqlite"
+)
+
+const (
+	//supported dialects, selected with ls_db_dialect
+	MySQL      string = "mysql"
+	PostgreSQL string = "postgres"
+	SQLite     string = "sqlite"
+	//Memory keeps batches and feed in the process for demos, it opens no
+	//database so it is not one of the Dialects
+	Memory string = "memory"
+)
+
+//Dialects lists every supported dialect
+var Dialects = []string{MySQL, PostgreSQL, SQLite}
+
+//dialects running ddl inside a transaction, mysql commits every ddl statement
+//on its own
+var transactionalDDL = map[string]bool{
+	PostgreSQL: true,
+	SQLite:     true,
+}
+
+//the statement creating the migration bookkeeping table of each dialect
+var createMigrationTable = map[string]string{
+	MySQL: `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INT NOT NULL,
+		name VARCHAR(255) NOT NULL,
+		applied DATETIME NOT NULL,
+		PRIMARY KEY (version))
+	ENGINE = InnoDB`,
+	PostgreSQL: `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER NOT NULL,
+		name VARCHAR(255) NOT NULL,
+		applied TIMESTAMP NOT NULL,
+		PRIMARY KEY (version))`,
+	SQLite: `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER NOT NULL,
+		name VARCHAR(255) NOT NULL,
+		applied TIMESTAMP NOT NULL,
+		PRIMARY KEY (version))`,
+}
+
+//Open connects to a database of the given dialect. Repositories write mysql
+//flavoured placeholders through dbmapper, a postgres connection translates
+//them so the same queries run on both. sqlite keeps the whole farm in a
+//local file and reads the ? placeholders as they are
+func Open(dialect string, dsn string) (*sql.DB, error) {
+	switch dialect {
+	case MySQL:
+		return sql.Open("mysql", dsn)
+	case PostgreSQL:
+		connector, err := pq.NewConnector(dsn)
+		if err != nil {
+			return nil, err
+		}
+		return sql.OpenDB(&postgresConnector{connector}), nil
+	case SQLite:
+		return sql.Open("sqlite", dsn)
+	default:
+		return nil, fmt.Errorf("Unknown database dialect %s, expected one of %v.", dialect, Dialects)
+	}
+}
@@ -0,0 +1,301 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/ncrypthic/dbmapper"
+)
+
+//migrations are compiled into the binary, a folder for each dialect where
+//each version is a pair of files named like 0001_base_schema.up.sql and
+//0001_base_schema.down.sql
+//
+//go:embed migrations/*/*.sql
+var files embed.FS
+
+const (
+	selectMigration = `SELECT version, applied FROM schema_migrations ORDER BY version`
+	insertMigration = `INSERT INTO schema_migrations(version, name, applied) VALUES (:version, :name, :applied)`
+	deleteMigration = `DELETE FROM schema_migrations WHERE version = :version`
+)
+
+//execer runs statements on the database or inside a transaction
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+//connection runs the statements of a step on a single connection of the pool,
+//a temporary table only exists on the connection that created it
+type connection struct {
+	*sql.Conn
+}
+
+func (conn connection) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return conn.ExecContext(context.Background(), query, args...)
+}
+
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+//Migration is one version of the schema
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+//MigrationStatus tells whether a migration has been applied to the database
+type MigrationStatus struct {
+	Version int
+	Name    string
+	Applied null.Time
+}
+
+//Migrations returns every embedded migration of a dialect ordered by version
+func Migrations(dialect string) ([]Migration, error) {
+	if _, ok := createMigrationTable[dialect]; !ok {
+		return nil, fmt.Errorf("Unknown database dialect %s, expected one of %v.", dialect, Dialects)
+	}
+	entries, err := files.ReadDir(path.Join("migrations", dialect))
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("Unexpected migration file %s.", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		content, err := files.ReadFile(path.Join("migrations", dialect, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("Migration %d is named both %s and %s.", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if strings.TrimSpace(migration.Up) == "" || strings.TrimSpace(migration.Down) == "" {
+			return nil, fmt.Errorf("Migration %d %s needs both an up and a down file.", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+//Up applies every pending migration in order and returns the ones applied
+func Up(db *sql.DB, dialect string) ([]Migration, error) {
+	migrations, applied, err := load(db, dialect)
+	if err != nil {
+		return nil, err
+	}
+	done := make([]Migration, 0)
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		err := apply(db, dialect, func(db execer) error {
+			if err := execute(db, migration.Up); err != nil {
+				return fmt.Errorf("migration %04d %s: %s", migration.Version, migration.Name, err.Error())
+			}
+			return record(db, migration)
+		})
+		if err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+//Baseline records every migration up to version as applied without running
+//it, for databases created from the sql files kept before the migrations
+func Baseline(db *sql.DB, dialect string, version int) ([]Migration, error) {
+	migrations, applied, err := load(db, dialect)
+	if err != nil {
+		return nil, err
+	} else if len(applied) > 0 {
+		return nil, fmt.Errorf("The database already has %d migrations recorded.", len(applied))
+	}
+	done := make([]Migration, 0)
+	for _, migration := range migrations {
+		if migration.Version > version {
+			break
+		} else if err := record(db, migration); err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+//Down reverts the last steps applied migrations, newest first
+func Down(db *sql.DB, dialect string, steps int) ([]Migration, error) {
+	migrations, applied, err := load(db, dialect)
+	if err != nil {
+		return nil, err
+	}
+	done := make([]Migration, 0)
+	for i := len(migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		err := apply(db, dialect, func(db execer) error {
+			if err := execute(db, migration.Down); err != nil {
+				return fmt.Errorf("migration %04d %s: %s", migration.Version, migration.Name, err.Error())
+			}
+			remove := dbmapper.Prepare(deleteMigration).With(
+				dbmapper.Param("version", migration.Version),
+			)
+			if err := remove.Error(); err != nil {
+				return err
+			} else if _, err := db.Exec(remove.SQL(), remove.Params()...); err != nil {
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+//Status lists every migration with the time it was applied, if it was
+func Status(db *sql.DB, dialect string) ([]MigrationStatus, error) {
+	migrations, applied, err := load(db, dialect)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if at, ok := applied[migration.Version]; ok {
+			status.Applied = null.TimeFrom(at)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+//apply runs one migration step, in a transaction where the dialect allows it
+//so a failing step leaves neither half its statements nor its record behind
+func apply(db *sql.DB, dialect string, step func(db execer) error) error {
+	if !transactionalDDL[dialect] {
+		conn, err := db.Conn(context.Background())
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		return step(connection{conn})
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := step(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func record(db execer, migration Migration) error {
+	insert := dbmapper.Prepare(insertMigration).With(
+		dbmapper.Param("version", migration.Version),
+		dbmapper.Param("name", migration.Name),
+		dbmapper.Param("applied", time.Now()),
+	)
+	if err := insert.Error(); err != nil {
+		return err
+	} else if _, err := db.Exec(insert.SQL(), insert.Params()...); err != nil {
+		return err
+	}
+	return nil
+}
+
+//load reads the embedded migrations and the versions already applied
+func load(db *sql.DB, dialect string) ([]Migration, map[int]time.Time, error) {
+	migrations, err := Migrations(dialect)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := db.Exec(createMigrationTable[dialect]); err != nil {
+		return nil, nil, err
+	}
+	rows, err := db.Query(selectMigration)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, nil, err
+		}
+		applied[version] = at
+	}
+	return migrations, applied, rows.Err()
+}
+
+//execute runs the statements of a migration one by one, the driver does not
+//accept several statements in a single call
+func execute(db execer, script string) error {
+	for _, statement := range statements(script) {
+		if _, err := db.Exec(statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//statements splits a script on the semicolons ending a line, comment lines
+//are dropped
+func statements(script string) []string {
+	result := make([]string, 0)
+	current := make([]string, 0)
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current = append(current, line)
+		if strings.HasSuffix(trimmed, ";") {
+			statement := strings.TrimSuffix(strings.TrimSpace(strings.Join(current, "\n")), ";")
+			result = append(result, statement)
+			current = current[:0]
+		}
+	}
+	if statement := strings.TrimSpace(strings.Join(current, "\n")); statement != "" {
+		result = append(result, statement)
+	}
+	return result
+}
@@ -0,0 +1,174 @@
+package database_test
+
+import (
+	"database/sql"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/livestockz/api/database"
+	"github.com/livestockz/api/database/databasetest"
+)
+
+//selectColumns lists every column of every table in the test database
+var selectColumns = map[string]string{
+	database.MySQL:      `SELECT table_name, column_name FROM information_schema.columns WHERE table_schema = DATABASE()`,
+	database.PostgreSQL: `SELECT table_name, column_name FROM information_schema.columns WHERE table_schema = current_schema()`,
+	database.SQLite:     `SELECT m.name, p.name FROM sqlite_master m JOIN pragma_table_info(m.name) p WHERE m.type = 'table'`,
+}
+
+func columns(t *testing.T, db *sql.DB, dialect string) map[string]map[string]bool {
+	rows, err := db.Query(selectColumns[dialect])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	tables := make(map[string]map[string]bool)
+	for rows.Next() {
+		var table, column string
+		if err := rows.Scan(&table, &column); err != nil {
+			t.Fatal(err)
+		}
+		if tables[table] == nil {
+			tables[table] = make(map[string]bool)
+		}
+		tables[table][column] = true
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return tables
+}
+
+func TestMigrationsMatchAcrossDialects(t *testing.T) {
+	schemas := make(map[string]map[string]map[string]bool)
+	databasetest.Run(t, func(t *testing.T, db *sql.DB) {
+		dialect := path.Base(t.Name())
+		schema := columns(t, db, dialect)
+		schemas[dialect] = schema
+
+		//every version steps back on its own, not only the whole history
+		if _, err := database.Down(db, dialect, 1); err != nil {
+			t.Fatal(err)
+		} else if area := columns(t, db, dialect)["growth_pool"]; area["area"] || !area["name"] {
+			t.Fatalf("reverting the last migration left growth_pool with %v", area)
+		}
+		migrations, err := database.Migrations(dialect)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for step := 1; step < len(migrations); step++ {
+			if _, err := database.Down(db, dialect, 1); err != nil {
+				t.Fatalf("reverting %s: %v", migrations[len(migrations)-1-step].Name, err)
+			}
+		}
+		if left := columns(t, db, dialect); len(left) != 1 || left["schema_migrations"] == nil {
+			t.Fatalf("reverting every migration left %d tables", len(left))
+		}
+		if _, err := database.Up(db, dialect); err != nil {
+			t.Fatal(err)
+		} else if again := columns(t, db, dialect); !reflect.DeepEqual(again, schema) {
+			t.Fatal("migrating up again built another schema")
+		}
+	})
+
+	var first string
+	for _, dialect := range database.Dialects {
+		schema, ok := schemas[dialect]
+		if !ok {
+			continue
+		} else if first == "" {
+			first = dialect
+			continue
+		}
+		for table, columns := range schemas[first] {
+			if !reflect.DeepEqual(schema[table], columns) {
+				t.Errorf("%s has %s as %v, %s as %v", first, table, columns, dialect, schema[table])
+			}
+		}
+		for table := range schema {
+			if schemas[first][table] == nil {
+				t.Errorf("%s has table %s, %s has not", dialect, table, first)
+			}
+		}
+	}
+}
+
+func TestFailedMigrationRollsBack(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *sql.DB) {
+		dialect := path.Base(t.Name())
+		if dialect == database.MySQL {
+			t.Skip("mysql commits every ddl statement on its own")
+		}
+		if _, err := database.Down(db, dialect, 2); err != nil {
+			t.Fatal(err)
+		}
+		//the last statement of 0016_species creates this index
+		if _, err := db.Exec(`CREATE INDEX fk_growth_sampling_growth_batch_cycle_idx ON growth_pool (name)`); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := database.Up(db, dialect); err == nil {
+			t.Fatal("applied a migration whose index already exists")
+		}
+		if tables := columns(t, db, dialect); tables["growth_species"] != nil || tables["growth_batch"]["growth_species_id"] {
+			t.Fatal("the failed migration left its first statements applied")
+		}
+		statuses, err := database.Status(db, dialect)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, status := range statuses {
+			if status.Version >= 16 && status.Applied.Valid {
+				t.Fatalf("migration %04d %s is recorded as applied", status.Version, status.Name)
+			}
+		}
+	})
+}
+
+func TestFeedTypeUnitMigration(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *sql.DB) {
+		dialect := path.Base(t.Name())
+		migrations, err := database.Migrations(dialect)
+		if err != nil {
+			t.Fatal(err)
+		}
+		//back to the schema before 0009_feed_type_unit
+		if _, err := database.Down(db, dialect, len(migrations)-8); err != nil {
+			t.Fatal(err)
+		}
+		pellet, sack := "0b7a1c52-6e1f-4c1e-9d6a-2f3b4c5d6e01", "0b7a1c52-6e1f-4c1e-9d6a-2f3b4c5d6e02"
+		now := time.Now()
+		for id, unit := range map[string]string{pellet: "Ton", sack: "sack"} {
+			if _, err := db.Exec(`INSERT INTO feed_type (id, name, unit, status, deleted, created) VALUES (?, ?, ?, 1, 0, ?)`, id, "Pellet "+unit, unit, now); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := db.Exec(`INSERT INTO feed_incoming (id, feed_type_id, incoming_date, qty, price, created) VALUES (?, ?, ?, 2, 5000000, ?)`, "0b7a1c52-6e1f-4c1e-9d6a-2f3b4c5d6e03", pellet, now, now); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := database.Up(db, dialect); err == nil || !strings.Contains(err.Error(), "0009") {
+			t.Fatalf("migrated a feed type counted in sacks, got %v", err)
+		}
+		if _, err := db.Exec(`DELETE FROM feed_type WHERE id = ?`, sack); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := database.Up(db, dialect); err != nil {
+			t.Fatal(err)
+		}
+		var qty, price float64
+		if err := db.QueryRow(`SELECT qty, price FROM feed_incoming WHERE feed_type_id = ?`, pellet).Scan(&qty, &price); err != nil {
+			t.Fatal(err)
+		} else if qty != 2000 || price != 5000 {
+			t.Fatalf("2 ton at 5000000 migrated to %v kg at %v", qty, price)
+		}
+		if _, err := database.Down(db, dialect, len(migrations)-8); err != nil {
+			t.Fatal(err)
+		} else if err := db.QueryRow(`SELECT qty, price FROM feed_incoming WHERE feed_type_id = ?`, pellet).Scan(&qty, &price); err != nil {
+			t.Fatal(err)
+		} else if qty != 2 || price != 5000000 {
+			t.Fatalf("2000 kg at 5000 reverted to %v ton at %v", qty, price)
+		}
+	})
+}
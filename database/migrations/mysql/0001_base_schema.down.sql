@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS `feed_incoming`;
+DROP TABLE IF EXISTS `user`;
+DROP TABLE IF EXISTS `growth_summary`;
+DROP TABLE IF EXISTS `growth_sales_detail`;
+DROP TABLE IF EXISTS `growth_sales`;
+DROP TABLE IF EXISTS `growth_feeding`;
+DROP TABLE IF EXISTS `growth_death`;
+DROP TABLE IF EXISTS `growth_batch_cycle`;
+DROP TABLE IF EXISTS `growth_pool`;
+DROP TABLE IF EXISTS `growth_batch`;
+DROP TABLE IF EXISTS `feed_adjustment`;
+DROP TABLE IF EXISTS `feed_type`;
@@ -1,28 +1,4 @@
--- MySQL Workbench Forward Engineering
-
-SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0;
-SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0;
-SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='TRADITIONAL,ALLOW_INVALID_DATES';
-
--- -----------------------------------------------------
--- Schema mydb
--- -----------------------------------------------------
--- -----------------------------------------------------
--- Schema livestock
--- -----------------------------------------------------
-
--- -----------------------------------------------------
--- Schema livestock
--- -----------------------------------------------------
-CREATE SCHEMA IF NOT EXISTS `livestock` DEFAULT CHARACTER SET latin1 ;
-USE `livestock` ;
-
--- -----------------------------------------------------
--- Table `livestock`.`feed_type`
--- -----------------------------------------------------
-DROP TABLE IF EXISTS `livestock`.`feed_type` ;
-
-CREATE TABLE IF NOT EXISTS `livestock`.`feed_type` (
+CREATE TABLE IF NOT EXISTS `feed_type` (
   `id` CHAR(36) NOT NULL,
   `name` VARCHAR(255) NOT NULL,
   `unit` VARCHAR(50) NOT NULL,
@@ -34,13 +10,7 @@ CREATE TABLE IF NOT EXISTS `livestock`.`feed_type` (
 ENGINE = InnoDB
 DEFAULT CHARACTER SET = latin1;
 
-
--- -----------------------------------------------------
--- Table `livestock`.`feed_adjustment`
--- -----------------------------------------------------
-DROP TABLE IF EXISTS `livestock`.`feed_adjustment` ;
-
-CREATE TABLE IF NOT EXISTS `livestock`.`feed_adjustment` (
+CREATE TABLE IF NOT EXISTS `feed_adjustment` (
   `id` CHAR(36) NOT NULL,
   `feed_type_id` CHAR(36) NOT NULL,
   `qty` DECIMAL(20,2) NOT NULL,
@@ -50,19 +20,13 @@ CREATE TABLE IF NOT EXISTS `livestock`.`feed_adjustment` (
   INDEX `fk_feed_adjustment_feed_type_idx` (`feed_type_id` ASC),
   CONSTRAINT `fk_feed_adjustment_feed_type`
     FOREIGN KEY (`feed_type_id`)
-    REFERENCES `livestock`.`feed_type` (`id`)
+    REFERENCES `feed_type` (`id`)
     ON DELETE CASCADE
     ON UPDATE CASCADE)
 ENGINE = InnoDB
 DEFAULT CHARACTER SET = latin1;
 
-
--- -----------------------------------------------------
--- Table `livestock`.`growth_batch`
--- -----------------------------------------------------
-DROP TABLE IF EXISTS `livestock`.`growth_batch` ;
-
-CREATE TABLE IF NOT EXISTS `livestock`.`growth_batch` (
+CREATE TABLE IF NOT EXISTS `growth_batch` (
   `id` CHAR(36) NOT NULL,
   `name` VARCHAR(45) NOT NULL,
   `status` TINYINT(1) NOT NULL,
@@ -73,13 +37,7 @@ CREATE TABLE IF NOT EXISTS `livestock`.`growth_batch` (
 ENGINE = InnoDB
 DEFAULT CHARACTER SET = latin1;
 
-
--- -----------------------------------------------------
--- Table `livestock`.`growth_pool`
--- -----------------------------------------------------
-DROP TABLE IF EXISTS `livestock`.`growth_pool` ;
-
-CREATE TABLE IF NOT EXISTS `livestock`.`growth_pool` (
+CREATE TABLE IF NOT EXISTS `growth_pool` (
   `id` CHAR(36) NOT NULL,
   `name` VARCHAR(45) NOT NULL,
   `status` CHAR(32) NOT NULL,
@@ -91,13 +49,7 @@ CREATE TABLE IF NOT EXISTS `livestock`.`growth_pool` (
 ENGINE = InnoDB
 DEFAULT CHARACTER SET = latin1;
 
-
--- -----------------------------------------------------
--- Table `livestock`.`growth_batch_cycle`
--- -----------------------------------------------------
-DROP TABLE IF EXISTS `livestock`.`growth_batch_cycle` ;
-
-CREATE TABLE IF NOT EXISTS `livestock`.`growth_batch_cycle` (
+CREATE TABLE IF NOT EXISTS `growth_batch_cycle` (
   `id` CHAR(36) NOT NULL,
   `growth_batch_id` CHAR(36) NOT NULL,
   `growth_pool_id` CHAR(36) NOT NULL,
@@ -112,24 +64,18 @@ CREATE TABLE IF NOT EXISTS `livestock`.`growth_batch_cycle` (
   INDEX `fk_placement_pool_idx` (`growth_pool_id` ASC),
   CONSTRAINT `fk_batch_cycle_batch`
     FOREIGN KEY (`growth_batch_id`)
-    REFERENCES `livestock`.`growth_batch` (`id`)
+    REFERENCES `growth_batch` (`id`)
     ON DELETE CASCADE
     ON UPDATE CASCADE,
   CONSTRAINT `fk_batch_cycle_pool`
     FOREIGN KEY (`growth_pool_id`)
-    REFERENCES `livestock`.`growth_pool` (`id`)
+    REFERENCES `growth_pool` (`id`)
     ON DELETE CASCADE
     ON UPDATE CASCADE)
 ENGINE = InnoDB
 DEFAULT CHARACTER SET = latin1;
 
-
--- -----------------------------------------------------
--- Table `livestock`.`growth_death`
--- -----------------------------------------------------
-DROP TABLE IF EXISTS `livestock`.`growth_death` ;
-
-CREATE TABLE IF NOT EXISTS `livestock`.`growth_death` (
+CREATE TABLE IF NOT EXISTS `growth_death` (
   `id` CHAR(36) NOT NULL,
   `growth_batch_cycle_id` CHAR(36) NOT NULL,
   `death_date` DATE NOT NULL,
@@ -140,19 +86,13 @@ CREATE TABLE IF NOT EXISTS `livestock`.`growth_death` (
   INDEX `fk_death_batch_cycle_idx` (`growth_batch_cycle_id` ASC),
   CONSTRAINT `fk_death_batch_cycle`
     FOREIGN KEY (`growth_batch_cycle_id`)
-    REFERENCES `livestock`.`growth_batch_cycle` (`id`)
+    REFERENCES `growth_batch_cycle` (`id`)
     ON DELETE CASCADE
     ON UPDATE CASCADE)
 ENGINE = InnoDB
 DEFAULT CHARACTER SET = latin1;
 
-
--- -----------------------------------------------------
--- Table `livestock`.`growth_feeding`
--- -----------------------------------------------------
-DROP TABLE IF EXISTS `livestock`.`growth_feeding` ;
-
-CREATE TABLE IF NOT EXISTS `livestock`.`growth_feeding` (
+CREATE TABLE IF NOT EXISTS `growth_feeding` (
   `id` CHAR(36) NOT NULL,
   `growth_batch_cycle_id` CHAR(36) NOT NULL,
   `feed_type_id` CHAR(36) NOT NULL,
@@ -164,24 +104,18 @@ CREATE TABLE IF NOT EXISTS `livestock`.`growth_feeding` (
   INDEX `fk_feeding_feed_idx` (`feed_type_id` ASC),
   CONSTRAINT `fk_feeding_batch_cycle`
     FOREIGN KEY (`growth_batch_cycle_id`)
-    REFERENCES `livestock`.`growth_batch_cycle` (`id`)
+    REFERENCES `growth_batch_cycle` (`id`)
     ON DELETE CASCADE
     ON UPDATE CASCADE,
   CONSTRAINT `fk_feeding_feed`
     FOREIGN KEY (`feed_type_id`)
-    REFERENCES `livestock`.`feed_type` (`id`)
+    REFERENCES `feed_type` (`id`)
     ON DELETE CASCADE
     ON UPDATE CASCADE)
 ENGINE = InnoDB
 DEFAULT CHARACTER SET = latin1;
 
-
--- -----------------------------------------------------
--- Table `livestock`.`growth_sales`
--- -----------------------------------------------------
-DROP TABLE IF EXISTS `livestock`.`growth_sales` ;
-
-CREATE TABLE IF NOT EXISTS `livestock`.`growth_sales` (
+CREATE TABLE IF NOT EXISTS `growth_sales` (
   `id` CHAR(36) NOT NULL,
   `sales_date` DATE NOT NULL,
   `weight` DECIMAL(10,2) NOT NULL,
@@ -192,13 +126,7 @@ CREATE TABLE IF NOT EXISTS `livestock`.`growth_sales` (
 ENGINE = InnoDB
 DEFAULT CHARACTER SET = latin1;
 
-
--- -----------------------------------------------------
--- Table `livestock`.`growth_sales_detail`
--- -----------------------------------------------------
-DROP TABLE IF EXISTS `livestock`.`growth_sales_detail` ;
-
-CREATE TABLE IF NOT EXISTS `livestock`.`growth_sales_detail` (
+CREATE TABLE IF NOT EXISTS `growth_sales_detail` (
   `id` CHAR(36) NOT NULL,
   `sales_id` CHAR(36) NOT NULL,
   `growth_batch_cycle_id` CHAR(36) NOT NULL,
@@ -210,24 +138,18 @@ CREATE TABLE IF NOT EXISTS `livestock`.`growth_sales_detail` (
   INDEX `fk_sales_detail_sales_idx` (`sales_id` ASC),
   CONSTRAINT `fk_sales_batch_cycle`
     FOREIGN KEY (`growth_batch_cycle_id`)
-    REFERENCES `livestock`.`growth_batch_cycle` (`id`)
+    REFERENCES `growth_batch_cycle` (`id`)
     ON DELETE CASCADE
     ON UPDATE CASCADE,
   CONSTRAINT `fk_sales_detail_sales`
     FOREIGN KEY (`sales_id`)
-    REFERENCES `livestock`.`growth_sales` (`id`)
+    REFERENCES `growth_sales` (`id`)
     ON DELETE CASCADE
     ON UPDATE CASCADE)
 ENGINE = InnoDB
 DEFAULT CHARACTER SET = latin1;
 
-
--- -----------------------------------------------------
--- Table `livestock`.`growth_summary`
--- -----------------------------------------------------
-DROP TABLE IF EXISTS `livestock`.`growth_summary` ;
-
-CREATE TABLE IF NOT EXISTS `livestock`.`growth_summary` (
+CREATE TABLE IF NOT EXISTS `growth_summary` (
   `id` CHAR(36) NOT NULL,
   `growth_cycle_batch_id` CHAR(36) NOT NULL,
   `summary_date` DATE NOT NULL,
@@ -239,19 +161,13 @@ CREATE TABLE IF NOT EXISTS `livestock`.`growth_summary` (
   INDEX `fk_summary_cycle_batch_idx` (`growth_cycle_batch_id` ASC),
   CONSTRAINT `fk_summary_cycle_batch`
     FOREIGN KEY (`growth_cycle_batch_id`)
-    REFERENCES `livestock`.`growth_batch_cycle` (`id`)
+    REFERENCES `growth_batch_cycle` (`id`)
     ON DELETE CASCADE
     ON UPDATE CASCADE)
 ENGINE = InnoDB
 DEFAULT CHARACTER SET = latin1;
 
-
--- -----------------------------------------------------
--- Table `livestock`.`user`
--- -----------------------------------------------------
-DROP TABLE IF EXISTS `livestock`.`user` ;
-
-CREATE TABLE IF NOT EXISTS `livestock`.`user` (
+CREATE TABLE IF NOT EXISTS `user` (
   `id` CHAR(36) NOT NULL,
   `username` VARCHAR(45) NOT NULL,
   `password` VARCHAR(255) NOT NULL,
@@ -263,13 +179,7 @@ CREATE TABLE IF NOT EXISTS `livestock`.`user` (
 ENGINE = InnoDB
 DEFAULT CHARACTER SET = latin1;
 
-
--- -----------------------------------------------------
--- Table `livestock`.`feed_incoming`
--- -----------------------------------------------------
-DROP TABLE IF EXISTS `livestock`.`feed_incoming` ;
-
-CREATE TABLE IF NOT EXISTS `livestock`.`feed_incoming` (
+CREATE TABLE IF NOT EXISTS `feed_incoming` (
   `id` CHAR(36) NOT NULL,
   `feed_type_id` CHAR(36) NOT NULL,
   `qty` DECIMAL(20,2) NOT NULL,
@@ -279,12 +189,8 @@ CREATE TABLE IF NOT EXISTS `livestock`.`feed_incoming` (
   INDEX `fk_feed_incoming_feed_type_idx` (`feed_type_id` ASC),
   CONSTRAINT `fk_feed_incoming_feed_type`
     FOREIGN KEY (`feed_type_id`)
-    REFERENCES `livestock`.`feed_type` (`id`)
+    REFERENCES `feed_type` (`id`)
     ON DELETE CASCADE
     ON UPDATE CASCADE)
-ENGINE = InnoDB;
-
-
-SET SQL_MODE=@OLD_SQL_MODE;
-SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;
-SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS;
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = latin1;
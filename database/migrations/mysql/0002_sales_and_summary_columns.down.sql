@@ -0,0 +1,10 @@
+ALTER TABLE `growth_sales_detail` DROP `updated`;
+ALTER TABLE `growth_sales_detail` CHANGE `created` `detail_date` DATE NOT NULL;
+ALTER TABLE `growth_sales` CHANGE `qty` `weight` DECIMAL(10,2) NOT NULL;
+ALTER TABLE `growth_sales` DROP `updated`;
+ALTER TABLE `growth_sales` CHANGE `created` `timestamp` DATETIME NOT NULL;
+ALTER TABLE `growth_sales` ADD `user_id` INT(11) NOT NULL AFTER `weight`;
+ALTER TABLE `growth_summary` CHANGE `growth_batch_cycle_id` `growth_cycle_batch_id` CHAR(36) CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL;
+ALTER TABLE `growth_summary` DROP `weight`, DROP `amount`;
+ALTER TABLE `growth_feeding` DROP `feeding_date`;
+ALTER TABLE `growth_death` DROP `weight`;
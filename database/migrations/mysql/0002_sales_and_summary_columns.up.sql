@@ -7,4 +7,4 @@ ALTER TABLE `growth_sales` CHANGE `timestamp` `created` DATETIME NOT NULL;
 ALTER TABLE `growth_sales` ADD `updated` DATETIME NULL AFTER `created`;
 ALTER TABLE `growth_sales` CHANGE `weight` `qty` DECIMAL(5,0) NULL;
 ALTER TABLE `growth_sales_detail` CHANGE `detail_date` `created` DATETIME NOT NULL;
-ALTER TABLE `growth_sales_detail` ADD `updated` DATETIME NULL AFTER `created`;
\ No newline at end of file
+ALTER TABLE `growth_sales_detail` ADD `updated` DATETIME NULL AFTER `created`;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS `growth_cost`;
+ALTER TABLE `growth_sales_detail` DROP `price`;
+ALTER TABLE `feed_incoming` DROP `price`;
+ALTER TABLE `growth_batch_cycle` DROP `seed_cost`;
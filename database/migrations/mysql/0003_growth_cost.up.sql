@@ -0,0 +1,19 @@
+ALTER TABLE `growth_batch_cycle` ADD `seed_cost` DECIMAL(20,2) NOT NULL DEFAULT 0 AFTER `amount`;
+ALTER TABLE `feed_incoming` ADD `price` DECIMAL(20,2) NOT NULL DEFAULT 0 AFTER `qty`;
+ALTER TABLE `growth_sales_detail` ADD `price` DECIMAL(20,2) NOT NULL DEFAULT 0 AFTER `weight`;
+CREATE TABLE IF NOT EXISTS `growth_cost` (
+  `id` CHAR(36) NOT NULL,
+  `growth_batch_cycle_id` CHAR(36) NOT NULL,
+  `cost_date` DATE NOT NULL,
+  `description` VARCHAR(255) NOT NULL,
+  `amount` DECIMAL(20,2) NOT NULL,
+  `created` DATETIME NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_cost_batch_cycle_idx` (`growth_batch_cycle_id` ASC),
+  CONSTRAINT `fk_cost_batch_cycle`
+    FOREIGN KEY (`growth_batch_cycle_id`)
+    REFERENCES `growth_batch_cycle` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = latin1;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS `feed_type_cost`;
+ALTER TABLE `feed_incoming` DROP `incoming_date`, DROP `supplier`, DROP `invoice`, DROP `lot_number`;
+ALTER TABLE `feed_type` DROP `average_cost`;
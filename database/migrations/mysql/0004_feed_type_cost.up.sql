@@ -0,0 +1,28 @@
+ALTER TABLE `feed_type` ADD `average_cost` DECIMAL(20,4) NOT NULL DEFAULT 0 AFTER `status`;
+ALTER TABLE `feed_incoming` ADD `incoming_date` DATE NOT NULL AFTER `feed_type_id`, ADD `supplier` VARCHAR(255) NULL DEFAULT NULL AFTER `price`, ADD `invoice` VARCHAR(100) NULL DEFAULT NULL AFTER `supplier`, ADD `lot_number` VARCHAR(100) NULL DEFAULT NULL AFTER `invoice`;
+UPDATE `feed_incoming` SET `incoming_date` = DATE(`created`);
+CREATE TABLE IF NOT EXISTS `feed_type_cost` (
+  `id` CHAR(36) NOT NULL,
+  `feed_type_id` CHAR(36) NOT NULL,
+  `feed_incoming_id` CHAR(36) NOT NULL,
+  `cost_date` DATE NOT NULL,
+  `stock` DECIMAL(20,2) NOT NULL,
+  `qty` DECIMAL(20,2) NOT NULL,
+  `price` DECIMAL(20,2) NOT NULL,
+  `average_cost` DECIMAL(20,4) NOT NULL,
+  `created` DATETIME NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_feed_type_cost_feed_type_idx` (`feed_type_id` ASC),
+  INDEX `fk_feed_type_cost_feed_incoming_idx` (`feed_incoming_id` ASC),
+  CONSTRAINT `fk_feed_type_cost_feed_type`
+    FOREIGN KEY (`feed_type_id`)
+    REFERENCES `feed_type` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE,
+  CONSTRAINT `fk_feed_type_cost_feed_incoming`
+    FOREIGN KEY (`feed_incoming_id`)
+    REFERENCES `feed_incoming` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = latin1;
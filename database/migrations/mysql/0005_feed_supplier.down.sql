@@ -0,0 +1,5 @@
+ALTER TABLE `feed_incoming` ADD `supplier` VARCHAR(255) NULL DEFAULT NULL AFTER `price`;
+UPDATE `feed_incoming` `fi` JOIN `feed_supplier` `s` ON `s`.`id` = `fi`.`feed_supplier_id` SET `fi`.`supplier` = `s`.`name`;
+ALTER TABLE `feed_incoming` DROP FOREIGN KEY `fk_feed_incoming_feed_supplier`;
+ALTER TABLE `feed_incoming` DROP `feed_supplier_id`;
+DROP TABLE IF EXISTS `feed_supplier`;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS `feed_supplier` (
+  `id` CHAR(36) NOT NULL,
+  `name` VARCHAR(255) NOT NULL,
+  `contact` VARCHAR(100) NULL DEFAULT NULL,
+  `phone` VARCHAR(50) NULL DEFAULT NULL,
+  `address` VARCHAR(255) NULL DEFAULT NULL,
+  `status` TINYINT(1) NOT NULL,
+  `deleted` TINYINT(1) NOT NULL,
+  `created` DATETIME NOT NULL,
+  `updated` DATETIME NULL DEFAULT NULL,
+  PRIMARY KEY (`id`))
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = latin1;
+ALTER TABLE `feed_incoming` ADD `feed_supplier_id` CHAR(36) NULL DEFAULT NULL AFTER `feed_type_id`, ADD INDEX `fk_feed_incoming_feed_supplier_idx` (`feed_supplier_id` ASC);
+INSERT INTO `feed_supplier` (`id`, `name`, `status`, `deleted`, `created`) SELECT UUID(), `supplier`, 1, 0, NOW() FROM (SELECT DISTINCT `supplier` FROM `feed_incoming` WHERE `supplier` IS NOT NULL AND `supplier` <> '') AS `s`;
+UPDATE `feed_incoming` `fi` JOIN `feed_supplier` `s` ON `s`.`name` = `fi`.`supplier` SET `fi`.`feed_supplier_id` = `s`.`id`;
+ALTER TABLE `feed_incoming` DROP `supplier`, ADD CONSTRAINT `fk_feed_incoming_feed_supplier` FOREIGN KEY (`feed_supplier_id`) REFERENCES `feed_supplier` (`id`) ON DELETE SET NULL ON UPDATE CASCADE;
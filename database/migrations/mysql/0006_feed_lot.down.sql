@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS `feed_lot_usage`;
+DROP TABLE IF EXISTS `feed_lot`;
+ALTER TABLE `feed_incoming` DROP `manufactured`, DROP `expired`;
@@ -0,0 +1,45 @@
+ALTER TABLE `feed_incoming` ADD `manufactured` DATE NULL DEFAULT NULL AFTER `lot_number`, ADD `expired` DATE NULL DEFAULT NULL AFTER `manufactured`;
+CREATE TABLE IF NOT EXISTS `feed_lot` (
+  `id` CHAR(36) NOT NULL,
+  `feed_type_id` CHAR(36) NOT NULL,
+  `feed_incoming_id` CHAR(36) NOT NULL,
+  `lot_number` VARCHAR(100) NULL DEFAULT NULL,
+  `manufactured` DATE NULL DEFAULT NULL,
+  `expired` DATE NULL DEFAULT NULL,
+  `qty` DECIMAL(20,2) NOT NULL,
+  `remaining` DECIMAL(20,2) NOT NULL,
+  `created` DATETIME NOT NULL,
+  `updated` DATETIME NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_feed_lot_feed_type_idx` (`feed_type_id` ASC),
+  INDEX `fk_feed_lot_feed_incoming_idx` (`feed_incoming_id` ASC),
+  INDEX `feed_lot_expired_idx` (`expired` ASC),
+  CONSTRAINT `fk_feed_lot_feed_type`
+    FOREIGN KEY (`feed_type_id`)
+    REFERENCES `feed_type` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE,
+  CONSTRAINT `fk_feed_lot_feed_incoming`
+    FOREIGN KEY (`feed_incoming_id`)
+    REFERENCES `feed_incoming` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = latin1;
+CREATE TABLE IF NOT EXISTS `feed_lot_usage` (
+  `id` CHAR(36) NOT NULL,
+  `feed_lot_id` CHAR(36) NOT NULL,
+  `reference_type` VARCHAR(20) NOT NULL,
+  `reference_id` CHAR(36) NOT NULL,
+  `qty` DECIMAL(20,2) NOT NULL,
+  `created` DATETIME NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_feed_lot_usage_feed_lot_idx` (`feed_lot_id` ASC),
+  INDEX `feed_lot_usage_reference_idx` (`reference_id` ASC),
+  CONSTRAINT `fk_feed_lot_usage_feed_lot`
+    FOREIGN KEY (`feed_lot_id`)
+    REFERENCES `feed_lot` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = latin1;
@@ -0,0 +1,4 @@
+ALTER TABLE `feed_adjustment` DROP FOREIGN KEY `fk_feed_adjustment_feed_stocktake`;
+ALTER TABLE `feed_adjustment` DROP `feed_stocktake_id`, DROP `reason`;
+DROP TABLE IF EXISTS `feed_stocktake_detail`;
+DROP TABLE IF EXISTS `feed_stocktake`;
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS `feed_stocktake` (
+  `id` CHAR(36) NOT NULL,
+  `stocktake_date` DATE NOT NULL,
+  `status` VARCHAR(20) NOT NULL,
+  `remarks` TEXT NULL DEFAULT NULL,
+  `approved` DATETIME NULL DEFAULT NULL,
+  `created` DATETIME NOT NULL,
+  `updated` DATETIME NULL DEFAULT NULL,
+  PRIMARY KEY (`id`))
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = latin1;
+CREATE TABLE IF NOT EXISTS `feed_stocktake_detail` (
+  `id` CHAR(36) NOT NULL,
+  `feed_stocktake_id` CHAR(36) NOT NULL,
+  `feed_type_id` CHAR(36) NOT NULL,
+  `counted_qty` DECIMAL(20,2) NOT NULL,
+  `system_qty` DECIMAL(20,2) NOT NULL DEFAULT 0,
+  `variance` DECIMAL(20,2) NOT NULL DEFAULT 0,
+  `created` DATETIME NOT NULL,
+  `updated` DATETIME NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_feed_stocktake_detail_feed_stocktake_idx` (`feed_stocktake_id` ASC),
+  INDEX `fk_feed_stocktake_detail_feed_type_idx` (`feed_type_id` ASC),
+  CONSTRAINT `fk_feed_stocktake_detail_feed_stocktake`
+    FOREIGN KEY (`feed_stocktake_id`)
+    REFERENCES `feed_stocktake` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE,
+  CONSTRAINT `fk_feed_stocktake_detail_feed_type`
+    FOREIGN KEY (`feed_type_id`)
+    REFERENCES `feed_type` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = latin1;
+ALTER TABLE `feed_adjustment` ADD `reason` VARCHAR(20) NOT NULL DEFAULT 'manual' AFTER `qty`, ADD `feed_stocktake_id` CHAR(36) NULL DEFAULT NULL AFTER `reason`, ADD INDEX `fk_feed_adjustment_feed_stocktake_idx` (`feed_stocktake_id` ASC), ADD CONSTRAINT `fk_feed_adjustment_feed_stocktake` FOREIGN KEY (`feed_stocktake_id`) REFERENCES `feed_stocktake` (`id`) ON DELETE SET NULL ON UPDATE CASCADE;
@@ -0,0 +1 @@
+ALTER TABLE `feed_type` DROP `reorder_point`, DROP `reorder_qty`;
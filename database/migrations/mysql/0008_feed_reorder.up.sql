@@ -0,0 +1 @@
+ALTER TABLE `feed_type` ADD `reorder_point` DECIMAL(20,2) NOT NULL DEFAULT 0 AFTER `average_cost`, ADD `reorder_qty` DECIMAL(20,2) NOT NULL DEFAULT 0 AFTER `reorder_point`;
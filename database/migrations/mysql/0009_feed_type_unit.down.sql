@@ -0,0 +1,14 @@
+-- rows go back to the unit of their feed type, g, ton or the unit it has a factor for
+DROP TEMPORARY TABLE IF EXISTS `feed_unit_rescale`;
+CREATE TEMPORARY TABLE `feed_unit_rescale` (`feed_type_id` CHAR(36) NOT NULL, `factor` DOUBLE NOT NULL, PRIMARY KEY (`feed_type_id`));
+INSERT INTO `feed_unit_rescale` (`feed_type_id`, `factor`) SELECT `id`, CASE LOWER(TRIM(`unit`)) WHEN 'g' THEN 1000 ELSE 0.001 END FROM `feed_type` WHERE LOWER(TRIM(`unit`)) IN ('g', 'ton') UNION ALL SELECT `t`.`id`, 1.0 / `u`.`factor` FROM `feed_type` `t` JOIN `feed_type_unit` `u` ON `u`.`feed_type_id` = `t`.`id` AND `u`.`unit` = LOWER(TRIM(`t`.`unit`));
+UPDATE `feed_type` `t` JOIN `feed_unit_rescale` `r` ON `r`.`feed_type_id` = `t`.`id` SET `t`.`average_cost` = `t`.`average_cost` / `r`.`factor`, `t`.`reorder_point` = `t`.`reorder_point` * `r`.`factor`, `t`.`reorder_qty` = `t`.`reorder_qty` * `r`.`factor`;
+UPDATE `feed_incoming` `t` JOIN `feed_unit_rescale` `r` ON `r`.`feed_type_id` = `t`.`feed_type_id` SET `t`.`qty` = `t`.`qty` * `r`.`factor`, `t`.`price` = `t`.`price` / `r`.`factor`;
+UPDATE `feed_adjustment` `t` JOIN `feed_unit_rescale` `r` ON `r`.`feed_type_id` = `t`.`feed_type_id` SET `t`.`qty` = `t`.`qty` * `r`.`factor`;
+UPDATE `growth_feeding` `t` JOIN `feed_unit_rescale` `r` ON `r`.`feed_type_id` = `t`.`feed_type_id` SET `t`.`qty` = `t`.`qty` * `r`.`factor`;
+UPDATE `feed_lot_usage` `t` JOIN `feed_lot` `l` ON `l`.`id` = `t`.`feed_lot_id` JOIN `feed_unit_rescale` `r` ON `r`.`feed_type_id` = `l`.`feed_type_id` SET `t`.`qty` = `t`.`qty` * `r`.`factor`;
+UPDATE `feed_lot` `t` JOIN `feed_unit_rescale` `r` ON `r`.`feed_type_id` = `t`.`feed_type_id` SET `t`.`qty` = `t`.`qty` * `r`.`factor`, `t`.`remaining` = `t`.`remaining` * `r`.`factor`;
+UPDATE `feed_stocktake_detail` `t` JOIN `feed_unit_rescale` `r` ON `r`.`feed_type_id` = `t`.`feed_type_id` SET `t`.`counted_qty` = `t`.`counted_qty` * `r`.`factor`, `t`.`system_qty` = `t`.`system_qty` * `r`.`factor`, `t`.`variance` = `t`.`variance` * `r`.`factor`;
+UPDATE `feed_type_cost` `t` JOIN `feed_unit_rescale` `r` ON `r`.`feed_type_id` = `t`.`feed_type_id` SET `t`.`stock` = `t`.`stock` * `r`.`factor`, `t`.`qty` = `t`.`qty` * `r`.`factor`, `t`.`price` = `t`.`price` / `r`.`factor`, `t`.`average_cost` = `t`.`average_cost` / `r`.`factor`;
+DROP TEMPORARY TABLE `feed_unit_rescale`;
+DROP TABLE IF EXISTS `feed_type_unit`;
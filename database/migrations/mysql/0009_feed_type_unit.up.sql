@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS `feed_type_unit` (
+  `id` CHAR(36) NOT NULL,
+  `feed_type_id` CHAR(36) NOT NULL,
+  `unit` VARCHAR(45) NOT NULL,
+  `factor` DECIMAL(20,6) NOT NULL,
+  `created` DATETIME NOT NULL,
+  `updated` DATETIME NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `feed_type_unit_unique` (`feed_type_id` ASC, `unit` ASC),
+  CONSTRAINT `fk_feed_type_unit_feed_type`
+    FOREIGN KEY (`feed_type_id`)
+    REFERENCES `feed_type` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = latin1;
+-- quantities are stored in kg from now on, rows of feed types counted in g or ton
+-- are rescaled. A feed type counted in any other unit has no factor yet, it is
+-- inserted twice so the migration stops on its duplicate name until the unit of
+-- the feed type and its rows are changed to kg, g or ton
+DROP TEMPORARY TABLE IF EXISTS `feed_type_without_factor`;
+CREATE TEMPORARY TABLE `feed_type_without_factor` (`name` VARCHAR(255) NOT NULL, PRIMARY KEY (`name`));
+INSERT INTO `feed_type_without_factor` (`name`) SELECT `name` FROM `feed_type` WHERE LOWER(TRIM(`unit`)) NOT IN ('kg', 'g', 'ton') UNION ALL SELECT `name` FROM `feed_type` WHERE LOWER(TRIM(`unit`)) NOT IN ('kg', 'g', 'ton');
+DROP TEMPORARY TABLE `feed_type_without_factor`;
+DROP TEMPORARY TABLE IF EXISTS `feed_unit_rescale`;
+CREATE TEMPORARY TABLE `feed_unit_rescale` (`feed_type_id` CHAR(36) NOT NULL, `factor` DOUBLE NOT NULL, PRIMARY KEY (`feed_type_id`));
+INSERT INTO `feed_unit_rescale` (`feed_type_id`, `factor`) SELECT `id`, CASE LOWER(TRIM(`unit`)) WHEN 'g' THEN 0.001 ELSE 1000 END FROM `feed_type` WHERE LOWER(TRIM(`unit`)) IN ('g', 'ton');
+UPDATE `feed_type` `t` JOIN `feed_unit_rescale` `r` ON `r`.`feed_type_id` = `t`.`id` SET `t`.`average_cost` = `t`.`average_cost` / `r`.`factor`, `t`.`reorder_point` = `t`.`reorder_point` * `r`.`factor`, `t`.`reorder_qty` = `t`.`reorder_qty` * `r`.`factor`;
+UPDATE `feed_incoming` `t` JOIN `feed_unit_rescale` `r` ON `r`.`feed_type_id` = `t`.`feed_type_id` SET `t`.`qty` = `t`.`qty` * `r`.`factor`, `t`.`price` = `t`.`price` / `r`.`factor`;
+UPDATE `feed_adjustment` `t` JOIN `feed_unit_rescale` `r` ON `r`.`feed_type_id` = `t`.`feed_type_id` SET `t`.`qty` = `t`.`qty` * `r`.`factor`;
+UPDATE `growth_feeding` `t` JOIN `feed_unit_rescale` `r` ON `r`.`feed_type_id` = `t`.`feed_type_id` SET `t`.`qty` = `t`.`qty` * `r`.`factor`;
+UPDATE `feed_lot_usage` `t` JOIN `feed_lot` `l` ON `l`.`id` = `t`.`feed_lot_id` JOIN `feed_unit_rescale` `r` ON `r`.`feed_type_id` = `l`.`feed_type_id` SET `t`.`qty` = `t`.`qty` * `r`.`factor`;
+UPDATE `feed_lot` `t` JOIN `feed_unit_rescale` `r` ON `r`.`feed_type_id` = `t`.`feed_type_id` SET `t`.`qty` = `t`.`qty` * `r`.`factor`, `t`.`remaining` = `t`.`remaining` * `r`.`factor`;
+UPDATE `feed_stocktake_detail` `t` JOIN `feed_unit_rescale` `r` ON `r`.`feed_type_id` = `t`.`feed_type_id` SET `t`.`counted_qty` = `t`.`counted_qty` * `r`.`factor`, `t`.`system_qty` = `t`.`system_qty` * `r`.`factor`, `t`.`variance` = `t`.`variance` * `r`.`factor`;
+UPDATE `feed_type_cost` `t` JOIN `feed_unit_rescale` `r` ON `r`.`feed_type_id` = `t`.`feed_type_id` SET `t`.`stock` = `t`.`stock` * `r`.`factor`, `t`.`qty` = `t`.`qty` * `r`.`factor`, `t`.`price` = `t`.`price` / `r`.`factor`, `t`.`average_cost` = `t`.`average_cost` / `r`.`factor`;
+DROP TEMPORARY TABLE `feed_unit_rescale`;
@@ -0,0 +1 @@
+ALTER TABLE `feed_type` DROP INDEX `feed_type_stage_idx`, DROP `protein`, DROP `fat`, DROP `pellet_size`, DROP `stage`;
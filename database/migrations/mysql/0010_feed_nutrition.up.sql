@@ -0,0 +1 @@
+ALTER TABLE `feed_type` ADD `protein` DECIMAL(5,2) NOT NULL DEFAULT 0 AFTER `average_cost`, ADD `fat` DECIMAL(5,2) NOT NULL DEFAULT 0 AFTER `protein`, ADD `pellet_size` DECIMAL(5,2) NOT NULL DEFAULT 0 AFTER `fat`, ADD `stage` VARCHAR(20) NOT NULL DEFAULT '' AFTER `pellet_size`, ADD INDEX `feed_type_stage_idx` (`stage` ASC, `pellet_size` ASC);
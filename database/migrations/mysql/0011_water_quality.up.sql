@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS `growth_water_quality` (
+  `id` CHAR(36) NOT NULL,
+  `growth_pool_id` CHAR(36) NOT NULL,
+  `measured_at` DATETIME NOT NULL,
+  `dissolved_oxygen` DECIMAL(10,2) NULL DEFAULT NULL,
+  `ph` DECIMAL(10,2) NULL DEFAULT NULL,
+  `temperature` DECIMAL(10,2) NULL DEFAULT NULL,
+  `ammonia` DECIMAL(10,3) NULL DEFAULT NULL,
+  `nitrite` DECIMAL(10,3) NULL DEFAULT NULL,
+  `salinity` DECIMAL(10,2) NULL DEFAULT NULL,
+  `transparency` DECIMAL(10,2) NULL DEFAULT NULL,
+  `out_of_range` TINYINT(1) NOT NULL DEFAULT 0,
+  `out_of_range_parameters` VARCHAR(255) NOT NULL DEFAULT '',
+  `remarks` VARCHAR(255) NULL DEFAULT NULL,
+  `created` DATETIME NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_growth_water_quality_growth_pool_idx` (`growth_pool_id` ASC, `measured_at` ASC),
+  CONSTRAINT `fk_growth_water_quality_growth_pool`
+    FOREIGN KEY (`growth_pool_id`)
+    REFERENCES `growth_pool` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = latin1;
+CREATE TABLE IF NOT EXISTS `growth_water_quality_range` (
+  `parameter` VARCHAR(45) NOT NULL,
+  `min_value` DECIMAL(10,3) NULL DEFAULT NULL,
+  `max_value` DECIMAL(10,3) NULL DEFAULT NULL,
+  `updated` DATETIME NULL DEFAULT NULL,
+  PRIMARY KEY (`parameter`))
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = latin1;
+INSERT INTO `growth_water_quality_range` (`parameter`, `min_value`, `max_value`, `updated`) VALUES
+  ('dissolved_oxygen', 4, NULL, NOW()),
+  ('ph', 6.5, 8.5, NOW()),
+  ('temperature', 25, 32, NOW()),
+  ('ammonia', NULL, 0.1, NOW()),
+  ('nitrite', NULL, 1, NOW()),
+  ('salinity', NULL, NULL, NOW()),
+  ('transparency', 25, 45, NOW());
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `growth_pool_device`;
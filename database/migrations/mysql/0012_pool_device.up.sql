@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS `growth_pool_device` (
+  `id` CHAR(36) NOT NULL,
+  `growth_pool_id` CHAR(36) NOT NULL,
+  `device_id` VARCHAR(100) NOT NULL,
+  `remarks` VARCHAR(255) NULL DEFAULT NULL,
+  `created` DATETIME NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `growth_pool_device_device_id_UNIQUE` (`device_id` ASC),
+  INDEX `fk_growth_pool_device_growth_pool_idx` (`growth_pool_id` ASC),
+  CONSTRAINT `fk_growth_pool_device_growth_pool`
+    FOREIGN KEY (`growth_pool_id`)
+    REFERENCES `growth_pool` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = latin1;
@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS `growth_treatment` (
+  `id` CHAR(36) NOT NULL,
+  `growth_batch_cycle_id` CHAR(36) NOT NULL,
+  `product` VARCHAR(100) NOT NULL,
+  `dose` DECIMAL(10,3) NOT NULL,
+  `unit` VARCHAR(20) NOT NULL,
+  `method` VARCHAR(20) NOT NULL,
+  `start_date` DATETIME NOT NULL,
+  `end_date` DATETIME NULL DEFAULT NULL,
+  `withdrawal_days` INT(11) NOT NULL DEFAULT 0,
+  `remarks` VARCHAR(255) NULL DEFAULT NULL,
+  `created` DATETIME NOT NULL,
+  `updated` DATETIME NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_growth_treatment_growth_batch_cycle_idx` (`growth_batch_cycle_id` ASC),
+  CONSTRAINT `fk_growth_treatment_growth_batch_cycle`
+    FOREIGN KEY (`growth_batch_cycle_id`)
+    REFERENCES `growth_batch_cycle` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = latin1;
+CREATE TABLE IF NOT EXISTS `growth_treatment_override` (
+  `id` CHAR(36) NOT NULL,
+  `growth_batch_cycle_id` CHAR(36) NOT NULL,
+  `growth_treatment_id` CHAR(36) NOT NULL,
+  `authorized_by` VARCHAR(100) NOT NULL,
+  `reason` VARCHAR(255) NOT NULL,
+  `created` DATETIME NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_growth_treatment_override_growth_treatment_idx` (`growth_treatment_id` ASC),
+  CONSTRAINT `fk_growth_treatment_override_growth_treatment`
+    FOREIGN KEY (`growth_treatment_id`)
+    REFERENCES `growth_treatment` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = latin1;
@@ -0,0 +1,3 @@
+ALTER TABLE `growth_death` DROP FOREIGN KEY `fk_growth_death_growth_death_cause`;
+ALTER TABLE `growth_death` DROP `growth_death_cause_id`;
+DROP TABLE IF EXISTS `growth_death_cause`;
@@ -0,0 +1,4 @@
+ALTER TABLE `growth_batch_cycle` DROP FOREIGN KEY `fk_growth_batch_cycle_growth_seed_source`;
+ALTER TABLE `growth_batch_cycle` DROP `growth_seed_source_id`;
+DROP TABLE IF EXISTS `growth_seed_source`;
+DROP TABLE IF EXISTS `growth_hatchery`;
@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS `growth_hatchery` (
+  `id` CHAR(36) NOT NULL,
+  `name` VARCHAR(100) NOT NULL,
+  `contact` VARCHAR(100) NOT NULL DEFAULT '',
+  `phone` VARCHAR(45) NOT NULL DEFAULT '',
+  `address` VARCHAR(255) NOT NULL DEFAULT '',
+  `deleted` TINYINT(1) NOT NULL DEFAULT 0,
+  `created` DATETIME NOT NULL,
+  `updated` DATETIME NULL DEFAULT NULL,
+  PRIMARY KEY (`id`))
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = latin1;
+CREATE TABLE IF NOT EXISTS `growth_seed_source` (
+  `id` CHAR(36) NOT NULL,
+  `growth_hatchery_id` CHAR(36) NOT NULL,
+  `species` VARCHAR(100) NOT NULL,
+  `strain` VARCHAR(100) NOT NULL DEFAULT '',
+  `lot_code` VARCHAR(100) NOT NULL,
+  `stocking_size` DECIMAL(10,3) NOT NULL DEFAULT 0,
+  `quality_grade` VARCHAR(20) NOT NULL DEFAULT '',
+  `remarks` VARCHAR(255) NOT NULL DEFAULT '',
+  `created` DATETIME NOT NULL,
+  `updated` DATETIME NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_growth_seed_source_growth_hatchery_idx` (`growth_hatchery_id` ASC),
+  CONSTRAINT `fk_growth_seed_source_growth_hatchery`
+    FOREIGN KEY (`growth_hatchery_id`)
+    REFERENCES `growth_hatchery` (`id`)
+    ON DELETE RESTRICT
+    ON UPDATE CASCADE)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = latin1;
+ALTER TABLE `growth_batch_cycle` ADD `growth_seed_source_id` CHAR(36) NULL DEFAULT NULL AFTER `growth_pool_id`;
+ALTER TABLE `growth_batch_cycle` ADD INDEX `fk_growth_batch_cycle_growth_seed_source_idx` (`growth_seed_source_id` ASC);
+ALTER TABLE `growth_batch_cycle` ADD CONSTRAINT `fk_growth_batch_cycle_growth_seed_source` FOREIGN KEY (`growth_seed_source_id`) REFERENCES `growth_seed_source` (`id`) ON DELETE SET NULL ON UPDATE CASCADE;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS `growth_sampling`;
+ALTER TABLE `growth_batch` DROP FOREIGN KEY `fk_growth_batch_growth_species`;
+ALTER TABLE `growth_batch` DROP `growth_species_id`;
+DROP TABLE IF EXISTS `growth_species_curve`;
+DROP TABLE IF EXISTS `growth_species`;
@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS `growth_species` (
+  `id` CHAR(36) NOT NULL,
+  `name` VARCHAR(100) NOT NULL,
+  `scientific_name` VARCHAR(100) NOT NULL DEFAULT '',
+  `target_weight` DECIMAL(10,3) NOT NULL DEFAULT 0,
+  `deleted` TINYINT(1) NOT NULL DEFAULT 0,
+  `created` DATETIME NOT NULL,
+  `updated` DATETIME NULL DEFAULT NULL,
+  PRIMARY KEY (`id`))
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = latin1;
+CREATE TABLE IF NOT EXISTS `growth_species_curve` (
+  `growth_species_id` CHAR(36) NOT NULL,
+  `day` INT(11) NOT NULL,
+  `weight` DECIMAL(10,3) NOT NULL,
+  PRIMARY KEY (`growth_species_id`, `day`),
+  CONSTRAINT `fk_growth_species_curve_growth_species`
+    FOREIGN KEY (`growth_species_id`)
+    REFERENCES `growth_species` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = latin1;
+ALTER TABLE `growth_batch` ADD `growth_species_id` CHAR(36) NULL DEFAULT NULL AFTER `status`;
+ALTER TABLE `growth_batch` ADD INDEX `fk_growth_batch_growth_species_idx` (`growth_species_id` ASC);
+ALTER TABLE `growth_batch` ADD CONSTRAINT `fk_growth_batch_growth_species` FOREIGN KEY (`growth_species_id`) REFERENCES `growth_species` (`id`) ON DELETE SET NULL ON UPDATE CASCADE;
+CREATE TABLE IF NOT EXISTS `growth_sampling` (
+  `id` CHAR(36) NOT NULL,
+  `growth_batch_cycle_id` CHAR(36) NOT NULL,
+  `sampling_date` DATETIME NOT NULL,
+  `sample_size` INT(11) NOT NULL,
+  `average_weight` DECIMAL(10,3) NOT NULL,
+  `remarks` VARCHAR(255) NULL DEFAULT NULL,
+  `created` DATETIME NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_growth_sampling_growth_batch_cycle_idx` (`growth_batch_cycle_id` ASC, `sampling_date` ASC),
+  CONSTRAINT `fk_growth_sampling_growth_batch_cycle`
+    FOREIGN KEY (`growth_batch_cycle_id`)
+    REFERENCES `growth_batch_cycle` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = latin1;
@@ -0,0 +1 @@
+ALTER TABLE `growth_pool` DROP `area`;
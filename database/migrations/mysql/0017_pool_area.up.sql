@@ -0,0 +1 @@
+ALTER TABLE `growth_pool` ADD `area` DECIMAL(10,2) NOT NULL DEFAULT 0 AFTER `status`;
@@ -0,0 +1,175 @@
+-- every version follows the mysql migration of the same number, ids are
+-- UUID and the TINYINT flags SMALLINT
+
+CREATE TABLE IF NOT EXISTS feed_type (
+  id UUID NOT NULL,
+  name VARCHAR(255) NOT NULL,
+  unit VARCHAR(50) NOT NULL,
+  status SMALLINT NOT NULL,
+  deleted SMALLINT NOT NULL,
+  created TIMESTAMP NOT NULL,
+  updated TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (id));
+
+CREATE TABLE IF NOT EXISTS feed_adjustment (
+  id UUID NOT NULL,
+  feed_type_id UUID NOT NULL,
+  qty DECIMAL(20,2) NOT NULL,
+  remarks VARCHAR(255) NULL DEFAULT NULL,
+  created TIMESTAMP NOT NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT fk_feed_adjustment_feed_type
+    FOREIGN KEY (feed_type_id)
+    REFERENCES feed_type (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE);
+CREATE INDEX fk_feed_adjustment_feed_type_idx ON feed_adjustment (feed_type_id);
+
+CREATE TABLE IF NOT EXISTS growth_batch (
+  id UUID NOT NULL,
+  name VARCHAR(45) NOT NULL,
+  status SMALLINT NOT NULL,
+  deleted SMALLINT NOT NULL,
+  created TIMESTAMP NOT NULL,
+  updated TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (id));
+
+CREATE TABLE IF NOT EXISTS growth_pool (
+  id UUID NOT NULL,
+  name VARCHAR(45) NOT NULL,
+  status CHAR(32) NOT NULL,
+  deleted SMALLINT NOT NULL,
+  created TIMESTAMP NOT NULL,
+  updated TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (id));
+CREATE INDEX growth_pool_status_idx ON growth_pool (status);
+
+CREATE TABLE IF NOT EXISTS growth_batch_cycle (
+  id UUID NOT NULL,
+  growth_batch_id UUID NOT NULL,
+  growth_pool_id UUID NOT NULL,
+  cycle_start DATE NOT NULL,
+  cycle_finish DATE NULL DEFAULT NULL,
+  weight DECIMAL(10,2) NOT NULL,
+  amount DECIMAL(20,0) NOT NULL,
+  created TIMESTAMP NOT NULL,
+  updated TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT fk_batch_cycle_batch
+    FOREIGN KEY (growth_batch_id)
+    REFERENCES growth_batch (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE,
+  CONSTRAINT fk_batch_cycle_pool
+    FOREIGN KEY (growth_pool_id)
+    REFERENCES growth_pool (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE);
+CREATE INDEX fk_placement_batch_idx ON growth_batch_cycle (growth_batch_id);
+CREATE INDEX fk_placement_pool_idx ON growth_batch_cycle (growth_pool_id);
+
+CREATE TABLE IF NOT EXISTS growth_death (
+  id UUID NOT NULL,
+  growth_batch_cycle_id UUID NOT NULL,
+  death_date DATE NOT NULL,
+  amount DECIMAL(20,0) NOT NULL,
+  remarks VARCHAR(255) NULL,
+  created TIMESTAMP NOT NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT fk_death_batch_cycle
+    FOREIGN KEY (growth_batch_cycle_id)
+    REFERENCES growth_batch_cycle (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE);
+CREATE INDEX fk_death_batch_cycle_idx ON growth_death (growth_batch_cycle_id);
+
+CREATE TABLE IF NOT EXISTS growth_feeding (
+  id UUID NOT NULL,
+  growth_batch_cycle_id UUID NOT NULL,
+  feed_type_id UUID NOT NULL,
+  qty DECIMAL(20,2) NOT NULL,
+  remarks VARCHAR(255) NULL,
+  created TIMESTAMP NOT NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT fk_feeding_batch_cycle
+    FOREIGN KEY (growth_batch_cycle_id)
+    REFERENCES growth_batch_cycle (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE,
+  CONSTRAINT fk_feeding_feed
+    FOREIGN KEY (feed_type_id)
+    REFERENCES feed_type (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE);
+CREATE INDEX fk_feeding_batch_cycle_idx ON growth_feeding (growth_batch_cycle_id);
+CREATE INDEX fk_feeding_feed_idx ON growth_feeding (feed_type_id);
+
+CREATE TABLE IF NOT EXISTS growth_sales (
+  id UUID NOT NULL,
+  sales_date DATE NOT NULL,
+  weight DECIMAL(10,2) NOT NULL,
+  user_id INTEGER NOT NULL,
+  timestamp TIMESTAMP NOT NULL,
+  reference VARCHAR(255) NULL DEFAULT NULL,
+  PRIMARY KEY (id));
+
+CREATE TABLE IF NOT EXISTS growth_sales_detail (
+  id UUID NOT NULL,
+  sales_id UUID NOT NULL,
+  growth_batch_cycle_id UUID NOT NULL,
+  amount DECIMAL(20,0) NOT NULL,
+  weight DECIMAL(10,2) NOT NULL,
+  detail_date DATE NOT NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT fk_sales_batch_cycle
+    FOREIGN KEY (growth_batch_cycle_id)
+    REFERENCES growth_batch_cycle (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE,
+  CONSTRAINT fk_sales_detail_sales
+    FOREIGN KEY (sales_id)
+    REFERENCES growth_sales (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE);
+CREATE INDEX fk_sales_batch_cycle_idx ON growth_sales_detail (growth_batch_cycle_id);
+CREATE INDEX fk_sales_detail_sales_idx ON growth_sales_detail (sales_id);
+
+CREATE TABLE IF NOT EXISTS growth_summary (
+  id UUID NOT NULL,
+  growth_cycle_batch_id UUID NOT NULL,
+  summary_date DATE NOT NULL,
+  adg DECIMAL(5,2) NOT NULL,
+  fcr DECIMAL(5,2) NOT NULL,
+  sr DECIMAL(5,2) NOT NULL,
+  created TIMESTAMP NOT NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT fk_summary_cycle_batch
+    FOREIGN KEY (growth_cycle_batch_id)
+    REFERENCES growth_batch_cycle (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE);
+CREATE INDEX fk_summary_cycle_batch_idx ON growth_summary (growth_cycle_batch_id);
+
+CREATE TABLE IF NOT EXISTS "user" (
+  id UUID NOT NULL,
+  username VARCHAR(45) NOT NULL,
+  password VARCHAR(255) NOT NULL,
+  fullname VARCHAR(100) NOT NULL,
+  role VARCHAR(255) NOT NULL,
+  status SMALLINT NOT NULL,
+  deleted SMALLINT NOT NULL,
+  PRIMARY KEY (id));
+
+CREATE TABLE IF NOT EXISTS feed_incoming (
+  id UUID NOT NULL,
+  feed_type_id UUID NOT NULL,
+  qty DECIMAL(20,2) NOT NULL,
+  remarks VARCHAR(255) NULL,
+  created TIMESTAMP NOT NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT fk_feed_incoming_feed_type
+    FOREIGN KEY (feed_type_id)
+    REFERENCES feed_type (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE);
+CREATE INDEX fk_feed_incoming_feed_type_idx ON feed_incoming (feed_type_id);
@@ -0,0 +1,16 @@
+ALTER TABLE growth_sales_detail DROP COLUMN updated;
+ALTER TABLE growth_sales_detail ALTER COLUMN created TYPE DATE;
+ALTER TABLE growth_sales_detail RENAME COLUMN created TO detail_date;
+UPDATE growth_sales SET qty = 0 WHERE qty IS NULL;
+ALTER TABLE growth_sales ALTER COLUMN qty TYPE DECIMAL(10,2), ALTER COLUMN qty SET NOT NULL;
+ALTER TABLE growth_sales RENAME COLUMN qty TO weight;
+ALTER TABLE growth_sales DROP COLUMN updated;
+ALTER TABLE growth_sales RENAME COLUMN created TO timestamp;
+ALTER TABLE growth_sales ADD COLUMN user_id INTEGER NULL;
+UPDATE growth_sales SET user_id = 0;
+ALTER TABLE growth_sales ALTER COLUMN user_id SET NOT NULL;
+ALTER TABLE growth_summary RENAME COLUMN growth_batch_cycle_id TO growth_cycle_batch_id;
+ALTER TABLE growth_summary DROP COLUMN amount;
+ALTER TABLE growth_summary DROP COLUMN weight;
+ALTER TABLE growth_feeding DROP COLUMN feeding_date;
+ALTER TABLE growth_death DROP COLUMN weight;
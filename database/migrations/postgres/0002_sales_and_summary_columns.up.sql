@@ -0,0 +1,22 @@
+ALTER TABLE growth_death ADD COLUMN weight DECIMAL(10,2) NULL;
+UPDATE growth_death SET weight = 0;
+ALTER TABLE growth_death ALTER COLUMN weight SET NOT NULL;
+-- feedings kept no date before, the day they were recorded is the closest
+ALTER TABLE growth_feeding ADD COLUMN feeding_date DATE NULL;
+UPDATE growth_feeding SET feeding_date = CAST(created AS DATE);
+ALTER TABLE growth_feeding ALTER COLUMN feeding_date SET NOT NULL;
+ALTER TABLE growth_summary ADD COLUMN weight DECIMAL(10,2) NULL;
+UPDATE growth_summary SET weight = 0;
+ALTER TABLE growth_summary ALTER COLUMN weight SET NOT NULL;
+ALTER TABLE growth_summary ADD COLUMN amount DECIMAL(20,0) NULL;
+UPDATE growth_summary SET amount = 0;
+ALTER TABLE growth_summary ALTER COLUMN amount SET NOT NULL;
+ALTER TABLE growth_summary RENAME COLUMN growth_cycle_batch_id TO growth_batch_cycle_id;
+ALTER TABLE growth_sales DROP COLUMN user_id;
+ALTER TABLE growth_sales RENAME COLUMN timestamp TO created;
+ALTER TABLE growth_sales ADD COLUMN updated TIMESTAMP NULL;
+ALTER TABLE growth_sales RENAME COLUMN weight TO qty;
+ALTER TABLE growth_sales ALTER COLUMN qty TYPE DECIMAL(5,0), ALTER COLUMN qty DROP NOT NULL;
+ALTER TABLE growth_sales_detail RENAME COLUMN detail_date TO created;
+ALTER TABLE growth_sales_detail ALTER COLUMN created TYPE TIMESTAMP;
+ALTER TABLE growth_sales_detail ADD COLUMN updated TIMESTAMP NULL;
@@ -0,0 +1,17 @@
+ALTER TABLE growth_batch_cycle ADD COLUMN seed_cost DECIMAL(20,2) NOT NULL DEFAULT 0;
+ALTER TABLE feed_incoming ADD COLUMN price DECIMAL(20,2) NOT NULL DEFAULT 0;
+ALTER TABLE growth_sales_detail ADD COLUMN price DECIMAL(20,2) NOT NULL DEFAULT 0;
+CREATE TABLE IF NOT EXISTS growth_cost (
+  id UUID NOT NULL,
+  growth_batch_cycle_id UUID NOT NULL,
+  cost_date DATE NOT NULL,
+  description VARCHAR(255) NOT NULL,
+  amount DECIMAL(20,2) NOT NULL,
+  created TIMESTAMP NOT NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT fk_cost_batch_cycle
+    FOREIGN KEY (growth_batch_cycle_id)
+    REFERENCES growth_batch_cycle (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE);
+CREATE INDEX fk_cost_batch_cycle_idx ON growth_cost (growth_batch_cycle_id);
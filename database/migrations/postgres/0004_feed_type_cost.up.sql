@@ -0,0 +1,30 @@
+ALTER TABLE feed_type ADD COLUMN average_cost DECIMAL(20,4) NOT NULL DEFAULT 0;
+ALTER TABLE feed_incoming ADD COLUMN incoming_date DATE NULL;
+UPDATE feed_incoming SET incoming_date = CAST(created AS DATE);
+ALTER TABLE feed_incoming ALTER COLUMN incoming_date SET NOT NULL;
+ALTER TABLE feed_incoming ADD COLUMN supplier VARCHAR(255) NULL DEFAULT NULL;
+ALTER TABLE feed_incoming ADD COLUMN invoice VARCHAR(100) NULL DEFAULT NULL;
+ALTER TABLE feed_incoming ADD COLUMN lot_number VARCHAR(100) NULL DEFAULT NULL;
+CREATE TABLE IF NOT EXISTS feed_type_cost (
+  id UUID NOT NULL,
+  feed_type_id UUID NOT NULL,
+  feed_incoming_id UUID NOT NULL,
+  cost_date DATE NOT NULL,
+  stock DECIMAL(20,2) NOT NULL,
+  qty DECIMAL(20,2) NOT NULL,
+  price DECIMAL(20,2) NOT NULL,
+  average_cost DECIMAL(20,4) NOT NULL,
+  created TIMESTAMP NOT NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT fk_feed_type_cost_feed_type
+    FOREIGN KEY (feed_type_id)
+    REFERENCES feed_type (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE,
+  CONSTRAINT fk_feed_type_cost_feed_incoming
+    FOREIGN KEY (feed_incoming_id)
+    REFERENCES feed_incoming (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE);
+CREATE INDEX fk_feed_type_cost_feed_type_idx ON feed_type_cost (feed_type_id);
+CREATE INDEX fk_feed_type_cost_feed_incoming_idx ON feed_type_cost (feed_incoming_id);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS feed_supplier (
+  id UUID NOT NULL,
+  name VARCHAR(255) NOT NULL,
+  contact VARCHAR(100) NULL DEFAULT NULL,
+  phone VARCHAR(50) NULL DEFAULT NULL,
+  address VARCHAR(255) NULL DEFAULT NULL,
+  status SMALLINT NOT NULL,
+  deleted SMALLINT NOT NULL,
+  created TIMESTAMP NOT NULL,
+  updated TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (id));
+ALTER TABLE feed_incoming ADD COLUMN feed_supplier_id UUID NULL DEFAULT NULL;
+CREATE INDEX fk_feed_incoming_feed_supplier_idx ON feed_incoming (feed_supplier_id);
+INSERT INTO feed_supplier (id, name, status, deleted, created) SELECT gen_random_uuid(), supplier, 1, 0, NOW() FROM (SELECT DISTINCT supplier FROM feed_incoming WHERE supplier IS NOT NULL AND supplier <> '') s;
+UPDATE feed_incoming SET feed_supplier_id = (SELECT s.id FROM feed_supplier s WHERE s.name = feed_incoming.supplier) WHERE supplier IS NOT NULL AND supplier <> '';
+ALTER TABLE feed_incoming DROP COLUMN supplier;
+ALTER TABLE feed_incoming ADD CONSTRAINT fk_feed_incoming_feed_supplier FOREIGN KEY (feed_supplier_id) REFERENCES feed_supplier (id) ON DELETE SET NULL ON UPDATE CASCADE;
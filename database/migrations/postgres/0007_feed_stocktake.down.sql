@@ -0,0 +1,6 @@
+ALTER TABLE feed_adjustment DROP CONSTRAINT fk_feed_adjustment_feed_stocktake;
+DROP INDEX fk_feed_adjustment_feed_stocktake_idx;
+ALTER TABLE feed_adjustment DROP COLUMN feed_stocktake_id;
+ALTER TABLE feed_adjustment DROP COLUMN reason;
+DROP TABLE IF EXISTS feed_stocktake_detail;
+DROP TABLE IF EXISTS feed_stocktake;
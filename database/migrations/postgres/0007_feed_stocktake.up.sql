@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS feed_stocktake (
+  id UUID NOT NULL,
+  stocktake_date DATE NOT NULL,
+  status VARCHAR(20) NOT NULL,
+  remarks TEXT NULL DEFAULT NULL,
+  approved TIMESTAMP NULL DEFAULT NULL,
+  created TIMESTAMP NOT NULL,
+  updated TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (id));
+CREATE TABLE IF NOT EXISTS feed_stocktake_detail (
+  id UUID NOT NULL,
+  feed_stocktake_id UUID NOT NULL,
+  feed_type_id UUID NOT NULL,
+  counted_qty DECIMAL(20,2) NOT NULL,
+  system_qty DECIMAL(20,2) NOT NULL DEFAULT 0,
+  variance DECIMAL(20,2) NOT NULL DEFAULT 0,
+  created TIMESTAMP NOT NULL,
+  updated TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT fk_feed_stocktake_detail_feed_stocktake
+    FOREIGN KEY (feed_stocktake_id)
+    REFERENCES feed_stocktake (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE,
+  CONSTRAINT fk_feed_stocktake_detail_feed_type
+    FOREIGN KEY (feed_type_id)
+    REFERENCES feed_type (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE);
+CREATE INDEX fk_feed_stocktake_detail_feed_stocktake_idx ON feed_stocktake_detail (feed_stocktake_id);
+CREATE INDEX fk_feed_stocktake_detail_feed_type_idx ON feed_stocktake_detail (feed_type_id);
+ALTER TABLE feed_adjustment ADD COLUMN reason VARCHAR(20) NOT NULL DEFAULT 'manual';
+ALTER TABLE feed_adjustment ADD COLUMN feed_stocktake_id UUID NULL DEFAULT NULL;
+CREATE INDEX fk_feed_adjustment_feed_stocktake_idx ON feed_adjustment (feed_stocktake_id);
+ALTER TABLE feed_adjustment ADD CONSTRAINT fk_feed_adjustment_feed_stocktake FOREIGN KEY (feed_stocktake_id) REFERENCES feed_stocktake (id) ON DELETE SET NULL ON UPDATE CASCADE;
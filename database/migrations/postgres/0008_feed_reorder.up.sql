@@ -0,0 +1,2 @@
+ALTER TABLE feed_type ADD COLUMN reorder_point DECIMAL(20,2) NOT NULL DEFAULT 0;
+ALTER TABLE feed_type ADD COLUMN reorder_qty DECIMAL(20,2) NOT NULL DEFAULT 0;
@@ -0,0 +1,13 @@
+-- rows go back to the unit of their feed type, g, ton or the unit it has a factor for
+CREATE TEMPORARY TABLE feed_unit_rescale (feed_type_id UUID NOT NULL, factor DOUBLE PRECISION NOT NULL, PRIMARY KEY (feed_type_id));
+INSERT INTO feed_unit_rescale (feed_type_id, factor) SELECT id, CASE LOWER(TRIM(unit)) WHEN 'g' THEN 1000 ELSE 0.001 END FROM feed_type WHERE LOWER(TRIM(unit)) IN ('g', 'ton') UNION ALL SELECT t.id, 1.0 / u.factor FROM feed_type t JOIN feed_type_unit u ON u.feed_type_id = t.id AND u.unit = LOWER(TRIM(t.unit));
+UPDATE feed_type AS t SET average_cost = t.average_cost / r.factor, reorder_point = t.reorder_point * r.factor, reorder_qty = t.reorder_qty * r.factor FROM feed_unit_rescale r WHERE r.feed_type_id = t.id;
+UPDATE feed_incoming AS t SET qty = t.qty * r.factor, price = t.price / r.factor FROM feed_unit_rescale r WHERE r.feed_type_id = t.feed_type_id;
+UPDATE feed_adjustment AS t SET qty = t.qty * r.factor FROM feed_unit_rescale r WHERE r.feed_type_id = t.feed_type_id;
+UPDATE growth_feeding AS t SET qty = t.qty * r.factor FROM feed_unit_rescale r WHERE r.feed_type_id = t.feed_type_id;
+UPDATE feed_lot_usage AS t SET qty = t.qty * r.factor FROM feed_lot l JOIN feed_unit_rescale r ON r.feed_type_id = l.feed_type_id WHERE l.id = t.feed_lot_id;
+UPDATE feed_lot AS t SET qty = t.qty * r.factor, remaining = t.remaining * r.factor FROM feed_unit_rescale r WHERE r.feed_type_id = t.feed_type_id;
+UPDATE feed_stocktake_detail AS t SET counted_qty = t.counted_qty * r.factor, system_qty = t.system_qty * r.factor, variance = t.variance * r.factor FROM feed_unit_rescale r WHERE r.feed_type_id = t.feed_type_id;
+UPDATE feed_type_cost AS t SET stock = t.stock * r.factor, qty = t.qty * r.factor, price = t.price / r.factor, average_cost = t.average_cost / r.factor FROM feed_unit_rescale r WHERE r.feed_type_id = t.feed_type_id;
+DROP TABLE feed_unit_rescale;
+DROP TABLE IF EXISTS feed_type_unit;
@@ -0,0 +1,5 @@
+DROP INDEX feed_type_stage_idx;
+ALTER TABLE feed_type DROP COLUMN stage;
+ALTER TABLE feed_type DROP COLUMN pellet_size;
+ALTER TABLE feed_type DROP COLUMN fat;
+ALTER TABLE feed_type DROP COLUMN protein;
@@ -0,0 +1,5 @@
+ALTER TABLE feed_type ADD COLUMN protein DECIMAL(5,2) NOT NULL DEFAULT 0;
+ALTER TABLE feed_type ADD COLUMN fat DECIMAL(5,2) NOT NULL DEFAULT 0;
+ALTER TABLE feed_type ADD COLUMN pellet_size DECIMAL(5,2) NOT NULL DEFAULT 0;
+ALTER TABLE feed_type ADD COLUMN stage VARCHAR(20) NOT NULL DEFAULT '';
+CREATE INDEX feed_type_stage_idx ON feed_type (stage, pellet_size);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS growth_water_quality_range;
+DROP TABLE IF EXISTS growth_water_quality;
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS growth_water_quality (
+  id UUID NOT NULL,
+  growth_pool_id UUID NOT NULL,
+  measured_at TIMESTAMP NOT NULL,
+  dissolved_oxygen DECIMAL(10,2) NULL DEFAULT NULL,
+  ph DECIMAL(10,2) NULL DEFAULT NULL,
+  temperature DECIMAL(10,2) NULL DEFAULT NULL,
+  ammonia DECIMAL(10,3) NULL DEFAULT NULL,
+  nitrite DECIMAL(10,3) NULL DEFAULT NULL,
+  salinity DECIMAL(10,2) NULL DEFAULT NULL,
+  transparency DECIMAL(10,2) NULL DEFAULT NULL,
+  out_of_range SMALLINT NOT NULL DEFAULT 0,
+  out_of_range_parameters VARCHAR(255) NOT NULL DEFAULT '',
+  remarks VARCHAR(255) NULL DEFAULT NULL,
+  created TIMESTAMP NOT NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT fk_growth_water_quality_growth_pool
+    FOREIGN KEY (growth_pool_id)
+    REFERENCES growth_pool (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE);
+CREATE INDEX fk_growth_water_quality_growth_pool_idx ON growth_water_quality (growth_pool_id, measured_at);
+CREATE TABLE IF NOT EXISTS growth_water_quality_range (
+  parameter VARCHAR(45) NOT NULL,
+  min_value DECIMAL(10,3) NULL DEFAULT NULL,
+  max_value DECIMAL(10,3) NULL DEFAULT NULL,
+  updated TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (parameter));
+INSERT INTO growth_water_quality_range (parameter, min_value, max_value, updated) VALUES
+  ('dissolved_oxygen', 4, NULL, NOW()),
+  ('ph', 6.5, 8.5, NOW()),
+  ('temperature', 25, 32, NOW()),
+  ('ammonia', NULL, 0.1, NOW()),
+  ('nitrite', NULL, 1, NOW()),
+  ('salinity', NULL, NULL, NOW()),
+  ('transparency', 25, 45, NOW());
@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS growth_treatment (
+  id UUID NOT NULL,
+  growth_batch_cycle_id UUID NOT NULL,
+  product VARCHAR(100) NOT NULL,
+  dose DECIMAL(10,3) NOT NULL,
+  unit VARCHAR(20) NOT NULL,
+  method VARCHAR(20) NOT NULL,
+  start_date TIMESTAMP NOT NULL,
+  end_date TIMESTAMP NULL DEFAULT NULL,
+  withdrawal_days INTEGER NOT NULL DEFAULT 0,
+  remarks VARCHAR(255) NULL DEFAULT NULL,
+  created TIMESTAMP NOT NULL,
+  updated TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT fk_growth_treatment_growth_batch_cycle
+    FOREIGN KEY (growth_batch_cycle_id)
+    REFERENCES growth_batch_cycle (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE);
+CREATE INDEX fk_growth_treatment_growth_batch_cycle_idx ON growth_treatment (growth_batch_cycle_id);
+CREATE TABLE IF NOT EXISTS growth_treatment_override (
+  id UUID NOT NULL,
+  growth_batch_cycle_id UUID NOT NULL,
+  growth_treatment_id UUID NOT NULL,
+  authorized_by VARCHAR(100) NOT NULL,
+  reason VARCHAR(255) NOT NULL,
+  created TIMESTAMP NOT NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT fk_growth_treatment_override_growth_treatment
+    FOREIGN KEY (growth_treatment_id)
+    REFERENCES growth_treatment (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE);
+CREATE INDEX fk_growth_treatment_override_growth_treatment_idx ON growth_treatment_override (growth_treatment_id);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS growth_death_cause (
+  id UUID NOT NULL,
+  name VARCHAR(45) NOT NULL,
+  description VARCHAR(255) NOT NULL DEFAULT '',
+  deleted SMALLINT NOT NULL DEFAULT 0,
+  created TIMESTAMP NOT NULL,
+  updated TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT growth_death_cause_name_unique UNIQUE (name));
+INSERT INTO growth_death_cause (id, name, description, deleted, created) VALUES
+  ('6f1c2a4e-0c3b-4d8e-9a51-1f0d2b7c0001', 'Unknown', 'Cause was not recorded', 0, NOW()),
+  ('6f1c2a4e-0c3b-4d8e-9a51-1f0d2b7c0002', 'Disease', 'Bacterial, viral or parasitic infection', 0, NOW()),
+  ('6f1c2a4e-0c3b-4d8e-9a51-1f0d2b7c0003', 'Predation', 'Birds, snakes and other predators', 0, NOW()),
+  ('6f1c2a4e-0c3b-4d8e-9a51-1f0d2b7c0004', 'Handling', 'Stocking, sampling, grading or transport', 0, NOW()),
+  ('6f1c2a4e-0c3b-4d8e-9a51-1f0d2b7c0005', 'Water quality', 'Low oxygen, ammonia or temperature stress', 0, NOW());
+ALTER TABLE growth_death ADD COLUMN growth_death_cause_id UUID NOT NULL DEFAULT '6f1c2a4e-0c3b-4d8e-9a51-1f0d2b7c0001';
+CREATE INDEX fk_growth_death_growth_death_cause_idx ON growth_death (growth_death_cause_id);
+ALTER TABLE growth_death ADD CONSTRAINT fk_growth_death_growth_death_cause FOREIGN KEY (growth_death_cause_id) REFERENCES growth_death_cause (id) ON DELETE RESTRICT ON UPDATE CASCADE;
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS growth_hatchery (
+  id UUID NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  contact VARCHAR(100) NOT NULL DEFAULT '',
+  phone VARCHAR(45) NOT NULL DEFAULT '',
+  address VARCHAR(255) NOT NULL DEFAULT '',
+  deleted SMALLINT NOT NULL DEFAULT 0,
+  created TIMESTAMP NOT NULL,
+  updated TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (id));
+CREATE TABLE IF NOT EXISTS growth_seed_source (
+  id UUID NOT NULL,
+  growth_hatchery_id UUID NOT NULL,
+  species VARCHAR(100) NOT NULL,
+  strain VARCHAR(100) NOT NULL DEFAULT '',
+  lot_code VARCHAR(100) NOT NULL,
+  stocking_size DECIMAL(10,3) NOT NULL DEFAULT 0,
+  quality_grade VARCHAR(20) NOT NULL DEFAULT '',
+  remarks VARCHAR(255) NOT NULL DEFAULT '',
+  created TIMESTAMP NOT NULL,
+  updated TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT fk_growth_seed_source_growth_hatchery
+    FOREIGN KEY (growth_hatchery_id)
+    REFERENCES growth_hatchery (id)
+    ON DELETE RESTRICT
+    ON UPDATE CASCADE);
+CREATE INDEX fk_growth_seed_source_growth_hatchery_idx ON growth_seed_source (growth_hatchery_id);
+ALTER TABLE growth_batch_cycle ADD COLUMN growth_seed_source_id UUID NULL DEFAULT NULL;
+CREATE INDEX fk_growth_batch_cycle_growth_seed_source_idx ON growth_batch_cycle (growth_seed_source_id);
+ALTER TABLE growth_batch_cycle ADD CONSTRAINT fk_growth_batch_cycle_growth_seed_source FOREIGN KEY (growth_seed_source_id) REFERENCES growth_seed_source (id) ON DELETE SET NULL ON UPDATE CASCADE;
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS growth_sampling;
+ALTER TABLE growth_batch DROP CONSTRAINT fk_growth_batch_growth_species;
+DROP INDEX fk_growth_batch_growth_species_idx;
+ALTER TABLE growth_batch DROP COLUMN growth_species_id;
+DROP TABLE IF EXISTS growth_species_curve;
+DROP TABLE IF EXISTS growth_species;
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS growth_species (
+  id UUID NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  scientific_name VARCHAR(100) NOT NULL DEFAULT '',
+  target_weight DECIMAL(10,3) NOT NULL DEFAULT 0,
+  deleted SMALLINT NOT NULL DEFAULT 0,
+  created TIMESTAMP NOT NULL,
+  updated TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (id));
+CREATE TABLE IF NOT EXISTS growth_species_curve (
+  growth_species_id UUID NOT NULL,
+  day INTEGER NOT NULL,
+  weight DECIMAL(10,3) NOT NULL,
+  PRIMARY KEY (growth_species_id, day),
+  CONSTRAINT fk_growth_species_curve_growth_species
+    FOREIGN KEY (growth_species_id)
+    REFERENCES growth_species (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE);
+ALTER TABLE growth_batch ADD COLUMN growth_species_id UUID NULL DEFAULT NULL;
+CREATE INDEX fk_growth_batch_growth_species_idx ON growth_batch (growth_species_id);
+ALTER TABLE growth_batch ADD CONSTRAINT fk_growth_batch_growth_species FOREIGN KEY (growth_species_id) REFERENCES growth_species (id) ON DELETE SET NULL ON UPDATE CASCADE;
+CREATE TABLE IF NOT EXISTS growth_sampling (
+  id UUID NOT NULL,
+  growth_batch_cycle_id UUID NOT NULL,
+  sampling_date TIMESTAMP NOT NULL,
+  sample_size INTEGER NOT NULL,
+  average_weight DECIMAL(10,3) NOT NULL,
+  remarks VARCHAR(255) NULL DEFAULT NULL,
+  created TIMESTAMP NOT NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT fk_growth_sampling_growth_batch_cycle
+    FOREIGN KEY (growth_batch_cycle_id)
+    REFERENCES growth_batch_cycle (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE);
+CREATE INDEX fk_growth_sampling_growth_batch_cycle_idx ON growth_sampling (growth_batch_cycle_id, sampling_date);
@@ -0,0 +1 @@
+ALTER TABLE growth_pool ADD COLUMN area DECIMAL(10,2) NOT NULL DEFAULT 0;
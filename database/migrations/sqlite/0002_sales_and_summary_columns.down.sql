@@ -0,0 +1,16 @@
+ALTER TABLE growth_sales_detail DROP COLUMN updated;
+ALTER TABLE growth_sales_detail ADD COLUMN detail_date DATE NOT NULL DEFAULT '1970-01-01';
+UPDATE growth_sales_detail SET detail_date = DATE(created);
+ALTER TABLE growth_sales_detail DROP COLUMN created;
+ALTER TABLE growth_sales ADD COLUMN weight REAL NOT NULL DEFAULT 0;
+UPDATE growth_sales SET weight = COALESCE(qty, 0);
+ALTER TABLE growth_sales DROP COLUMN qty;
+ALTER TABLE growth_sales DROP COLUMN updated;
+ALTER TABLE growth_sales RENAME COLUMN created TO timestamp;
+ALTER TABLE growth_sales ADD COLUMN user_id INTEGER NOT NULL DEFAULT 0;
+UPDATE growth_sales SET user_id = 0;
+ALTER TABLE growth_summary RENAME COLUMN growth_batch_cycle_id TO growth_cycle_batch_id;
+ALTER TABLE growth_summary DROP COLUMN amount;
+ALTER TABLE growth_summary DROP COLUMN weight;
+ALTER TABLE growth_feeding DROP COLUMN feeding_date;
+ALTER TABLE growth_death DROP COLUMN weight;
@@ -0,0 +1,21 @@
+ALTER TABLE growth_death ADD COLUMN weight REAL NOT NULL DEFAULT 0;
+UPDATE growth_death SET weight = 0;
+-- feedings kept no date before, the day they were recorded is the closest
+ALTER TABLE growth_feeding ADD COLUMN feeding_date DATE NOT NULL DEFAULT '1970-01-01';
+UPDATE growth_feeding SET feeding_date = DATE(created);
+ALTER TABLE growth_summary ADD COLUMN weight REAL NOT NULL DEFAULT 0;
+UPDATE growth_summary SET weight = 0;
+ALTER TABLE growth_summary ADD COLUMN amount REAL NOT NULL DEFAULT 0;
+UPDATE growth_summary SET amount = 0;
+ALTER TABLE growth_summary RENAME COLUMN growth_cycle_batch_id TO growth_batch_cycle_id;
+ALTER TABLE growth_sales DROP COLUMN user_id;
+ALTER TABLE growth_sales RENAME COLUMN timestamp TO created;
+ALTER TABLE growth_sales ADD COLUMN updated TIMESTAMP NULL;
+-- sqlite changes a column type only through a new column
+ALTER TABLE growth_sales ADD COLUMN qty REAL NULL;
+UPDATE growth_sales SET qty = weight;
+ALTER TABLE growth_sales DROP COLUMN weight;
+ALTER TABLE growth_sales_detail ADD COLUMN created TIMESTAMP NOT NULL DEFAULT '1970-01-01 00:00:00';
+UPDATE growth_sales_detail SET created = detail_date;
+ALTER TABLE growth_sales_detail DROP COLUMN detail_date;
+ALTER TABLE growth_sales_detail ADD COLUMN updated TIMESTAMP NULL;
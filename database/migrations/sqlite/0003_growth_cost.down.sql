@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS growth_cost;
+ALTER TABLE growth_sales_detail DROP COLUMN price;
+ALTER TABLE feed_incoming DROP COLUMN price;
+ALTER TABLE growth_batch_cycle DROP COLUMN seed_cost;
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS feed_type_cost;
+ALTER TABLE feed_incoming DROP COLUMN lot_number;
+ALTER TABLE feed_incoming DROP COLUMN invoice;
+ALTER TABLE feed_incoming DROP COLUMN supplier;
+ALTER TABLE feed_incoming DROP COLUMN incoming_date;
+ALTER TABLE feed_type DROP COLUMN average_cost;
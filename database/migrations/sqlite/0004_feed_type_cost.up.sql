@@ -0,0 +1,29 @@
+ALTER TABLE feed_type ADD COLUMN average_cost REAL NOT NULL DEFAULT 0;
+ALTER TABLE feed_incoming ADD COLUMN incoming_date DATE NOT NULL DEFAULT '1970-01-01';
+UPDATE feed_incoming SET incoming_date = DATE(created);
+ALTER TABLE feed_incoming ADD COLUMN supplier VARCHAR(255) NULL DEFAULT NULL;
+ALTER TABLE feed_incoming ADD COLUMN invoice VARCHAR(100) NULL DEFAULT NULL;
+ALTER TABLE feed_incoming ADD COLUMN lot_number VARCHAR(100) NULL DEFAULT NULL;
+CREATE TABLE IF NOT EXISTS feed_type_cost (
+  id CHAR(36) NOT NULL,
+  feed_type_id CHAR(36) NOT NULL,
+  feed_incoming_id CHAR(36) NOT NULL,
+  cost_date DATE NOT NULL,
+  stock REAL NOT NULL,
+  qty REAL NOT NULL,
+  price REAL NOT NULL,
+  average_cost REAL NOT NULL,
+  created TIMESTAMP NOT NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT fk_feed_type_cost_feed_type
+    FOREIGN KEY (feed_type_id)
+    REFERENCES feed_type (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE,
+  CONSTRAINT fk_feed_type_cost_feed_incoming
+    FOREIGN KEY (feed_incoming_id)
+    REFERENCES feed_incoming (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE);
+CREATE INDEX fk_feed_type_cost_feed_type_idx ON feed_type_cost (feed_type_id);
+CREATE INDEX fk_feed_type_cost_feed_incoming_idx ON feed_type_cost (feed_incoming_id);
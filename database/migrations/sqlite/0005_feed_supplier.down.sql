@@ -0,0 +1,5 @@
+ALTER TABLE feed_incoming ADD COLUMN supplier VARCHAR(255) NULL DEFAULT NULL;
+UPDATE feed_incoming SET supplier = (SELECT s.name FROM feed_supplier s WHERE s.id = feed_incoming.feed_supplier_id) WHERE feed_supplier_id IS NOT NULL;
+DROP INDEX fk_feed_incoming_feed_supplier_idx;
+ALTER TABLE feed_incoming DROP COLUMN feed_supplier_id;
+DROP TABLE IF EXISTS feed_supplier;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS feed_supplier (
+  id CHAR(36) NOT NULL,
+  name VARCHAR(255) NOT NULL,
+  contact VARCHAR(100) NULL DEFAULT NULL,
+  phone VARCHAR(50) NULL DEFAULT NULL,
+  address VARCHAR(255) NULL DEFAULT NULL,
+  status SMALLINT NOT NULL,
+  deleted SMALLINT NOT NULL,
+  created TIMESTAMP NOT NULL,
+  updated TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (id));
+ALTER TABLE feed_incoming ADD COLUMN feed_supplier_id CHAR(36) NULL DEFAULT NULL;
+CREATE INDEX fk_feed_incoming_feed_supplier_idx ON feed_incoming (feed_supplier_id);
+INSERT INTO feed_supplier (id, name, status, deleted, created) SELECT lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' || substr(lower(hex(randomblob(2))), 2) || '-' || substr('89ab', 1 + abs(random()) % 4, 1) || substr(lower(hex(randomblob(2))), 2) || '-' || lower(hex(randomblob(6))), supplier, 1, 0, CURRENT_TIMESTAMP FROM (SELECT DISTINCT supplier FROM feed_incoming WHERE supplier IS NOT NULL AND supplier <> '') s;
+UPDATE feed_incoming SET feed_supplier_id = (SELECT s.id FROM feed_supplier s WHERE s.name = feed_incoming.supplier) WHERE supplier IS NOT NULL AND supplier <> '';
+ALTER TABLE feed_incoming DROP COLUMN supplier;
+-- sqlite cannot add a foreign key to an existing table nor drop a column
+-- holding one, the reference is kept by the repositories
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS feed_lot_usage;
+DROP TABLE IF EXISTS feed_lot;
+ALTER TABLE feed_incoming DROP COLUMN expired;
+ALTER TABLE feed_incoming DROP COLUMN manufactured;
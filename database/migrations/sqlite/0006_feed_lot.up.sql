@@ -0,0 +1,42 @@
+ALTER TABLE feed_incoming ADD COLUMN manufactured DATE NULL DEFAULT NULL;
+ALTER TABLE feed_incoming ADD COLUMN expired DATE NULL DEFAULT NULL;
+CREATE TABLE IF NOT EXISTS feed_lot (
+  id CHAR(36) NOT NULL,
+  feed_type_id CHAR(36) NOT NULL,
+  feed_incoming_id CHAR(36) NOT NULL,
+  lot_number VARCHAR(100) NULL DEFAULT NULL,
+  manufactured DATE NULL DEFAULT NULL,
+  expired DATE NULL DEFAULT NULL,
+  qty REAL NOT NULL,
+  remaining REAL NOT NULL,
+  created TIMESTAMP NOT NULL,
+  updated TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT fk_feed_lot_feed_type
+    FOREIGN KEY (feed_type_id)
+    REFERENCES feed_type (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE,
+  CONSTRAINT fk_feed_lot_feed_incoming
+    FOREIGN KEY (feed_incoming_id)
+    REFERENCES feed_incoming (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE);
+CREATE INDEX fk_feed_lot_feed_type_idx ON feed_lot (feed_type_id);
+CREATE INDEX fk_feed_lot_feed_incoming_idx ON feed_lot (feed_incoming_id);
+CREATE INDEX feed_lot_expired_idx ON feed_lot (expired);
+CREATE TABLE IF NOT EXISTS feed_lot_usage (
+  id CHAR(36) NOT NULL,
+  feed_lot_id CHAR(36) NOT NULL,
+  reference_type VARCHAR(20) NOT NULL,
+  reference_id CHAR(36) NOT NULL,
+  qty REAL NOT NULL,
+  created TIMESTAMP NOT NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT fk_feed_lot_usage_feed_lot
+    FOREIGN KEY (feed_lot_id)
+    REFERENCES feed_lot (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE);
+CREATE INDEX fk_feed_lot_usage_feed_lot_idx ON feed_lot_usage (feed_lot_id);
+CREATE INDEX feed_lot_usage_reference_idx ON feed_lot_usage (reference_id);
@@ -0,0 +1,2 @@
+ALTER TABLE feed_type DROP COLUMN reorder_qty;
+ALTER TABLE feed_type DROP COLUMN reorder_point;
@@ -0,0 +1,2 @@
+ALTER TABLE feed_type ADD COLUMN reorder_point REAL NOT NULL DEFAULT 0;
+ALTER TABLE feed_type ADD COLUMN reorder_qty REAL NOT NULL DEFAULT 0;
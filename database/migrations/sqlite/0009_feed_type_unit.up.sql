@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS feed_type_unit (
+  id CHAR(36) NOT NULL,
+  feed_type_id CHAR(36) NOT NULL,
+  unit VARCHAR(45) NOT NULL,
+  factor REAL NOT NULL,
+  created TIMESTAMP NOT NULL,
+  updated TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT feed_type_unit_unique UNIQUE (feed_type_id, unit),
+  CONSTRAINT fk_feed_type_unit_feed_type
+    FOREIGN KEY (feed_type_id)
+    REFERENCES feed_type (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE);
+-- quantities are stored in kg from now on, rows of feed types counted in g or ton
+-- are rescaled. A feed type counted in any other unit has no factor yet, it is
+-- inserted twice so the migration stops on its duplicate name until the unit of
+-- the feed type and its rows are changed to kg, g or ton
+CREATE TEMPORARY TABLE feed_type_without_factor (name VARCHAR(255) NOT NULL, PRIMARY KEY (name));
+INSERT INTO feed_type_without_factor (name) SELECT name FROM feed_type WHERE LOWER(TRIM(unit)) NOT IN ('kg', 'g', 'ton') UNION ALL SELECT name FROM feed_type WHERE LOWER(TRIM(unit)) NOT IN ('kg', 'g', 'ton');
+DROP TABLE feed_type_without_factor;
+CREATE TEMPORARY TABLE feed_unit_rescale (feed_type_id CHAR(36) NOT NULL, factor REAL NOT NULL, PRIMARY KEY (feed_type_id));
+INSERT INTO feed_unit_rescale (feed_type_id, factor) SELECT id, CASE LOWER(TRIM(unit)) WHEN 'g' THEN 0.001 ELSE 1000 END FROM feed_type WHERE LOWER(TRIM(unit)) IN ('g', 'ton');
+UPDATE feed_type AS t SET average_cost = t.average_cost / r.factor, reorder_point = t.reorder_point * r.factor, reorder_qty = t.reorder_qty * r.factor FROM feed_unit_rescale r WHERE r.feed_type_id = t.id;
+UPDATE feed_incoming AS t SET qty = t.qty * r.factor, price = t.price / r.factor FROM feed_unit_rescale r WHERE r.feed_type_id = t.feed_type_id;
+UPDATE feed_adjustment AS t SET qty = t.qty * r.factor FROM feed_unit_rescale r WHERE r.feed_type_id = t.feed_type_id;
+UPDATE growth_feeding AS t SET qty = t.qty * r.factor FROM feed_unit_rescale r WHERE r.feed_type_id = t.feed_type_id;
+UPDATE feed_lot_usage AS t SET qty = t.qty * r.factor FROM feed_lot l JOIN feed_unit_rescale r ON r.feed_type_id = l.feed_type_id WHERE l.id = t.feed_lot_id;
+UPDATE feed_lot AS t SET qty = t.qty * r.factor, remaining = t.remaining * r.factor FROM feed_unit_rescale r WHERE r.feed_type_id = t.feed_type_id;
+UPDATE feed_stocktake_detail AS t SET counted_qty = t.counted_qty * r.factor, system_qty = t.system_qty * r.factor, variance = t.variance * r.factor FROM feed_unit_rescale r WHERE r.feed_type_id = t.feed_type_id;
+UPDATE feed_type_cost AS t SET stock = t.stock * r.factor, qty = t.qty * r.factor, price = t.price / r.factor, average_cost = t.average_cost / r.factor FROM feed_unit_rescale r WHERE r.feed_type_id = t.feed_type_id;
+DROP TABLE feed_unit_rescale;
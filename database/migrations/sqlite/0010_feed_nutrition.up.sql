@@ -0,0 +1,5 @@
+ALTER TABLE feed_type ADD COLUMN protein REAL NOT NULL DEFAULT 0;
+ALTER TABLE feed_type ADD COLUMN fat REAL NOT NULL DEFAULT 0;
+ALTER TABLE feed_type ADD COLUMN pellet_size REAL NOT NULL DEFAULT 0;
+ALTER TABLE feed_type ADD COLUMN stage VARCHAR(20) NOT NULL DEFAULT '';
+CREATE INDEX feed_type_stage_idx ON feed_type (stage, pellet_size);
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS growth_water_quality (
+  id CHAR(36) NOT NULL,
+  growth_pool_id CHAR(36) NOT NULL,
+  measured_at TIMESTAMP NOT NULL,
+  dissolved_oxygen REAL NULL DEFAULT NULL,
+  ph REAL NULL DEFAULT NULL,
+  temperature REAL NULL DEFAULT NULL,
+  ammonia REAL NULL DEFAULT NULL,
+  nitrite REAL NULL DEFAULT NULL,
+  salinity REAL NULL DEFAULT NULL,
+  transparency REAL NULL DEFAULT NULL,
+  out_of_range SMALLINT NOT NULL DEFAULT 0,
+  out_of_range_parameters VARCHAR(255) NOT NULL DEFAULT '',
+  remarks VARCHAR(255) NULL DEFAULT NULL,
+  created TIMESTAMP NOT NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT fk_growth_water_quality_growth_pool
+    FOREIGN KEY (growth_pool_id)
+    REFERENCES growth_pool (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE);
+CREATE INDEX fk_growth_water_quality_growth_pool_idx ON growth_water_quality (growth_pool_id, measured_at);
+CREATE TABLE IF NOT EXISTS growth_water_quality_range (
+  parameter VARCHAR(45) NOT NULL,
+  min_value REAL NULL DEFAULT NULL,
+  max_value REAL NULL DEFAULT NULL,
+  updated TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (parameter));
+INSERT INTO growth_water_quality_range (parameter, min_value, max_value, updated) VALUES
+  ('dissolved_oxygen', 4, NULL, CURRENT_TIMESTAMP),
+  ('ph', 6.5, 8.5, CURRENT_TIMESTAMP),
+  ('temperature', 25, 32, CURRENT_TIMESTAMP),
+  ('ammonia', NULL, 0.1, CURRENT_TIMESTAMP),
+  ('nitrite', NULL, 1, CURRENT_TIMESTAMP),
+  ('salinity', NULL, NULL, CURRENT_TIMESTAMP),
+  ('transparency', 25, 45, CURRENT_TIMESTAMP);
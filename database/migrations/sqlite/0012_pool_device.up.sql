@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS growth_pool_device (
+  id CHAR(36) NOT NULL,
+  growth_pool_id CHAR(36) NOT NULL,
+  device_id VARCHAR(100) NOT NULL,
+  remarks VARCHAR(255) NULL DEFAULT NULL,
+  created TIMESTAMP NOT NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT growth_pool_device_device_id_unique UNIQUE (device_id),
+  CONSTRAINT fk_growth_pool_device_growth_pool
+    FOREIGN KEY (growth_pool_id)
+    REFERENCES growth_pool (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE);
+CREATE INDEX fk_growth_pool_device_growth_pool_idx ON growth_pool_device (growth_pool_id);
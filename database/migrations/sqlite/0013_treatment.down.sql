@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS growth_treatment_override;
+DROP TABLE IF EXISTS growth_treatment;
@@ -0,0 +1,3 @@
+DROP INDEX fk_growth_death_growth_death_cause_idx;
+ALTER TABLE growth_death DROP COLUMN growth_death_cause_id;
+DROP TABLE IF EXISTS growth_death_cause;
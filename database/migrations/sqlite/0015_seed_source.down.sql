@@ -0,0 +1,4 @@
+DROP INDEX fk_growth_batch_cycle_growth_seed_source_idx;
+ALTER TABLE growth_batch_cycle DROP COLUMN growth_seed_source_id;
+DROP TABLE IF EXISTS growth_seed_source;
+DROP TABLE IF EXISTS growth_hatchery;
@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS growth_hatchery (
+  id CHAR(36) NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  contact VARCHAR(100) NOT NULL DEFAULT '',
+  phone VARCHAR(45) NOT NULL DEFAULT '',
+  address VARCHAR(255) NOT NULL DEFAULT '',
+  deleted SMALLINT NOT NULL DEFAULT 0,
+  created TIMESTAMP NOT NULL,
+  updated TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (id));
+CREATE TABLE IF NOT EXISTS growth_seed_source (
+  id CHAR(36) NOT NULL,
+  growth_hatchery_id CHAR(36) NOT NULL,
+  species VARCHAR(100) NOT NULL,
+  strain VARCHAR(100) NOT NULL DEFAULT '',
+  lot_code VARCHAR(100) NOT NULL,
+  stocking_size REAL NOT NULL DEFAULT 0,
+  quality_grade VARCHAR(20) NOT NULL DEFAULT '',
+  remarks VARCHAR(255) NOT NULL DEFAULT '',
+  created TIMESTAMP NOT NULL,
+  updated TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT fk_growth_seed_source_growth_hatchery
+    FOREIGN KEY (growth_hatchery_id)
+    REFERENCES growth_hatchery (id)
+    ON DELETE RESTRICT
+    ON UPDATE CASCADE);
+CREATE INDEX fk_growth_seed_source_growth_hatchery_idx ON growth_seed_source (growth_hatchery_id);
+ALTER TABLE growth_batch_cycle ADD COLUMN growth_seed_source_id CHAR(36) NULL DEFAULT NULL;
+CREATE INDEX fk_growth_batch_cycle_growth_seed_source_idx ON growth_batch_cycle (growth_seed_source_id);
+-- sqlite cannot add a foreign key to an existing table nor drop a column
+-- holding one, the reference is kept by the repositories
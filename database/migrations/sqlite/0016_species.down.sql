@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS growth_sampling;
+DROP INDEX fk_growth_batch_growth_species_idx;
+ALTER TABLE growth_batch DROP COLUMN growth_species_id;
+DROP TABLE IF EXISTS growth_species_curve;
+DROP TABLE IF EXISTS growth_species;
@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS growth_species (
+  id CHAR(36) NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  scientific_name VARCHAR(100) NOT NULL DEFAULT '',
+  target_weight REAL NOT NULL DEFAULT 0,
+  deleted SMALLINT NOT NULL DEFAULT 0,
+  created TIMESTAMP NOT NULL,
+  updated TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (id));
+CREATE TABLE IF NOT EXISTS growth_species_curve (
+  growth_species_id CHAR(36) NOT NULL,
+  day INTEGER NOT NULL,
+  weight REAL NOT NULL,
+  PRIMARY KEY (growth_species_id, day),
+  CONSTRAINT fk_growth_species_curve_growth_species
+    FOREIGN KEY (growth_species_id)
+    REFERENCES growth_species (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE);
+ALTER TABLE growth_batch ADD COLUMN growth_species_id CHAR(36) NULL DEFAULT NULL;
+CREATE INDEX fk_growth_batch_growth_species_idx ON growth_batch (growth_species_id);
+-- sqlite cannot add a foreign key to an existing table nor drop a column
+-- holding one, the reference is kept by the repositories
+CREATE TABLE IF NOT EXISTS growth_sampling (
+  id CHAR(36) NOT NULL,
+  growth_batch_cycle_id CHAR(36) NOT NULL,
+  sampling_date TIMESTAMP NOT NULL,
+  sample_size INTEGER NOT NULL,
+  average_weight REAL NOT NULL,
+  remarks VARCHAR(255) NULL DEFAULT NULL,
+  created TIMESTAMP NOT NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT fk_growth_sampling_growth_batch_cycle
+    FOREIGN KEY (growth_batch_cycle_id)
+    REFERENCES growth_batch_cycle (id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE);
+CREATE INDEX fk_growth_sampling_growth_batch_cycle_idx ON growth_sampling (growth_batch_cycle_id, sampling_date);
@@ -0,0 +1 @@
+ALTER TABLE growth_pool ADD COLUMN area REAL NOT NULL DEFAULT 0;
@@ -0,0 +1,112 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//dollarTag opens dollar quoted text, $$ or a tag like $body$
+var dollarTag = regexp.MustCompile(`^\$[A-Za-z_]*\$`)
+
+//postgresConnector hands out connections that accept the queries written for
+//mysql: ? placeholders become $1, $2... and bools the 0 and 1 kept by the
+//SMALLINT flag columns
+type postgresConnector struct {
+	driver.Connector
+}
+
+func (c *postgresConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &postgresConn{conn}, nil
+}
+
+//postgresConn only exposes Prepare, Begin and Close so database/sql sends
+//every query through Prepare
+type postgresConn struct {
+	driver.Conn
+}
+
+func (c *postgresConn) Prepare(query string) (driver.Stmt, error) {
+	stmt, err := c.Conn.Prepare(rebind(query))
+	if err != nil {
+		return nil, err
+	}
+	return &postgresStmt{stmt}, nil
+}
+
+type postgresStmt struct {
+	driver.Stmt
+}
+
+func (s *postgresStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.Stmt.Exec(flags(args))
+}
+
+func (s *postgresStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.Stmt.Query(flags(args))
+}
+
+//rebind numbers the ? placeholders. Question marks inside 'text', "quoted
+//identifiers", $$ dollar quoted text and -- or /* */ comments are kept
+func rebind(query string) string {
+	var result strings.Builder
+	number := 0
+	for i := 0; i < len(query); {
+		end := i + 1
+		if query[i] == '\'' || query[i] == '"' {
+			//a doubled quote closes and reopens, which keeps it quoted
+			end = closing(query, i+1, query[i:i+1])
+		} else if strings.HasPrefix(query[i:], "--") {
+			end = closing(query, i+2, "\n")
+		} else if strings.HasPrefix(query[i:], "/*") {
+			end = closing(query, i+2, "*/")
+		} else if tag := dollarTag.FindString(query[i:]); tag != "" && (i == 0 || !identifier(query[i-1])) {
+			end = closing(query, i+len(tag), tag)
+		} else if query[i] == '?' {
+			number++
+			result.WriteString("$" + strconv.Itoa(number))
+			i++
+			continue
+		}
+		result.WriteString(query[i:end])
+		i = end
+	}
+	return result.String()
+}
+
+//closing returns the index right after the first terminator from start on, the
+//end of the query when it is never closed
+func closing(query string, start int, terminator string) int {
+	if n := strings.Index(query[start:], terminator); n >= 0 {
+		return start + n + len(terminator)
+	}
+	return len(query)
+}
+
+//identifier tells whether char can be part of an unquoted name, a $ right
+//after one belongs to the name and opens no dollar quote
+func identifier(char byte) bool {
+	return char == '_' || char == '$' || (char >= '0' && char <= '9') || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+}
+
+//flags sends bools as 0 and 1. pq binds every value as untyped text the server
+//reads in the type of its column, 1 and 0 are valid for the SMALLINT flag
+//columns and for a BOOLEAN alike while true and false are not valid numbers
+func flags(args []driver.Value) []driver.Value {
+	for i, arg := range args {
+		if flag, ok := arg.(bool); ok {
+			if flag {
+				args[i] = int64(1)
+			} else {
+				args[i] = int64(0)
+			}
+		}
+	}
+	return args
+}
@@ -0,0 +1,34 @@
+package database
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+func TestRebind(t *testing.T) {
+	for _, c := range []struct {
+		query    string
+		expected string
+	}{
+		{`SELECT id FROM growth_batch WHERE id = ? AND deleted = ?`, `SELECT id FROM growth_batch WHERE id = $1 AND deleted = $2`},
+		{`SELECT 'what?', 'it''s ?' FROM t WHERE a = ?`, `SELECT 'what?', 'it''s ?' FROM t WHERE a = $1`},
+		{`SELECT "why?" FROM "user" WHERE name = ?`, `SELECT "why?" FROM "user" WHERE name = $1`},
+		{"SELECT a -- is it?\nFROM t WHERE a = ?", "SELECT a -- is it?\nFROM t WHERE a = $1"},
+		{`SELECT a /* or ? */ FROM t WHERE a = ?`, `SELECT a /* or ? */ FROM t WHERE a = $1`},
+		{`SELECT $$ ? $$, $tag$ ? $tag$ WHERE a = ?`, `SELECT $$ ? $$, $tag$ ? $tag$ WHERE a = $1`},
+		{`SELECT a$b$ FROM t WHERE a = ?`, `SELECT a$b$ FROM t WHERE a = $1`},
+		{`SELECT 'never closed ?`, `SELECT 'never closed ?`},
+	} {
+		if query := rebind(c.query); query != c.expected {
+			t.Errorf("rebind(%q) = %q", c.query, query)
+		}
+	}
+}
+
+func TestFlags(t *testing.T) {
+	args := flags([]driver.Value{true, false, int64(7), "true"})
+	if !reflect.DeepEqual(args, []driver.Value{int64(1), int64(0), int64(7), "true"}) {
+		t.Fatalf("flags sent %v", args)
+	}
+}
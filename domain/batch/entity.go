@@ -15,63 +15,248 @@ const (
 	Pool_Inactive    string = "inactive"
 	Pool_Assigned    string = "assigned"
 	Pool_Maintenance string = "maintenance"
+	//water quality parameter
+	Water_Dissolved_Oxygen string = "dissolved_oxygen"
+	Water_PH               string = "ph"
+	Water_Temperature      string = "temperature"
+	Water_Ammonia          string = "ammonia"
+	Water_Nitrite          string = "nitrite"
+	Water_Salinity         string = "salinity"
+	Water_Transparency     string = "transparency"
+	//forecast weight projection
+	Forecast_Method_Curve string = "curve"
+	Forecast_Method_ADG   string = "adg"
+	Forecast_Method_None  string = "none"
+	//treatment method
+	Treatment_Method_Feed      string = "feed"
+	Treatment_Method_Bath      string = "bath"
+	Treatment_Method_Water     string = "water"
+	Treatment_Method_Injection string = "injection"
+	//batch cycle relation, ?include=feeding,deaths,cutoff
+	Cycle_Include_Feeding string = "feeding"
+	Cycle_Include_Deaths  string = "deaths"
+	Cycle_Include_CutOff  string = "cutoff"
 )
 
+//CycleIncludes lists every relation a batch cycle can be loaded with
+var CycleIncludes = []string{
+	Cycle_Include_Feeding,
+	Cycle_Include_Deaths,
+	Cycle_Include_CutOff,
+}
+
+func hasInclude(include []string, relation string) bool {
+	for _, i := range include {
+		if i == relation {
+			return true
+		}
+	}
+	return false
+}
+
+//TreatmentMethods lists every accepted treatment method
+var TreatmentMethods = []string{
+	Treatment_Method_Feed,
+	Treatment_Method_Bath,
+	Treatment_Method_Water,
+	Treatment_Method_Injection,
+}
+
+//WaterParameters lists every water quality parameter in reading order
+var WaterParameters = []string{
+	Water_Dissolved_Oxygen,
+	Water_PH,
+	Water_Temperature,
+	Water_Ammonia,
+	Water_Nitrite,
+	Water_Salinity,
+	Water_Transparency,
+}
+
 type Batch struct {
-	ID      uuid.UUID `json:"id"`
-	Name    string    `json:"name"`
-	Status  int32     `json:"status"`
-	Deleted bool      `json:"deleted"`
-	Created time.Time `json:"created"`
-	Updated null.Time `json:"updated"`
+	ID        uuid.UUID     `json:"id"`
+	Name      string        `json:"name"`
+	Status    int32         `json:"status"`
+	Species   *Species      `json:"species"`
+	SpeciesID uuid.NullUUID `json:"-"`
+	Deleted   bool          `json:"deleted"`
+	Created   time.Time     `json:"created"`
+	Updated   null.Time     `json:"updated"`
 	//Pool []Pool
 }
 
+//Pool area is the water surface in m2, zero when unknown
 type Pool struct {
 	ID      uuid.UUID `json:"id"`
 	Name    string    `json:"name"`
 	Status  string    `json:"status"`
+	Area    float64   `json:"area"`
 	Deleted bool      `json:"deleted"`
 	Created time.Time `json:"created"`
 	Updated null.Time `json:"updated"`
 }
 
-type BatchCycle struct {
+//Species carries the reference growth curve, weights are average grams per head
+type Species struct {
+	ID             uuid.UUID          `json:"id"`
+	Name           string             `json:"name"`
+	ScientificName string             `json:"scientific_name"`
+	TargetWeight   float64            `json:"target_weight"`
+	Curve          []GrowthCurvePoint `json:"curve"`
+	Deleted        bool               `json:"deleted"`
+	Created        time.Time          `json:"created"`
+	Updated        null.Time          `json:"updated"`
+}
+
+//GrowthCurvePoint is the expected average weight in grams on a day of culture
+type GrowthCurvePoint struct {
+	Day    int32   `json:"day"`
+	Weight float64 `json:"weight"`
+}
+
+type Hatchery struct {
 	ID      uuid.UUID `json:"id"`
-	Batch   Batch     `json:"batch"`
-	BatchID uuid.UUID `json:"-"`
-	Pool    Pool      `json:"pool"`
-	PoolID  uuid.UUID `json:"-"`
-	Weight  float64   `json:"weight"`
-	Amount  float64   `json:"amount"`
-	Start   time.Time `json:"start"`
-	Finish  null.Time `json:"finish"`
-	Feeding []Feeding `json:"feeding"`
-	Deaths  []Death   `json:"deaths"`
-	CutOff  CutOff    `json:"cutoff"`
+	Name    string    `json:"name"`
+	Contact string    `json:"contact"`
+	Phone   string    `json:"phone"`
+	Address string    `json:"address"`
+	Deleted bool      `json:"deleted"`
 	Created time.Time `json:"created"`
 	Updated null.Time `json:"updated"`
 }
 
-type Death struct {
+//SeedSource is a seed lot bought from a hatchery, StockingSize is the
+//average weight per head in grams when it was stocked
+type SeedSource struct {
 	ID           uuid.UUID `json:"id"`
-	BatchCycleID uuid.UUID `json:"batch_cycle_id"`
-	DeathDate    time.Time `json:"death_date"`
-	Weight       float64   `json:"weight"`
-	Amount       float64   `json:"amount"`
+	Hatchery     Hatchery  `json:"hatchery"`
+	HatcheryID   uuid.UUID `json:"-"`
+	Species      string    `json:"species"`
+	Strain       string    `json:"strain"`
+	LotCode      string    `json:"lot_code"`
+	StockingSize float64   `json:"stocking_size"`
+	QualityGrade string    `json:"quality_grade"`
 	Remarks      string    `json:"remarks"`
 	Created      time.Time `json:"created"`
+	Updated      null.Time `json:"updated"`
 }
 
-type Feeding struct {
+type PoolDevice struct {
+	ID       uuid.UUID `json:"id"`
+	PoolID   uuid.UUID `json:"pool_id"`
+	DeviceID string    `json:"device_id"`
+	Remarks  string    `json:"remarks"`
+	Created  time.Time `json:"created"`
+}
+
+type WaterQuality struct {
+	ID              uuid.UUID  `json:"id"`
+	PoolID          uuid.UUID  `json:"pool_id"`
+	MeasuredAt      time.Time  `json:"measured_at"`
+	DissolvedOxygen null.Float `json:"dissolved_oxygen"`
+	PH              null.Float `json:"ph"`
+	Temperature     null.Float `json:"temperature"`
+	Ammonia         null.Float `json:"ammonia"`
+	Nitrite         null.Float `json:"nitrite"`
+	Salinity        null.Float `json:"salinity"`
+	Transparency    null.Float `json:"transparency"`
+	OutOfRange      bool       `json:"out_of_range"`
+	OutOfRangeList  []string   `json:"out_of_range_parameters"`
+	Remarks         string     `json:"remarks"`
+	Created         time.Time  `json:"created"`
+}
+
+type WaterQualityRange struct {
+	Parameter string     `json:"parameter"`
+	Min       null.Float `json:"min"`
+	Max       null.Float `json:"max"`
+	Updated   null.Time  `json:"updated"`
+}
+
+type BatchCycle struct {
 	ID           uuid.UUID     `json:"id"`
-	BatchCycleID uuid.UUID     `json:"batch_cycle_id"`
-	FeedType     feed.FeedType `json:"feed_type"`
-	FeedTypeID   uuid.UUID     `json:"-"`
-	FeedingDate  time.Time     `json:"feeding_date"`
-	Qty          float64       `json:"qty"`
-	Remarks      string        `json:"remarks"`
-	Created      time.Time     `json:"created"`
+	Batch        Batch         `json:"batch"`
+	BatchID      uuid.UUID     `json:"-"`
+	Pool         Pool          `json:"pool"`
+	PoolID       uuid.UUID     `json:"-"`
+	SeedSource   *SeedSource   `json:"seed_source"`
+	SeedSourceID uuid.NullUUID `json:"-"`
+	//ClearSeedSource unlinks the seed source on update, without it an update
+	//with no seed source keeps the stored one
+	ClearSeedSource bool          `json:"-"`
+	Weight          float64       `json:"weight"`
+	Amount          float64       `json:"amount"`
+	SeedCost        float64       `json:"seed_cost"`
+	Start           time.Time     `json:"start"`
+	Finish          null.Time     `json:"finish"`
+	Feeding         []Feeding     `json:"feeding"`
+	ProteinIntake   float64       `json:"protein_intake"`
+	Deaths          []Death       `json:"deaths"`
+	CutOff          CutOff        `json:"cutoff"`
+	Treatments      []Treatment   `json:"treatments"`
+	Samplings       []Sampling    `json:"samplings"`
+	Growth          *GrowthMetric `json:"growth"`
+	Created         time.Time     `json:"created"`
+	Updated         null.Time     `json:"updated"`
+}
+
+type Death struct {
+	ID           uuid.UUID  `json:"id"`
+	BatchCycleID uuid.UUID  `json:"batch_cycle_id"`
+	Cause        DeathCause `json:"cause"`
+	CauseID      uuid.UUID  `json:"-"`
+	DeathDate    time.Time  `json:"death_date"`
+	Weight       float64    `json:"weight"`
+	Amount       float64    `json:"amount"`
+	Remarks      string     `json:"remarks"`
+	Created      time.Time  `json:"created"`
+}
+
+type DeathCause struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Deleted     bool      `json:"deleted"`
+	Created     time.Time `json:"created"`
+	Updated     null.Time `json:"updated"`
+}
+
+//Sampling is a weighed sample of a cycle, AverageWeight is grams per head
+type Sampling struct {
+	ID            uuid.UUID `json:"id"`
+	BatchCycleID  uuid.UUID `json:"batch_cycle_id"`
+	SamplingDate  time.Time `json:"sampling_date"`
+	SampleSize    int32     `json:"sample_size"`
+	AverageWeight float64   `json:"average_weight"`
+	Remarks       string    `json:"remarks"`
+	Created       time.Time `json:"created"`
+}
+
+//GrowthMetric compares the latest sampling against the species reference curve,
+//weights are average grams per head
+type GrowthMetric struct {
+	DayOfCulture     int32     `json:"day_of_culture"`
+	SamplingDate     null.Time `json:"sampling_date"`
+	SampledWeight    float64   `json:"sampled_weight"`
+	ReferenceWeight  float64   `json:"reference_weight"`
+	Deviation        float64   `json:"deviation"`
+	DeviationPct     float64   `json:"deviation_pct"`
+	TargetWeight     float64   `json:"target_weight"`
+	ProjectedHarvest null.Time `json:"projected_harvest"`
+}
+
+type Feeding struct {
+	ID           uuid.UUID           `json:"id"`
+	BatchCycleID uuid.UUID           `json:"batch_cycle_id"`
+	FeedType     feed.FeedType       `json:"feed_type"`
+	FeedTypeID   uuid.UUID           `json:"-"`
+	FeedingDate  time.Time           `json:"feeding_date"`
+	Qty          float64             `json:"qty"`
+	Unit         string              `json:"unit,omitempty"`
+	UnitCost     float64             `json:"unit_cost"`
+	Lots         []feed.FeedLotUsage `json:"lots"`
+	Remarks      string              `json:"remarks"`
+	Created      time.Time           `json:"created"`
 }
 
 type CutOff struct {
@@ -104,6 +289,229 @@ type SalesDetail struct {
 	BatchCycleID uuid.UUID `json:"batch_cycle_id"`
 	Amount       float64   `json:"amount"`
 	Weight       float64   `json:"weight"`
+	Price        float64   `json:"price"`
 	Created      time.Time `json:"created"`
 	Updated      null.Time `json:"updated"`
 }
+
+type Treatment struct {
+	ID              uuid.UUID `json:"id"`
+	BatchCycleID    uuid.UUID `json:"batch_cycle_id"`
+	Product         string    `json:"product"`
+	Dose            float64   `json:"dose"`
+	Unit            string    `json:"unit"`
+	Method          string    `json:"method"`
+	StartDate       time.Time `json:"start_date"`
+	EndDate         null.Time `json:"end_date"`
+	WithdrawalDays  int32     `json:"withdrawal_days"`
+	WithdrawalUntil null.Time `json:"withdrawal_until"`
+	Remarks         string    `json:"remarks"`
+	Created         time.Time `json:"created"`
+	Updated         null.Time `json:"updated"`
+}
+
+type TreatmentOverride struct {
+	ID           uuid.UUID `json:"id"`
+	BatchCycleID uuid.UUID `json:"batch_cycle_id"`
+	TreatmentID  uuid.UUID `json:"treatment_id"`
+	AuthorizedBy string    `json:"authorized_by"`
+	Reason       string    `json:"reason"`
+	Created      time.Time `json:"created"`
+}
+
+type Cost struct {
+	ID           uuid.UUID `json:"id"`
+	BatchCycleID uuid.UUID `json:"batch_cycle_id"`
+	CostDate     time.Time `json:"cost_date"`
+	Description  string    `json:"description"`
+	Amount       float64   `json:"amount"`
+	Created      time.Time `json:"created"`
+}
+
+type FeedCost struct {
+	FeedType      feed.FeedType `json:"feed_type"`
+	Qty           float64       `json:"qty"`
+	UnitPrice     float64       `json:"unit_price"`
+	Amount        float64       `json:"amount"`
+	ProteinIntake float64       `json:"protein_intake"`
+}
+
+type ProfitAndLoss struct {
+	BatchCycleID   uuid.UUID  `json:"batch_cycle_id"`
+	Feed           []FeedCost `json:"feed"`
+	Costs          []Cost     `json:"costs"`
+	FeedCost       float64    `json:"feed_cost"`
+	ProteinIntake  float64    `json:"protein_intake"`
+	SeedCost       float64    `json:"seed_cost"`
+	OtherCost      float64    `json:"other_cost"`
+	TotalCost      float64    `json:"total_cost"`
+	HarvestWeight  float64    `json:"harvest_weight"`
+	Revenue        float64    `json:"revenue"`
+	CostPerKg      float64    `json:"cost_per_kg"`
+	GrossMargin    float64    `json:"gross_margin"`
+	GrossMarginPct float64    `json:"gross_margin_pct"`
+	ROI            float64    `json:"roi"`
+}
+
+type MortalityGroup struct {
+	ID     uuid.UUID `json:"id"`
+	Name   string    `json:"name"`
+	Amount float64   `json:"amount"`
+	Weight float64   `json:"weight"`
+}
+
+type MortalityWeek struct {
+	Week   time.Time `json:"week"`
+	Amount float64   `json:"amount"`
+	Weight float64   `json:"weight"`
+}
+
+type SurvivalPoint struct {
+	Date  time.Time `json:"date"`
+	Dead  float64   `json:"dead"`
+	Alive float64   `json:"alive"`
+	SR    float64   `json:"sr"`
+}
+
+type SurvivalCurve struct {
+	BatchCycleID uuid.UUID       `json:"batch_cycle_id"`
+	Batch        MortalityGroup  `json:"batch"`
+	Pool         MortalityGroup  `json:"pool"`
+	Start        time.Time       `json:"start"`
+	Stocked      float64         `json:"stocked"`
+	Points       []SurvivalPoint `json:"points"`
+}
+
+type MortalityReport struct {
+	From     time.Time        `json:"from"`
+	To       time.Time        `json:"to"`
+	Amount   float64          `json:"amount"`
+	Weight   float64          `json:"weight"`
+	ByCause  []MortalityGroup `json:"by_cause"`
+	ByPool   []MortalityGroup `json:"by_pool"`
+	ByBatch  []MortalityGroup `json:"by_batch"`
+	ByWeek   []MortalityWeek  `json:"by_week"`
+	Survival []SurvivalCurve  `json:"survival"`
+}
+
+//MortalityRecord is a death joined with its cause, cycle, batch and pool
+type MortalityRecord struct {
+	DeathID      uuid.UUID
+	BatchCycleID uuid.UUID
+	DeathDate    time.Time
+	Amount       float64
+	Weight       float64
+	CauseID      uuid.UUID
+	CauseName    string
+	BatchID      uuid.UUID
+	BatchName    string
+	PoolID       uuid.UUID
+	PoolName     string
+	Stocked      float64
+	Start        time.Time
+}
+
+//Forecast projects every open cycle forward, amounts are heads and biomass is kg,
+//a cycle is harvestable once its projected weight reaches the species target
+type Forecast struct {
+	From   time.Time       `json:"from"`
+	To     time.Time       `json:"to"`
+	Weeks  []ForecastWeek  `json:"weeks"`
+	Pools  []ForecastPool  `json:"pools"`
+	Cycles []CycleForecast `json:"cycles"`
+}
+
+//ForecastWeek is the projection taken on the last day of the week within the period
+type ForecastWeek struct {
+	Week        time.Time `json:"week"`
+	Date        time.Time `json:"date"`
+	Cycles      int32     `json:"cycles"`
+	Amount      float64   `json:"amount"`
+	Weight      float64   `json:"weight"`
+	Biomass     float64   `json:"biomass"`
+	Harvestable float64   `json:"harvestable"`
+}
+
+type ForecastPool struct {
+	Pool  Pool           `json:"pool"`
+	Weeks []ForecastWeek `json:"weeks"`
+}
+
+//CycleForecast tells how a cycle was projected, Method is curve, adg or none
+//when there is nothing to grow the last weight with
+type CycleForecast struct {
+	BatchCycleID  uuid.UUID      `json:"batch_cycle_id"`
+	Batch         Batch          `json:"batch"`
+	Pool          Pool           `json:"pool"`
+	Start         time.Time      `json:"start"`
+	Alive         float64        `json:"alive"`
+	DailySurvival float64        `json:"daily_survival"`
+	Method        string         `json:"method"`
+	TargetWeight  float64        `json:"target_weight"`
+	Weeks         []ForecastWeek `json:"weeks"`
+}
+
+//CycleReport is a closed cycle with its cut off, density is heads per m2 and
+//harvested is kg
+type CycleReport struct {
+	BatchCycleID    uuid.UUID      `json:"batch_cycle_id"`
+	BatchID         uuid.UUID      `json:"batch_id"`
+	BatchName       string         `json:"batch_name"`
+	PoolID          uuid.UUID      `json:"pool_id"`
+	PoolName        string         `json:"pool_name"`
+	SpeciesID       uuid.NullUUID  `json:"species_id"`
+	SpeciesName     null.String    `json:"species_name"`
+	Start           time.Time      `json:"start"`
+	SummaryDate     time.Time      `json:"summary_date"`
+	DayOfCulture    int32          `json:"day_of_culture"`
+	Stocked         float64        `json:"stocked"`
+	StockingDensity float64        `json:"stocking_density"`
+	Harvested       float64        `json:"harvested"`
+	HarvestedAmount float64        `json:"harvested_amount"`
+	ADG             float64        `json:"adg"`
+	FCR             float64        `json:"fcr"`
+	SR              float64        `json:"sr"`
+	PoolRank        CycleBenchmark `json:"pool_rank"`
+	SpeciesRank     CycleBenchmark `json:"species_rank"`
+	PoolArea        float64        `json:"-"`
+}
+
+//CycleBenchmark ranks a cycle on each metric, higher ADG and SR and lower FCR rank first
+type CycleBenchmark struct {
+	ADG Benchmark `json:"adg"`
+	FCR Benchmark `json:"fcr"`
+	SR  Benchmark `json:"sr"`
+}
+
+//Benchmark percentile is the share of the other cycles ranked below
+type Benchmark struct {
+	Rank       int32   `json:"rank"`
+	Of         int32   `json:"of"`
+	Percentile float64 `json:"percentile"`
+}
+
+type HatcheryPerformance struct {
+	HatcheryID  uuid.UUID `json:"hatchery_id"`
+	Name        string    `json:"name"`
+	SeedSources int32     `json:"seed_sources"`
+	Cycles      int32     `json:"cycles"`
+	Stocked     float64   `json:"stocked"`
+	Harvested   float64   `json:"harvested"`
+	SR          float64   `json:"sr"`
+	FCR         float64   `json:"fcr"`
+	Rank        int32     `json:"rank"`
+}
+
+type SalesTrace struct {
+	SalesDetailID uuid.UUID     `json:"sales_detail_id"`
+	BatchCycleID  uuid.UUID     `json:"batch_cycle_id"`
+	Amount        float64       `json:"amount"`
+	Weight        float64       `json:"weight"`
+	Batch         Batch         `json:"batch"`
+	BatchID       uuid.UUID     `json:"-"`
+	Pool          Pool          `json:"pool"`
+	PoolID        uuid.UUID     `json:"-"`
+	Start         time.Time     `json:"start"`
+	SeedSource    *SeedSource   `json:"seed_source"`
+	SeedSourceID  uuid.NullUUID `json:"-"`
+}
@@ -0,0 +1,1842 @@
+package batch
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/livestockz/api/domain/feed"
+	uuid "github.com/satori/go.uuid"
+)
+
+//MemoryRepository keeps the growth tables in memory for tests and demos. It follows
+//BatchRepository on ordering, paging totals, soft delete, foreign keys and not found
+//errors, the zero value is ready to use and the transaction methods ignore the tx.
+//Feedings and cut offs are recorded on Feed when it is set, so feed stock and the
+//supplier report see them like they see the growth tables.
+type MemoryRepository struct {
+	Feed *feed.MemoryRepository
+
+	mu             sync.Mutex
+	batches        []Batch
+	pools          []Pool
+	speciesList    []Species
+	hatcheries     []Hatchery
+	seedSources    []SeedSource
+	devices        []PoolDevice
+	waterQualities []WaterQuality
+	ranges         []WaterQualityRange
+	cycles         []BatchCycle
+	deaths         []Death
+	causes         []DeathCause
+	samplings      []Sampling
+	feedings       []Feeding
+	summaries      []CutOff
+	sales          []Sales
+	salesDetails   []SalesDetail
+	costs          []Cost
+	treatments     []Treatment
+	overrides      []TreatmentOverride
+}
+
+//NewMemoryRepository returns a MemoryRepository recording feedings on feedRepository
+//and holding the death causes and water quality ranges the migrations seed
+func NewMemoryRepository(feedRepository *feed.MemoryRepository) *MemoryRepository {
+	now := time.Now()
+	repo := &MemoryRepository{Feed: feedRepository}
+	for _, cause := range []DeathCause{
+		{ID: uuid.FromStringOrNil("6f1c2a4e-0c3b-4d8e-9a51-1f0d2b7c0001"), Name: "Unknown", Description: "Cause was not recorded"},
+		{ID: uuid.FromStringOrNil("6f1c2a4e-0c3b-4d8e-9a51-1f0d2b7c0002"), Name: "Disease", Description: "Bacterial, viral or parasitic infection"},
+		{ID: uuid.FromStringOrNil("6f1c2a4e-0c3b-4d8e-9a51-1f0d2b7c0003"), Name: "Predation", Description: "Birds, snakes and other predators"},
+		{ID: uuid.FromStringOrNil("6f1c2a4e-0c3b-4d8e-9a51-1f0d2b7c0004"), Name: "Handling", Description: "Stocking, sampling, grading or transport"},
+		{ID: uuid.FromStringOrNil("6f1c2a4e-0c3b-4d8e-9a51-1f0d2b7c0005"), Name: "Water quality", Description: "Low oxygen, ammonia or temperature stress"},
+	} {
+		cause.Created = now
+		repo.causes = append(repo.causes, cause)
+	}
+	for _, r := range []WaterQualityRange{
+		{Parameter: "dissolved_oxygen", Min: null.FloatFrom(4)},
+		{Parameter: "ph", Min: null.FloatFrom(6.5), Max: null.FloatFrom(8.5)},
+		{Parameter: "temperature", Min: null.FloatFrom(25), Max: null.FloatFrom(32)},
+		{Parameter: "ammonia", Max: null.FloatFrom(0.1)},
+		{Parameter: "nitrite", Max: null.FloatFrom(1)},
+		{Parameter: "salinity"},
+		{Parameter: "transparency", Min: null.FloatFrom(25), Max: null.FloatFrom(45)},
+	} {
+		r.Updated = null.TimeFrom(now)
+		repo.ranges = append(repo.ranges, r)
+	}
+	return repo
+}
+
+//memoryPage returns the rows LIMIT :end OFFSET :start picks out of total rows
+func memoryPage(page int32, limit int32, total int) (int, int) {
+	start := int(page) * int(limit)
+	end := start + int(limit)
+	if start < 0 {
+		start = 0
+	}
+	if start > total {
+		start = total
+	}
+	if end < start {
+		end = start
+	} else if end > total {
+		end = total
+	}
+	return start, end
+}
+
+func memoryDeleted(deleted string, value bool) bool {
+	if deleted == Deleted_True {
+		return value
+	} else if deleted == Deleted_False {
+		return !value
+	}
+	return true
+}
+
+//batch
+func (repo *MemoryRepository) findBatch(id uuid.UUID) (*Batch, error) {
+	for _, row := range repo.batches {
+		if row.ID == id {
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("growth batch with id %s not found", id)
+}
+
+func (repo *MemoryRepository) sortedBatches(deleted string) []Batch {
+	batches := make([]Batch, 0)
+	for _, row := range repo.batches {
+		if memoryDeleted(deleted, row.Deleted) {
+			batches = append(batches, row)
+		}
+	}
+	sort.SliceStable(batches, func(i, j int) bool {
+		return batches[i].Name < batches[j].Name
+	})
+	return batches
+}
+
+func (repo *MemoryRepository) ResolveGrowthBatchPage(page int32, limit int32, deleted string) (*[]Batch, int32, int32, int32, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	batches := repo.sortedBatches(deleted)
+	start, end := memoryPage(page, limit, len(batches))
+	result := batches[start:end]
+	return &result, page, limit, int32(len(batches)), nil
+}
+
+func (repo *MemoryRepository) ResolveGrowthBatchByID(id uuid.UUID) (*Batch, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.findBatch(id)
+}
+
+func (repo *MemoryRepository) InsertGrowthBatch(batch *Batch) (*Batch, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if err := repo.insertGrowthBatch(batch); err != nil {
+		return nil, err
+	}
+	return repo.findBatch(batch.ID)
+}
+
+func (repo *MemoryRepository) InsertGrowthBatchTransaction(tx *sql.Tx, batch *Batch) (*Batch, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if err := repo.insertGrowthBatch(batch); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+func (repo *MemoryRepository) insertGrowthBatch(batch *Batch) error {
+	if batch.SpeciesID.Valid {
+		if _, err := repo.findSpecies(batch.SpeciesID.UUID); err != nil {
+			return err
+		}
+	}
+	row := *batch
+	row.Species = nil
+	row.Created = time.Now()
+	row.Updated = null.Time{}
+	repo.batches = append(repo.batches, row)
+	return nil
+}
+
+func (repo *MemoryRepository) UpdateGrowthBatchByID(batch *Batch) (*Batch, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if batch.SpeciesID.Valid {
+		if _, err := repo.findSpecies(batch.SpeciesID.UUID); err != nil {
+			return nil, err
+		}
+	}
+	for i, row := range repo.batches {
+		if row.ID == batch.ID {
+			row.Name = batch.Name
+			row.Status = batch.Status
+			row.SpeciesID = batch.SpeciesID
+			row.Deleted = batch.Deleted
+			row.Updated = null.TimeFrom(time.Now())
+			repo.batches[i] = row
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("growth batch with id %s not found", batch.ID)
+}
+
+func (repo *MemoryRepository) RemoveGrowthBatchByID(id uuid.UUID) (*Batch, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for i := range repo.batches {
+		if repo.batches[i].ID == id {
+			repo.batches[i].Deleted = true
+			repo.batches[i].Updated = null.TimeFrom(time.Now())
+			return nil, nil
+		}
+	}
+	return nil, fmt.Errorf("growth batch with id %s not found", id)
+}
+
+func (repo *MemoryRepository) RemoveGrowthBatchByIDs(ids []uuid.UUID) (*[]Batch, error) {
+	for _, v := range ids {
+		if _, err := repo.RemoveGrowthBatchByID(v); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+//pool
+func (repo *MemoryRepository) findPool(id uuid.UUID) (*Pool, error) {
+	for _, row := range repo.pools {
+		if row.ID == id {
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("growth pool with id %s not found", id)
+}
+
+func (repo *MemoryRepository) sortedPools(deleted string) []Pool {
+	pools := make([]Pool, 0)
+	for _, row := range repo.pools {
+		if memoryDeleted(deleted, row.Deleted) {
+			pools = append(pools, row)
+		}
+	}
+	sort.SliceStable(pools, func(i, j int) bool {
+		return pools[i].Name < pools[j].Name
+	})
+	return pools
+}
+
+func (repo *MemoryRepository) ResolveGrowthPoolPage(page int32, limit int32, deleted string) (*[]Pool, int32, int32, int32, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	pools := repo.sortedPools(deleted)
+	start, end := memoryPage(page, limit, len(pools))
+	result := pools[start:end]
+	return &result, page, limit, int32(len(pools)), nil
+}
+
+func (repo *MemoryRepository) ResolveGrowthPoolByID(id uuid.UUID) (*Pool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.findPool(id)
+}
+
+func (repo *MemoryRepository) InsertGrowthPool(pool *Pool) (*Pool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.insertGrowthPool(pool)
+	return repo.findPool(pool.ID)
+}
+
+func (repo *MemoryRepository) InsertGrowthPoolTransaction(tx *sql.Tx, pool *Pool) (*Pool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.insertGrowthPool(pool)
+	return pool, nil
+}
+
+func (repo *MemoryRepository) insertGrowthPool(pool *Pool) {
+	row := *pool
+	row.Created = time.Now()
+	row.Updated = null.Time{}
+	repo.pools = append(repo.pools, row)
+}
+
+func (repo *MemoryRepository) UpdateGrowthPoolByID(pool *Pool) (*Pool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for i, row := range repo.pools {
+		if row.ID == pool.ID {
+			row.Name = pool.Name
+			row.Status = pool.Status
+			row.Area = pool.Area
+			row.Deleted = pool.Deleted
+			row.Updated = null.TimeFrom(time.Now())
+			repo.pools[i] = row
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("growth pool with id %s not found", pool.ID)
+}
+
+func (repo *MemoryRepository) RemoveGrowthPoolByID(id uuid.UUID) (*Pool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for i := range repo.pools {
+		if repo.pools[i].ID == id {
+			repo.pools[i].Deleted = true
+			repo.pools[i].Updated = null.TimeFrom(time.Now())
+			return nil, nil
+		}
+	}
+	return nil, fmt.Errorf("growth pool with id %s not found", id)
+}
+
+func (repo *MemoryRepository) RemoveGrowthPoolByIDs(ids []uuid.UUID) (*[]Pool, error) {
+	for _, v := range ids {
+		if _, err := repo.RemoveGrowthPoolByID(v); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+//species, the curve is handed out sorted by day like the curve query does
+func (repo *MemoryRepository) findSpecies(id uuid.UUID) (*Species, error) {
+	for _, row := range repo.speciesList {
+		if row.ID == id {
+			row.Curve = sortedCurve(row.Curve)
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("growth species with id %s not found", id)
+}
+
+func sortedCurve(curve []GrowthCurvePoint) []GrowthCurvePoint {
+	points := make([]GrowthCurvePoint, len(curve))
+	copy(points, curve)
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].Day < points[j].Day
+	})
+	return points
+}
+
+func (repo *MemoryRepository) ResolveGrowthSpecies(deleted string) (*[]Species, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	species := make([]Species, 0)
+	for _, row := range repo.speciesList {
+		if memoryDeleted(deleted, row.Deleted) {
+			row.Curve = sortedCurve(row.Curve)
+			species = append(species, row)
+		}
+	}
+	sort.SliceStable(species, func(i, j int) bool {
+		return species[i].Name < species[j].Name
+	})
+	return &species, nil
+}
+
+func (repo *MemoryRepository) ResolveGrowthSpeciesByID(id uuid.UUID) (*Species, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.findSpecies(id)
+}
+
+func (repo *MemoryRepository) InsertGrowthSpecies(species *Species) (*Species, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	row := *species
+	row.Curve = sortedCurve(species.Curve)
+	row.Created = time.Now()
+	row.Updated = null.Time{}
+	repo.speciesList = append(repo.speciesList, row)
+	return repo.findSpecies(species.ID)
+}
+
+func (repo *MemoryRepository) UpdateGrowthSpeciesByID(species *Species) (*Species, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for i, row := range repo.speciesList {
+		if row.ID == species.ID {
+			row.Name = species.Name
+			row.ScientificName = species.ScientificName
+			row.TargetWeight = species.TargetWeight
+			row.Deleted = species.Deleted
+			row.Curve = sortedCurve(species.Curve)
+			row.Updated = null.TimeFrom(time.Now())
+			repo.speciesList[i] = row
+			return repo.findSpecies(species.ID)
+		}
+	}
+	return nil, fmt.Errorf("growth species with id %s not found", species.ID)
+}
+
+func (repo *MemoryRepository) RemoveGrowthSpeciesByID(id uuid.UUID) (*Species, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for i := range repo.speciesList {
+		if repo.speciesList[i].ID == id {
+			repo.speciesList[i].Deleted = true
+			repo.speciesList[i].Updated = null.TimeFrom(time.Now())
+			return nil, nil
+		}
+	}
+	return nil, fmt.Errorf("growth species with id %s not found", id)
+}
+
+//hatchery
+func (repo *MemoryRepository) findHatchery(id uuid.UUID) (*Hatchery, error) {
+	for _, row := range repo.hatcheries {
+		if row.ID == id {
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("growth hatchery with id %s not found", id)
+}
+
+func (repo *MemoryRepository) ResolveGrowthHatcheryPage(page int32, limit int32, deleted string) (*[]Hatchery, int32, int32, int32, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	hatcheries := make([]Hatchery, 0)
+	for _, row := range repo.hatcheries {
+		if memoryDeleted(deleted, row.Deleted) {
+			hatcheries = append(hatcheries, row)
+		}
+	}
+	sort.SliceStable(hatcheries, func(i, j int) bool {
+		return hatcheries[i].Name < hatcheries[j].Name
+	})
+	start, end := memoryPage(page, limit, len(hatcheries))
+	result := hatcheries[start:end]
+	return &result, page, limit, int32(len(hatcheries)), nil
+}
+
+func (repo *MemoryRepository) ResolveGrowthHatcheryByID(id uuid.UUID) (*Hatchery, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.findHatchery(id)
+}
+
+func (repo *MemoryRepository) InsertGrowthHatchery(hatchery *Hatchery) (*Hatchery, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	row := *hatchery
+	row.Created = time.Now()
+	row.Updated = null.Time{}
+	repo.hatcheries = append(repo.hatcheries, row)
+	return &row, nil
+}
+
+func (repo *MemoryRepository) UpdateGrowthHatcheryByID(hatchery *Hatchery) (*Hatchery, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for i, row := range repo.hatcheries {
+		if row.ID == hatchery.ID {
+			row.Name = hatchery.Name
+			row.Contact = hatchery.Contact
+			row.Phone = hatchery.Phone
+			row.Address = hatchery.Address
+			row.Deleted = hatchery.Deleted
+			row.Updated = null.TimeFrom(time.Now())
+			repo.hatcheries[i] = row
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("growth hatchery with id %s not found", hatchery.ID)
+}
+
+func (repo *MemoryRepository) RemoveGrowthHatcheryByID(id uuid.UUID) (*Hatchery, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for i := range repo.hatcheries {
+		if repo.hatcheries[i].ID == id {
+			repo.hatcheries[i].Deleted = true
+			repo.hatcheries[i].Updated = null.TimeFrom(time.Now())
+			return nil, nil
+		}
+	}
+	return nil, fmt.Errorf("growth hatchery with id %s not found", id)
+}
+
+//ResolveGrowthHatcheryPerformance weights sr by stocked amount and fcr by weight gain
+//over the cycles cut off within the period, from and to are inclusive like BETWEEN
+func (repo *MemoryRepository) ResolveGrowthHatcheryPerformance(from time.Time, to time.Time) (*[]HatcheryPerformance, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	performances := make([]HatcheryPerformance, 0)
+	for _, hatchery := range repo.hatcheries {
+		performance := HatcheryPerformance{HatcheryID: hatchery.ID, Name: hatchery.Name}
+		seedSources := make(map[uuid.UUID]bool)
+		var gain, weighted float64
+		for _, seedSource := range repo.seedSources {
+			if seedSource.HatcheryID != hatchery.ID {
+				continue
+			}
+			for _, cycle := range repo.cycles {
+				if !cycle.SeedSourceID.Valid || cycle.SeedSourceID.UUID != seedSource.ID {
+					continue
+				}
+				for _, summary := range repo.summaries {
+					if summary.BatchCycleID == cycle.ID && !summary.SummaryDate.Before(from) && !summary.SummaryDate.After(to) {
+						seedSources[seedSource.ID] = true
+						performance.Cycles = performance.Cycles + 1
+						performance.Stocked = performance.Stocked + cycle.Amount
+						performance.Harvested = performance.Harvested + summary.Amount
+						gain = gain + summary.Weight - cycle.Weight
+						weighted = weighted + summary.FCR*(summary.Weight-cycle.Weight)
+					}
+				}
+			}
+		}
+		if performance.Cycles > 0 {
+			performance.SeedSources = int32(len(seedSources))
+			if performance.Stocked != 0 {
+				performance.SR = performance.Harvested / performance.Stocked * 100
+			}
+			if gain != 0 {
+				performance.FCR = weighted / gain
+			}
+			performances = append(performances, performance)
+		}
+	}
+	sort.SliceStable(performances, func(i, j int) bool {
+		if performances[i].SR != performances[j].SR {
+			return performances[i].SR > performances[j].SR
+		}
+		return performances[i].FCR < performances[j].FCR
+	})
+	return &performances, nil
+}
+
+//seed source
+func (repo *MemoryRepository) findSeedSource(id uuid.UUID) (*SeedSource, error) {
+	for _, row := range repo.seedSources {
+		if row.ID == id {
+			if hatchery, err := repo.findHatchery(row.HatcheryID); err != nil {
+				return nil, err
+			} else {
+				row.Hatchery = *hatchery
+			}
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("growth seed source with id %s not found", id)
+}
+
+func (repo *MemoryRepository) ResolveGrowthSeedSourcePage(page int32, limit int32, hatcheryId uuid.UUID) (*[]SeedSource, int32, int32, int32, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	seedSources := make([]SeedSource, 0)
+	for _, row := range repo.seedSources {
+		if hatcheryId == uuid.Nil || row.HatcheryID == hatcheryId {
+			seedSources = append(seedSources, row)
+		}
+	}
+	sort.SliceStable(seedSources, func(i, j int) bool {
+		return seedSources[i].Created.After(seedSources[j].Created)
+	})
+	start, end := memoryPage(page, limit, len(seedSources))
+	result := seedSources[start:end]
+	for i := range result {
+		if hatchery, err := repo.findHatchery(result[i].HatcheryID); err != nil {
+			return nil, page, limit, 0, err
+		} else {
+			result[i].Hatchery = *hatchery
+		}
+	}
+	return &result, page, limit, int32(len(seedSources)), nil
+}
+
+func (repo *MemoryRepository) ResolveGrowthSeedSourceByID(id uuid.UUID) (*SeedSource, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.findSeedSource(id)
+}
+
+func (repo *MemoryRepository) InsertGrowthSeedSource(seedSource *SeedSource) (*SeedSource, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if _, err := repo.findHatchery(seedSource.Hatchery.ID); err != nil {
+		return nil, err
+	}
+	row := *seedSource
+	row.HatcheryID = seedSource.Hatchery.ID
+	row.Hatchery = Hatchery{}
+	row.Created = time.Now()
+	row.Updated = null.Time{}
+	repo.seedSources = append(repo.seedSources, row)
+	return repo.findSeedSource(seedSource.ID)
+}
+
+func (repo *MemoryRepository) UpdateGrowthSeedSourceByID(seedSource *SeedSource) (*SeedSource, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if _, err := repo.findHatchery(seedSource.Hatchery.ID); err != nil {
+		return nil, err
+	}
+	for i, row := range repo.seedSources {
+		if row.ID == seedSource.ID {
+			row.HatcheryID = seedSource.Hatchery.ID
+			row.Species = seedSource.Species
+			row.Strain = seedSource.Strain
+			row.LotCode = seedSource.LotCode
+			row.StockingSize = seedSource.StockingSize
+			row.QualityGrade = seedSource.QualityGrade
+			row.Remarks = seedSource.Remarks
+			row.Updated = null.TimeFrom(time.Now())
+			repo.seedSources[i] = row
+			return repo.findSeedSource(seedSource.ID)
+		}
+	}
+	return nil, fmt.Errorf("growth seed source with id %s not found", seedSource.ID)
+}
+
+//pool device
+func (repo *MemoryRepository) ResolveGrowthPoolDeviceByPoolID(poolId uuid.UUID) (*[]PoolDevice, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	devices := make([]PoolDevice, 0)
+	for _, row := range repo.devices {
+		if row.PoolID == poolId {
+			devices = append(devices, row)
+		}
+	}
+	sort.SliceStable(devices, func(i, j int) bool {
+		return devices[i].DeviceID < devices[j].DeviceID
+	})
+	return &devices, nil
+}
+
+func (repo *MemoryRepository) ResolveGrowthPoolDeviceByDeviceID(deviceId string) (*PoolDevice, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for _, row := range repo.devices {
+		if row.DeviceID == deviceId {
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("device %s is not assigned to any pool", deviceId)
+}
+
+func (repo *MemoryRepository) findPoolDevice(id uuid.UUID) (*PoolDevice, error) {
+	for _, row := range repo.devices {
+		if row.ID == id {
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("pool device with id %s not found", id)
+}
+
+func (repo *MemoryRepository) ResolveGrowthPoolDeviceByID(id uuid.UUID) (*PoolDevice, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.findPoolDevice(id)
+}
+
+//InsertGrowthPoolDevice keeps a device on a single pool like the unique device_id
+func (repo *MemoryRepository) InsertGrowthPoolDevice(device *PoolDevice) (*PoolDevice, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if _, err := repo.findPool(device.PoolID); err != nil {
+		return nil, err
+	}
+	for _, row := range repo.devices {
+		if row.DeviceID == device.DeviceID {
+			return nil, fmt.Errorf("device %s is already assigned to pool %s", device.DeviceID, row.PoolID)
+		}
+	}
+	row := *device
+	row.Created = time.Now()
+	repo.devices = append(repo.devices, row)
+	return &row, nil
+}
+
+func (repo *MemoryRepository) RemoveGrowthPoolDeviceByID(id uuid.UUID) (*PoolDevice, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for i, row := range repo.devices {
+		if row.ID == id {
+			repo.devices = append(repo.devices[:i], repo.devices[i+1:]...)
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("pool device with id %s not found", id)
+}
+
+//pool water quality
+func waterQualityRow(row WaterQuality) WaterQuality {
+	//out of range parameters come back as an empty list when there are none
+	list := make([]string, len(row.OutOfRangeList))
+	copy(list, row.OutOfRangeList)
+	row.OutOfRangeList = list
+	return row
+}
+
+func (repo *MemoryRepository) ResolveGrowthWaterQualityByPoolID(poolId uuid.UUID, from time.Time, to time.Time) (*[]WaterQuality, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	readings := make([]WaterQuality, 0)
+	for _, row := range repo.waterQualities {
+		if row.PoolID == poolId && !row.MeasuredAt.Before(from) && row.MeasuredAt.Before(to) {
+			readings = append(readings, waterQualityRow(row))
+		}
+	}
+	sort.SliceStable(readings, func(i, j int) bool {
+		return readings[i].MeasuredAt.Before(readings[j].MeasuredAt)
+	})
+	return &readings, nil
+}
+
+func (repo *MemoryRepository) findWaterQuality(id uuid.UUID) (*WaterQuality, error) {
+	for _, row := range repo.waterQualities {
+		if row.ID == id {
+			row = waterQualityRow(row)
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("water quality with id %s not found", id)
+}
+
+func (repo *MemoryRepository) ResolveGrowthWaterQualityByID(id uuid.UUID) (*WaterQuality, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.findWaterQuality(id)
+}
+
+func (repo *MemoryRepository) InsertGrowthWaterQuality(waterQuality *WaterQuality) (*WaterQuality, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if _, err := repo.findPool(waterQuality.PoolID); err != nil {
+		return nil, err
+	}
+	repo.insertGrowthWaterQuality(waterQuality)
+	return repo.findWaterQuality(waterQuality.ID)
+}
+
+func (repo *MemoryRepository) InsertGrowthWaterQualityTransaction(tx *sql.Tx, waterQuality *WaterQuality) (*WaterQuality, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if _, err := repo.findPool(waterQuality.PoolID); err != nil {
+		return nil, err
+	}
+	repo.insertGrowthWaterQuality(waterQuality)
+	return waterQuality, nil
+}
+
+//InsertGrowthWaterQualityBatch checks every pool before it stores any reading
+func (repo *MemoryRepository) InsertGrowthWaterQualityBatch(waterQualities *[]WaterQuality) (*[]WaterQuality, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for _, waterQuality := range *waterQualities {
+		if _, err := repo.findPool(waterQuality.PoolID); err != nil {
+			return nil, err
+		}
+	}
+	for _, waterQuality := range *waterQualities {
+		repo.insertGrowthWaterQuality(&waterQuality)
+	}
+	return waterQualities, nil
+}
+
+func (repo *MemoryRepository) insertGrowthWaterQuality(waterQuality *WaterQuality) {
+	row := waterQualityRow(*waterQuality)
+	row.Created = time.Now()
+	repo.waterQualities = append(repo.waterQualities, row)
+}
+
+func (repo *MemoryRepository) ResolveGrowthWaterQualityRange() (*[]WaterQualityRange, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	ranges := make([]WaterQualityRange, len(repo.ranges))
+	copy(ranges, repo.ranges)
+	return &ranges, nil
+}
+
+func (repo *MemoryRepository) ReplaceGrowthWaterQualityRange(ranges *[]WaterQualityRange) (*[]WaterQualityRange, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for _, r := range *ranges {
+		kept := make([]WaterQualityRange, 0)
+		for _, row := range repo.ranges {
+			if row.Parameter != r.Parameter {
+				kept = append(kept, row)
+			}
+		}
+		r.Updated = null.TimeFrom(time.Now())
+		repo.ranges = append(kept, r)
+	}
+	result := make([]WaterQualityRange, len(repo.ranges))
+	copy(result, repo.ranges)
+	return &result, nil
+}
+
+//batch cycle
+func (repo *MemoryRepository) findBatchCycle(batchId uuid.UUID, cycleId uuid.UUID) (*BatchCycle, error) {
+	for _, row := range repo.cycles {
+		if row.ID == cycleId && row.BatchID == batchId {
+			if err := repo.populateBatchCycle(&row, CycleIncludes); err != nil {
+				return nil, err
+			}
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("growth batch cycle with batchId %s and cycleId %s not found", batchId, cycleId)
+}
+
+//populateBatchCycle attaches the batch, pool and seed source plus the included
+//relations with their death causes like BatchRepository does
+func (repo *MemoryRepository) populateBatchCycle(batchCycle *BatchCycle, include []string) error {
+	if batch, err := repo.findBatch(batchCycle.BatchID); err != nil {
+		return err
+	} else {
+		batchCycle.Batch = *batch
+	}
+	if pool, err := repo.findPool(batchCycle.PoolID); err != nil {
+		return err
+	} else {
+		batchCycle.Pool = *pool
+	}
+	if hasInclude(include, Cycle_Include_Feeding) {
+		batchCycle.Feeding = repo.feedingByBatchCycleID(batchCycle.ID)
+	}
+	if hasInclude(include, Cycle_Include_Deaths) {
+		batchCycle.Deaths = repo.deathByBatchCycleID(batchCycle.ID)
+		for i, death := range batchCycle.Deaths {
+			if cause, err := repo.findDeathCause(death.CauseID); err == nil {
+				batchCycle.Deaths[i].Cause = *cause
+			}
+		}
+	}
+	if batchCycle.SeedSourceID.Valid {
+		if seedSource, err := repo.findSeedSource(batchCycle.SeedSourceID.UUID); err != nil {
+			return err
+		} else {
+			batchCycle.SeedSource = seedSource
+		}
+	}
+	if cutoff := repo.summaryByBatchCycleID(batchCycle.ID); cutoff != nil && hasInclude(include, Cycle_Include_CutOff) {
+		batchCycle.CutOff = *cutoff
+		batchCycle.CutOff.BatchID = batchCycle.BatchID
+	}
+	return nil
+}
+
+func (repo *MemoryRepository) ResolveGrowthBatchCyclePage(batchId uuid.UUID, page int32, limit int32, include []string) (*[]BatchCycle, int32, int32, int32, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	batchCycles := make([]BatchCycle, 0)
+	for _, row := range repo.cycles {
+		if row.BatchID == batchId {
+			batchCycles = append(batchCycles, row)
+		}
+	}
+	sort.SliceStable(batchCycles, func(i, j int) bool {
+		return batchCycles[i].Created.Before(batchCycles[j].Created)
+	})
+	start, end := memoryPage(page, limit, len(batchCycles))
+	result := batchCycles[start:end]
+	for i := range result {
+		if err := repo.populateBatchCycle(&result[i], include); err != nil {
+			return nil, page, limit, 0, err
+		}
+	}
+	return &result, page, limit, int32(len(batchCycles)), nil
+}
+
+func (repo *MemoryRepository) ResolveGrowthBatchCycleByID(batchId uuid.UUID, cycleId uuid.UUID) (*BatchCycle, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.findBatchCycle(batchId, cycleId)
+}
+
+//ResolveGrowthOpenBatchCycle lists every cycle not finished yet with its batch, pool and deaths
+func (repo *MemoryRepository) ResolveGrowthOpenBatchCycle() (*[]BatchCycle, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	batchCycles := make([]BatchCycle, 0)
+	for _, row := range repo.cycles {
+		if row.Finish.Valid {
+			continue
+		}
+		if batch, err := repo.findBatch(row.BatchID); err != nil {
+			return nil, err
+		} else {
+			row.Batch = *batch
+		}
+		if pool, err := repo.findPool(row.PoolID); err != nil {
+			return nil, err
+		} else {
+			row.Pool = *pool
+		}
+		row.Deaths = repo.deathByBatchCycleID(row.ID)
+		batchCycles = append(batchCycles, row)
+	}
+	sort.SliceStable(batchCycles, func(i, j int) bool {
+		return batchCycles[i].Start.Before(batchCycles[j].Start)
+	})
+	return &batchCycles, nil
+}
+
+func (repo *MemoryRepository) InsertGrowthBatchCycle(batchCycle *BatchCycle) (*BatchCycle, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if err := repo.insertGrowthBatchCycle(batchCycle); err != nil {
+		return nil, err
+	}
+	return repo.findBatchCycle(batchCycle.BatchID, batchCycle.ID)
+}
+
+func (repo *MemoryRepository) InsertGrowthBatchCycleTransaction(tx *sql.Tx, batchCycle *BatchCycle) (*BatchCycle, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if err := repo.insertGrowthBatchCycle(batchCycle); err != nil {
+		return nil, err
+	}
+	return batchCycle, nil
+}
+
+//batchCycleRow keeps the columns of growth_batch_cycle, batch and pool come from
+//the attached Batch and Pool like the insert and update queries take them
+func (repo *MemoryRepository) batchCycleRow(batchCycle *BatchCycle) (BatchCycle, error) {
+	row := BatchCycle{
+		ID:           batchCycle.ID,
+		BatchID:      batchCycle.Batch.ID,
+		PoolID:       batchCycle.Pool.ID,
+		SeedSourceID: batchCycle.SeedSourceID,
+		Weight:       batchCycle.Weight,
+		Amount:       batchCycle.Amount,
+		SeedCost:     batchCycle.SeedCost,
+		Start:        batchCycle.Start,
+		Finish:       batchCycle.Finish,
+	}
+	if _, err := repo.findBatch(row.BatchID); err != nil {
+		return row, err
+	} else if _, err := repo.findPool(row.PoolID); err != nil {
+		return row, err
+	} else if row.SeedSourceID.Valid {
+		if _, err := repo.findSeedSource(row.SeedSourceID.UUID); err != nil {
+			return row, err
+		}
+	}
+	return row, nil
+}
+
+func (repo *MemoryRepository) insertGrowthBatchCycle(batchCycle *BatchCycle) error {
+	row, err := repo.batchCycleRow(batchCycle)
+	if err != nil {
+		return err
+	}
+	row.Finish = null.Time{}
+	row.Created = time.Now()
+	repo.cycles = append(repo.cycles, row)
+	return nil
+}
+
+func (repo *MemoryRepository) UpdateGrowthBatchCycleByID(batchCycle *BatchCycle) (*BatchCycle, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if _, err := repo.findBatchCycle(batchCycle.BatchID, batchCycle.ID); err != nil {
+		return nil, err
+	} else if row, err := repo.batchCycleRow(batchCycle); err != nil {
+		return nil, err
+	} else {
+		repo.updateGrowthBatchCycle(row)
+	}
+	return repo.findBatchCycle(batchCycle.BatchID, batchCycle.ID)
+}
+
+func (repo *MemoryRepository) UpdateGrowthBatchCycleByIDTransaction(tx *sql.Tx, batchCycle *BatchCycle) (*BatchCycle, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if row, err := repo.batchCycleRow(batchCycle); err != nil {
+		return nil, err
+	} else {
+		repo.updateGrowthBatchCycle(row)
+	}
+	return batchCycle, nil
+}
+
+func (repo *MemoryRepository) updateGrowthBatchCycle(row BatchCycle) {
+	for i := range repo.cycles {
+		if repo.cycles[i].ID == row.ID {
+			row.Created = repo.cycles[i].Created
+			row.Updated = null.TimeFrom(time.Now())
+			repo.cycles[i] = row
+		}
+	}
+}
+
+//cycle report, every cycle closed by a cut off
+func (repo *MemoryRepository) ResolveGrowthCycleReport() (*[]CycleReport, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	reports := make([]CycleReport, 0)
+	for _, summary := range repo.summaries {
+		for _, cycle := range repo.cycles {
+			if cycle.ID != summary.BatchCycleID {
+				continue
+			}
+			batch, err := repo.findBatch(cycle.BatchID)
+			if err != nil {
+				continue
+			}
+			pool, err := repo.findPool(cycle.PoolID)
+			if err != nil {
+				continue
+			}
+			report := CycleReport{
+				BatchCycleID:    cycle.ID,
+				BatchID:         batch.ID,
+				BatchName:       batch.Name,
+				PoolID:          pool.ID,
+				PoolName:        pool.Name,
+				PoolArea:        pool.Area,
+				Start:           cycle.Start,
+				Stocked:         cycle.Amount,
+				SummaryDate:     summary.SummaryDate,
+				Harvested:       summary.Weight,
+				HarvestedAmount: summary.Amount,
+				ADG:             summary.ADG,
+				FCR:             summary.FCR,
+				SR:              summary.SR,
+			}
+			if batch.SpeciesID.Valid {
+				if species, err := repo.findSpecies(batch.SpeciesID.UUID); err == nil {
+					report.SpeciesID = uuid.NullUUID{UUID: species.ID, Valid: true}
+					report.SpeciesName = null.StringFrom(species.Name)
+				}
+			}
+			reports = append(reports, report)
+		}
+	}
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[i].SummaryDate.Before(reports[j].SummaryDate)
+	})
+	return &reports, nil
+}
+
+//death
+func (repo *MemoryRepository) deathByBatchCycleID(cycleId uuid.UUID) []Death {
+	deaths := make([]Death, 0)
+	for _, row := range repo.deaths {
+		if row.BatchCycleID == cycleId {
+			deaths = append(deaths, row)
+		}
+	}
+	sort.SliceStable(deaths, func(i, j int) bool {
+		return deaths[i].Created.Before(deaths[j].Created)
+	})
+	return deaths
+}
+
+func (repo *MemoryRepository) ResolveGrowthDeathByBatchCycleID(cycleId uuid.UUID) (*[]Death, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	deaths := repo.deathByBatchCycleID(cycleId)
+	return &deaths, nil
+}
+
+func (repo *MemoryRepository) findDeath(id uuid.UUID) (*Death, error) {
+	for _, row := range repo.deaths {
+		if row.ID == id {
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("growth death with id %s not found", id)
+}
+
+func (repo *MemoryRepository) ResolveGrowthDeathByID(deathId uuid.UUID) (*Death, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.findDeath(deathId)
+}
+
+func (repo *MemoryRepository) InsertGrowthDeath(death *Death) (*Death, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if err := repo.insertGrowthDeath(death); err != nil {
+		return nil, err
+	}
+	return repo.findDeath(death.ID)
+}
+
+func (repo *MemoryRepository) InsertGrowthDeathTransaction(tx *sql.Tx, death *Death) (*Death, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if err := repo.insertGrowthDeath(death); err != nil {
+		return nil, err
+	}
+	return death, nil
+}
+
+func (repo *MemoryRepository) insertGrowthDeath(death *Death) error {
+	if !repo.hasBatchCycle(death.BatchCycleID) {
+		return fmt.Errorf("growth batch cycle with id %s not found", death.BatchCycleID)
+	} else if _, err := repo.findDeathCause(death.CauseID); err != nil {
+		return err
+	}
+	row := *death
+	row.Cause = DeathCause{}
+	row.Created = time.Now()
+	repo.deaths = append(repo.deaths, row)
+	return nil
+}
+
+func (repo *MemoryRepository) hasBatchCycle(cycleId uuid.UUID) bool {
+	for _, row := range repo.cycles {
+		if row.ID == cycleId {
+			return true
+		}
+	}
+	return false
+}
+
+//death cause
+func (repo *MemoryRepository) findDeathCause(id uuid.UUID) (*DeathCause, error) {
+	for _, row := range repo.causes {
+		if row.ID == id {
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("death cause with id %s not found", id)
+}
+
+//uniqueDeathCause keeps the names unique like growth_death_cause_name_unique
+func (repo *MemoryRepository) uniqueDeathCause(cause *DeathCause) error {
+	for _, row := range repo.causes {
+		if row.ID != cause.ID && row.Name == cause.Name {
+			return fmt.Errorf("death cause %s already exists", cause.Name)
+		}
+	}
+	return nil
+}
+
+func (repo *MemoryRepository) ResolveGrowthDeathCause(deleted string) (*[]DeathCause, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	causes := make([]DeathCause, 0)
+	for _, row := range repo.causes {
+		if memoryDeleted(deleted, row.Deleted) {
+			causes = append(causes, row)
+		}
+	}
+	sort.SliceStable(causes, func(i, j int) bool {
+		return causes[i].Name < causes[j].Name
+	})
+	return &causes, nil
+}
+
+func (repo *MemoryRepository) ResolveGrowthDeathCauseByID(id uuid.UUID) (*DeathCause, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.findDeathCause(id)
+}
+
+func (repo *MemoryRepository) InsertGrowthDeathCause(cause *DeathCause) (*DeathCause, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if err := repo.uniqueDeathCause(cause); err != nil {
+		return nil, err
+	}
+	row := *cause
+	row.Created = time.Now()
+	row.Updated = null.Time{}
+	repo.causes = append(repo.causes, row)
+	return &row, nil
+}
+
+func (repo *MemoryRepository) UpdateGrowthDeathCauseByID(cause *DeathCause) (*DeathCause, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if _, err := repo.findDeathCause(cause.ID); err != nil {
+		return nil, err
+	} else if err := repo.uniqueDeathCause(cause); err != nil {
+		return nil, err
+	}
+	for i, row := range repo.causes {
+		if row.ID == cause.ID {
+			row.Name = cause.Name
+			row.Description = cause.Description
+			row.Deleted = cause.Deleted
+			row.Updated = null.TimeFrom(time.Now())
+			repo.causes[i] = row
+		}
+	}
+	return repo.findDeathCause(cause.ID)
+}
+
+func (repo *MemoryRepository) RemoveGrowthDeathCauseByID(id uuid.UUID) (*DeathCause, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for i := range repo.causes {
+		if repo.causes[i].ID == id {
+			repo.causes[i].Deleted = true
+			repo.causes[i].Updated = null.TimeFrom(time.Now())
+			return nil, nil
+		}
+	}
+	return nil, fmt.Errorf("death cause with id %s not found", id)
+}
+
+//ResolveGrowthMortality returns every death of the cycles that lost animals within
+//the period so survival can be accumulated from the cycle start, to is exclusive
+func (repo *MemoryRepository) ResolveGrowthMortality(from time.Time, to time.Time, batchId uuid.UUID, poolId uuid.UUID) (*[]MortalityRecord, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	lost := make(map[uuid.UUID]bool)
+	for _, death := range repo.deaths {
+		if !death.DeathDate.Before(from) && death.DeathDate.Before(to) {
+			lost[death.BatchCycleID] = true
+		}
+	}
+	records := make([]MortalityRecord, 0)
+	for _, death := range repo.deaths {
+		if !lost[death.BatchCycleID] || !death.DeathDate.Before(to) {
+			continue
+		}
+		for _, cycle := range repo.cycles {
+			if cycle.ID != death.BatchCycleID || (batchId != uuid.Nil && cycle.BatchID != batchId) || (poolId != uuid.Nil && cycle.PoolID != poolId) {
+				continue
+			}
+			cause, err := repo.findDeathCause(death.CauseID)
+			if err != nil {
+				continue
+			}
+			batch, err := repo.findBatch(cycle.BatchID)
+			if err != nil {
+				continue
+			}
+			pool, err := repo.findPool(cycle.PoolID)
+			if err != nil {
+				continue
+			}
+			records = append(records, MortalityRecord{
+				DeathID:      death.ID,
+				BatchCycleID: cycle.ID,
+				DeathDate:    death.DeathDate,
+				Amount:       death.Amount,
+				Weight:       death.Weight,
+				CauseID:      cause.ID,
+				CauseName:    cause.Name,
+				BatchID:      batch.ID,
+				BatchName:    batch.Name,
+				PoolID:       pool.ID,
+				PoolName:     pool.Name,
+				Stocked:      cycle.Amount,
+				Start:        cycle.Start,
+			})
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].BatchCycleID != records[j].BatchCycleID {
+			return records[i].BatchCycleID.String() < records[j].BatchCycleID.String()
+		}
+		return records[i].DeathDate.Before(records[j].DeathDate)
+	})
+	return &records, nil
+}
+
+//sampling
+func (repo *MemoryRepository) ResolveGrowthSamplingByBatchCycleID(cycleId uuid.UUID) (*[]Sampling, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	samplings := make([]Sampling, 0)
+	for _, row := range repo.samplings {
+		if row.BatchCycleID == cycleId {
+			samplings = append(samplings, row)
+		}
+	}
+	sort.SliceStable(samplings, func(i, j int) bool {
+		return samplings[i].SamplingDate.Before(samplings[j].SamplingDate)
+	})
+	return &samplings, nil
+}
+
+func (repo *MemoryRepository) findSampling(id uuid.UUID) (*Sampling, error) {
+	for _, row := range repo.samplings {
+		if row.ID == id {
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("growth sampling with id %s not found", id)
+}
+
+func (repo *MemoryRepository) ResolveGrowthSamplingByID(samplingId uuid.UUID) (*Sampling, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.findSampling(samplingId)
+}
+
+func (repo *MemoryRepository) InsertGrowthSampling(sampling *Sampling) (*Sampling, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if !repo.hasBatchCycle(sampling.BatchCycleID) {
+		return nil, fmt.Errorf("growth batch cycle with id %s not found", sampling.BatchCycleID)
+	}
+	row := *sampling
+	row.Created = time.Now()
+	repo.samplings = append(repo.samplings, row)
+	return &row, nil
+}
+
+//feeding
+func (repo *MemoryRepository) feedingByBatchCycleID(cycleId uuid.UUID) []Feeding {
+	feedings := make([]Feeding, 0)
+	for _, row := range repo.feedings {
+		if row.BatchCycleID == cycleId {
+			feedings = append(feedings, row)
+		}
+	}
+	sort.SliceStable(feedings, func(i, j int) bool {
+		return feedings[i].Created.Before(feedings[j].Created)
+	})
+	return feedings
+}
+
+func (repo *MemoryRepository) ResolveGrowthFeedingByBatchCycleID(cycleId uuid.UUID) (*[]Feeding, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	feedings := repo.feedingByBatchCycleID(cycleId)
+	return &feedings, nil
+}
+
+func (repo *MemoryRepository) findFeeding(id uuid.UUID) (*Feeding, error) {
+	for _, row := range repo.feedings {
+		if row.ID == id {
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("growth feeding with id %s not found", id)
+}
+
+func (repo *MemoryRepository) ResolveGrowthFeedingByID(feedingId uuid.UUID) (*Feeding, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.findFeeding(feedingId)
+}
+
+func (repo *MemoryRepository) InsertGrowthFeeding(feeding *Feeding) (*Feeding, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	//the cycle is checked before any lot is taken off
+	if !repo.hasBatchCycle(feeding.BatchCycleID) {
+		return nil, fmt.Errorf("growth batch cycle with id %s not found", feeding.BatchCycleID)
+	}
+	lots := make([]feed.FeedLotUsage, 0)
+	if repo.Feed != nil {
+		usages, err := repo.Feed.ConsumeFeedLotTransaction(nil, feeding.FeedType.ID, feed.Lot_Usage_Feeding, feeding.ID, feeding.Qty)
+		if err != nil {
+			return nil, err
+		}
+		lots = *usages
+	}
+	if err := repo.insertGrowthFeeding(feeding); err != nil {
+		return nil, err
+	}
+	result, err := repo.findFeeding(feeding.ID)
+	if err != nil {
+		return nil, err
+	}
+	result.Lots = lots
+	return result, nil
+}
+
+func (repo *MemoryRepository) InsertGrowthFeedingTransaction(tx *sql.Tx, feeding *Feeding) (*Feeding, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if err := repo.insertGrowthFeeding(feeding); err != nil {
+		return nil, err
+	}
+	return feeding, nil
+}
+
+func (repo *MemoryRepository) insertGrowthFeeding(feeding *Feeding) error {
+	if !repo.hasBatchCycle(feeding.BatchCycleID) {
+		return fmt.Errorf("growth batch cycle with id %s not found", feeding.BatchCycleID)
+	}
+	if repo.Feed != nil {
+		if err := repo.Feed.RecordGrowthFeeding(feeding.BatchCycleID, feeding.FeedType.ID, feeding.FeedingDate, feeding.Qty); err != nil {
+			return err
+		}
+	}
+	row := Feeding{
+		ID:           feeding.ID,
+		BatchCycleID: feeding.BatchCycleID,
+		FeedTypeID:   feeding.FeedType.ID,
+		FeedingDate:  feeding.FeedingDate,
+		Qty:          feeding.Qty,
+		Remarks:      feeding.Remarks,
+		Created:      time.Now(),
+	}
+	repo.feedings = append(repo.feedings, row)
+	return nil
+}
+
+//summary
+func (repo *MemoryRepository) summaryByBatchCycleID(cycleId uuid.UUID) *CutOff {
+	for _, row := range repo.summaries {
+		if row.BatchCycleID == cycleId {
+			return &row
+		}
+	}
+	return nil
+}
+
+func (repo *MemoryRepository) findSummary(id uuid.UUID) (*CutOff, error) {
+	for _, row := range repo.summaries {
+		if row.ID == id {
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("growth summary with id %s not found", id)
+}
+
+//UpdateGrowthBatchCycleAndInsertGrowthSummaryTransaction checks the cycle and its
+//summary before it writes either of them
+func (repo *MemoryRepository) UpdateGrowthBatchCycleAndInsertGrowthSummaryTransaction(batchCycle *BatchCycle, cutoff *CutOff) (*CutOff, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	row, err := repo.batchCycleRow(batchCycle)
+	if err != nil {
+		return nil, err
+	} else if !repo.hasBatchCycle(cutoff.BatchCycleID) {
+		return nil, fmt.Errorf("growth batch cycle with id %s not found", cutoff.BatchCycleID)
+	}
+	repo.updateGrowthBatchCycle(row)
+	repo.insertGrowthSummary(cutoff)
+	if result, err := repo.findSummary(cutoff.ID); err != nil {
+		return nil, err
+	} else {
+		result.BatchID = batchCycle.BatchID
+		return result, nil
+	}
+}
+
+func (repo *MemoryRepository) ResolveGrowthSummaryByBatchCycleID(cycleId uuid.UUID) (*CutOff, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.summaryByBatchCycleID(cycleId), nil
+}
+
+func (repo *MemoryRepository) ResolveGrowthSummaryByID(summaryId uuid.UUID) (*CutOff, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.findSummary(summaryId)
+}
+
+func (repo *MemoryRepository) InsertGrowthSummary(cutoff *CutOff) (*CutOff, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if !repo.hasBatchCycle(cutoff.BatchCycleID) {
+		return nil, fmt.Errorf("growth batch cycle with id %s not found", cutoff.BatchCycleID)
+	}
+	repo.insertGrowthSummary(cutoff)
+	if result, err := repo.findSummary(cutoff.ID); err != nil {
+		return nil, err
+	} else {
+		result.BatchID = cutoff.BatchID
+		return result, nil
+	}
+}
+
+func (repo *MemoryRepository) InsertGrowthSummaryTransaction(tx *sql.Tx, cutoff *CutOff) (*CutOff, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if !repo.hasBatchCycle(cutoff.BatchCycleID) {
+		return nil, fmt.Errorf("growth batch cycle with id %s not found", cutoff.BatchCycleID)
+	}
+	repo.insertGrowthSummary(cutoff)
+	return cutoff, nil
+}
+
+func (repo *MemoryRepository) insertGrowthSummary(cutoff *CutOff) {
+	row := *cutoff
+	row.BatchID = uuid.Nil
+	row.Created = time.Now()
+	repo.summaries = append(repo.summaries, row)
+	if repo.Feed != nil {
+		repo.Feed.RecordGrowthSummary(row.BatchCycleID, row.SummaryDate, row.FCR)
+	}
+}
+
+//sales
+func (repo *MemoryRepository) findSales(id uuid.UUID) (*Sales, error) {
+	for _, row := range repo.sales {
+		if row.ID == id {
+			row.Detail = make([]SalesDetail, 0)
+			for _, detail := range repo.salesDetails {
+				if detail.SalesID == id {
+					row.Detail = append(row.Detail, detail)
+				}
+			}
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("growth sales with id %s not found", id)
+}
+
+func (repo *MemoryRepository) ResolveGrowthSales(from time.Time, to time.Time) (*[]Sales, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	sales := make([]Sales, 0)
+	for _, row := range repo.sales {
+		if !row.SalesDate.Before(from) && row.SalesDate.Before(to) {
+			sales = append(sales, row)
+		}
+	}
+	sort.SliceStable(sales, func(i, j int) bool {
+		return sales[i].SalesDate.Before(sales[j].SalesDate)
+	})
+	return &sales, nil
+}
+
+func (repo *MemoryRepository) ResolveGrowthSalesByID(salesId uuid.UUID) (*Sales, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.findSales(salesId)
+}
+
+func (repo *MemoryRepository) InsertGrowthSales(sales *Sales) (*Sales, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	row := *sales
+	row.Detail = nil
+	row.Created = time.Now()
+	row.Updated = null.Time{}
+	repo.sales = append(repo.sales, row)
+	return repo.findSales(sales.ID)
+}
+
+func (repo *MemoryRepository) UpdateGrowthSalesByID(sales *Sales) (*Sales, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for i, row := range repo.sales {
+		if row.ID == sales.ID {
+			row.SalesDate = sales.SalesDate
+			row.Qty = sales.Qty
+			row.Reference = sales.Reference
+			row.Updated = null.TimeFrom(time.Now())
+			repo.sales[i] = row
+			return repo.findSales(sales.ID)
+		}
+	}
+	return nil, fmt.Errorf("growth sales with id %s not found", sales.ID)
+}
+
+//sales detail
+func (repo *MemoryRepository) ResolveGrowthSalesTraceBySalesID(salesId uuid.UUID) (*[]SalesTrace, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	traces := make([]SalesTrace, 0)
+	for _, detail := range repo.salesDetails {
+		if detail.SalesID != salesId {
+			continue
+		}
+		for _, cycle := range repo.cycles {
+			if cycle.ID == detail.BatchCycleID {
+				traces = append(traces, SalesTrace{
+					SalesDetailID: detail.ID,
+					BatchCycleID:  detail.BatchCycleID,
+					Amount:        detail.Amount,
+					Weight:        detail.Weight,
+					BatchID:       cycle.BatchID,
+					PoolID:        cycle.PoolID,
+					SeedSourceID:  cycle.SeedSourceID,
+					Start:         cycle.Start,
+				})
+			}
+		}
+	}
+	return &traces, nil
+}
+
+func (repo *MemoryRepository) ResolveGrowthSalesDetailByBatchCycleID(cycleId uuid.UUID) (*[]SalesDetail, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	details := make([]SalesDetail, 0)
+	for _, row := range repo.salesDetails {
+		if row.BatchCycleID == cycleId {
+			details = append(details, row)
+		}
+	}
+	return &details, nil
+}
+
+//UpdateGrowthBatchCycleInsertGrowthSummaryAndInsertSalesDetail checks every detail,
+//cycle and summary before it writes any of them, so one bad row leaves the sales untouched
+func (repo *MemoryRepository) UpdateGrowthBatchCycleInsertGrowthSummaryAndInsertSalesDetail(batchCycle *[]BatchCycle, cutoff *[]CutOff, sales *Sales) (*Sales, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for _, detail := range sales.Detail {
+		if !repo.hasBatchCycle(detail.BatchCycleID) {
+			return nil, fmt.Errorf("growth batch cycle with id %s not found", detail.BatchCycleID)
+		} else if _, err := repo.findSales(detail.SalesID); err != nil {
+			return nil, err
+		}
+	}
+	rows := make([]BatchCycle, 0)
+	for _, bc := range *batchCycle {
+		if row, err := repo.batchCycleRow(&bc); err != nil {
+			return nil, err
+		} else {
+			rows = append(rows, row)
+		}
+	}
+	for _, c := range *cutoff {
+		if !repo.hasBatchCycle(c.BatchCycleID) {
+			return nil, fmt.Errorf("growth batch cycle with id %s not found", c.BatchCycleID)
+		}
+	}
+	for _, detail := range sales.Detail {
+		row := detail
+		row.BatchID = uuid.Nil
+		row.Created = time.Now()
+		row.Updated = null.Time{}
+		repo.salesDetails = append(repo.salesDetails, row)
+	}
+	for _, row := range rows {
+		repo.updateGrowthBatchCycle(row)
+	}
+	for _, c := range *cutoff {
+		repo.insertGrowthSummary(&c)
+	}
+	return repo.findSales(sales.ID)
+}
+
+//cost
+func (repo *MemoryRepository) ResolveGrowthCostByBatchCycleID(cycleId uuid.UUID) (*[]Cost, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	costs := make([]Cost, 0)
+	for _, row := range repo.costs {
+		if row.BatchCycleID == cycleId {
+			costs = append(costs, row)
+		}
+	}
+	sort.SliceStable(costs, func(i, j int) bool {
+		return costs[i].CostDate.Before(costs[j].CostDate)
+	})
+	return &costs, nil
+}
+
+func (repo *MemoryRepository) findCost(id uuid.UUID) (*Cost, error) {
+	for _, row := range repo.costs {
+		if row.ID == id {
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("growth cost with id %s not found", id)
+}
+
+func (repo *MemoryRepository) ResolveGrowthCostByID(costId uuid.UUID) (*Cost, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.findCost(costId)
+}
+
+func (repo *MemoryRepository) InsertGrowthCost(cost *Cost) (*Cost, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if !repo.hasBatchCycle(cost.BatchCycleID) {
+		return nil, fmt.Errorf("growth batch cycle with id %s not found", cost.BatchCycleID)
+	}
+	row := *cost
+	row.Created = time.Now()
+	repo.costs = append(repo.costs, row)
+	return &row, nil
+}
+
+//treatment
+func (repo *MemoryRepository) ResolveGrowthTreatmentByBatchCycleID(cycleId uuid.UUID) (*[]Treatment, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	treatments := make([]Treatment, 0)
+	for _, row := range repo.treatments {
+		if row.BatchCycleID == cycleId {
+			treatments = append(treatments, row)
+		}
+	}
+	sort.SliceStable(treatments, func(i, j int) bool {
+		return treatments[i].StartDate.Before(treatments[j].StartDate)
+	})
+	return &treatments, nil
+}
+
+func (repo *MemoryRepository) findTreatment(id uuid.UUID) (*Treatment, error) {
+	for _, row := range repo.treatments {
+		if row.ID == id {
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("growth treatment with id %s not found", id)
+}
+
+func (repo *MemoryRepository) ResolveGrowthTreatmentByID(treatmentId uuid.UUID) (*Treatment, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.findTreatment(treatmentId)
+}
+
+func (repo *MemoryRepository) InsertGrowthTreatment(treatment *Treatment) (*Treatment, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if !repo.hasBatchCycle(treatment.BatchCycleID) {
+		return nil, fmt.Errorf("growth batch cycle with id %s not found", treatment.BatchCycleID)
+	}
+	row := *treatment
+	row.WithdrawalUntil = null.Time{}
+	row.Created = time.Now()
+	row.Updated = null.Time{}
+	repo.treatments = append(repo.treatments, row)
+	return &row, nil
+}
+
+func (repo *MemoryRepository) UpdateGrowthTreatmentByID(treatment *Treatment) (*Treatment, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for i, row := range repo.treatments {
+		if row.ID == treatment.ID {
+			row.Product = treatment.Product
+			row.Dose = treatment.Dose
+			row.Unit = treatment.Unit
+			row.Method = treatment.Method
+			row.StartDate = treatment.StartDate
+			row.EndDate = treatment.EndDate
+			row.WithdrawalDays = treatment.WithdrawalDays
+			row.Remarks = treatment.Remarks
+			row.Updated = null.TimeFrom(time.Now())
+			repo.treatments[i] = row
+		}
+	}
+	return repo.findTreatment(treatment.ID)
+}
+
+func (repo *MemoryRepository) ResolveGrowthTreatmentOverrideByBatchCycleID(cycleId uuid.UUID) (*[]TreatmentOverride, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	overrides := make([]TreatmentOverride, 0)
+	for _, row := range repo.overrides {
+		if row.BatchCycleID == cycleId {
+			overrides = append(overrides, row)
+		}
+	}
+	sort.SliceStable(overrides, func(i, j int) bool {
+		return overrides[i].Created.Before(overrides[j].Created)
+	})
+	return &overrides, nil
+}
+
+func (repo *MemoryRepository) findTreatmentOverride(id uuid.UUID) (*TreatmentOverride, error) {
+	for _, row := range repo.overrides {
+		if row.ID == id {
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("growth treatment override with id %s not found", id)
+}
+
+func (repo *MemoryRepository) ResolveGrowthTreatmentOverrideByID(overrideId uuid.UUID) (*TreatmentOverride, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.findTreatmentOverride(overrideId)
+}
+
+func (repo *MemoryRepository) InsertGrowthTreatmentOverride(override *TreatmentOverride) (*TreatmentOverride, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if !repo.hasBatchCycle(override.BatchCycleID) {
+		return nil, fmt.Errorf("growth batch cycle with id %s not found", override.BatchCycleID)
+	} else if _, err := repo.findTreatment(override.TreatmentID); err != nil {
+		return nil, err
+	}
+	row := *override
+	row.Created = time.Now()
+	repo.overrides = append(repo.overrides, row)
+	return &row, nil
+}
+
+//export, rows are copied before they are handed over so fn may use the repository
+func (repo *MemoryRepository) StreamGrowthBatch(deleted string, fn func(*Batch) error) error {
+	repo.mu.Lock()
+	batches := repo.sortedBatches(deleted)
+	repo.mu.Unlock()
+	for _, row := range batches {
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (repo *MemoryRepository) StreamGrowthPool(deleted string, fn func(*Pool) error) error {
+	repo.mu.Lock()
+	pools := repo.sortedPools(deleted)
+	repo.mu.Unlock()
+	for _, row := range pools {
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (repo *MemoryRepository) StreamGrowthBatchCycle(batchId uuid.UUID, fn func(*BatchCycle) error) error {
+	repo.mu.Lock()
+	batchCycles := make([]BatchCycle, 0)
+	for _, row := range repo.cycles {
+		if row.BatchID == batchId {
+			batchCycles = append(batchCycles, row)
+		}
+	}
+	repo.mu.Unlock()
+	sort.SliceStable(batchCycles, func(i, j int) bool {
+		return batchCycles[i].Created.Before(batchCycles[j].Created)
+	})
+	for _, row := range batchCycles {
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (repo *MemoryRepository) StreamGrowthSalesDetail(from time.Time, to time.Time, fn func(*Sales, *SalesDetail) error) error {
+	type salesLine struct {
+		sales  Sales
+		detail SalesDetail
+	}
+	repo.mu.Lock()
+	lines := make([]salesLine, 0)
+	for _, detail := range repo.salesDetails {
+		for _, sales := range repo.sales {
+			if sales.ID == detail.SalesID && !sales.SalesDate.Before(from) && sales.SalesDate.Before(to) {
+				sales.Detail = nil
+				lines = append(lines, salesLine{sales: sales, detail: detail})
+			}
+		}
+	}
+	repo.mu.Unlock()
+	sort.SliceStable(lines, func(i, j int) bool {
+		if !lines[i].sales.SalesDate.Equal(lines[j].sales.SalesDate) {
+			return lines[i].sales.SalesDate.Before(lines[j].sales.SalesDate)
+		}
+		return lines[i].detail.Created.Before(lines[j].detail.Created)
+	})
+	for _, line := range lines {
+		if err := fn(&line.sales, &line.detail); err != nil {
+			return err
+		}
+	}
+	return nil
+}
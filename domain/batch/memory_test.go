@@ -0,0 +1,60 @@
+package batch
+
+import (
+	"testing"
+
+	"github.com/livestockz/api/domain/feed"
+	uuid "github.com/satori/go.uuid"
+)
+
+func TestMemoryBatchPage(t *testing.T) {
+	repo := new(MemoryRepository)
+	ids := make([]uuid.UUID, 0)
+	for _, name := range []string{"Batch 3", "Batch 1", "Batch 2"} {
+		if batch, err := repo.InsertGrowthBatch(&Batch{ID: uuid.Must(uuid.NewV4()), Name: name, Status: 1}); err != nil {
+			t.Fatal(err)
+		} else {
+			ids = append(ids, batch.ID)
+		}
+	}
+	if _, err := repo.RemoveGrowthBatchByID(ids[1]); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		deleted string
+		total   int32
+		first   string
+	}{{Deleted_False, 2, "Batch 2"}, {Deleted_True, 1, "Batch 1"}, {Deleted_Any, 3, "Batch 1"}} {
+		page, _, _, total, err := repo.ResolveGrowthBatchPage(0, 2, c.deleted)
+		if err != nil {
+			t.Fatal(err)
+		} else if total != c.total || len(*page) == 0 || (*page)[0].Name != c.first {
+			t.Fatalf("deleted %q: got %+v of %d rows", c.deleted, *page, total)
+		}
+	}
+	if page, _, _, total, err := repo.ResolveGrowthBatchPage(1, 2, Deleted_Any); err != nil {
+		t.Fatal(err)
+	} else if total != 3 || len(*page) != 1 || (*page)[0].Name != "Batch 3" {
+		t.Fatalf("second page: got %d of %d rows", len(*page), total)
+	}
+	if _, err := repo.ResolveGrowthBatchByID(uuid.Must(uuid.NewV4())); err == nil {
+		t.Fatal("resolved an unknown batch")
+	}
+}
+
+func TestNewMemoryRepositorySeeds(t *testing.T) {
+	repo := NewMemoryRepository(feed.NewMemoryRepository())
+	if causes, err := repo.ResolveGrowthDeathCause(Deleted_False); err != nil {
+		t.Fatal(err)
+	} else if len(*causes) != 5 {
+		t.Fatalf("expected the 5 seeded death causes like the migrations, got %d", len(*causes))
+	}
+	if _, err := repo.ResolveGrowthDeathCauseByID(uuid.FromStringOrNil("6f1c2a4e-0c3b-4d8e-9a51-1f0d2b7c0001")); err != nil {
+		t.Fatal(err)
+	}
+	if ranges, err := repo.ResolveGrowthWaterQualityRange(); err != nil {
+		t.Fatal(err)
+	} else if len(*ranges) != 7 {
+		t.Fatalf("expected the 7 seeded water quality ranges, got %d", len(*ranges))
+	}
+}
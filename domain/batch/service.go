@@ -2,6 +2,8 @@ package batch
 
 import (
 	"fmt"
+	"math"
+	"sort"
 	"time"
 
 	"github.com/guregu/null"
@@ -22,20 +24,76 @@ type Service interface {
 	StoreGrowthPool(*Pool) (*Pool, error)
 	RemoveGrowthPoolByID(uuid.UUID) (*Pool, error)
 	RemoveGrowthPoolByIDs([]uuid.UUID) (*[]Pool, error)
+	//species
+	ResolveGrowthSpecies(deleted string) (*[]Species, error)
+	ResolveGrowthSpeciesByID(uuid.UUID) (*Species, error)
+	StoreGrowthSpecies(*Species) (*Species, error)
+	RemoveGrowthSpeciesByID(uuid.UUID) (*Species, error)
+	//hatchery
+	ResolveGrowthHatcheryPage(page int32, limit int32, deleted string) (*[]Hatchery, int32, int32, int32, error)
+	ResolveGrowthHatcheryByID(uuid.UUID) (*Hatchery, error)
+	StoreGrowthHatchery(*Hatchery) (*Hatchery, error)
+	RemoveGrowthHatcheryByID(uuid.UUID) (*Hatchery, error)
+	ResolveGrowthHatcheryPerformance(from time.Time, to time.Time) (*[]HatcheryPerformance, error)
+	//seed source
+	ResolveGrowthSeedSourcePage(page int32, limit int32, hatcheryId uuid.UUID) (*[]SeedSource, int32, int32, int32, error)
+	ResolveGrowthSeedSourceByID(uuid.UUID) (*SeedSource, error)
+	StoreGrowthSeedSource(*SeedSource) (*SeedSource, error)
+	//pool device
+	ResolveGrowthPoolDeviceByPoolID(poolId uuid.UUID) (*[]PoolDevice, error)
+	ResolveGrowthPoolByDeviceID(deviceId string) (*Pool, error)
+	StoreGrowthPoolDevice(*PoolDevice) (*PoolDevice, error)
+	RemoveGrowthPoolDeviceByID(poolId uuid.UUID, id uuid.UUID) (*PoolDevice, error)
+	//pool water quality
+	ResolveGrowthWaterQualityByPoolID(poolId uuid.UUID, from time.Time, to time.Time) (*[]WaterQuality, error)
+	StoreGrowthWaterQuality(*WaterQuality) (*WaterQuality, error)
+	StoreGrowthWaterQualityBatch([]WaterQuality) (*[]WaterQuality, error)
+	ResolveGrowthWaterQualityRange() (*[]WaterQualityRange, error)
+	StoreGrowthWaterQualityRange([]WaterQualityRange) (*[]WaterQualityRange, error)
 	//batch cycle
-	ResolveGrowthBatchCyclePage(batchId uuid.UUID, page int32, limit int32) (*[]BatchCycle, int32, int32, int32, error)
+	ResolveGrowthBatchCyclePage(batchId uuid.UUID, page int32, limit int32, include []string) (*[]BatchCycle, int32, int32, int32, error)
 	ResolveGrowthBatchCycleByID(batchId uuid.UUID, cycleId uuid.UUID) (*BatchCycle, error)
 	StoreGrowthBatchCycle(*BatchCycle) (*BatchCycle, error)
+	//sampling
+	ResolveGrowthSamplingByBatchCycleID(batchId uuid.UUID, cycleId uuid.UUID) (*[]Sampling, error)
+	StoreGrowthSampling(batchId uuid.UUID, sampling *Sampling) (*Sampling, error)
+	//cycle report
+	ResolveGrowthCycleReport(from time.Time, to time.Time, batchId uuid.UUID) (*[]CycleReport, error)
+	//forecast
+	ResolveGrowthForecast(from time.Time, to time.Time) (*Forecast, error)
 	//death
 	StoreGrowthDeath(*Death) (*Death, error)
+	//death cause
+	ResolveGrowthDeathCause(deleted string) (*[]DeathCause, error)
+	ResolveGrowthDeathCauseByID(uuid.UUID) (*DeathCause, error)
+	StoreGrowthDeathCause(*DeathCause) (*DeathCause, error)
+	RemoveGrowthDeathCauseByID(uuid.UUID) (*DeathCause, error)
+	//mortality report
+	ResolveGrowthMortalityReport(from time.Time, to time.Time, batchId uuid.UUID, poolId uuid.UUID) (*MortalityReport, error)
 	//death
 	StoreGrowthFeeding(*Feeding) (*Feeding, error)
 	//cut off
 	StoreGrowthCutOff(*CutOff) (*CutOff, error)
+	//treatment
+	ResolveGrowthTreatmentByBatchCycleID(batchId uuid.UUID, cycleId uuid.UUID) (*[]Treatment, error)
+	StoreGrowthTreatment(batchId uuid.UUID, treatment *Treatment) (*Treatment, error)
+	StoreGrowthTreatmentOverride(batchId uuid.UUID, override *TreatmentOverride) (*TreatmentOverride, error)
 	//sales
+	ResolveGrowthSales(from time.Time, to time.Time) (*[]Sales, error)
 	ResolveGrowthSalesByID(salesId uuid.UUID) (*Sales, error)
 	StoreGrowthSales(sales *Sales) (*Sales, error)
 	StoreGrowthSalesDetail(sales *Sales) (*Sales, error)
+	ResolveGrowthSalesTraceBySalesID(salesId uuid.UUID) (*[]SalesTrace, error)
+	//cost
+	ResolveGrowthCostByBatchCycleID(batchId uuid.UUID, cycleId uuid.UUID) (*[]Cost, error)
+	StoreGrowthCost(batchId uuid.UUID, cost *Cost) (*Cost, error)
+	//profit and loss
+	ResolveGrowthBatchCycleProfitAndLoss(batchId uuid.UUID, cycleId uuid.UUID) (*ProfitAndLoss, error)
+	//export
+	StreamGrowthBatch(deleted string, fn func(*Batch) error) error
+	StreamGrowthPool(deleted string, fn func(*Pool) error) error
+	StreamGrowthBatchCycle(batchId uuid.UUID, fn func(*BatchCycle) error) error
+	StreamGrowthSalesDetail(from time.Time, to time.Time, fn func(*Sales, *SalesDetail) error) error
 }
 
 type BatchService struct {
@@ -46,7 +104,16 @@ type BatchService struct {
 func (svc *BatchService) ResolveGrowthBatchPage(page int32, limit int32, deleted string) (*[]Batch, int32, int32, int32, error) {
 	if batches, page, limit, total, err := svc.BatchRepository.ResolveGrowthBatchPage(page, limit, deleted); err != nil {
 		return nil, 0, 0, 0, err
+	} else if species, err := svc.BatchRepository.ResolveGrowthSpecies(Deleted_Any); err != nil {
+		return nil, 0, 0, 0, err
 	} else {
+		for i, batch := range *batches {
+			for j, s := range *species {
+				if batch.SpeciesID.Valid && batch.SpeciesID.UUID == s.ID {
+					(*batches)[i].Species = &(*species)[j]
+				}
+			}
+		}
 		return batches, page, limit, total, nil
 	}
 }
@@ -55,11 +122,27 @@ func (svc *BatchService) ResolveGrowthBatchByID(id uuid.UUID) (*Batch, error) {
 	if batch, err := svc.BatchRepository.ResolveGrowthBatchByID(id); err != nil {
 		return nil, fmt.Errorf("found an error: %s", err.Error())
 	} else {
+		if batch.SpeciesID.Valid {
+			if species, err := svc.BatchRepository.ResolveGrowthSpeciesByID(batch.SpeciesID.UUID); err != nil {
+				return nil, err
+			} else {
+				batch.Species = species
+			}
+		}
 		return batch, nil
 	}
 }
 
 func (svc *BatchService) StoreGrowthBatch(batch *Batch) (*Batch, error) {
+	batch.SpeciesID = uuid.NullUUID{}
+	if batch.Species != nil {
+		if species, err := svc.BatchRepository.ResolveGrowthSpeciesByID(batch.Species.ID); err != nil {
+			return nil, err
+		} else if species.Deleted {
+			return nil, fmt.Errorf("Species %s has been deleted.", species.Name)
+		}
+		batch.SpeciesID = uuid.NullUUID{UUID: batch.Species.ID, Valid: true}
+	}
 	if batch.ID == uuid.Nil {
 		batch.ID = uuid.Must(uuid.NewV4())
 		if result, err := svc.BatchRepository.InsertGrowthBatch(batch); err != nil {
@@ -145,83 +228,111 @@ func (svc *BatchService) RemoveGrowthPoolByIDs(ids []uuid.UUID) (*[]Pool, error)
 }
 
 //batch cycle
-func (svc *BatchService) ResolveGrowthBatchCyclePage(batchId uuid.UUID, page int32, limit int32) (*[]BatchCycle, int32, int32, int32, error) {
-	if batchCycles, page, limit, total, err := svc.BatchRepository.ResolveGrowthBatchCyclePage(batchId, page, limit); err != nil {
+func (svc *BatchService) ResolveGrowthBatchCyclePage(batchId uuid.UUID, page int32, limit int32, include []string) (*[]BatchCycle, int32, int32, int32, error) {
+	for _, relation := range include {
+		if !hasInclude(CycleIncludes, relation) {
+			return nil, 0, 0, 0, fmt.Errorf("Unknown batch cycle relation %s.", relation)
+		}
+	}
+	if batchCycles, page, limit, total, err := svc.BatchRepository.ResolveGrowthBatchCyclePage(batchId, page, limit, include); err != nil {
 		return nil, 0, 0, 0, err
+	} else if !hasInclude(include, Cycle_Include_Feeding) {
+		return batchCycles, page, limit, total, nil
 	} else {
-		//prepare ids of feed type id
+		//feed types and their costs of the whole page in one query each
 		var ids []uuid.UUID
 		for _, batchCycle := range *batchCycles {
 			for _, feeding := range batchCycle.Feeding {
 				ids = append(ids, feeding.FeedTypeID)
 			}
 		}
-		//find feed type ids
-		feedTypes, err := svc.FeedService.ResolveFeedTypeByIDs(ids)
+		if len(ids) < 1 {
+			return batchCycles, page, limit, total, nil
+		}
+		feedTypes, err := svc.feedTypeMap(ids)
+		if err != nil {
+			return nil, 0, 0, 0, err
+		}
+		costs, err := svc.FeedService.ResolveFeedTypeCostByFeedTypeIDs(ids)
 		if err != nil {
 			return nil, 0, 0, 0, err
 		}
 
-		//map feed type to batchcycle feeding's feed type
-		var newBatchCycles []BatchCycle
-		for _, batchCycle := range *batchCycles {
-			var newFeeding []Feeding
+		for i := range *batchCycles {
+			batchCycle := &(*batchCycles)[i]
+			newFeeding := make([]Feeding, 0)
 			for _, feeding := range batchCycle.Feeding {
-				for _, feedType := range *feedTypes {
-					if feeding.FeedTypeID.String() == feedType.ID.String() {
-						feeding.FeedType = feedType
-						newFeeding = append(newFeeding, feeding)
-					}
+				if feedType, ok := feedTypes[feeding.FeedTypeID]; ok {
+					feeding.FeedType = feedType
+					feeding.UnitCost = feed.FeedTypeCostAt(*costs, feeding.FeedTypeID, feeding.FeedingDate)
+					batchCycle.ProteinIntake = batchCycle.ProteinIntake + feed.ProteinIntake(feedType, feeding.Qty)
+					newFeeding = append(newFeeding, feeding)
 				}
 			}
 			batchCycle.Feeding = newFeeding
-			newBatchCycles = append(newBatchCycles, batchCycle)
 		}
-		return &newBatchCycles, page, limit, total, nil
+		return batchCycles, page, limit, total, nil
 	}
 }
 
-func (svc *BatchService) ResolveGrowthBatchCycleByID(batchId uuid.UUID, cycleId uuid.UUID) (*BatchCycle, error) {
-	if batchCycle, err := svc.BatchRepository.ResolveGrowthBatchCycleByID(batchId, cycleId); err != nil {
+//feedTypeMap resolves the given feed types keyed by id
+func (svc *BatchService) feedTypeMap(ids []uuid.UUID) (map[uuid.UUID]feed.FeedType, error) {
+	feedTypes := make(map[uuid.UUID]feed.FeedType)
+	if rows, err := svc.FeedService.ResolveFeedTypeByIDs(ids); err != nil {
+		return nil, err
+	} else {
+		for _, row := range *rows {
+			feedTypes[row.ID] = row
+		}
+	}
+	return feedTypes, nil
+}
+
+//species
+func (svc *BatchService) ResolveGrowthSpecies(deleted string) (*[]Species, error) {
+	if species, err := svc.BatchRepository.ResolveGrowthSpecies(deleted); err != nil {
 		return nil, fmt.Errorf("found an error: %s", err.Error())
 	} else {
+		return species, nil
+	}
+}
 
-		//populate feed type id
-		var ids []uuid.UUID
-		for _, feeding := range batchCycle.Feeding {
-			ids = append(ids, feeding.FeedTypeID)
-		}
-		//find feed type ids
-		feedTypes, err := svc.FeedService.ResolveFeedTypeByIDs(ids)
-		if err != nil {
-			return nil, err
-		}
-		//replace feed type in feeding on batchCycle
-		var newFeeding []Feeding
-		for _, feeding := range batchCycle.Feeding {
-			for _, feedType := range *feedTypes {
-				if feeding.FeedTypeID.String() == feedType.ID.String() {
-					feeding.FeedType = feedType
-					newFeeding = append(newFeeding, feeding)
-				}
-			}
-		}
-		batchCycle.Feeding = newFeeding
-		return batchCycle, nil
+func (svc *BatchService) ResolveGrowthSpeciesByID(id uuid.UUID) (*Species, error) {
+	if species, err := svc.BatchRepository.ResolveGrowthSpeciesByID(id); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+		return species, nil
 	}
 }
 
-func (svc *BatchService) StoreGrowthBatchCycle(batchCycle *BatchCycle) (*BatchCycle, error) {
-	if batchCycle.ID == uuid.Nil {
-		batchCycle.ID = uuid.Must(uuid.NewV4())
-		if result, err := svc.BatchRepository.InsertGrowthBatchCycle(batchCycle); err != nil {
+func (svc *BatchService) StoreGrowthSpecies(species *Species) (*Species, error) {
+	if species.TargetWeight < 0 {
+		return nil, fmt.Errorf("Target weight cannot be negative.")
+	}
+	//curve is kept ordered by day of culture, one weight per day
+	if species.Curve == nil {
+		species.Curve = make([]GrowthCurvePoint, 0)
+	}
+	sort.Slice(species.Curve, func(i, j int) bool {
+		return species.Curve[i].Day < species.Curve[j].Day
+	})
+	for i, point := range species.Curve {
+		if point.Day < 0 || point.Weight <= 0 {
+			return nil, fmt.Errorf("Curve day cannot be negative and weight must be greater than zero.")
+		} else if i > 0 && species.Curve[i-1].Day == point.Day {
+			return nil, fmt.Errorf("Curve has more than one weight on day %d.", point.Day)
+		}
+	}
+	if species.ID == uuid.Nil {
+		species.ID = uuid.Must(uuid.NewV4())
+		if result, err := svc.BatchRepository.InsertGrowthSpecies(species); err != nil {
 			return nil, err
 		} else {
 			return result, nil
 		}
 	} else {
 		//update
-		if result, err := svc.BatchRepository.UpdateGrowthBatchCycleByID(batchCycle); err != nil {
+		if result, err := svc.BatchRepository.UpdateGrowthSpeciesByID(species); err != nil {
 			return nil, err
 		} else {
 			return result, nil
@@ -229,141 +340,1359 @@ func (svc *BatchService) StoreGrowthBatchCycle(batchCycle *BatchCycle) (*BatchCy
 	}
 }
 
-//growth death
-func (svc *BatchService) StoreGrowthDeath(death *Death) (*Death, error) {
-	death.ID = uuid.Must(uuid.NewV4())
-	if result, err := svc.BatchRepository.InsertGrowthDeath(death); err != nil {
-		return nil, err
+func (svc *BatchService) RemoveGrowthSpeciesByID(id uuid.UUID) (*Species, error) {
+	if _, err := svc.BatchRepository.RemoveGrowthSpeciesByID(id); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
 	} else {
-		return result, nil
+		return nil, nil
 	}
 }
 
-//growth feeding
-func (svc *BatchService) StoreGrowthFeeding(feeding *Feeding) (*Feeding, error) {
-	feeding.ID = uuid.Must(uuid.NewV4())
-	if result, err := svc.BatchRepository.InsertGrowthFeeding(feeding); err != nil {
-		return nil, err
-	} else if feedtype, err := svc.FeedService.ResolveFeedTypeByID(result.FeedTypeID); err != nil {
-		return nil, err
+//hatchery
+func (svc *BatchService) ResolveGrowthHatcheryPage(page int32, limit int32, deleted string) (*[]Hatchery, int32, int32, int32, error) {
+	if hatcheries, page, limit, total, err := svc.BatchRepository.ResolveGrowthHatcheryPage(page, limit, deleted); err != nil {
+		return nil, 0, 0, 0, err
 	} else {
-		result.FeedType = *feedtype
-		return result, nil
+		return hatcheries, page, limit, total, nil
 	}
 }
 
-//growth cut off
-func (svc *BatchService) StoreGrowthCutOff(cutoff *CutOff) (*CutOff, error) {
-	//validate cutoff existed
-	if cutoffs, error := svc.BatchRepository.ResolveGrowthSummaryByBatchCycleID(cutoff.BatchCycleID); error != nil {
-		return nil, error
-	} else if cutoffs != nil {
-		return nil, fmt.Errorf("You cannot cutoff this cycle, cutoff existed.")
+func (svc *BatchService) ResolveGrowthHatcheryByID(id uuid.UUID) (*Hatchery, error) {
+	if hatchery, err := svc.BatchRepository.ResolveGrowthHatcheryByID(id); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+		return hatchery, nil
 	}
+}
 
-	//get batch cycle and feeding data
-	if batchCycle, error := svc.BatchRepository.ResolveGrowthBatchCycleByID(cutoff.BatchID, cutoff.BatchCycleID); error != nil {
-		return nil, error
-	} else if feedings, err := svc.BatchRepository.ResolveGrowthFeedingByBatchCycleID(cutoff.BatchCycleID); err != nil {
-		return nil, error
+func (svc *BatchService) StoreGrowthHatchery(hatchery *Hatchery) (*Hatchery, error) {
+	if hatchery.ID == uuid.Nil {
+		hatchery.ID = uuid.Must(uuid.NewV4())
+		if result, err := svc.BatchRepository.InsertGrowthHatchery(hatchery); err != nil {
+			return nil, err
+		} else {
+			return result, nil
+		}
 	} else {
-		//calculate ADG
-		days := cutoff.SummaryDate.Sub(batchCycle.Start).Hours() / 24
-		cutoff.ADG = (cutoff.Weight - batchCycle.Weight) / days
-
-		//calculate FCR
-		var total float64
-		for _, feeding := range *feedings {
-			total = total + feeding.Qty
+		//update
+		if result, err := svc.BatchRepository.UpdateGrowthHatcheryByID(hatchery); err != nil {
+			return nil, err
+		} else {
+			return result, nil
 		}
-		cutoff.FCR = total / (cutoff.Weight - batchCycle.Weight)
+	}
+}
 
-		//calculate SR
-		cutoff.SR = (cutoff.Amount / batchCycle.Amount) * 100
+func (svc *BatchService) RemoveGrowthHatcheryByID(id uuid.UUID) (*Hatchery, error) {
+	if _, err := svc.BatchRepository.RemoveGrowthHatcheryByID(id); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+		return nil, nil
+	}
+}
 
-		//update cycle finish date on batch cycle then insert growth summary
-		batchCycle.Finish = null.TimeFrom(cutoff.SummaryDate)
-		cutoff.ID = uuid.Must(uuid.NewV4())
-		summary, err := svc.BatchRepository.UpdateGrowthBatchCycleAndInsertGrowthSummaryTransaction(batchCycle, cutoff)
-		if err != nil {
-			return nil, error
-		} else {
-			return summary, nil
+//ResolveGrowthHatcheryPerformance ranks hatcheries by the SR then FCR of the
+//cycles stocked from their seed and cut off within the period
+func (svc *BatchService) ResolveGrowthHatcheryPerformance(from time.Time, to time.Time) (*[]HatcheryPerformance, error) {
+	if performances, err := svc.BatchRepository.ResolveGrowthHatcheryPerformance(from, to); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+		for i := range *performances {
+			(*performances)[i].Rank = int32(i + 1)
 		}
+		return performances, nil
+	}
+}
 
+//seed source
+func (svc *BatchService) ResolveGrowthSeedSourcePage(page int32, limit int32, hatcheryId uuid.UUID) (*[]SeedSource, int32, int32, int32, error) {
+	if seedSources, page, limit, total, err := svc.BatchRepository.ResolveGrowthSeedSourcePage(page, limit, hatcheryId); err != nil {
+		return nil, 0, 0, 0, err
+	} else {
+		return seedSources, page, limit, total, nil
 	}
 }
 
-//growth sales
-func (svc *BatchService) ResolveGrowthSalesByID(salesId uuid.UUID) (*Sales, error) {
-	if result, err := svc.BatchRepository.ResolveGrowthSalesByID(salesId); err != nil {
-		return nil, err
+func (svc *BatchService) ResolveGrowthSeedSourceByID(id uuid.UUID) (*SeedSource, error) {
+	if seedSource, err := svc.BatchRepository.ResolveGrowthSeedSourceByID(id); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
 	} else {
-		return result, nil
+		return seedSource, nil
 	}
 }
-func (svc *BatchService) StoreGrowthSales(sales *Sales) (*Sales, error) {
-	if sales.ID == uuid.Nil {
-		sales.ID = uuid.Must(uuid.NewV4())
-		if sales, err := svc.BatchRepository.InsertGrowthSales(sales); err != nil {
+
+func (svc *BatchService) StoreGrowthSeedSource(seedSource *SeedSource) (*SeedSource, error) {
+	if hatchery, err := svc.BatchRepository.ResolveGrowthHatcheryByID(seedSource.Hatchery.ID); err != nil {
+		return nil, err
+	} else if hatchery.Deleted {
+		return nil, fmt.Errorf("Hatchery %s has been deleted.", hatchery.Name)
+	}
+	if seedSource.ID == uuid.Nil {
+		seedSource.ID = uuid.Must(uuid.NewV4())
+		if result, err := svc.BatchRepository.InsertGrowthSeedSource(seedSource); err != nil {
 			return nil, err
 		} else {
-			return sales, nil
+			return result, nil
 		}
 	} else {
-		if sales, err := svc.BatchRepository.UpdateGrowthSalesByID(sales); err != nil {
+		//update
+		if result, err := svc.BatchRepository.UpdateGrowthSeedSourceByID(seedSource); err != nil {
 			return nil, err
 		} else {
-			return sales, nil
+			return result, nil
 		}
 	}
 }
 
-func (svc *BatchService) StoreGrowthSalesDetail(sales *Sales) (*Sales, error) {
-	//set sales id and create cutoff
-	cutoffs := make([]CutOff, 0)
-	batchCycles := make([]BatchCycle, 0)
-	salesDetail := make([]SalesDetail, 0)
-	for _, detail := range sales.Detail {
-		detail.ID = uuid.Must(uuid.NewV4())
-		salesDetail = append(salesDetail, detail)
-		var cutoff CutOff
-		if batchCycle, error := svc.BatchRepository.ResolveGrowthBatchCycleByID(detail.BatchID, detail.BatchCycleID); error != nil {
-			return nil, error
-		} else if feedings, err := svc.BatchRepository.ResolveGrowthFeedingByBatchCycleID(detail.BatchCycleID); err != nil {
-			return nil, error
-		} else {
-			//calculate ADG
-			days := cutoff.SummaryDate.Sub(batchCycle.Start).Hours() / 24
-			cutoff.ADG = (cutoff.Weight - batchCycle.Weight) / days
+//pool device
+func (svc *BatchService) ResolveGrowthPoolDeviceByPoolID(poolId uuid.UUID) (*[]PoolDevice, error) {
+	if _, err := svc.BatchRepository.ResolveGrowthPoolByID(poolId); err != nil {
+		return nil, err
+	} else if devices, err := svc.BatchRepository.ResolveGrowthPoolDeviceByPoolID(poolId); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+		return devices, nil
+	}
+}
 
-			//calculate FCR
-			var total float64
-			for _, feeding := range *feedings {
-				total = total + feeding.Qty
-			}
-			cutoff.FCR = total / (cutoff.Weight - batchCycle.Weight)
+func (svc *BatchService) ResolveGrowthPoolByDeviceID(deviceId string) (*Pool, error) {
+	if device, err := svc.BatchRepository.ResolveGrowthPoolDeviceByDeviceID(deviceId); err != nil {
+		return nil, err
+	} else if pool, err := svc.BatchRepository.ResolveGrowthPoolByID(device.PoolID); err != nil {
+		return nil, err
+	} else {
+		return pool, nil
+	}
+}
 
-			//calculate SR
-			cutoff.SR = (cutoff.Amount / batchCycle.Amount) * 100
+func (svc *BatchService) StoreGrowthPoolDevice(device *PoolDevice) (*PoolDevice, error) {
+	if _, err := svc.BatchRepository.ResolveGrowthPoolByID(device.PoolID); err != nil {
+		return nil, err
+	} else if current, err := svc.BatchRepository.ResolveGrowthPoolDeviceByDeviceID(device.DeviceID); err == nil {
+		return nil, fmt.Errorf("Device %s is already assigned to pool %s", device.DeviceID, current.PoolID)
+	}
+	device.ID = uuid.Must(uuid.NewV4())
+	if result, err := svc.BatchRepository.InsertGrowthPoolDevice(device); err != nil {
+		return nil, err
+	} else {
+		return result, nil
+	}
+}
 
-			//set cycle finish date on batch cycle then insert growth summary
-			batchCycle.Finish = null.TimeFrom(time.Now())
-			batchCycles = append(batchCycles, *batchCycle)
-			cutoff.ID = uuid.Must(uuid.NewV4())
-			cutoff.BatchCycleID = detail.BatchCycleID
-			cutoff.BatchID = detail.BatchID
-			cutoff.Weight = detail.Weight
-			cutoff.Amount = detail.Amount
-			cutoff.SummaryDate = time.Now()
-			cutoffs = append(cutoffs, cutoff)
+func (svc *BatchService) RemoveGrowthPoolDeviceByID(poolId uuid.UUID, id uuid.UUID) (*PoolDevice, error) {
+	if device, err := svc.BatchRepository.ResolveGrowthPoolDeviceByID(id); err != nil {
+		return nil, err
+	} else if device.PoolID != poolId {
+		return nil, fmt.Errorf("Device %s is not assigned to pool %s", device.DeviceID, poolId)
+	} else if result, err := svc.BatchRepository.RemoveGrowthPoolDeviceByID(id); err != nil {
+		return nil, err
+	} else {
+		return result, nil
+	}
+}
+
+//pool water quality
+func (svc *BatchService) ResolveGrowthWaterQualityByPoolID(poolId uuid.UUID, from time.Time, to time.Time) (*[]WaterQuality, error) {
+	if _, err := svc.BatchRepository.ResolveGrowthPoolByID(poolId); err != nil {
+		return nil, err
+	} else if readings, err := svc.BatchRepository.ResolveGrowthWaterQualityByPoolID(poolId, from, to.AddDate(0, 0, 1)); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+		return readings, nil
+	}
+}
+
+//StoreGrowthWaterQuality records a reading of a pool and flags the parameters
+//measured outside their safe range
+func (svc *BatchService) StoreGrowthWaterQuality(waterQuality *WaterQuality) (*WaterQuality, error) {
+	if _, err := svc.BatchRepository.ResolveGrowthPoolByID(waterQuality.PoolID); err != nil {
+		return nil, err
+	}
+	ranges, err := svc.BatchRepository.ResolveGrowthWaterQualityRange()
+	if err != nil {
+		return nil, err
+	}
+	waterQuality.ID = uuid.Must(uuid.NewV4())
+	flagWaterQuality(waterQuality, *ranges)
+	if result, err := svc.BatchRepository.InsertGrowthWaterQuality(waterQuality); err != nil {
+		return nil, err
+	} else {
+		return result, nil
+	}
+}
+
+//StoreGrowthWaterQualityBatch stores readings collected from sensors at once,
+//the pool of every reading must have been resolved already
+func (svc *BatchService) StoreGrowthWaterQualityBatch(waterQualities []WaterQuality) (*[]WaterQuality, error) {
+	ranges, err := svc.BatchRepository.ResolveGrowthWaterQualityRange()
+	if err != nil {
+		return nil, err
+	}
+	for i := range waterQualities {
+		waterQualities[i].ID = uuid.Must(uuid.NewV4())
+		flagWaterQuality(&waterQualities[i], *ranges)
+	}
+	if result, err := svc.BatchRepository.InsertGrowthWaterQualityBatch(&waterQualities); err != nil {
+		return nil, err
+	} else {
+		return result, nil
+	}
+}
+
+func flagWaterQuality(waterQuality *WaterQuality, ranges []WaterQualityRange) {
+	if waterQuality.MeasuredAt.IsZero() {
+		waterQuality.MeasuredAt = time.Now()
+	}
+	values := waterQualityValues(waterQuality)
+	waterQuality.OutOfRangeList = make([]string, 0)
+	for _, parameter := range WaterParameters {
+		value := values[parameter]
+		if !value.Valid {
+			continue
+		}
+		for _, r := range ranges {
+			if r.Parameter == parameter && ((r.Min.Valid && value.Float64 < r.Min.Float64) || (r.Max.Valid && value.Float64 > r.Max.Float64)) {
+				waterQuality.OutOfRangeList = append(waterQuality.OutOfRangeList, parameter)
+			}
 		}
 	}
-	sales.Detail = salesDetail
+	waterQuality.OutOfRange = len(waterQuality.OutOfRangeList) > 0
+}
 
-	if result, err := svc.BatchRepository.UpdateGrowthBatchCycleInsertGrowthSummaryAndInsertSalesDetail(&batchCycles, &cutoffs, sales); err != nil {
+func (svc *BatchService) ResolveGrowthWaterQualityRange() (*[]WaterQualityRange, error) {
+	if ranges, err := svc.BatchRepository.ResolveGrowthWaterQualityRange(); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+		return ranges, nil
+	}
+}
+
+func (svc *BatchService) StoreGrowthWaterQualityRange(ranges []WaterQualityRange) (*[]WaterQualityRange, error) {
+	for _, r := range ranges {
+		known := false
+		for _, parameter := range WaterParameters {
+			if r.Parameter == parameter {
+				known = true
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("Unknown water quality parameter %s", r.Parameter)
+		}
+		if r.Min.Valid && r.Max.Valid && r.Min.Float64 > r.Max.Float64 {
+			return nil, fmt.Errorf("Minimum of %s cannot be bigger than its maximum", r.Parameter)
+		}
+	}
+	if result, err := svc.BatchRepository.ReplaceGrowthWaterQualityRange(&ranges); err != nil {
 		return nil, err
 	} else {
 		return result, nil
 	}
 }
+
+func waterQualityValues(waterQuality *WaterQuality) map[string]null.Float {
+	return map[string]null.Float{
+		Water_Dissolved_Oxygen: waterQuality.DissolvedOxygen,
+		Water_PH:               waterQuality.PH,
+		Water_Temperature:      waterQuality.Temperature,
+		Water_Ammonia:          waterQuality.Ammonia,
+		Water_Nitrite:          waterQuality.Nitrite,
+		Water_Salinity:         waterQuality.Salinity,
+		Water_Transparency:     waterQuality.Transparency,
+	}
+}
+
+func (svc *BatchService) ResolveGrowthBatchCycleByID(batchId uuid.UUID, cycleId uuid.UUID) (*BatchCycle, error) {
+	if batchCycle, err := svc.BatchRepository.ResolveGrowthBatchCycleByID(batchId, cycleId); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+
+		//populate feed type id
+		var ids []uuid.UUID
+		for _, feeding := range batchCycle.Feeding {
+			ids = append(ids, feeding.FeedTypeID)
+		}
+		//find feed type ids
+		feedTypes, err := svc.feedTypeMap(ids)
+		if err != nil {
+			return nil, err
+		}
+		costs, err := svc.FeedService.ResolveFeedTypeCostByFeedTypeIDs(ids)
+		if err != nil {
+			return nil, err
+		}
+		//find lots consumed by each feeding
+		var feedingIds []uuid.UUID
+		for _, feeding := range batchCycle.Feeding {
+			feedingIds = append(feedingIds, feeding.ID)
+		}
+		usages, err := svc.FeedService.ResolveFeedLotUsageByReferenceIDs(feedingIds)
+		if err != nil {
+			return nil, err
+		}
+		//replace feed type in feeding on batchCycle
+		lots := make(map[uuid.UUID][]feed.FeedLotUsage)
+		for _, usage := range *usages {
+			if usage.ReferenceType == feed.Lot_Usage_Feeding {
+				lots[usage.ReferenceID] = append(lots[usage.ReferenceID], usage)
+			}
+		}
+		var newFeeding []Feeding
+		for _, feeding := range batchCycle.Feeding {
+			if feedType, ok := feedTypes[feeding.FeedTypeID]; ok {
+				feeding.FeedType = feedType
+				feeding.UnitCost = feed.FeedTypeCostAt(*costs, feeding.FeedTypeID, feeding.FeedingDate)
+				batchCycle.ProteinIntake = batchCycle.ProteinIntake + feed.ProteinIntake(feedType, feeding.Qty)
+				feeding.Lots = make([]feed.FeedLotUsage, 0)
+				feeding.Lots = append(feeding.Lots, lots[feeding.ID]...)
+				newFeeding = append(newFeeding, feeding)
+			}
+		}
+		batchCycle.Feeding = newFeeding
+		//treatments with their withdrawal period
+		treatments, err := svc.BatchRepository.ResolveGrowthTreatmentByBatchCycleID(cycleId)
+		if err != nil {
+			return nil, err
+		}
+		for i := range *treatments {
+			(*treatments)[i].WithdrawalUntil = withdrawalUntil((*treatments)[i])
+		}
+		batchCycle.Treatments = *treatments
+		//samplings against the species reference curve
+		samplings, err := svc.BatchRepository.ResolveGrowthSamplingByBatchCycleID(cycleId)
+		if err != nil {
+			return nil, err
+		}
+		batchCycle.Samplings = *samplings
+		var species *Species
+		if batchCycle.Batch.SpeciesID.Valid {
+			if species, err = svc.BatchRepository.ResolveGrowthSpeciesByID(batchCycle.Batch.SpeciesID.UUID); err != nil {
+				return nil, err
+			}
+		}
+		batchCycle.Growth = growthMetric(*batchCycle, *samplings, species, time.Now())
+		return batchCycle, nil
+	}
+}
+
+func (svc *BatchService) StoreGrowthBatchCycle(batchCycle *BatchCycle) (*BatchCycle, error) {
+	//link the seed lot the cycle was stocked from
+	batchCycle.SeedSourceID = uuid.NullUUID{}
+	if batchCycle.SeedSource != nil {
+		if _, err := svc.BatchRepository.ResolveGrowthSeedSourceByID(batchCycle.SeedSource.ID); err != nil {
+			return nil, err
+		}
+		batchCycle.SeedSourceID = uuid.NullUUID{UUID: batchCycle.SeedSource.ID, Valid: true}
+	} else if batchCycle.ID != uuid.Nil && !batchCycle.ClearSeedSource {
+		if stored, err := svc.BatchRepository.ResolveGrowthBatchCycleByID(batchCycle.Batch.ID, batchCycle.ID); err != nil {
+			return nil, err
+		} else {
+			batchCycle.SeedSource = stored.SeedSource
+			batchCycle.SeedSourceID = stored.SeedSourceID
+		}
+	}
+	if batchCycle.ID == uuid.Nil {
+		batchCycle.ID = uuid.Must(uuid.NewV4())
+		if result, err := svc.BatchRepository.InsertGrowthBatchCycle(batchCycle); err != nil {
+			return nil, err
+		} else {
+			return result, nil
+		}
+	} else {
+		//update
+		if result, err := svc.BatchRepository.UpdateGrowthBatchCycleByID(batchCycle); err != nil {
+			return nil, err
+		} else {
+			return result, nil
+		}
+	}
+}
+
+//growth sampling
+func (svc *BatchService) ResolveGrowthSamplingByBatchCycleID(batchId uuid.UUID, cycleId uuid.UUID) (*[]Sampling, error) {
+	if _, err := svc.BatchRepository.ResolveGrowthBatchCycleByID(batchId, cycleId); err != nil {
+		return nil, err
+	} else if samplings, err := svc.BatchRepository.ResolveGrowthSamplingByBatchCycleID(cycleId); err != nil {
+		return nil, err
+	} else {
+		return samplings, nil
+	}
+}
+
+func (svc *BatchService) StoreGrowthSampling(batchId uuid.UUID, sampling *Sampling) (*Sampling, error) {
+	if batchCycle, err := svc.BatchRepository.ResolveGrowthBatchCycleByID(batchId, sampling.BatchCycleID); err != nil {
+		return nil, err
+	} else if sampling.SamplingDate.Before(batchCycle.Start) {
+		return nil, fmt.Errorf("Sampling date cannot be before the cycle start.")
+	}
+	sampling.ID = uuid.Must(uuid.NewV4())
+	if result, err := svc.BatchRepository.InsertGrowthSampling(sampling); err != nil {
+		return nil, err
+	} else {
+		return result, nil
+	}
+}
+
+//growthMetric compares the latest sampling, or the stocking weight when the cycle
+//has not been sampled, with the reference curve and projects the day the
+//target weight is reached by following the curve scaled to the actual growth
+func growthMetric(batchCycle BatchCycle, samplings []Sampling, species *Species, now time.Time) *GrowthMetric {
+	metric := GrowthMetric{}
+	end := now
+	if batchCycle.Finish.Valid {
+		end = batchCycle.Finish.Time
+	}
+	metric.DayOfCulture = int32(dayOfCulture(batchCycle.Start, end))
+
+	//stocking weight is biomass in kg, samples are grams per head
+	var stocked float64
+	if batchCycle.Amount > 0 {
+		stocked = batchCycle.Weight / batchCycle.Amount * 1000
+	}
+	sampledDay := 0.0
+	metric.SampledWeight = stocked
+	if len(samplings) > 0 {
+		latest := samplings[len(samplings)-1]
+		metric.SamplingDate = null.TimeFrom(latest.SamplingDate)
+		metric.SampledWeight = latest.AverageWeight
+		sampledDay = dayOfCulture(batchCycle.Start, latest.SamplingDate)
+	}
+	if species == nil {
+		return &metric
+	}
+	metric.TargetWeight = species.TargetWeight
+	if len(species.Curve) > 0 {
+		metric.ReferenceWeight = referenceWeight(species.Curve, sampledDay)
+		metric.Deviation = metric.SampledWeight - metric.ReferenceWeight
+		if metric.ReferenceWeight > 0 {
+			metric.DeviationPct = metric.Deviation / metric.ReferenceWeight * 100
+		}
+	}
+	if batchCycle.Finish.Valid || species.TargetWeight <= 0 {
+		return &metric
+	}
+	if day, ok := projectTargetDay(species, sampledDay, metric.SampledWeight, stocked); ok {
+		metric.ProjectedHarvest = null.TimeFrom(batchCycle.Start.AddDate(0, 0, int(math.Ceil(day))))
+	}
+	return &metric
+}
+
+//projectTargetDay finds the day of culture the target weight is reached, from
+//a weight observed on a given day
+func projectTargetDay(species *Species, day float64, weight float64, stocked float64) (float64, bool) {
+	if weight >= species.TargetWeight {
+		return day, true
+	}
+	if len(species.Curve) > 0 {
+		reference := referenceWeight(species.Curve, day)
+		if reference <= 0 {
+			return 0, false
+		}
+		ratio := weight / reference
+		for d := math.Floor(day) + 1; d <= day+maxProjectionDays; d++ {
+			if referenceWeight(species.Curve, d)*ratio >= species.TargetWeight {
+				return d, true
+			}
+		}
+		return 0, false
+	}
+	//no reference curve, keep the average daily gain since stocking
+	if day <= 0 || weight <= stocked {
+		return 0, false
+	}
+	adg := (weight - stocked) / day
+	return day + (species.TargetWeight-weight)/adg, true
+}
+
+//maxProjectionDays bounds how far ahead a harvest is projected
+const maxProjectionDays = 730
+
+//referenceWeight interpolates the curve on a day of culture, before the first
+//point it keeps the first weight and past the last one it keeps the last slope
+func referenceWeight(curve []GrowthCurvePoint, day float64) float64 {
+	if len(curve) == 0 {
+		return 0
+	}
+	if len(curve) == 1 || day <= float64(curve[0].Day) {
+		return curve[0].Weight
+	}
+	i := 1
+	for i < len(curve)-1 && day > float64(curve[i].Day) {
+		i++
+	}
+	a, b := curve[i-1], curve[i]
+	slope := (b.Weight - a.Weight) / float64(b.Day-a.Day)
+	return a.Weight + slope*(day-float64(a.Day))
+}
+
+func dayOfCulture(start time.Time, date time.Time) float64 {
+	days := date.Sub(start).Hours() / 24
+	if days < 0 {
+		return 0
+	}
+	return math.Floor(days)
+}
+
+//growth death
+func (svc *BatchService) StoreGrowthDeath(death *Death) (*Death, error) {
+	cause, err := svc.BatchRepository.ResolveGrowthDeathCauseByID(death.Cause.ID)
+	if err != nil {
+		return nil, err
+	} else if cause.Deleted {
+		return nil, fmt.Errorf("Death cause %s is no longer in use.", cause.Name)
+	}
+	death.ID = uuid.Must(uuid.NewV4())
+	death.CauseID = cause.ID
+	if result, err := svc.BatchRepository.InsertGrowthDeath(death); err != nil {
+		return nil, err
+	} else {
+		result.Cause = *cause
+		return result, nil
+	}
+}
+
+//growth death cause
+func (svc *BatchService) ResolveGrowthDeathCause(deleted string) (*[]DeathCause, error) {
+	if causes, err := svc.BatchRepository.ResolveGrowthDeathCause(deleted); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+		return causes, nil
+	}
+}
+
+func (svc *BatchService) ResolveGrowthDeathCauseByID(id uuid.UUID) (*DeathCause, error) {
+	if cause, err := svc.BatchRepository.ResolveGrowthDeathCauseByID(id); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+		return cause, nil
+	}
+}
+
+func (svc *BatchService) StoreGrowthDeathCause(cause *DeathCause) (*DeathCause, error) {
+	if cause.ID == uuid.Nil {
+		cause.ID = uuid.Must(uuid.NewV4())
+		if result, err := svc.BatchRepository.InsertGrowthDeathCause(cause); err != nil {
+			return nil, err
+		} else {
+			return result, nil
+		}
+	} else {
+		//update
+		if result, err := svc.BatchRepository.UpdateGrowthDeathCauseByID(cause); err != nil {
+			return nil, err
+		} else {
+			return result, nil
+		}
+	}
+}
+
+func (svc *BatchService) RemoveGrowthDeathCauseByID(id uuid.UUID) (*DeathCause, error) {
+	if _, err := svc.BatchRepository.RemoveGrowthDeathCauseByID(id); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+		return nil, nil
+	}
+}
+
+//ResolveGrowthMortalityReport aggregates deaths between from and to (inclusive)
+//by cause, pool, batch and week, with the survival curve of every cycle involved
+func (svc *BatchService) ResolveGrowthMortalityReport(from time.Time, to time.Time, batchId uuid.UUID, poolId uuid.UUID) (*MortalityReport, error) {
+	until := to.AddDate(0, 0, 1)
+	records, err := svc.BatchRepository.ResolveGrowthMortality(from, until, batchId, poolId)
+	if err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	}
+
+	report := MortalityReport{From: from, To: to}
+	causes := make(map[uuid.UUID]*MortalityGroup)
+	pools := make(map[uuid.UUID]*MortalityGroup)
+	batches := make(map[uuid.UUID]*MortalityGroup)
+	weeks := make(map[time.Time]*MortalityWeek)
+	curves := make(map[uuid.UUID]*SurvivalCurve)
+	var order []uuid.UUID
+	for _, record := range *records {
+		//survival is accumulated over the whole cycle, not only the period
+		curve, ok := curves[record.BatchCycleID]
+		if !ok {
+			curve = &SurvivalCurve{
+				BatchCycleID: record.BatchCycleID,
+				Batch:        MortalityGroup{ID: record.BatchID, Name: record.BatchName},
+				Pool:         MortalityGroup{ID: record.PoolID, Name: record.PoolName},
+				Start:        record.Start,
+				Stocked:      record.Stocked,
+				Points:       []SurvivalPoint{{Date: record.Start, Alive: record.Stocked, SR: 100}},
+			}
+			curves[record.BatchCycleID] = curve
+			order = append(order, record.BatchCycleID)
+		}
+		last := curve.Points[len(curve.Points)-1]
+		dead := last.Dead + record.Amount
+		point := SurvivalPoint{Date: record.DeathDate, Dead: dead, Alive: curve.Stocked - dead}
+		if curve.Stocked > 0 {
+			point.SR = point.Alive / curve.Stocked * 100
+		}
+		if sameDay(last.Date, record.DeathDate) && len(curve.Points) > 1 {
+			curve.Points[len(curve.Points)-1] = point
+		} else {
+			curve.Points = append(curve.Points, point)
+		}
+
+		if record.DeathDate.Before(from) {
+			continue
+		}
+		report.Amount = report.Amount + record.Amount
+		report.Weight = report.Weight + record.Weight
+		addMortality(causes, record.CauseID, record.CauseName, record)
+		addMortality(pools, record.PoolID, record.PoolName, record)
+		addMortality(batches, record.BatchID, record.BatchName, record)
+		week := weekStart(record.DeathDate)
+		if _, ok := weeks[week]; !ok {
+			weeks[week] = &MortalityWeek{Week: week}
+		}
+		weeks[week].Amount = weeks[week].Amount + record.Amount
+		weeks[week].Weight = weeks[week].Weight + record.Weight
+	}
+
+	report.ByCause = sortMortality(causes)
+	report.ByPool = sortMortality(pools)
+	report.ByBatch = sortMortality(batches)
+	report.ByWeek = make([]MortalityWeek, 0)
+	for _, week := range weeks {
+		report.ByWeek = append(report.ByWeek, *week)
+	}
+	sort.Slice(report.ByWeek, func(i, j int) bool {
+		return report.ByWeek[i].Week.Before(report.ByWeek[j].Week)
+	})
+	report.Survival = make([]SurvivalCurve, 0)
+	for _, id := range order {
+		report.Survival = append(report.Survival, *curves[id])
+	}
+	sort.SliceStable(report.Survival, func(i, j int) bool {
+		return report.Survival[i].Start.Before(report.Survival[j].Start)
+	})
+	return &report, nil
+}
+
+func addMortality(groups map[uuid.UUID]*MortalityGroup, id uuid.UUID, name string, record MortalityRecord) {
+	if _, ok := groups[id]; !ok {
+		groups[id] = &MortalityGroup{ID: id, Name: name}
+	}
+	groups[id].Amount = groups[id].Amount + record.Amount
+	groups[id].Weight = groups[id].Weight + record.Weight
+}
+
+//sortMortality lists the groups with the biggest loss first
+func sortMortality(groups map[uuid.UUID]*MortalityGroup) []MortalityGroup {
+	result := make([]MortalityGroup, 0)
+	for _, group := range groups {
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Amount == result[j].Amount {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].Amount > result[j].Amount
+	})
+	return result
+}
+
+//weekStart returns the monday the given date belongs to
+func weekStart(date time.Time) time.Time {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+func sameDay(a time.Time, b time.Time) bool {
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
+
+//growth feeding
+func (svc *BatchService) StoreGrowthFeeding(feeding *Feeding) (*Feeding, error) {
+	feeding.ID = uuid.Must(uuid.NewV4())
+	//feeding is stored in the base unit so FCR always comes out in kg
+	if qty, err := svc.FeedService.ConvertToBaseUnit(feeding.FeedType.ID, feeding.Qty, feeding.Unit); err != nil {
+		return nil, err
+	} else {
+		feeding.Qty = qty
+		feeding.Unit = feed.Unit_Base
+	}
+	//lots are planned and taken off in the transaction storing the feeding
+	if result, err := svc.BatchRepository.InsertGrowthFeeding(feeding); err != nil {
+		return nil, err
+	} else if feedtype, err := svc.FeedService.ResolveFeedTypeByID(result.FeedTypeID); err != nil {
+		return nil, err
+	} else if costs, err := svc.FeedService.ResolveFeedTypeCostByFeedTypeIDs([]uuid.UUID{result.FeedTypeID}); err != nil {
+		return nil, err
+	} else {
+		result.FeedType = *feedtype
+		result.UnitCost = feed.FeedTypeCostAt(*costs, result.FeedTypeID, result.FeedingDate)
+		return result, nil
+	}
+}
+
+//growth cut off
+func (svc *BatchService) StoreGrowthCutOff(cutoff *CutOff) (*CutOff, error) {
+	//validate cutoff existed
+	if cutoffs, error := svc.BatchRepository.ResolveGrowthSummaryByBatchCycleID(cutoff.BatchCycleID); error != nil {
+		return nil, error
+	} else if cutoffs != nil {
+		return nil, fmt.Errorf("You cannot cutoff this cycle, cutoff existed.")
+	}
+
+	//get batch cycle and feeding data
+	if batchCycle, error := svc.BatchRepository.ResolveGrowthBatchCycleByID(cutoff.BatchID, cutoff.BatchCycleID); error != nil {
+		return nil, error
+	} else if feedings, err := svc.BatchRepository.ResolveGrowthFeedingByBatchCycleID(cutoff.BatchCycleID); err != nil {
+		return nil, err
+	} else if err := svc.validateWithdrawal(cutoff.BatchCycleID, cutoff.SummaryDate); err != nil {
+		return nil, err
+	} else {
+		//calculate ADG
+		days := cutoff.SummaryDate.Sub(batchCycle.Start).Hours() / 24
+		cutoff.ADG = (cutoff.Weight - batchCycle.Weight) / days
+
+		//calculate FCR, feedings are stored in kg like the biomass weight
+		var total float64
+		for _, feeding := range *feedings {
+			total = total + feeding.Qty
+		}
+		cutoff.FCR = total / (cutoff.Weight - batchCycle.Weight)
+
+		//calculate SR
+		cutoff.SR = (cutoff.Amount / batchCycle.Amount) * 100
+
+		//update cycle finish date on batch cycle then insert growth summary
+		batchCycle.Finish = null.TimeFrom(cutoff.SummaryDate)
+		cutoff.ID = uuid.Must(uuid.NewV4())
+		summary, err := svc.BatchRepository.UpdateGrowthBatchCycleAndInsertGrowthSummaryTransaction(batchCycle, cutoff)
+		if err != nil {
+			return nil, err
+		} else {
+			return summary, nil
+		}
+
+	}
+}
+
+//growth sales
+func (svc *BatchService) ResolveGrowthSalesByID(salesId uuid.UUID) (*Sales, error) {
+	if result, err := svc.BatchRepository.ResolveGrowthSalesByID(salesId); err != nil {
+		return nil, err
+	} else {
+		return result, nil
+	}
+}
+func (svc *BatchService) StoreGrowthSales(sales *Sales) (*Sales, error) {
+	if sales.ID == uuid.Nil {
+		sales.ID = uuid.Must(uuid.NewV4())
+		if sales, err := svc.BatchRepository.InsertGrowthSales(sales); err != nil {
+			return nil, err
+		} else {
+			return sales, nil
+		}
+	} else {
+		if sales, err := svc.BatchRepository.UpdateGrowthSalesByID(sales); err != nil {
+			return nil, err
+		} else {
+			return sales, nil
+		}
+	}
+}
+
+func (svc *BatchService) StoreGrowthSalesDetail(sales *Sales) (*Sales, error) {
+	//set sales id and create cutoff
+	cutoffs := make([]CutOff, 0)
+	batchCycles := make([]BatchCycle, 0)
+	salesDetail := make([]SalesDetail, 0)
+	salesDate := sales.SalesDate
+	if salesDate.IsZero() {
+		salesDate = time.Now()
+	}
+	for _, detail := range sales.Detail {
+		//a cycle under medication cannot be sold before its withdrawal period ends
+		if err := svc.validateWithdrawal(detail.BatchCycleID, salesDate); err != nil {
+			return nil, err
+		}
+		detail.ID = uuid.Must(uuid.NewV4())
+		detail.SalesID = sales.ID
+		salesDetail = append(salesDetail, detail)
+		var cutoff CutOff
+		if batchCycle, error := svc.BatchRepository.ResolveGrowthBatchCycleByID(detail.BatchID, detail.BatchCycleID); error != nil {
+			return nil, error
+		} else if feedings, err := svc.BatchRepository.ResolveGrowthFeedingByBatchCycleID(detail.BatchCycleID); err != nil {
+			return nil, err
+		} else {
+			//the sold harvest is the cut off of the cycle
+			cutoff.ID = uuid.Must(uuid.NewV4())
+			cutoff.BatchCycleID = detail.BatchCycleID
+			cutoff.BatchID = detail.BatchID
+			cutoff.Weight = detail.Weight
+			cutoff.Amount = detail.Amount
+			cutoff.SummaryDate = salesDate
+
+			//calculate ADG
+			days := cutoff.SummaryDate.Sub(batchCycle.Start).Hours() / 24
+			cutoff.ADG = (cutoff.Weight - batchCycle.Weight) / days
+
+			//calculate FCR
+			var total float64
+			for _, feeding := range *feedings {
+				total = total + feeding.Qty
+			}
+			cutoff.FCR = total / (cutoff.Weight - batchCycle.Weight)
+
+			//calculate SR
+			cutoff.SR = (cutoff.Amount / batchCycle.Amount) * 100
+
+			//set cycle finish date on batch cycle then insert growth summary
+			batchCycle.Finish = null.TimeFrom(salesDate)
+			batchCycles = append(batchCycles, *batchCycle)
+			cutoffs = append(cutoffs, cutoff)
+		}
+	}
+	sales.Detail = salesDetail
+
+	if result, err := svc.BatchRepository.UpdateGrowthBatchCycleInsertGrowthSummaryAndInsertSalesDetail(&batchCycles, &cutoffs, sales); err != nil {
+		return nil, err
+	} else {
+		return result, nil
+	}
+}
+
+//growth treatment
+func (svc *BatchService) ResolveGrowthTreatmentByBatchCycleID(batchId uuid.UUID, cycleId uuid.UUID) (*[]Treatment, error) {
+	if _, err := svc.BatchRepository.ResolveGrowthBatchCycleByID(batchId, cycleId); err != nil {
+		return nil, err
+	} else if treatments, err := svc.BatchRepository.ResolveGrowthTreatmentByBatchCycleID(cycleId); err != nil {
+		return nil, err
+	} else {
+		for i := range *treatments {
+			(*treatments)[i].WithdrawalUntil = withdrawalUntil((*treatments)[i])
+		}
+		return treatments, nil
+	}
+}
+
+func (svc *BatchService) StoreGrowthTreatment(batchId uuid.UUID, treatment *Treatment) (*Treatment, error) {
+	if _, err := svc.BatchRepository.ResolveGrowthBatchCycleByID(batchId, treatment.BatchCycleID); err != nil {
+		return nil, err
+	}
+	valid := false
+	for _, method := range TreatmentMethods {
+		if treatment.Method == method {
+			valid = true
+		}
+	}
+	if !valid {
+		return nil, fmt.Errorf("Unknown treatment method %s.", treatment.Method)
+	} else if treatment.WithdrawalDays < 0 {
+		return nil, fmt.Errorf("Withdrawal days cannot be negative.")
+	} else if treatment.EndDate.Valid && treatment.EndDate.Time.Before(treatment.StartDate) {
+		return nil, fmt.Errorf("Treatment end date cannot be before its start date.")
+	}
+
+	var result *Treatment
+	var err error
+	if treatment.ID == uuid.Nil {
+		treatment.ID = uuid.Must(uuid.NewV4())
+		result, err = svc.BatchRepository.InsertGrowthTreatment(treatment)
+	} else if current, e := svc.BatchRepository.ResolveGrowthTreatmentByID(treatment.ID); e != nil {
+		return nil, e
+	} else if current.BatchCycleID != treatment.BatchCycleID {
+		return nil, fmt.Errorf("Inconsistent cycle id.")
+	} else {
+		result, err = svc.BatchRepository.UpdateGrowthTreatmentByID(treatment)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.WithdrawalUntil = withdrawalUntil(*result)
+	return result, nil
+}
+
+//StoreGrowthTreatmentOverride records who allowed a cycle to be harvested
+//while the treatment withdrawal period is still running
+func (svc *BatchService) StoreGrowthTreatmentOverride(batchId uuid.UUID, override *TreatmentOverride) (*TreatmentOverride, error) {
+	if _, err := svc.BatchRepository.ResolveGrowthBatchCycleByID(batchId, override.BatchCycleID); err != nil {
+		return nil, err
+	} else if treatment, err := svc.BatchRepository.ResolveGrowthTreatmentByID(override.TreatmentID); err != nil {
+		return nil, err
+	} else if treatment.BatchCycleID != override.BatchCycleID {
+		return nil, fmt.Errorf("Inconsistent cycle id.")
+	}
+	override.ID = uuid.Must(uuid.NewV4())
+	if result, err := svc.BatchRepository.InsertGrowthTreatmentOverride(override); err != nil {
+		return nil, err
+	} else {
+		return result, nil
+	}
+}
+
+//validateWithdrawal refuses a harvest on the given date while any treatment
+//without an override is still inside its withdrawal period
+func (svc *BatchService) validateWithdrawal(cycleId uuid.UUID, date time.Time) error {
+	treatments, err := svc.BatchRepository.ResolveGrowthTreatmentByBatchCycleID(cycleId)
+	if err != nil {
+		return err
+	}
+	if len(*treatments) < 1 {
+		return nil
+	}
+	overrides, err := svc.BatchRepository.ResolveGrowthTreatmentOverrideByBatchCycleID(cycleId)
+	if err != nil {
+		return err
+	}
+	for _, treatment := range *treatments {
+		if treatment.StartDate.After(date) {
+			continue
+		}
+		until := withdrawalUntil(treatment)
+		if until.Valid && !date.Before(until.Time) {
+			continue
+		}
+		overridden := false
+		for _, override := range *overrides {
+			if override.TreatmentID == treatment.ID {
+				overridden = true
+			}
+		}
+		if overridden {
+			continue
+		}
+		if !until.Valid {
+			return fmt.Errorf("Cycle %s is still under treatment with %s, record an override to harvest it.", cycleId, treatment.Product)
+		}
+		return fmt.Errorf("Cycle %s is inside the withdrawal period of %s until %s, record an override to harvest it.", cycleId, treatment.Product, until.Time.Format("2006-01-02"))
+	}
+	return nil
+}
+
+//withdrawalUntil is the end of the withdrawal period, null while the treatment is still running
+func withdrawalUntil(treatment Treatment) null.Time {
+	if !treatment.EndDate.Valid {
+		return null.Time{}
+	}
+	return null.TimeFrom(treatment.EndDate.Time.AddDate(0, 0, int(treatment.WithdrawalDays)))
+}
+
+//ResolveGrowthSalesTraceBySalesID traces every detail of a sales back to
+//its cycle, batch, pool and seed source
+func (svc *BatchService) ResolveGrowthSalesTraceBySalesID(salesId uuid.UUID) (*[]SalesTrace, error) {
+	if _, err := svc.BatchRepository.ResolveGrowthSalesByID(salesId); err != nil {
+		return nil, err
+	}
+	traces, err := svc.BatchRepository.ResolveGrowthSalesTraceBySalesID(salesId)
+	if err != nil {
+		return nil, err
+	}
+	batches := make(map[uuid.UUID]*Batch)
+	pools := make(map[uuid.UUID]*Pool)
+	seedSources := make(map[uuid.UUID]*SeedSource)
+	for i, trace := range *traces {
+		if _, ok := batches[trace.BatchID]; !ok {
+			if batches[trace.BatchID], err = svc.BatchRepository.ResolveGrowthBatchByID(trace.BatchID); err != nil {
+				return nil, err
+			}
+		}
+		if _, ok := pools[trace.PoolID]; !ok {
+			if pools[trace.PoolID], err = svc.BatchRepository.ResolveGrowthPoolByID(trace.PoolID); err != nil {
+				return nil, err
+			}
+		}
+		(*traces)[i].Batch = *batches[trace.BatchID]
+		(*traces)[i].Pool = *pools[trace.PoolID]
+		if trace.SeedSourceID.Valid {
+			if _, ok := seedSources[trace.SeedSourceID.UUID]; !ok {
+				if seedSources[trace.SeedSourceID.UUID], err = svc.BatchRepository.ResolveGrowthSeedSourceByID(trace.SeedSourceID.UUID); err != nil {
+					return nil, err
+				}
+			}
+			(*traces)[i].SeedSource = seedSources[trace.SeedSourceID.UUID]
+		}
+	}
+	return traces, nil
+}
+
+//growth cost
+func (svc *BatchService) ResolveGrowthCostByBatchCycleID(batchId uuid.UUID, cycleId uuid.UUID) (*[]Cost, error) {
+	if _, err := svc.BatchRepository.ResolveGrowthBatchCycleByID(batchId, cycleId); err != nil {
+		return nil, err
+	} else if costs, err := svc.BatchRepository.ResolveGrowthCostByBatchCycleID(cycleId); err != nil {
+		return nil, err
+	} else {
+		return costs, nil
+	}
+}
+
+func (svc *BatchService) StoreGrowthCost(batchId uuid.UUID, cost *Cost) (*Cost, error) {
+	if _, err := svc.BatchRepository.ResolveGrowthBatchCycleByID(batchId, cost.BatchCycleID); err != nil {
+		return nil, err
+	}
+	cost.ID = uuid.Must(uuid.NewV4())
+	if result, err := svc.BatchRepository.InsertGrowthCost(cost); err != nil {
+		return nil, err
+	} else {
+		return result, nil
+	}
+}
+
+//growth profit and loss
+func (svc *BatchService) ResolveGrowthBatchCycleProfitAndLoss(batchId uuid.UUID, cycleId uuid.UUID) (*ProfitAndLoss, error) {
+	batchCycle, err := svc.BatchRepository.ResolveGrowthBatchCycleByID(batchId, cycleId)
+	if err != nil {
+		return nil, err
+	}
+	costs, err := svc.BatchRepository.ResolveGrowthCostByBatchCycleID(cycleId)
+	if err != nil {
+		return nil, err
+	}
+	details, err := svc.BatchRepository.ResolveGrowthSalesDetailByBatchCycleID(cycleId)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uuid.UUID
+	for _, feeding := range batchCycle.Feeding {
+		ids = append(ids, feeding.FeedTypeID)
+	}
+	feedTypes, err := svc.FeedService.ResolveFeedTypeByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	feedTypeCosts, err := svc.FeedService.ResolveFeedTypeCostByFeedTypeIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	//value every feeding at the average cost in effect on its feeding date
+	consumed := make(map[uuid.UUID]float64)
+	valued := make(map[uuid.UUID]float64)
+	for _, feeding := range batchCycle.Feeding {
+		consumed[feeding.FeedTypeID] = consumed[feeding.FeedTypeID] + feeding.Qty
+		valued[feeding.FeedTypeID] = valued[feeding.FeedTypeID] + (feeding.Qty * feed.FeedTypeCostAt(*feedTypeCosts, feeding.FeedTypeID, feeding.FeedingDate))
+	}
+
+	pnl := ProfitAndLoss{
+		BatchCycleID: batchCycle.ID,
+		Feed:         make([]FeedCost, 0),
+		Costs:        *costs,
+		SeedCost:     batchCycle.SeedCost,
+	}
+	for _, feedType := range *feedTypes {
+		feedCost := FeedCost{
+			FeedType: feedType,
+			Qty:      consumed[feedType.ID],
+			Amount:   valued[feedType.ID],
+		}
+		if feedCost.Qty != 0 {
+			feedCost.UnitPrice = feedCost.Amount / feedCost.Qty
+		}
+		feedCost.ProteinIntake = feed.ProteinIntake(feedType, feedCost.Qty)
+		pnl.FeedCost = pnl.FeedCost + feedCost.Amount
+		pnl.ProteinIntake = pnl.ProteinIntake + feedCost.ProteinIntake
+		pnl.Feed = append(pnl.Feed, feedCost)
+	}
+	for _, cost := range *costs {
+		pnl.OtherCost = pnl.OtherCost + cost.Amount
+	}
+	for _, detail := range *details {
+		pnl.HarvestWeight = pnl.HarvestWeight + detail.Weight
+		pnl.Revenue = pnl.Revenue + (detail.Weight * detail.Price)
+	}
+
+	pnl.TotalCost = pnl.FeedCost + pnl.SeedCost + pnl.OtherCost
+	pnl.GrossMargin = pnl.Revenue - pnl.TotalCost
+	if pnl.HarvestWeight > 0 {
+		pnl.CostPerKg = pnl.TotalCost / pnl.HarvestWeight
+	}
+	if pnl.Revenue > 0 {
+		pnl.GrossMarginPct = (pnl.GrossMargin / pnl.Revenue) * 100
+	}
+	if pnl.TotalCost > 0 {
+		pnl.ROI = (pnl.GrossMargin / pnl.TotalCost) * 100
+	}
+	return &pnl, nil
+}
+
+//growth forecast
+func (svc *BatchService) ResolveGrowthForecast(from time.Time, to time.Time) (*Forecast, error) {
+	batchCycles, err := svc.BatchRepository.ResolveGrowthOpenBatchCycle()
+	if err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	}
+
+	//one projection per week, taken on the last day of the week within the period
+	var dates []time.Time
+	for week := weekStart(from); !week.After(to); week = week.AddDate(0, 0, 7) {
+		date := week.AddDate(0, 0, 6)
+		if date.After(to) {
+			date = to
+		}
+		dates = append(dates, date)
+	}
+
+	now := time.Now()
+	forecast := Forecast{From: from, To: to, Weeks: forecastWeeks(dates), Pools: make([]ForecastPool, 0), Cycles: make([]CycleForecast, 0)}
+	pools := make(map[uuid.UUID]int)
+	species := make(map[uuid.UUID]*Species)
+	for _, batchCycle := range *batchCycles {
+		samplings, err := svc.BatchRepository.ResolveGrowthSamplingByBatchCycleID(batchCycle.ID)
+		if err != nil {
+			return nil, err
+		}
+		sales, err := svc.BatchRepository.ResolveGrowthSalesDetailByBatchCycleID(batchCycle.ID)
+		if err != nil {
+			return nil, err
+		}
+		if batchCycle.Batch.SpeciesID.Valid {
+			id := batchCycle.Batch.SpeciesID.UUID
+			if _, ok := species[id]; !ok {
+				if species[id], err = svc.BatchRepository.ResolveGrowthSpeciesByID(id); err != nil {
+					return nil, err
+				}
+			}
+			batchCycle.Batch.Species = species[id]
+		}
+
+		projection := newCycleProjection(batchCycle, *samplings, *sales, now)
+		cycle := CycleForecast{
+			BatchCycleID:  batchCycle.ID,
+			Batch:         batchCycle.Batch,
+			Pool:          batchCycle.Pool,
+			Start:         batchCycle.Start,
+			Alive:         projection.alive,
+			DailySurvival: projection.survival,
+			Method:        projection.method,
+			Weeks:         forecastWeeks(dates),
+		}
+		if projection.species != nil {
+			cycle.TargetWeight = projection.species.TargetWeight
+		}
+		if _, ok := pools[batchCycle.PoolID]; !ok {
+			pools[batchCycle.PoolID] = len(forecast.Pools)
+			forecast.Pools = append(forecast.Pools, ForecastPool{Pool: batchCycle.Pool, Weeks: forecastWeeks(dates)})
+		}
+		pool := &forecast.Pools[pools[batchCycle.PoolID]]
+		for i, date := range dates {
+			amount := projection.amount(date)
+			weight := projection.weight(date)
+			week := &cycle.Weeks[i]
+			week.Amount = amount
+			week.Weight = weight
+			week.Biomass = amount * weight / 1000
+			if projection.species != nil && projection.species.TargetWeight > 0 && weight >= projection.species.TargetWeight {
+				week.Cycles = 1
+				week.Harvestable = week.Biomass
+			}
+			addForecastWeek(&pool.Weeks[i], *week)
+			addForecastWeek(&forecast.Weeks[i], *week)
+		}
+		forecast.Cycles = append(forecast.Cycles, cycle)
+	}
+	return &forecast, nil
+}
+
+func forecastWeeks(dates []time.Time) []ForecastWeek {
+	weeks := make([]ForecastWeek, len(dates))
+	for i, date := range dates {
+		weeks[i] = ForecastWeek{Week: weekStart(date), Date: date}
+	}
+	return weeks
+}
+
+//addForecastWeek sums a cycle week into a total, weight becomes the average
+//weight of the summed heads
+func addForecastWeek(total *ForecastWeek, week ForecastWeek) {
+	total.Cycles = total.Cycles + week.Cycles
+	total.Amount = total.Amount + week.Amount
+	total.Biomass = total.Biomass + week.Biomass
+	total.Harvestable = total.Harvestable + week.Harvestable
+	if total.Amount > 0 {
+		total.Weight = total.Biomass / total.Amount * 1000
+	}
+}
+
+//cycleProjection projects a cycle population with the daily survival observed
+//since stocking and its weight with the species curve or the latest ADG
+type cycleProjection struct {
+	start         time.Time
+	observed      time.Time
+	alive         float64
+	survival      float64
+	species       *Species
+	method        string
+	sampledDay    float64
+	sampledWeight float64
+	adg           float64
+}
+
+func newCycleProjection(batchCycle BatchCycle, samplings []Sampling, sales []SalesDetail, now time.Time) cycleProjection {
+	projection := cycleProjection{
+		start:    batchCycle.Start,
+		observed: now,
+		survival: 1,
+		species:  batchCycle.Batch.Species,
+		method:   Forecast_Method_None,
+	}
+
+	//sold heads leave the pool but are not mortality
+	var dead, sold float64
+	for _, death := range batchCycle.Deaths {
+		dead = dead + death.Amount
+	}
+	for _, detail := range sales {
+		sold = sold + detail.Amount
+	}
+	projection.alive = math.Max(batchCycle.Amount-dead-sold, 0)
+	if days := dayOfCulture(batchCycle.Start, now); days >= 1 && batchCycle.Amount > 0 && dead < batchCycle.Amount {
+		projection.survival = math.Pow((batchCycle.Amount-dead)/batchCycle.Amount, 1/days)
+	}
+
+	//stocking weight is biomass in kg, samples are grams per head
+	var stocked float64
+	if batchCycle.Amount > 0 {
+		stocked = batchCycle.Weight / batchCycle.Amount * 1000
+	}
+	projection.sampledWeight = stocked
+	if len(samplings) > 0 {
+		latest := samplings[len(samplings)-1]
+		projection.sampledDay = dayOfCulture(batchCycle.Start, latest.SamplingDate)
+		projection.sampledWeight = latest.AverageWeight
+	}
+
+	if projection.species != nil && len(projection.species.Curve) > 0 && referenceWeight(projection.species.Curve, projection.sampledDay) > 0 {
+		projection.method = Forecast_Method_Curve
+		return projection
+	}
+	//ADG trend between the last two samplings, or since stocking with one sampling
+	previousDay, previousWeight := 0.0, stocked
+	if len(samplings) > 1 {
+		previous := samplings[len(samplings)-2]
+		previousDay = dayOfCulture(batchCycle.Start, previous.SamplingDate)
+		previousWeight = previous.AverageWeight
+	}
+	if len(samplings) > 0 && projection.sampledDay > previousDay && projection.sampledWeight > previousWeight {
+		projection.method = Forecast_Method_ADG
+		projection.adg = (projection.sampledWeight - previousWeight) / (projection.sampledDay - previousDay)
+	}
+	return projection
+}
+
+//amount is the heads expected alive on a date
+func (p cycleProjection) amount(date time.Time) float64 {
+	days := date.Sub(p.observed).Hours() / 24
+	if days <= 0 {
+		return p.alive
+	}
+	return p.alive * math.Pow(p.survival, days)
+}
+
+//weight is the average grams per head expected on a date
+func (p cycleProjection) weight(date time.Time) float64 {
+	day := dayOfCulture(p.start, date)
+	if day < p.sampledDay {
+		return p.sampledWeight
+	}
+	switch p.method {
+	case Forecast_Method_Curve:
+		return referenceWeight(p.species.Curve, day) * p.sampledWeight / referenceWeight(p.species.Curve, p.sampledDay)
+	case Forecast_Method_ADG:
+		return p.sampledWeight + p.adg*(day-p.sampledDay)
+	default:
+		return p.sampledWeight
+	}
+}
+
+//growth cycle report, cycles are ranked against every closed cycle of the same
+//pool and species so a filter does not change the ranking
+func (svc *BatchService) ResolveGrowthCycleReport(from time.Time, to time.Time, batchId uuid.UUID) (*[]CycleReport, error) {
+	reports, err := svc.BatchRepository.ResolveGrowthCycleReport()
+	if err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	}
+
+	pools := make(map[uuid.UUID][]*CycleReport)
+	species := make(map[uuid.UUID][]*CycleReport)
+	for i := range *reports {
+		report := &(*reports)[i]
+		report.DayOfCulture = int32(dayOfCulture(report.Start, report.SummaryDate))
+		if report.PoolArea > 0 {
+			report.StockingDensity = report.Stocked / report.PoolArea
+		}
+		pools[report.PoolID] = append(pools[report.PoolID], report)
+		if report.SpeciesID.Valid {
+			species[report.SpeciesID.UUID] = append(species[report.SpeciesID.UUID], report)
+		}
+	}
+	for _, group := range pools {
+		rankCycles(group, func(r *CycleReport) *CycleBenchmark { return &r.PoolRank })
+	}
+	for _, group := range species {
+		rankCycles(group, func(r *CycleReport) *CycleBenchmark { return &r.SpeciesRank })
+	}
+
+	until := to.AddDate(0, 0, 1)
+	result := make([]CycleReport, 0)
+	for _, report := range *reports {
+		if report.SummaryDate.Before(from) || !report.SummaryDate.Before(until) {
+			continue
+		} else if batchId != uuid.Nil && report.BatchID != batchId {
+			continue
+		}
+		result = append(result, report)
+	}
+	return &result, nil
+}
+
+//rankCycles ranks a group on ADG and SR descending and FCR ascending, equal
+//values share the same rank
+func rankCycles(group []*CycleReport, benchmark func(*CycleReport) *CycleBenchmark) {
+	rank := func(value func(*CycleReport) float64, higherFirst bool, target func(*CycleBenchmark) *Benchmark) {
+		for _, report := range group {
+			position := int32(1)
+			for _, other := range group {
+				if (higherFirst && value(other) > value(report)) || (!higherFirst && value(other) < value(report)) {
+					position++
+				}
+			}
+			result := target(benchmark(report))
+			result.Rank = position
+			result.Of = int32(len(group))
+			result.Percentile = 100
+			if len(group) > 1 {
+				result.Percentile = float64(len(group)-int(position)) / float64(len(group)-1) * 100
+			}
+		}
+	}
+	rank(func(r *CycleReport) float64 { return r.ADG }, true, func(b *CycleBenchmark) *Benchmark { return &b.ADG })
+	rank(func(r *CycleReport) float64 { return r.FCR }, false, func(b *CycleBenchmark) *Benchmark { return &b.FCR })
+	rank(func(r *CycleReport) float64 { return r.SR }, true, func(b *CycleBenchmark) *Benchmark { return &b.SR })
+}
+
+//growth sales
+func (svc *BatchService) ResolveGrowthSales(from time.Time, to time.Time) (*[]Sales, error) {
+	if sales, err := svc.BatchRepository.ResolveGrowthSales(from, to.AddDate(0, 0, 1)); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+		return sales, nil
+	}
+}
+
+//export
+func (svc *BatchService) StreamGrowthBatch(deleted string, fn func(*Batch) error) error {
+	return svc.BatchRepository.StreamGrowthBatch(deleted, fn)
+}
+
+func (svc *BatchService) StreamGrowthPool(deleted string, fn func(*Pool) error) error {
+	return svc.BatchRepository.StreamGrowthPool(deleted, fn)
+}
+
+func (svc *BatchService) StreamGrowthBatchCycle(batchId uuid.UUID, fn func(*BatchCycle) error) error {
+	if _, err := svc.BatchRepository.ResolveGrowthBatchByID(batchId); err != nil {
+		return err
+	}
+	return svc.BatchRepository.StreamGrowthBatchCycle(batchId, fn)
+}
+
+func (svc *BatchService) StreamGrowthSalesDetail(from time.Time, to time.Time, fn func(*Sales, *SalesDetail) error) error {
+	return svc.BatchRepository.StreamGrowthSalesDetail(from, to.AddDate(0, 0, 1), fn)
+}
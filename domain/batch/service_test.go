@@ -0,0 +1,235 @@
+package batch
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/livestockz/api/domain/feed"
+	uuid "github.com/satori/go.uuid"
+)
+
+type testFarm struct {
+	svc      *BatchService
+	repo     *MemoryRepository
+	feedtype *feed.FeedType
+	cycle    *BatchCycle
+}
+
+//newTestFarm stocks 1000 heads weighing 10 kg in total on the first of january
+func newTestFarm(t *testing.T) *testFarm {
+	feedRepo := new(feed.MemoryRepository)
+	repo := &MemoryRepository{Feed: feedRepo}
+	svc := &BatchService{BatchRepository: repo, FeedService: &feed.FeedService{FeedRepository: feedRepo}}
+	feedtype, err := feedRepo.InsertFeedType(&feed.FeedType{ID: uuid.Must(uuid.NewV4()), Name: "Pellet", Unit: "kg", Status: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := feedRepo.InsertFeedTypeUnit(&feed.FeedTypeUnit{ID: uuid.Must(uuid.NewV4()), FeedTypeID: feedtype.ID, Unit: "sack", Factor: 25}); err != nil {
+		t.Fatal(err)
+	}
+	batch, err := repo.InsertGrowthBatch(&Batch{ID: uuid.Must(uuid.NewV4()), Name: "Batch 1", Status: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool, err := repo.InsertGrowthPool(&Pool{ID: uuid.Must(uuid.NewV4()), Name: "Pool 1", Status: "active"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cycle, err := repo.InsertGrowthBatchCycle(&BatchCycle{
+		ID:      uuid.Must(uuid.NewV4()),
+		Batch:   *batch,
+		BatchID: batch.ID,
+		Pool:    *pool,
+		Weight:  10,
+		Amount:  1000,
+		Start:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testFarm{svc: svc, repo: repo, feedtype: feedtype, cycle: cycle}
+}
+
+//feed receives 45 kg and gives them to the cycle, a 25 kg sack and 20000 g
+func (farm *testFarm) feed(t *testing.T) {
+	if _, err := farm.svc.FeedService.StoreFeedIncoming(&feed.FeedIncoming{FeedType: *farm.feedtype, IncomingDate: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), Qty: 45}); err != nil {
+		t.Fatal(err)
+	}
+	for _, feeding := range []Feeding{{Qty: 1, Unit: "sack"}, {Qty: 20000, Unit: "g"}} {
+		feeding.BatchCycleID = farm.cycle.ID
+		feeding.FeedType = *farm.feedtype
+		feeding.FeedingDate = time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+		if _, err := farm.svc.StoreGrowthFeeding(&feeding); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func almostEqual(a float64, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+//assertHarvest checks a harvest of 900 heads weighing 40 kg 60 days after stocking
+func assertHarvest(t *testing.T, cutoff *CutOff) {
+	if !almostEqual(cutoff.ADG, 0.5) || !almostEqual(cutoff.FCR, 1.5) || !almostEqual(cutoff.SR, 90) {
+		t.Fatalf("got adg %v, fcr %v and sr %v", cutoff.ADG, cutoff.FCR, cutoff.SR)
+	}
+}
+
+func TestStoreGrowthCutOff(t *testing.T) {
+	farm := newTestFarm(t)
+	farm.feed(t)
+	summaryDate := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	cutoff := &CutOff{BatchID: farm.cycle.BatchID, BatchCycleID: farm.cycle.ID, SummaryDate: summaryDate, Weight: 40, Amount: 900}
+	result, err := farm.svc.StoreGrowthCutOff(cutoff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertHarvest(t, result)
+	if cycle, err := farm.repo.ResolveGrowthBatchCycleByID(farm.cycle.BatchID, farm.cycle.ID); err != nil {
+		t.Fatal(err)
+	} else if !cycle.Finish.Valid || !cycle.Finish.Time.Equal(summaryDate) || cycle.CutOff.ID != result.ID {
+		t.Fatalf("cycle finished %v with cut off %s", cycle.Finish, cycle.CutOff.ID)
+	}
+	if _, err := farm.svc.StoreGrowthCutOff(&CutOff{BatchID: farm.cycle.BatchID, BatchCycleID: farm.cycle.ID, SummaryDate: summaryDate, Weight: 40, Amount: 900}); err == nil {
+		t.Fatal("a cycle was cut off twice")
+	}
+}
+
+func TestStoreGrowthCutOffUnknownCycle(t *testing.T) {
+	farm := newTestFarm(t)
+	cycleId := uuid.Must(uuid.NewV4())
+	if _, err := farm.svc.StoreGrowthCutOff(&CutOff{BatchID: farm.cycle.BatchID, BatchCycleID: cycleId, Weight: 40, Amount: 900}); err == nil {
+		t.Fatal("cut off an unknown cycle")
+	}
+}
+
+func TestStoreGrowthSalesDetail(t *testing.T) {
+	farm := newTestFarm(t)
+	farm.feed(t)
+	salesDate := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	sales, err := farm.svc.StoreGrowthSales(&Sales{SalesDate: salesDate, Qty: 40, Reference: "INV-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sales.Detail = []SalesDetail{{BatchID: farm.cycle.BatchID, BatchCycleID: farm.cycle.ID, Amount: 900, Weight: 40, Price: 25000}}
+	result, err := farm.svc.StoreGrowthSalesDetail(sales)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(result.Detail) != 1 || result.Detail[0].SalesID != sales.ID || result.Detail[0].Price != 25000 {
+		t.Fatalf("sales read back with detail %+v", result.Detail)
+	}
+	cutoff, err := farm.repo.ResolveGrowthSummaryByBatchCycleID(farm.cycle.ID)
+	if err != nil {
+		t.Fatal(err)
+	} else if cutoff == nil || !cutoff.SummaryDate.Equal(salesDate) {
+		t.Fatalf("sold cycle was cut off with %+v", cutoff)
+	}
+	assertHarvest(t, cutoff)
+	if cycle, err := farm.repo.ResolveGrowthBatchCycleByID(farm.cycle.BatchID, farm.cycle.ID); err != nil {
+		t.Fatal(err)
+	} else if !cycle.Finish.Valid || !cycle.Finish.Time.Equal(salesDate) {
+		t.Fatalf("sold cycle finished %v", cycle.Finish)
+	}
+}
+
+func TestStoreGrowthSalesDetailUnknownCycle(t *testing.T) {
+	farm := newTestFarm(t)
+	sales, err := farm.svc.StoreGrowthSales(&Sales{SalesDate: time.Now(), Qty: 40})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sales.Detail = []SalesDetail{
+		{BatchID: farm.cycle.BatchID, BatchCycleID: farm.cycle.ID, Amount: 900, Weight: 40},
+		{BatchID: farm.cycle.BatchID, BatchCycleID: uuid.Must(uuid.NewV4()), Amount: 100, Weight: 5},
+	}
+	if _, err := farm.svc.StoreGrowthSalesDetail(sales); err == nil {
+		t.Fatal("sold an unknown cycle")
+	}
+	if result, err := farm.svc.ResolveGrowthSalesByID(sales.ID); err != nil {
+		t.Fatal(err)
+	} else if len(result.Detail) != 0 {
+		t.Fatalf("failed sales kept %d details", len(result.Detail))
+	}
+	if cutoff, err := farm.repo.ResolveGrowthSummaryByBatchCycleID(farm.cycle.ID); err != nil {
+		t.Fatal(err)
+	} else if cutoff != nil {
+		t.Fatal("failed sales cut off the cycle")
+	}
+}
+
+func TestResolveGrowthBatchCyclePageInclude(t *testing.T) {
+	farm := newTestFarm(t)
+	farm.feed(t)
+	page, _, _, total, err := farm.svc.ResolveGrowthBatchCyclePage(farm.cycle.BatchID, 0, 10, []string{Cycle_Include_Feeding})
+	if err != nil {
+		t.Fatal(err)
+	} else if total != 1 || len(*page) != 1 {
+		t.Fatalf("got %d of %d cycles", len(*page), total)
+	}
+	cycle := (*page)[0]
+	if len(cycle.Feeding) != 2 || cycle.Feeding[0].FeedType.Name != "Pellet" || cycle.Deaths != nil {
+		t.Fatalf("cycle loaded as %+v", cycle)
+	}
+	if page, _, _, _, err := farm.svc.ResolveGrowthBatchCyclePage(farm.cycle.BatchID, 0, 10, []string{}); err != nil {
+		t.Fatal(err)
+	} else if (*page)[0].Feeding != nil || (*page)[0].Pool.ID != farm.cycle.PoolID {
+		t.Fatalf("cycle without relations loaded as %+v", (*page)[0])
+	}
+	if _, _, _, _, err := farm.svc.ResolveGrowthBatchCyclePage(farm.cycle.BatchID, 0, 10, []string{"sales"}); err == nil {
+		t.Fatal("loaded an unknown relation")
+	}
+}
+
+func TestResolveGrowthBatchCyclePageDeathCause(t *testing.T) {
+	farm := newTestFarm(t)
+	cause, err := farm.svc.StoreGrowthDeathCause(&DeathCause{Name: "Predation"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := farm.svc.StoreGrowthDeath(&Death{BatchCycleID: farm.cycle.ID, Cause: DeathCause{ID: cause.ID}, DeathDate: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), Weight: 0.1, Amount: 10}); err != nil {
+		t.Fatal(err)
+	}
+	page, _, _, _, err := farm.svc.ResolveGrowthBatchCyclePage(farm.cycle.BatchID, 0, 10, []string{Cycle_Include_Deaths})
+	if err != nil {
+		t.Fatal(err)
+	} else if deaths := (*page)[0].Deaths; len(deaths) != 1 || deaths[0].Cause.Name != "Predation" {
+		t.Fatalf("page loaded the deaths as %+v", deaths)
+	}
+}
+
+func TestStoreGrowthFeedingLot(t *testing.T) {
+	farm := newTestFarm(t)
+	incoming := &feed.FeedIncoming{FeedType: *farm.feedtype, IncomingDate: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), Qty: 30, LotNumber: "L-1"}
+	if _, err := farm.svc.FeedService.StoreFeedIncoming(incoming); err != nil {
+		t.Fatal(err)
+	}
+	feeding := &Feeding{BatchCycleID: farm.cycle.ID, FeedType: *farm.feedtype, FeedingDate: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Qty: 1, Unit: "sack"}
+	result, err := farm.svc.StoreGrowthFeeding(feeding)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(result.Lots) != 1 || result.Lots[0].Qty != 25 || result.Lots[0].LotNumber != "L-1" {
+		t.Fatalf("feeding drew from %+v", result.Lots)
+	}
+	if lots, err := farm.svc.FeedService.ResolveFeedLot(null.Time{}); err != nil {
+		t.Fatal(err)
+	} else if len(*lots) != 1 || (*lots)[0].Remaining != 5 {
+		t.Fatalf("lots left %+v", *lots)
+	}
+	if _, err := farm.svc.StoreGrowthFeeding(&Feeding{BatchCycleID: uuid.Must(uuid.NewV4()), FeedType: *farm.feedtype, FeedingDate: feeding.FeedingDate, Qty: 5}); err == nil {
+		t.Fatal("fed an unknown cycle")
+	} else if lots, err := farm.svc.FeedService.ResolveFeedLot(null.Time{}); err != nil {
+		t.Fatal(err)
+	} else if (*lots)[0].Remaining != 5 {
+		t.Fatalf("failed feeding left %v in the lot", (*lots)[0].Remaining)
+	}
+	if _, err := farm.svc.StoreGrowthFeeding(&Feeding{BatchCycleID: farm.cycle.ID, FeedType: *farm.feedtype, FeedingDate: feeding.FeedingDate, Qty: 6}); err == nil {
+		t.Fatal("fed more than the stock has left")
+	} else if lots, err := farm.svc.FeedService.ResolveFeedLot(null.Time{}); err != nil {
+		t.Fatal(err)
+	} else if (*lots)[0].Remaining != 5 {
+		t.Fatalf("short feeding left %v in the lot", (*lots)[0].Remaining)
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"strings"
+	"time"
 
+	"github.com/livestockz/api/domain/feed"
 	"github.com/ncrypthic/dbmapper"
 	. "github.com/ncrypthic/dbmapper/dialects/mysql"
 	uuid "github.com/satori/go.uuid"
@@ -15,6 +18,7 @@ type Repository interface {
 	ResolveGrowthBatchPage(page int32, limit int32, deleted string) (*[]Batch, int32, int32, int32, error)
 	ResolveGrowthBatchByID(id uuid.UUID) (*Batch, error)
 	InsertGrowthBatch(batch *Batch) (*Batch, error)
+	InsertGrowthBatchTransaction(tx *sql.Tx, batch *Batch) (*Batch, error)
 	UpdateGrowthBatchByID(batch *Batch) (*Batch, error)
 	RemoveGrowthBatchByID(id uuid.UUID) (*Batch, error)
 	RemoveGrowthBatchByIDs(ids []uuid.UUID) (*[]Batch, error)
@@ -22,23 +26,73 @@ type Repository interface {
 	ResolveGrowthPoolPage(page int32, limit int32, deleted string) (*[]Pool, int32, int32, int32, error)
 	ResolveGrowthPoolByID(id uuid.UUID) (*Pool, error)
 	InsertGrowthPool(batch *Pool) (*Pool, error)
+	InsertGrowthPoolTransaction(tx *sql.Tx, pool *Pool) (*Pool, error)
 	UpdateGrowthPoolByID(pool *Pool) (*Pool, error)
 	RemoveGrowthPoolByID(id uuid.UUID) (*Pool, error)
 	RemoveGrowthPoolByIDs(ids []uuid.UUID) (*[]Pool, error)
+	//species
+	ResolveGrowthSpecies(deleted string) (*[]Species, error)
+	ResolveGrowthSpeciesByID(id uuid.UUID) (*Species, error)
+	InsertGrowthSpecies(species *Species) (*Species, error)
+	UpdateGrowthSpeciesByID(species *Species) (*Species, error)
+	RemoveGrowthSpeciesByID(id uuid.UUID) (*Species, error)
+	//hatchery
+	ResolveGrowthHatcheryPage(page int32, limit int32, deleted string) (*[]Hatchery, int32, int32, int32, error)
+	ResolveGrowthHatcheryByID(id uuid.UUID) (*Hatchery, error)
+	InsertGrowthHatchery(hatchery *Hatchery) (*Hatchery, error)
+	UpdateGrowthHatcheryByID(hatchery *Hatchery) (*Hatchery, error)
+	RemoveGrowthHatcheryByID(id uuid.UUID) (*Hatchery, error)
+	ResolveGrowthHatcheryPerformance(from time.Time, to time.Time) (*[]HatcheryPerformance, error)
+	//seed source
+	ResolveGrowthSeedSourcePage(page int32, limit int32, hatcheryId uuid.UUID) (*[]SeedSource, int32, int32, int32, error)
+	ResolveGrowthSeedSourceByID(id uuid.UUID) (*SeedSource, error)
+	InsertGrowthSeedSource(seedSource *SeedSource) (*SeedSource, error)
+	UpdateGrowthSeedSourceByID(seedSource *SeedSource) (*SeedSource, error)
+	//pool device
+	ResolveGrowthPoolDeviceByPoolID(poolId uuid.UUID) (*[]PoolDevice, error)
+	ResolveGrowthPoolDeviceByDeviceID(deviceId string) (*PoolDevice, error)
+	ResolveGrowthPoolDeviceByID(id uuid.UUID) (*PoolDevice, error)
+	InsertGrowthPoolDevice(device *PoolDevice) (*PoolDevice, error)
+	RemoveGrowthPoolDeviceByID(id uuid.UUID) (*PoolDevice, error)
+	//pool water quality
+	ResolveGrowthWaterQualityByPoolID(poolId uuid.UUID, from time.Time, to time.Time) (*[]WaterQuality, error)
+	ResolveGrowthWaterQualityByID(id uuid.UUID) (*WaterQuality, error)
+	InsertGrowthWaterQuality(waterQuality *WaterQuality) (*WaterQuality, error)
+	InsertGrowthWaterQualityTransaction(tx *sql.Tx, waterQuality *WaterQuality) (*WaterQuality, error)
+	InsertGrowthWaterQualityBatch(waterQualities *[]WaterQuality) (*[]WaterQuality, error)
+	ResolveGrowthWaterQualityRange() (*[]WaterQualityRange, error)
+	ReplaceGrowthWaterQualityRange(ranges *[]WaterQualityRange) (*[]WaterQualityRange, error)
 	//batch cycle
-	ResolveGrowthBatchCyclePage(batchId uuid.UUID, page int32, limit int32) (*[]BatchCycle, int32, int32, int32, error)
+	ResolveGrowthBatchCyclePage(batchId uuid.UUID, page int32, limit int32, include []string) (*[]BatchCycle, int32, int32, int32, error)
 	ResolveGrowthBatchCycleByID(batchId uuid.UUID, cycleId uuid.UUID) (*BatchCycle, error)
+	ResolveGrowthOpenBatchCycle() (*[]BatchCycle, error)
 	InsertGrowthBatchCycle(batchCycle *BatchCycle) (*BatchCycle, error)
+	InsertGrowthBatchCycleTransaction(tx *sql.Tx, batchCycle *BatchCycle) (*BatchCycle, error)
 	UpdateGrowthBatchCycleByID(batchCycle *BatchCycle) (*BatchCycle, error)
 	UpdateGrowthBatchCycleByIDTransaction(tx *sql.Tx, batchCycle *BatchCycle) (*BatchCycle, error)
+	ResolveGrowthCycleReport() (*[]CycleReport, error)
 	//batch cycle death
 	ResolveGrowthDeathByBatchCycleID(cycleId uuid.UUID) (*[]Death, error)
 	ResolveGrowthDeathByID(deathId uuid.UUID) (*Death, error)
 	InsertGrowthDeath(death *Death) (*Death, error)
+	InsertGrowthDeathTransaction(tx *sql.Tx, death *Death) (*Death, error)
+	//death cause
+	ResolveGrowthDeathCause(deleted string) (*[]DeathCause, error)
+	ResolveGrowthDeathCauseByID(id uuid.UUID) (*DeathCause, error)
+	InsertGrowthDeathCause(cause *DeathCause) (*DeathCause, error)
+	UpdateGrowthDeathCauseByID(cause *DeathCause) (*DeathCause, error)
+	RemoveGrowthDeathCauseByID(id uuid.UUID) (*DeathCause, error)
+	//mortality report
+	ResolveGrowthMortality(from time.Time, to time.Time, batchId uuid.UUID, poolId uuid.UUID) (*[]MortalityRecord, error)
+	//batch cycle sampling
+	ResolveGrowthSamplingByBatchCycleID(cycleId uuid.UUID) (*[]Sampling, error)
+	ResolveGrowthSamplingByID(samplingId uuid.UUID) (*Sampling, error)
+	InsertGrowthSampling(sampling *Sampling) (*Sampling, error)
 	//batch cycle feeding
 	ResolveGrowthFeedingByBatchCycleID(cycleId uuid.UUID) (*[]Feeding, error)
 	ResolveGrowthFeedingByID(feedingId uuid.UUID) (*Feeding, error)
 	InsertGrowthFeeding(feeding *Feeding) (*Feeding, error)
+	InsertGrowthFeedingTransaction(tx *sql.Tx, feeding *Feeding) (*Feeding, error)
 	//batch cycle summary
 	UpdateGrowthBatchCycleAndInsertGrowthSummaryTransaction(batchCycle *BatchCycle, cutoff *CutOff) (*CutOff, error)
 	ResolveGrowthSummaryByBatchCycleID(cycleId uuid.UUID) (*CutOff, error)
@@ -47,49 +101,154 @@ type Repository interface {
 	InsertGrowthSummaryTransaction(tx *sql.Tx, cutoff *CutOff) (*CutOff, error)
 	//batch cycle sales
 	//ResolveGrowthSalesByBatchCycleID(cycleId uuid.UUID) (*[]Sales, error)
+	ResolveGrowthSales(from time.Time, to time.Time) (*[]Sales, error)
 	ResolveGrowthSalesByID(salesId uuid.UUID) (*Sales, error)
 	InsertGrowthSales(sales *Sales) (*Sales, error)
 	UpdateGrowthSalesByID(sales *Sales) (*Sales, error)
 	//batch cycle sales detail
 	//ResolveGrowthSalesDetailBySalesID(salesId uuid.UUID) (*[]SalesDetail, error)
+	ResolveGrowthSalesTraceBySalesID(salesId uuid.UUID) (*[]SalesTrace, error)
+	ResolveGrowthSalesDetailByBatchCycleID(cycleId uuid.UUID) (*[]SalesDetail, error)
 	UpdateGrowthBatchCycleInsertGrowthSummaryAndInsertSalesDetail(batchCycle *[]BatchCycle, cutoff *[]CutOff, sales *Sales) (*Sales, error)
+	//batch cycle cost
+	ResolveGrowthCostByBatchCycleID(cycleId uuid.UUID) (*[]Cost, error)
+	ResolveGrowthCostByID(costId uuid.UUID) (*Cost, error)
+	InsertGrowthCost(cost *Cost) (*Cost, error)
+	//batch cycle treatment
+	ResolveGrowthTreatmentByBatchCycleID(cycleId uuid.UUID) (*[]Treatment, error)
+	ResolveGrowthTreatmentByID(treatmentId uuid.UUID) (*Treatment, error)
+	InsertGrowthTreatment(treatment *Treatment) (*Treatment, error)
+	UpdateGrowthTreatmentByID(treatment *Treatment) (*Treatment, error)
+	ResolveGrowthTreatmentOverrideByBatchCycleID(cycleId uuid.UUID) (*[]TreatmentOverride, error)
+	ResolveGrowthTreatmentOverrideByID(overrideId uuid.UUID) (*TreatmentOverride, error)
+	InsertGrowthTreatmentOverride(override *TreatmentOverride) (*TreatmentOverride, error)
+	//export, rows are handed over one by one as they are read
+	StreamGrowthBatch(deleted string, fn func(*Batch) error) error
+	StreamGrowthPool(deleted string, fn func(*Pool) error) error
+	StreamGrowthBatchCycle(batchId uuid.UUID, fn func(*BatchCycle) error) error
+	StreamGrowthSalesDetail(from time.Time, to time.Time, fn func(*Sales, *SalesDetail) error) error
 }
 
 const (
 	//batch
-	selectGrowthBatch = `SELECT id, name, status, deleted, created, updated FROM growth_batch`
-	insertGrowthBatch = `INSERT INTO growth_batch(id, name, status, deleted, created) VALUES (:id ,:name, :status, :deleted, NOW())`
-	updateGrowthBatch = `UPDATE growth_batch SET name = :name, status = :status, deleted = :deleted, updated = NOW() WHERE id = :id`
-	deleteGrowthBatch = `UPDATE growth_batch SET deleted = 1, updated = NOW() WHERE id = :id`
+	selectGrowthBatch = `SELECT id, name, status, growth_species_id, deleted, created, updated FROM growth_batch`
+	insertGrowthBatch = `INSERT INTO growth_batch(id, name, status, growth_species_id, deleted, created) VALUES (:id ,:name, :status, :species, :deleted, CURRENT_TIMESTAMP)`
+	updateGrowthBatch = `UPDATE growth_batch SET name = :name, status = :status, growth_species_id = :species, deleted = :deleted, updated = CURRENT_TIMESTAMP WHERE id = :id`
+	deleteGrowthBatch = `UPDATE growth_batch SET deleted = 1, updated = CURRENT_TIMESTAMP WHERE id = :id`
 	//pool
-	selectGrowthPool = `SELECT id, name, status, deleted, created, updated FROM growth_pool`
-	insertGrowthPool = `INSERT INTO growth_pool(id, name, status, deleted, created) VALUES (:id ,:name, :status, :deleted, NOW())`
-	updateGrowthPool = `UPDATE growth_pool SET name = :name, status = :status, deleted = :deleted, updated = NOW() WHERE id = :id`
-	deleteGrowthPool = `UPDATE growth_pool SET deleted = 1, updated = NOW() WHERE id = :id`
+	selectGrowthPool = `SELECT id, name, status, area, deleted, created, updated FROM growth_pool`
+	insertGrowthPool = `INSERT INTO growth_pool(id, name, status, area, deleted, created) VALUES (:id ,:name, :status, :area, :deleted, CURRENT_TIMESTAMP)`
+	updateGrowthPool = `UPDATE growth_pool SET name = :name, status = :status, area = :area, deleted = :deleted, updated = CURRENT_TIMESTAMP WHERE id = :id`
+	deleteGrowthPool = `UPDATE growth_pool SET deleted = 1, updated = CURRENT_TIMESTAMP WHERE id = :id`
+	//species
+	selectGrowthSpecies      = `SELECT id, name, scientific_name, target_weight, deleted, created, updated FROM growth_species`
+	insertGrowthSpecies      = `INSERT INTO growth_species(id, name, scientific_name, target_weight, deleted, created) VALUES (:id, :name, :scientific_name, :target_weight, :deleted, CURRENT_TIMESTAMP)`
+	updateGrowthSpecies      = `UPDATE growth_species SET name = :name, scientific_name = :scientific_name, target_weight = :target_weight, deleted = :deleted, updated = CURRENT_TIMESTAMP WHERE id = :id`
+	deleteGrowthSpecies      = `UPDATE growth_species SET deleted = 1, updated = CURRENT_TIMESTAMP WHERE id = :id`
+	selectGrowthSpeciesCurve = `SELECT growth_species_id, day, weight FROM growth_species_curve`
+	insertGrowthSpeciesCurve = `INSERT INTO growth_species_curve(growth_species_id, day, weight) VALUES (:species, :day, :weight)`
+	deleteGrowthSpeciesCurve = `DELETE FROM growth_species_curve WHERE growth_species_id = :species`
+	//hatchery
+	selectGrowthHatchery = `SELECT id, name, contact, phone, address, deleted, created, updated FROM growth_hatchery`
+	insertGrowthHatchery = `INSERT INTO growth_hatchery(id, name, contact, phone, address, deleted, created) VALUES (:id ,:name, :contact, :phone, :address, :deleted, CURRENT_TIMESTAMP)`
+	updateGrowthHatchery = `UPDATE growth_hatchery SET name = :name, contact = :contact, phone = :phone, address = :address, deleted = :deleted, updated = CURRENT_TIMESTAMP WHERE id = :id`
+	deleteGrowthHatchery = `UPDATE growth_hatchery SET deleted = 1, updated = CURRENT_TIMESTAMP WHERE id = :id`
+	//hatchery report, sr is weighted by stocked amount and fcr by weight gain
+	selectGrowthHatcheryPerformance = `SELECT h.id AS hatchery_id, h.name, COUNT(DISTINCT ss.id) AS seed_sources, COUNT(gs.id) AS cycles,
+		SUM(bc.amount) AS stocked, SUM(gs.amount) AS harvested, SUM(gs.amount) / SUM(bc.amount) * 100 AS sr,
+		SUM(gs.fcr * (gs.weight - bc.weight)) / SUM(gs.weight - bc.weight) AS fcr
+		FROM growth_hatchery h
+		JOIN growth_seed_source ss ON ss.growth_hatchery_id = h.id
+		JOIN growth_batch_cycle bc ON bc.growth_seed_source_id = ss.id
+		JOIN growth_summary gs ON gs.growth_batch_cycle_id = bc.id
+		WHERE gs.summary_date BETWEEN :from AND :to
+		GROUP BY h.id, h.name ORDER BY sr DESC, fcr ASC`
+	//seed source
+	selectGrowthSeedSource = `SELECT id, growth_hatchery_id, species, strain, lot_code, stocking_size, quality_grade, remarks, created, updated FROM growth_seed_source`
+	insertGrowthSeedSource = `INSERT INTO growth_seed_source(id, growth_hatchery_id, species, strain, lot_code, stocking_size, quality_grade, remarks, created) VALUES (:id, :hatchery, :species, :strain, :lot_code, :stocking_size, :quality_grade, :remarks, CURRENT_TIMESTAMP)`
+	updateGrowthSeedSource = `UPDATE growth_seed_source SET growth_hatchery_id = :hatchery, species = :species, strain = :strain, lot_code = :lot_code, stocking_size = :stocking_size, quality_grade = :quality_grade, remarks = :remarks, updated = CURRENT_TIMESTAMP WHERE id = :id`
+	//pool device
+	selectGrowthPoolDevice = `SELECT id, growth_pool_id, device_id, remarks, created FROM growth_pool_device`
+	insertGrowthPoolDevice = `INSERT INTO growth_pool_device(id, growth_pool_id, device_id, remarks, created) VALUES (:id, :poolId, :device_id, :remarks, CURRENT_TIMESTAMP)`
+	deleteGrowthPoolDevice = `DELETE FROM growth_pool_device WHERE id = :id`
+	//pool water quality
+	selectGrowthWaterQuality      = `SELECT id, growth_pool_id, measured_at, dissolved_oxygen, ph, temperature, ammonia, nitrite, salinity, transparency, out_of_range, out_of_range_parameters, remarks, created FROM growth_water_quality`
+	insertGrowthWaterQuality      = `INSERT INTO growth_water_quality(id, growth_pool_id, measured_at, dissolved_oxygen, ph, temperature, ammonia, nitrite, salinity, transparency, out_of_range, out_of_range_parameters, remarks, created) VALUES (:id, :poolId, :measured_at, :dissolved_oxygen, :ph, :temperature, :ammonia, :nitrite, :salinity, :transparency, :out_of_range, :out_of_range_parameters, :remarks, CURRENT_TIMESTAMP)`
+	selectGrowthWaterQualityRange = `SELECT parameter, min_value, max_value, updated FROM growth_water_quality_range`
+	insertGrowthWaterQualityRange = `INSERT INTO growth_water_quality_range(parameter, min_value, max_value, updated) VALUES (:parameter, :min, :max, CURRENT_TIMESTAMP)`
+	deleteGrowthWaterQualityRange = `DELETE FROM growth_water_quality_range WHERE parameter = :parameter`
 	//batch cycle
-	selectGrowthBatchCycle = `SELECT id, growth_batch_id, growth_pool_id, cycle_start, cycle_finish, weight, amount, created, updated FROM growth_batch_cycle`
-	insertGrowthBatchCycle = `INSERT INTO growth_batch_cycle(id, growth_batch_id, growth_pool_id, cycle_start, weight, amount, created) VALUES (:id ,:batch, :pool, :start, :weight, :amount, NOW())`
-	updateGrowthBatchCycle = `UPDATE growth_batch_cycle SET growth_batch_id = :batch, growth_pool_id = :pool, cycle_start = :start, cycle_finish = :finish, weight = :weight, amount = :amount, updated = NOW() WHERE id = :id`
+	selectGrowthBatchCycle = `SELECT id, growth_batch_id, growth_pool_id, growth_seed_source_id, cycle_start, cycle_finish, weight, amount, seed_cost, created, updated FROM growth_batch_cycle`
+	insertGrowthBatchCycle = `INSERT INTO growth_batch_cycle(id, growth_batch_id, growth_pool_id, growth_seed_source_id, cycle_start, weight, amount, seed_cost, created) VALUES (:id ,:batch, :pool, :seed_source, :start, :weight, :amount, :seed_cost, CURRENT_TIMESTAMP)`
+	updateGrowthBatchCycle = `UPDATE growth_batch_cycle SET growth_batch_id = :batch, growth_pool_id = :pool, growth_seed_source_id = :seed_source, cycle_start = :start, cycle_finish = :finish, weight = :weight, amount = :amount, seed_cost = :seed_cost, updated = CURRENT_TIMESTAMP WHERE id = :id`
 	//death
-	selectGrowthDeath = `SELECT id, growth_batch_cycle_id, death_date, weight, amount, remarks, created FROM growth_death`
-	insertGrowthDeath = `INSERT INTO growth_death(id, growth_batch_cycle_id, death_date, weight, amount, remarks, created) VALUES (:id ,:cycleId, :death_date, :weight, :amount, :remarks, NOW())`
+	selectGrowthDeath = `SELECT id, growth_batch_cycle_id, growth_death_cause_id, death_date, weight, amount, remarks, created FROM growth_death`
+	insertGrowthDeath = `INSERT INTO growth_death(id, growth_batch_cycle_id, growth_death_cause_id, death_date, weight, amount, remarks, created) VALUES (:id ,:cycleId, :causeId, :death_date, :weight, :amount, :remarks, CURRENT_TIMESTAMP)`
+	//death cause
+	selectGrowthDeathCause = `SELECT id, name, description, deleted, created, updated FROM growth_death_cause`
+	insertGrowthDeathCause = `INSERT INTO growth_death_cause(id, name, description, deleted, created) VALUES (:id, :name, :description, :deleted, CURRENT_TIMESTAMP)`
+	updateGrowthDeathCause = `UPDATE growth_death_cause SET name = :name, description = :description, deleted = :deleted, updated = CURRENT_TIMESTAMP WHERE id = :id`
+	deleteGrowthDeathCause = `UPDATE growth_death_cause SET deleted = 1, updated = CURRENT_TIMESTAMP WHERE id = :id`
+	//mortality, every death of the cycles that lost animals within the period so
+	//survival can be accumulated from the cycle start
+	selectGrowthMortality = `SELECT d.id, d.growth_batch_cycle_id, d.death_date, d.amount, d.weight, c.id AS cause_id, c.name AS cause_name,
+		b.id AS batch_id, b.name AS batch_name, p.id AS pool_id, p.name AS pool_name, bc.amount AS stocked, bc.cycle_start
+		FROM growth_death d
+		JOIN growth_death_cause c ON c.id = d.growth_death_cause_id
+		JOIN growth_batch_cycle bc ON bc.id = d.growth_batch_cycle_id
+		JOIN growth_batch b ON b.id = bc.growth_batch_id
+		JOIN growth_pool p ON p.id = bc.growth_pool_id
+		WHERE d.death_date < :until
+		AND d.growth_batch_cycle_id IN (SELECT growth_batch_cycle_id FROM growth_death WHERE death_date >= :from AND death_date < :to)`
+	//cycle report, every cycle closed by a cut off
+	selectGrowthCycleReport = `SELECT bc.id, b.id AS batch_id, b.name AS batch_name, p.id AS pool_id, p.name AS pool_name, p.area AS pool_area,
+		sp.id AS species_id, sp.name AS species_name, bc.cycle_start, bc.amount AS stocked,
+		gs.summary_date, gs.weight AS harvested, gs.amount AS harvested_amount, gs.adg, gs.fcr, gs.sr
+		FROM growth_batch_cycle bc
+		JOIN growth_summary gs ON gs.growth_batch_cycle_id = bc.id
+		JOIN growth_batch b ON b.id = bc.growth_batch_id
+		JOIN growth_pool p ON p.id = bc.growth_pool_id
+		LEFT JOIN growth_species sp ON sp.id = b.growth_species_id
+		ORDER BY gs.summary_date ASC`
+	//sampling
+	selectGrowthSampling = `SELECT id, growth_batch_cycle_id, sampling_date, sample_size, average_weight, remarks, created FROM growth_sampling`
+	insertGrowthSampling = `INSERT INTO growth_sampling(id, growth_batch_cycle_id, sampling_date, sample_size, average_weight, remarks, created) VALUES (:id, :cycleId, :sampling_date, :sample_size, :average_weight, :remarks, CURRENT_TIMESTAMP)`
 	//feeding
 	selectGrowthFeeding = `SELECT id, growth_batch_cycle_id, feed_type_id, feeding_date, qty, remarks, created FROM growth_feeding`
-	insertGrowthFeeding = `INSERT INTO growth_feeding(id, growth_batch_cycle_id, feed_type_id, feeding_date, qty, remarks, created) VALUES (:id ,:cycleId, :feedTypeId,:feeding_date, :qty, :remarks, NOW())`
+	insertGrowthFeeding = `INSERT INTO growth_feeding(id, growth_batch_cycle_id, feed_type_id, feeding_date, qty, remarks, created) VALUES (:id ,:cycleId, :feedTypeId,:feeding_date, :qty, :remarks, CURRENT_TIMESTAMP)`
 	//summary
 	selectGrowthSummary = `SELECT id, growth_batch_cycle_id, summary_date, weight, amount, adg, fcr, sr, created FROM growth_summary`
-	insertGrowthSummary = `INSERT INTO growth_summary(id, growth_batch_cycle_id, summary_date, weight, amount, adg, fcr, sr, created) VALUES (:id ,:cycleId, :summary_date, :weight, :amount, :adg, :fcr, :sr, NOW())`
+	insertGrowthSummary = `INSERT INTO growth_summary(id, growth_batch_cycle_id, summary_date, weight, amount, adg, fcr, sr, created) VALUES (:id ,:cycleId, :summary_date, :weight, :amount, :adg, :fcr, :sr, CURRENT_TIMESTAMP)`
 	//sales
 	selectGrowthSales = `SELECT id, sales_date, qty, reference, created, updated FROM growth_sales`
-	insertGrowthSales = `INSERT INTO growth_sales(id, sales_date, qty, reference, created) VALUES (:id ,:sales_date, :qty, :reference, NOW())`
-	updateGrowthSales = `UPDATE growth_sales SET sales_date = :sales_date, qty = :qty, reference = :reference, updated = NOW() WHERE id = :id`
+	insertGrowthSales = `INSERT INTO growth_sales(id, sales_date, qty, reference, created) VALUES (:id ,:sales_date, :qty, :reference, CURRENT_TIMESTAMP)`
+	updateGrowthSales = `UPDATE growth_sales SET sales_date = :sales_date, qty = :qty, reference = :reference, updated = CURRENT_TIMESTAMP WHERE id = :id`
 	//sales detail
-	selectGrowthSalesDetail = `SELECT id, sales_id, growth_batch_cycle_id, amount, weight, created, updated FROM growth_sales_detail`
-	insertGrowthSalesDetail = `INSERT INTO growth_sales_detail(id, sales_id, growth_batch_cycle_id, amount, weight, created) VALUES (:id ,:sales_id, :batch_cycle_id, :amount, :weight, NOW())`
+	selectGrowthSalesDetail = `SELECT id, sales_id, growth_batch_cycle_id, amount, weight, price, created, updated FROM growth_sales_detail`
+	//sales line, a sold detail with its sales date and reference
+	selectGrowthSalesLine = `SELECT sd.id, sd.sales_id, sd.growth_batch_cycle_id, sd.amount, sd.weight, sd.price, sd.created, sd.updated, s.sales_date, s.reference
+		FROM growth_sales_detail sd JOIN growth_sales s ON s.id = sd.sales_id
+		WHERE s.sales_date >= :from AND s.sales_date < :to ORDER BY s.sales_date ASC, sd.created ASC`
+	//sales trace, the cycle each sold detail came from
+	selectGrowthSalesTrace = `SELECT sd.id AS sales_detail_id, sd.growth_batch_cycle_id, sd.amount, sd.weight, bc.growth_batch_id, bc.growth_pool_id, bc.growth_seed_source_id, bc.cycle_start
+		FROM growth_sales_detail sd JOIN growth_batch_cycle bc ON bc.id = sd.growth_batch_cycle_id
+		WHERE sd.sales_id = :salesId ORDER BY sd.created ASC`
+	insertGrowthSalesDetail = `INSERT INTO growth_sales_detail(id, sales_id, growth_batch_cycle_id, amount, weight, price, created) VALUES (:id ,:sales_id, :batch_cycle_id, :amount, :weight, :price, CURRENT_TIMESTAMP)`
+	//cost
+	selectGrowthCost = `SELECT id, growth_batch_cycle_id, cost_date, description, amount, created FROM growth_cost`
+	insertGrowthCost = `INSERT INTO growth_cost(id, growth_batch_cycle_id, cost_date, description, amount, created) VALUES (:id ,:cycleId, :cost_date, :description, :amount, CURRENT_TIMESTAMP)`
+	//treatment
+	selectGrowthTreatment         = `SELECT id, growth_batch_cycle_id, product, dose, unit, method, start_date, end_date, withdrawal_days, remarks, created, updated FROM growth_treatment`
+	insertGrowthTreatment         = `INSERT INTO growth_treatment(id, growth_batch_cycle_id, product, dose, unit, method, start_date, end_date, withdrawal_days, remarks, created) VALUES (:id, :cycleId, :product, :dose, :unit, :method, :start_date, :end_date, :withdrawal_days, :remarks, CURRENT_TIMESTAMP)`
+	updateGrowthTreatment         = `UPDATE growth_treatment SET product = :product, dose = :dose, unit = :unit, method = :method, start_date = :start_date, end_date = :end_date, withdrawal_days = :withdrawal_days, remarks = :remarks, updated = CURRENT_TIMESTAMP WHERE id = :id`
+	selectGrowthTreatmentOverride = `SELECT id, growth_batch_cycle_id, growth_treatment_id, authorized_by, reason, created FROM growth_treatment_override`
+	insertGrowthTreatmentOverride = `INSERT INTO growth_treatment_override(id, growth_batch_cycle_id, growth_treatment_id, authorized_by, reason, created) VALUES (:id, :cycleId, :treatmentId, :authorized_by, :reason, CURRENT_TIMESTAMP)`
 )
 
 type BatchRepository struct {
 	DB *sql.DB `inject:"db"`
+	//FeedRepository books feed lot usages in the transactions of the batch domain
+	FeedRepository feed.Repository `inject:"feedRepository"`
 }
 
 //batch
@@ -103,17 +262,17 @@ func (repo *BatchRepository) ResolveGrowthBatchPage(page int32, limit int32, del
 	//get data by given page
 	var query dbmapper.QueryMapper
 	if deleted == Deleted_Any {
-		query = dbmapper.Prepare(selectGrowthBatch+" ORDER BY name ASC LIMIT :start, :end").With(
+		query = dbmapper.Prepare(selectGrowthBatch+" ORDER BY name ASC LIMIT :end OFFSET :start").With(
 			dbmapper.Param("start", start),
 			dbmapper.Param("end", end),
 		)
 	} else if deleted == Deleted_True {
-		query = dbmapper.Prepare(selectGrowthBatch+" WHERE deleted = 1 ORDER BY name ASC LIMIT :start, :end").With(
+		query = dbmapper.Prepare(selectGrowthBatch+" WHERE deleted = 1 ORDER BY name ASC LIMIT :end OFFSET :start").With(
 			dbmapper.Param("start", start),
 			dbmapper.Param("end", end),
 		)
 	} else if deleted == Deleted_False {
-		query = dbmapper.Prepare(selectGrowthBatch+" WHERE deleted = 0 ORDER BY name ASC LIMIT :start, :end").With(
+		query = dbmapper.Prepare(selectGrowthBatch+" WHERE deleted = 0 ORDER BY name ASC LIMIT :end OFFSET :start").With(
 			dbmapper.Param("start", start),
 			dbmapper.Param("end", end),
 		)
@@ -193,6 +352,7 @@ func (repo *BatchRepository) InsertGrowthBatch(batch *Batch) (*Batch, error) {
 		dbmapper.Param("id", batch.ID),
 		dbmapper.Param("name", batch.Name),
 		dbmapper.Param("status", batch.Status),
+		dbmapper.Param("species", batch.SpeciesID),
 		dbmapper.Param("deleted", batch.Deleted),
 	)
 	//log.Print("sql:", insert.SQL())
@@ -218,6 +378,25 @@ func (repo *BatchRepository) InsertGrowthBatch(batch *Batch) (*Batch, error) {
 	}
 }
 
+func (repo *BatchRepository) InsertGrowthBatchTransaction(tx *sql.Tx, batch *Batch) (*Batch, error) {
+	//prepare query and params
+	insert := dbmapper.Prepare(insertGrowthBatch).With(
+		dbmapper.Param("id", batch.ID),
+		dbmapper.Param("name", batch.Name),
+		dbmapper.Param("status", batch.Status),
+		dbmapper.Param("species", batch.SpeciesID),
+		dbmapper.Param("deleted", batch.Deleted),
+	)
+	//validate query
+	if err := insert.Error(); err != nil {
+		return nil, err
+	} else if _, err := tx.Exec(insert.SQL(), insert.Params()...); err != nil {
+		return nil, err
+	} else {
+		return batch, nil
+	}
+}
+
 func (repo *BatchRepository) UpdateGrowthBatchByID(batch *Batch) (*Batch, error) {
 	//find whether if data exist
 	//fmt.Print("\n")
@@ -237,6 +416,7 @@ func (repo *BatchRepository) UpdateGrowthBatchByID(batch *Batch) (*Batch, error)
 		updater := dbmapper.Prepare(updateGrowthBatch).With(
 			dbmapper.Param("name", batch.Name),
 			dbmapper.Param("status", batch.Status),
+			dbmapper.Param("species", batch.SpeciesID),
 			dbmapper.Param("deleted", batch.Deleted),
 			dbmapper.Param("id", batch.ID),
 		)
@@ -311,6 +491,7 @@ func batchMapper(row *Batch) *dbmapper.MappedColumns {
 		dbmapper.Column("id").As(&row.ID),
 		dbmapper.Column("name").As(&row.Name),
 		dbmapper.Column("status").As(&row.Status),
+		dbmapper.Column("growth_species_id").As(&row.SpeciesID),
 		dbmapper.Column("deleted").As(&row.Deleted),
 		dbmapper.Column("created").As(&row.Created),
 		dbmapper.Column("updated").As(&row.Updated),
@@ -337,17 +518,17 @@ func (repo *BatchRepository) ResolveGrowthPoolPage(page int32, limit int32, dele
 	//get data by given page
 	var query dbmapper.QueryMapper
 	if deleted == Deleted_Any {
-		query = dbmapper.Prepare(selectGrowthPool+" ORDER BY name ASC LIMIT :start, :end").With(
+		query = dbmapper.Prepare(selectGrowthPool+" ORDER BY name ASC LIMIT :end OFFSET :start").With(
 			dbmapper.Param("start", start),
 			dbmapper.Param("end", end),
 		)
 	} else if deleted == Deleted_True {
-		query = dbmapper.Prepare(selectGrowthPool+" WHERE deleted = 1 ORDER BY name ASC LIMIT :start, :end").With(
+		query = dbmapper.Prepare(selectGrowthPool+" WHERE deleted = 1 ORDER BY name ASC LIMIT :end OFFSET :start").With(
 			dbmapper.Param("start", start),
 			dbmapper.Param("end", end),
 		)
 	} else if deleted == Deleted_False {
-		query = dbmapper.Prepare(selectGrowthPool+" WHERE deleted = 0 ORDER BY name ASC LIMIT :start, :end").With(
+		query = dbmapper.Prepare(selectGrowthPool+" WHERE deleted = 0 ORDER BY name ASC LIMIT :end OFFSET :start").With(
 			dbmapper.Param("start", start),
 			dbmapper.Param("end", end),
 		)
@@ -418,6 +599,7 @@ func (repo *BatchRepository) InsertGrowthPool(pool *Pool) (*Pool, error) {
 		dbmapper.Param("id", pool.ID),
 		dbmapper.Param("name", pool.Name),
 		dbmapper.Param("status", pool.Status),
+		dbmapper.Param("area", pool.Area),
 		dbmapper.Param("deleted", pool.Deleted),
 	)
 	//validate query
@@ -435,6 +617,25 @@ func (repo *BatchRepository) InsertGrowthPool(pool *Pool) (*Pool, error) {
 	}
 }
 
+func (repo *BatchRepository) InsertGrowthPoolTransaction(tx *sql.Tx, pool *Pool) (*Pool, error) {
+	//prepare query and params
+	insert := dbmapper.Prepare(insertGrowthPool).With(
+		dbmapper.Param("id", pool.ID),
+		dbmapper.Param("name", pool.Name),
+		dbmapper.Param("status", pool.Status),
+		dbmapper.Param("area", pool.Area),
+		dbmapper.Param("deleted", pool.Deleted),
+	)
+	//validate query
+	if err := insert.Error(); err != nil {
+		return nil, err
+	} else if _, err := tx.Exec(insert.SQL(), insert.Params()...); err != nil {
+		return nil, err
+	} else {
+		return pool, nil
+	}
+}
+
 func (repo *BatchRepository) UpdateGrowthPoolByID(pool *Pool) (*Pool, error) {
 	//find whether if data exist
 	_, err := repo.ResolveGrowthPoolByID(pool.ID)
@@ -447,6 +648,7 @@ func (repo *BatchRepository) UpdateGrowthPoolByID(pool *Pool) (*Pool, error) {
 		updater := dbmapper.Prepare(updateGrowthPool).With(
 			dbmapper.Param("name", pool.Name),
 			dbmapper.Param("status", pool.Status),
+			dbmapper.Param("area", pool.Area),
 			dbmapper.Param("deleted", pool.Deleted),
 			dbmapper.Param("id", pool.ID),
 		)
@@ -502,6 +704,7 @@ func poolMapper(row *Pool) *dbmapper.MappedColumns {
 		dbmapper.Column("id").As(&row.ID),
 		dbmapper.Column("name").As(&row.Name),
 		dbmapper.Column("status").As(&row.Status),
+		dbmapper.Column("area").As(&row.Area),
 		dbmapper.Column("deleted").As(&row.Deleted),
 		dbmapper.Column("created").As(&row.Created),
 		dbmapper.Column("updated").As(&row.Updated),
@@ -518,499 +721,1910 @@ func poolsMapper(rows *[]Pool) dbmapper.RowMapper {
 	}
 }
 
-//batch cycle
-func (repo *BatchRepository) ResolveGrowthBatchCyclePage(batchId uuid.UUID, page int32, limit int32) (*[]BatchCycle, int32, int32, int32, error) {
-	var start int32
-	var end int32
-
-	start = page * limit
-	end = limit
-
-	//get data by given page
-	var query dbmapper.QueryMapper
-	query = dbmapper.Prepare(selectGrowthBatchCycle+" WHERE growth_batch_id=:batchId ORDER BY created ASC LIMIT :start, :end").With(
-		dbmapper.Param("batchId", batchId),
-		dbmapper.Param("start", start),
-		dbmapper.Param("end", end),
-	)
-
+//species
+func (repo *BatchRepository) ResolveGrowthSpecies(deleted string) (*[]Species, error) {
+	where := ""
+	if deleted == Deleted_True {
+		where = " WHERE deleted = 1"
+	} else if deleted == Deleted_False {
+		where = " WHERE deleted = 0"
+	}
+	query := dbmapper.Prepare(selectGrowthSpecies + where + " ORDER BY name ASC")
 	if err := query.Error(); err != nil {
-		//log.Print(err.Error())
-		return nil, page, limit, 0, err
+		return nil, err
 	}
-
-	batchCycles := make([]BatchCycle, 0)
-	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(batchCyclesMapper(&batchCycles))
+	species := make([]Species, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(speciesListMapper(&species))
 
 	if err != nil {
-		return nil, page, limit, 0, err
+		return nil, err
 	}
-
-	var newBatchCycles []BatchCycle
-	for _, batchCycle := range batchCycles {
-		if batch, err := repo.ResolveGrowthBatchByID(batchCycle.BatchID); err != nil {
-			return nil, page, limit, 0, err
-		} else {
-			batchCycle.Batch = *batch
-		}
-		pool, err := repo.ResolveGrowthPoolByID(batchCycle.PoolID)
-		if err != nil {
-			return nil, page, limit, 0, err
-		} else {
-			batchCycle.Pool = *pool
-		}
-
-		feedings, err := repo.ResolveGrowthFeedingByBatchCycleID(batchCycle.ID)
-		if err != nil {
-			return nil, page, limit, 0, err
-		} else {
-			batchCycle.Feeding = *feedings
-		}
-
-		deaths, err := repo.ResolveGrowthDeathByBatchCycleID(batchCycle.ID)
-		if err != nil {
-			return nil, page, limit, 0, err
-		} else {
-			batchCycle.Deaths = *deaths
-		}
-
-		cutoff, err := repo.ResolveGrowthSummaryByBatchCycleID(batchCycle.ID)
-		if err != nil {
-			return nil, page, limit, 0, err
-		} else if cutoff != nil {
-			batchCycle.CutOff = *cutoff
-			batchCycle.CutOff.BatchID = batchCycle.BatchID
+	//attach every curve with a single query
+	curves := make(map[uuid.UUID][]GrowthCurvePoint)
+	curveQuery := dbmapper.Prepare(selectGrowthSpeciesCurve + " ORDER BY growth_species_id ASC, day ASC")
+	if err := curveQuery.Error(); err != nil {
+		return nil, err
+	}
+	err = Parse(repo.DB.Query(curveQuery.SQL(), curveQuery.Params()...)).Map(speciesCurveMapper(curves))
+	if err != nil {
+		return nil, err
+	}
+	for i := range species {
+		species[i].Curve = curves[species[i].ID]
+		if species[i].Curve == nil {
+			species[i].Curve = make([]GrowthCurvePoint, 0)
 		}
-
-		newBatchCycles = append(newBatchCycles, batchCycle)
 	}
+	return &species, nil
+}
 
-	//get total batch cycle
-	var summary dbmapper.QueryMapper
-	summary = dbmapper.Prepare("SELECT COUNT(*) AS total FROM growth_batch_cycle WHERE growth_batch_id=:batchId").With(
-		dbmapper.Param("batchId", batchId),
+func (repo *BatchRepository) ResolveGrowthSpeciesByID(id uuid.UUID) (*Species, error) {
+	query := dbmapper.Prepare(selectGrowthSpecies + " WHERE id = :id").With(
+		dbmapper.Param("id", id),
 	)
-
-	if err := summary.Error(); err != nil {
-		//log.Print(err.Error())
-		return nil, page, limit, 0, err
+	if err := query.Error(); err != nil {
+		return nil, err
 	}
+	species := make([]Species, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(speciesListMapper(&species))
 
-	var batchCyclesCount int32
-	total := make([]int32, 0)
-	err = Parse(repo.DB.Query(summary.SQL(), summary.Params()...)).Map(dbmapper.Int32("total", &total))
 	if err != nil {
-		return nil, page, limit, 0, err
-	} else {
-		batchCyclesCount = total[0]
+		return nil, err
 	}
-	return &newBatchCycles, page, limit, batchCyclesCount, nil
-
-}
-
-func (repo *BatchRepository) ResolveGrowthBatchCycleByID(batchId uuid.UUID, cycleId uuid.UUID) (*BatchCycle, error) {
-	query := dbmapper.Prepare(selectGrowthBatchCycle+" WHERE id = :cycleId and growth_batch_id = :batchId").With(
-		dbmapper.Param("cycleId", cycleId),
-		dbmapper.Param("batchId", batchId),
+	if len(species) < 1 {
+		return nil, fmt.Errorf("growth species with id %s not found", id)
+	}
+	curves := make(map[uuid.UUID][]GrowthCurvePoint)
+	curveQuery := dbmapper.Prepare(selectGrowthSpeciesCurve + " WHERE growth_species_id = :species ORDER BY day ASC").With(
+		dbmapper.Param("species", id),
 	)
-	if err := query.Error(); err != nil {
+	if err := curveQuery.Error(); err != nil {
 		return nil, err
 	}
-	batchCycles := make([]BatchCycle, 0)
-	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(batchCyclesMapper(&batchCycles))
-
+	err = Parse(repo.DB.Query(curveQuery.SQL(), curveQuery.Params()...)).Map(speciesCurveMapper(curves))
 	if err != nil {
 		return nil, err
 	}
-
-	if len(batchCycles) < 1 {
-		return nil, fmt.Errorf("growth batch cycle with batchId %s and cycleId %s not found", batchId, cycleId)
-	} else {
-		if batch, err := repo.ResolveGrowthBatchByID(batchCycles[0].BatchID); err != nil {
-			return nil, err
-		} else {
-			batchCycles[0].Batch = *batch
-		}
-
-		if pool, err := repo.ResolveGrowthPoolByID(batchCycles[0].PoolID); err != nil {
-			return nil, err
-		} else {
-			batchCycles[0].Pool = *pool
-		}
-
-		if feedings, err := repo.ResolveGrowthFeedingByBatchCycleID(batchCycles[0].ID); err != nil {
-			return nil, err
-		} else {
-			batchCycles[0].Feeding = *feedings
-		}
-
-		if deaths, err := repo.ResolveGrowthDeathByBatchCycleID(batchCycles[0].ID); err != nil {
-			return nil, err
-		} else {
-			batchCycles[0].Deaths = *deaths
-		}
-
-		cutoff, err := repo.ResolveGrowthSummaryByBatchCycleID(batchCycles[0].ID)
-		if err != nil {
-			return nil, err
-		} else if cutoff != nil {
-			batchCycles[0].CutOff = *cutoff
-			batchCycles[0].CutOff.BatchID = batchCycles[0].BatchID
-		}
-
-		return &batchCycles[0], nil
+	species[0].Curve = curves[id]
+	if species[0].Curve == nil {
+		species[0].Curve = make([]GrowthCurvePoint, 0)
 	}
+	return &species[0], nil
 }
 
-func (repo *BatchRepository) InsertGrowthBatchCycle(batchCycle *BatchCycle) (*BatchCycle, error) {
-
+func (repo *BatchRepository) InsertGrowthSpecies(species *Species) (*Species, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
 	//prepare query and params
-	insert := dbmapper.Prepare(insertGrowthBatchCycle).With(
-		dbmapper.Param("id", batchCycle.ID),
-		dbmapper.Param("batch", batchCycle.Batch.ID),
-		dbmapper.Param("pool", batchCycle.Pool.ID),
-		dbmapper.Param("start", batchCycle.Start),
-		dbmapper.Param("weight", batchCycle.Weight),
-		dbmapper.Param("amount", batchCycle.Amount),
+	insert := dbmapper.Prepare(insertGrowthSpecies).With(
+		dbmapper.Param("id", species.ID),
+		dbmapper.Param("name", species.Name),
+		dbmapper.Param("scientific_name", species.ScientificName),
+		dbmapper.Param("target_weight", species.TargetWeight),
+		dbmapper.Param("deleted", species.Deleted),
 	)
 	//validate query
 	if err := insert.Error(); err != nil {
+		tx.Rollback()
+		return nil, err
+	} else if _, err := tx.Exec(insert.SQL(), insert.Params()...); err != nil {
+		tx.Rollback()
+		return nil, err
+	} else if err := repo.replaceGrowthSpeciesCurveTransaction(tx, species); err != nil {
+		tx.Rollback()
+		return nil, err
+	} else if err := tx.Commit(); err != nil {
+		tx.Rollback()
 		return nil, err
-	} else {
-		//insert to database
-		if _, err := repo.DB.Exec(insert.SQL(), insert.Params()...); err != nil {
-			return nil, err
-		} else {
-			//find inserted data from database based on generated id
-			res, err := repo.ResolveGrowthBatchCycleByID(batchCycle.BatchID, batchCycle.ID)
-			return res, err
-		}
 	}
+	return repo.ResolveGrowthSpeciesByID(species.ID)
 }
 
-func (repo *BatchRepository) UpdateGrowthBatchCycleByIDTransaction(tx *sql.Tx, batchCycle *BatchCycle) (*BatchCycle, error) {
-	updater := dbmapper.Prepare(updateGrowthBatchCycle).With(
-		dbmapper.Param("batch", batchCycle.Batch.ID),
-		dbmapper.Param("pool", batchCycle.Pool.ID),
-		dbmapper.Param("start", batchCycle.Start),
-		dbmapper.Param("finish", batchCycle.Finish),
-		dbmapper.Param("weight", batchCycle.Weight),
-		dbmapper.Param("amount", batchCycle.Amount),
-		dbmapper.Param("id", batchCycle.ID),
+func (repo *BatchRepository) UpdateGrowthSpeciesByID(species *Species) (*Species, error) {
+	//find whether if data exist
+	if _, err := repo.ResolveGrowthSpeciesByID(species.ID); err != nil {
+		return nil, err
+	}
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	updater := dbmapper.Prepare(updateGrowthSpecies).With(
+		dbmapper.Param("name", species.Name),
+		dbmapper.Param("scientific_name", species.ScientificName),
+		dbmapper.Param("target_weight", species.TargetWeight),
+		dbmapper.Param("deleted", species.Deleted),
+		dbmapper.Param("id", species.ID),
 	)
 	//validate query
 	if err := updater.Error(); err != nil {
+		tx.Rollback()
 		return nil, err
-	} else {
-		//update to database
-		if _, err := tx.Exec(updater.SQL(), updater.Params()...); err != nil {
-			return nil, err
-		} else {
-			return batchCycle, nil
+	} else if _, err := tx.Exec(updater.SQL(), updater.Params()...); err != nil {
+		tx.Rollback()
+		return nil, err
+	} else if err := repo.replaceGrowthSpeciesCurveTransaction(tx, species); err != nil {
+		tx.Rollback()
+		return nil, err
+	} else if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return repo.ResolveGrowthSpeciesByID(species.ID)
+}
+
+//replaceGrowthSpeciesCurveTransaction swaps the whole reference curve of a species
+func (repo *BatchRepository) replaceGrowthSpeciesCurveTransaction(tx *sql.Tx, species *Species) error {
+	remover := dbmapper.Prepare(deleteGrowthSpeciesCurve).With(
+		dbmapper.Param("species", species.ID),
+	)
+	if err := remover.Error(); err != nil {
+		return err
+	} else if _, err := tx.Exec(remover.SQL(), remover.Params()...); err != nil {
+		return err
+	}
+	for _, point := range species.Curve {
+		insert := dbmapper.Prepare(insertGrowthSpeciesCurve).With(
+			dbmapper.Param("species", species.ID),
+			dbmapper.Param("day", point.Day),
+			dbmapper.Param("weight", point.Weight),
+		)
+		if err := insert.Error(); err != nil {
+			return err
+		} else if _, err := tx.Exec(insert.SQL(), insert.Params()...); err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
-func (repo *BatchRepository) UpdateGrowthBatchCycleByID(batchCycle *BatchCycle) (*BatchCycle, error) {
+func (repo *BatchRepository) RemoveGrowthSpeciesByID(id uuid.UUID) (*Species, error) {
 	//find whether if data exist
-	_, err := repo.ResolveGrowthBatchCycleByID(batchCycle.BatchID, batchCycle.ID)
-
-	if err != nil {
+	if _, err := repo.ResolveGrowthSpeciesByID(id); err != nil {
+		return nil, err
+	}
+	remover := dbmapper.Prepare(deleteGrowthSpecies).With(
+		dbmapper.Param("id", id),
+	)
+	//validate query
+	if err := remover.Error(); err != nil {
+		return nil, err
+	} else if _, err := repo.DB.Exec(remover.SQL(), remover.Params()...); err != nil {
 		return nil, err
-	} else {
-		//update
-		//prepare query and params
-		updater := dbmapper.Prepare(updateGrowthBatchCycle).With(
-			dbmapper.Param("batch", batchCycle.Batch.ID),
-			dbmapper.Param("pool", batchCycle.Pool.ID),
-			dbmapper.Param("start", batchCycle.Start),
-			dbmapper.Param("finish", batchCycle.Finish),
-			dbmapper.Param("weight", batchCycle.Weight),
-			dbmapper.Param("amount", batchCycle.Amount),
-			dbmapper.Param("id", batchCycle.ID),
-		)
-		//validate query
-		if err := updater.Error(); err != nil {
-			return nil, err
-		} else {
-			//update to database
-			if _, err := repo.DB.Exec(updater.SQL(), updater.Params()...); err != nil {
-				return nil, err
-			} else {
-				//find inserted data from database based on generated id
-				res, err := repo.ResolveGrowthBatchCycleByID(batchCycle.BatchID, batchCycle.ID)
-				return res, err
-			}
-		}
 	}
+	return nil, nil
 }
 
-func batchCycleMapper(row *BatchCycle) *dbmapper.MappedColumns {
+func speciesMapper(row *Species) *dbmapper.MappedColumns {
 	return dbmapper.Columns(
 		dbmapper.Column("id").As(&row.ID),
-		dbmapper.Column("growth_batch_id").As(&row.BatchID),
-		dbmapper.Column("growth_pool_id").As(&row.PoolID),
-		dbmapper.Column("cycle_start").As(&row.Start),
-		dbmapper.Column("cycle_finish").As(&row.Finish),
-		dbmapper.Column("weight").As(&row.Weight),
-		dbmapper.Column("amount").As(&row.Amount),
+		dbmapper.Column("name").As(&row.Name),
+		dbmapper.Column("scientific_name").As(&row.ScientificName),
+		dbmapper.Column("target_weight").As(&row.TargetWeight),
+		dbmapper.Column("deleted").As(&row.Deleted),
 		dbmapper.Column("created").As(&row.Created),
 		dbmapper.Column("updated").As(&row.Updated),
 	)
 }
 
-func batchCyclesMapper(rows *[]BatchCycle) dbmapper.RowMapper {
+func speciesListMapper(rows *[]Species) dbmapper.RowMapper {
 	return func() *dbmapper.MappedColumns {
-		row := BatchCycle{}
-		return batchCycleMapper(&row).Then(func() error {
+		row := Species{}
+		return speciesMapper(&row).Then(func() error {
 			*rows = append(*rows, row)
 			return nil
 		})
 	}
 }
 
-//growth death
-func (repo *BatchRepository) ResolveGrowthDeathByBatchCycleID(cycleId uuid.UUID) (*[]Death, error) {
-	query := dbmapper.Prepare(selectGrowthDeath + " WHERE growth_batch_cycle_id = :cycleId ORDER BY created ASC").With(
-		dbmapper.Param("cycleId", cycleId),
+func speciesCurveMapper(curves map[uuid.UUID][]GrowthCurvePoint) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		var speciesId uuid.UUID
+		point := GrowthCurvePoint{}
+		return dbmapper.Columns(
+			dbmapper.Column("growth_species_id").As(&speciesId),
+			dbmapper.Column("day").As(&point.Day),
+			dbmapper.Column("weight").As(&point.Weight),
+		).Then(func() error {
+			curves[speciesId] = append(curves[speciesId], point)
+			return nil
+		})
+	}
+}
+
+//hatchery
+func (repo *BatchRepository) ResolveGrowthHatcheryPage(page int32, limit int32, deleted string) (*[]Hatchery, int32, int32, int32, error) {
+	var start int32
+	var end int32
+
+	start = page * limit
+	end = limit
+
+	//filter by deleted status
+	where := ""
+	if deleted == Deleted_True {
+		where = " WHERE deleted = 1"
+	} else if deleted == Deleted_False {
+		where = " WHERE deleted = 0"
+	}
+
+	//get data by given page
+	var query dbmapper.QueryMapper
+	query = dbmapper.Prepare(selectGrowthHatchery+where+" ORDER BY name ASC LIMIT :end OFFSET :start").With(
+		dbmapper.Param("start", start),
+		dbmapper.Param("end", end),
 	)
 	if err := query.Error(); err != nil {
-		return nil, err
+		return nil, page, limit, 0, err
 	}
-	deaths := make([]Death, 0)
-	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(deathsMapper(&deaths))
+
+	hatcheries := make([]Hatchery, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(hatcheriesMapper(&hatcheries))
 
 	if err != nil {
-		return nil, err
+		return nil, page, limit, 0, err
 	}
-	return &deaths, nil
+
+	//get total hatchery
+	var summary dbmapper.QueryMapper
+	summary = dbmapper.Prepare("SELECT COUNT(*) AS total FROM growth_hatchery" + where)
+	if err := summary.Error(); err != nil {
+		return nil, page, limit, 0, err
+	}
+
+	var hatcheriesCount int32
+	total := make([]int32, 0)
+	err = Parse(repo.DB.Query(summary.SQL())).Map(dbmapper.Int32("total", &total))
+	if err != nil {
+		return nil, page, limit, 0, err
+	} else {
+		hatcheriesCount = total[0]
+	}
+	return &hatcheries, page, limit, hatcheriesCount, nil
 }
 
-func (repo *BatchRepository) ResolveGrowthDeathByID(deathId uuid.UUID) (*Death, error) {
-	query := dbmapper.Prepare(selectGrowthDeath + " WHERE id = :deathId").With(
-		dbmapper.Param("deathId", deathId),
+func (repo *BatchRepository) ResolveGrowthHatcheryByID(id uuid.UUID) (*Hatchery, error) {
+	query := dbmapper.Prepare(selectGrowthHatchery + " WHERE id = :id").With(
+		dbmapper.Param("id", id),
 	)
 	if err := query.Error(); err != nil {
 		return nil, err
 	}
-	deaths := make([]Death, 0)
-	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(deathsMapper(&deaths))
+	hatcheries := make([]Hatchery, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(hatcheriesMapper(&hatcheries))
 
 	if err != nil {
 		return nil, err
 	}
-	return &deaths[0], nil
+	if len(hatcheries) < 1 {
+		return nil, fmt.Errorf("growth hatchery with id %s not found", id)
+	}
+	return &hatcheries[0], nil
 }
 
-func (repo *BatchRepository) InsertGrowthDeath(death *Death) (*Death, error) {
+func (repo *BatchRepository) InsertGrowthHatchery(hatchery *Hatchery) (*Hatchery, error) {
 	//prepare query and params
-	insert := dbmapper.Prepare(insertGrowthDeath).With(
-		dbmapper.Param("id", death.ID),
-		dbmapper.Param("cycleId", death.BatchCycleID),
-		dbmapper.Param("death_date", death.DeathDate),
-		dbmapper.Param("weight", death.Weight),
-		dbmapper.Param("amount", death.Amount),
-		dbmapper.Param("remarks", death.Remarks),
+	insert := dbmapper.Prepare(insertGrowthHatchery).With(
+		dbmapper.Param("id", hatchery.ID),
+		dbmapper.Param("name", hatchery.Name),
+		dbmapper.Param("contact", hatchery.Contact),
+		dbmapper.Param("phone", hatchery.Phone),
+		dbmapper.Param("address", hatchery.Address),
+		dbmapper.Param("deleted", hatchery.Deleted),
 	)
 	//validate query
 	if err := insert.Error(); err != nil {
 		return nil, err
 	} else if _, err := repo.DB.Exec(insert.SQL(), insert.Params()...); err != nil {
 		return nil, err
-	} else if result, err := repo.ResolveGrowthDeathByID(death.ID); err != nil {
+	} else if result, err := repo.ResolveGrowthHatcheryByID(hatchery.ID); err != nil {
 		return nil, err
 	} else {
 		return result, nil
 	}
 }
 
-func deathMapper(row *Death) *dbmapper.MappedColumns {
+func (repo *BatchRepository) UpdateGrowthHatcheryByID(hatchery *Hatchery) (*Hatchery, error) {
+	//find whether if data exist
+	if _, err := repo.ResolveGrowthHatcheryByID(hatchery.ID); err != nil {
+		return nil, err
+	}
+	updater := dbmapper.Prepare(updateGrowthHatchery).With(
+		dbmapper.Param("name", hatchery.Name),
+		dbmapper.Param("contact", hatchery.Contact),
+		dbmapper.Param("phone", hatchery.Phone),
+		dbmapper.Param("address", hatchery.Address),
+		dbmapper.Param("deleted", hatchery.Deleted),
+		dbmapper.Param("id", hatchery.ID),
+	)
+	//validate query
+	if err := updater.Error(); err != nil {
+		return nil, err
+	} else if _, err := repo.DB.Exec(updater.SQL(), updater.Params()...); err != nil {
+		return nil, err
+	} else if result, err := repo.ResolveGrowthHatcheryByID(hatchery.ID); err != nil {
+		return nil, err
+	} else {
+		return result, nil
+	}
+}
+
+func (repo *BatchRepository) RemoveGrowthHatcheryByID(id uuid.UUID) (*Hatchery, error) {
+	//find whether if data exist
+	if _, err := repo.ResolveGrowthHatcheryByID(id); err != nil {
+		return nil, err
+	}
+	remover := dbmapper.Prepare(deleteGrowthHatchery).With(
+		dbmapper.Param("id", id),
+	)
+	//validate query
+	if err := remover.Error(); err != nil {
+		return nil, err
+	} else if _, err := repo.DB.Exec(remover.SQL(), remover.Params()...); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func hatcheryMapper(row *Hatchery) *dbmapper.MappedColumns {
 	return dbmapper.Columns(
 		dbmapper.Column("id").As(&row.ID),
-		dbmapper.Column("growth_batch_cycle_id").As(&row.BatchCycleID),
-		dbmapper.Column("death_date").As(&row.DeathDate),
-		dbmapper.Column("weight").As(&row.Weight),
-		dbmapper.Column("amount").As(&row.Amount),
+		dbmapper.Column("name").As(&row.Name),
+		dbmapper.Column("contact").As(&row.Contact),
+		dbmapper.Column("phone").As(&row.Phone),
+		dbmapper.Column("address").As(&row.Address),
+		dbmapper.Column("deleted").As(&row.Deleted),
 		dbmapper.Column("created").As(&row.Created),
-		dbmapper.Column("remarks").As(&row.Remarks),
+		dbmapper.Column("updated").As(&row.Updated),
 	)
 }
 
-func deathsMapper(rows *[]Death) dbmapper.RowMapper {
+func hatcheriesMapper(rows *[]Hatchery) dbmapper.RowMapper {
 	return func() *dbmapper.MappedColumns {
-		row := Death{}
-		return deathMapper(&row).Then(func() error {
+		row := Hatchery{}
+		return hatcheryMapper(&row).Then(func() error {
 			*rows = append(*rows, row)
 			return nil
 		})
 	}
 }
 
-//feeding
-func (repo *BatchRepository) ResolveGrowthFeedingByBatchCycleID(cycleId uuid.UUID) (*[]Feeding, error) {
-	query := dbmapper.Prepare(selectGrowthFeeding + " WHERE growth_batch_cycle_id = :cycleId ORDER BY created ASC").With(
-		dbmapper.Param("cycleId", cycleId),
+//hatchery report
+func (repo *BatchRepository) ResolveGrowthHatcheryPerformance(from time.Time, to time.Time) (*[]HatcheryPerformance, error) {
+	query := dbmapper.Prepare(selectGrowthHatcheryPerformance).With(
+		dbmapper.Param("from", from),
+		dbmapper.Param("to", to),
 	)
 	if err := query.Error(); err != nil {
 		return nil, err
 	}
-	feedings := make([]Feeding, 0)
-	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(feedingsMapper(&feedings))
+	performances := make([]HatcheryPerformance, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(hatcheryPerformancesMapper(&performances))
 
 	if err != nil {
 		return nil, err
 	}
-	return &feedings, nil
+	return &performances, nil
 }
 
-func (repo *BatchRepository) ResolveGrowthFeedingByID(FeedingId uuid.UUID) (*Feeding, error) {
-	query := dbmapper.Prepare(selectGrowthFeeding + " WHERE id = :FeedingId").With(
-		dbmapper.Param("FeedingId", FeedingId),
+func hatcheryPerformanceMapper(row *HatcheryPerformance) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("hatchery_id").As(&row.HatcheryID),
+		dbmapper.Column("name").As(&row.Name),
+		dbmapper.Column("seed_sources").As(&row.SeedSources),
+		dbmapper.Column("cycles").As(&row.Cycles),
+		dbmapper.Column("stocked").As(&row.Stocked),
+		dbmapper.Column("harvested").As(&row.Harvested),
+		dbmapper.Column("sr").As(&row.SR),
+		dbmapper.Column("fcr").As(&row.FCR),
+	)
+}
+
+func hatcheryPerformancesMapper(rows *[]HatcheryPerformance) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := HatcheryPerformance{}
+		return hatcheryPerformanceMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+//seed source
+func (repo *BatchRepository) ResolveGrowthSeedSourcePage(page int32, limit int32, hatcheryId uuid.UUID) (*[]SeedSource, int32, int32, int32, error) {
+	var start int32
+	var end int32
+
+	start = page * limit
+	end = limit
+
+	//filter by hatchery
+	where := ""
+	var params []*dbmapper.QueryParam
+	if hatcheryId != uuid.Nil {
+		where = " WHERE growth_hatchery_id = :hatchery"
+		params = append(params, dbmapper.Param("hatchery", hatcheryId))
+	}
+
+	//get data by given page
+	var query dbmapper.QueryMapper
+	query = dbmapper.Prepare(selectGrowthSeedSource + where + " ORDER BY created DESC LIMIT :end OFFSET :start").With(
+		append(params,
+			dbmapper.Param("start", start),
+			dbmapper.Param("end", end),
+		)...,
+	)
+	if err := query.Error(); err != nil {
+		return nil, page, limit, 0, err
+	}
+
+	seedSources := make([]SeedSource, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(seedSourcesMapper(&seedSources))
+
+	if err != nil {
+		return nil, page, limit, 0, err
+	}
+
+	var newSeedSources []SeedSource
+	for _, seedSource := range seedSources {
+		if hatchery, err := repo.ResolveGrowthHatcheryByID(seedSource.HatcheryID); err != nil {
+			return nil, page, limit, 0, err
+		} else {
+			seedSource.Hatchery = *hatchery
+		}
+		newSeedSources = append(newSeedSources, seedSource)
+	}
+
+	//get total seed source
+	var summary dbmapper.QueryMapper
+	summary = dbmapper.Prepare("SELECT COUNT(*) AS total FROM growth_seed_source" + where).With(params...)
+	if err := summary.Error(); err != nil {
+		return nil, page, limit, 0, err
+	}
+
+	var seedSourcesCount int32
+	total := make([]int32, 0)
+	err = Parse(repo.DB.Query(summary.SQL(), summary.Params()...)).Map(dbmapper.Int32("total", &total))
+	if err != nil {
+		return nil, page, limit, 0, err
+	} else {
+		seedSourcesCount = total[0]
+	}
+	return &newSeedSources, page, limit, seedSourcesCount, nil
+}
+
+func (repo *BatchRepository) ResolveGrowthSeedSourceByID(id uuid.UUID) (*SeedSource, error) {
+	query := dbmapper.Prepare(selectGrowthSeedSource + " WHERE id = :id").With(
+		dbmapper.Param("id", id),
 	)
 	if err := query.Error(); err != nil {
 		return nil, err
 	}
-	feedings := make([]Feeding, 0)
-	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(feedingsMapper(&feedings))
+	seedSources := make([]SeedSource, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(seedSourcesMapper(&seedSources))
 
 	if err != nil {
 		return nil, err
+	}
+	if len(seedSources) < 1 {
+		return nil, fmt.Errorf("growth seed source with id %s not found", id)
+	}
+	if hatchery, err := repo.ResolveGrowthHatcheryByID(seedSources[0].HatcheryID); err != nil {
+		return nil, err
 	} else {
-		return &feedings[0], nil
+		seedSources[0].Hatchery = *hatchery
 	}
+	return &seedSources[0], nil
 }
 
-func (repo *BatchRepository) InsertGrowthFeeding(feeding *Feeding) (*Feeding, error) {
+func (repo *BatchRepository) InsertGrowthSeedSource(seedSource *SeedSource) (*SeedSource, error) {
 	//prepare query and params
-	insert := dbmapper.Prepare(insertGrowthFeeding).With(
-		dbmapper.Param("id", feeding.ID),
-		dbmapper.Param("cycleId", feeding.BatchCycleID),
-		dbmapper.Param("feedTypeId", feeding.FeedType.ID),
-		dbmapper.Param("feeding_date", feeding.FeedingDate),
-		dbmapper.Param("qty", feeding.Qty),
-		dbmapper.Param("remarks", feeding.Remarks),
+	insert := dbmapper.Prepare(insertGrowthSeedSource).With(
+		dbmapper.Param("id", seedSource.ID),
+		dbmapper.Param("hatchery", seedSource.Hatchery.ID),
+		dbmapper.Param("species", seedSource.Species),
+		dbmapper.Param("strain", seedSource.Strain),
+		dbmapper.Param("lot_code", seedSource.LotCode),
+		dbmapper.Param("stocking_size", seedSource.StockingSize),
+		dbmapper.Param("quality_grade", seedSource.QualityGrade),
+		dbmapper.Param("remarks", seedSource.Remarks),
 	)
 	//validate query
 	if err := insert.Error(); err != nil {
 		return nil, err
 	} else if _, err := repo.DB.Exec(insert.SQL(), insert.Params()...); err != nil {
 		return nil, err
-	} else if result, err := repo.ResolveGrowthFeedingByID(feeding.ID); err != nil {
+	} else if result, err := repo.ResolveGrowthSeedSourceByID(seedSource.ID); err != nil {
 		return nil, err
 	} else {
 		return result, nil
 	}
 }
 
-func feedingMapper(row *Feeding) *dbmapper.MappedColumns {
+func (repo *BatchRepository) UpdateGrowthSeedSourceByID(seedSource *SeedSource) (*SeedSource, error) {
+	//find whether if data exist
+	if _, err := repo.ResolveGrowthSeedSourceByID(seedSource.ID); err != nil {
+		return nil, err
+	}
+	updater := dbmapper.Prepare(updateGrowthSeedSource).With(
+		dbmapper.Param("hatchery", seedSource.Hatchery.ID),
+		dbmapper.Param("species", seedSource.Species),
+		dbmapper.Param("strain", seedSource.Strain),
+		dbmapper.Param("lot_code", seedSource.LotCode),
+		dbmapper.Param("stocking_size", seedSource.StockingSize),
+		dbmapper.Param("quality_grade", seedSource.QualityGrade),
+		dbmapper.Param("remarks", seedSource.Remarks),
+		dbmapper.Param("id", seedSource.ID),
+	)
+	//validate query
+	if err := updater.Error(); err != nil {
+		return nil, err
+	} else if _, err := repo.DB.Exec(updater.SQL(), updater.Params()...); err != nil {
+		return nil, err
+	} else if result, err := repo.ResolveGrowthSeedSourceByID(seedSource.ID); err != nil {
+		return nil, err
+	} else {
+		return result, nil
+	}
+}
+
+func seedSourceMapper(row *SeedSource) *dbmapper.MappedColumns {
 	return dbmapper.Columns(
 		dbmapper.Column("id").As(&row.ID),
-		dbmapper.Column("growth_batch_cycle_id").As(&row.BatchCycleID),
-		dbmapper.Column("feed_type_id").As(&row.FeedTypeID),
-		dbmapper.Column("feeding_date").As(&row.FeedingDate),
-		dbmapper.Column("qty").As(&row.Qty),
+		dbmapper.Column("growth_hatchery_id").As(&row.HatcheryID),
+		dbmapper.Column("species").As(&row.Species),
+		dbmapper.Column("strain").As(&row.Strain),
+		dbmapper.Column("lot_code").As(&row.LotCode),
+		dbmapper.Column("stocking_size").As(&row.StockingSize),
+		dbmapper.Column("quality_grade").As(&row.QualityGrade),
 		dbmapper.Column("remarks").As(&row.Remarks),
 		dbmapper.Column("created").As(&row.Created),
+		dbmapper.Column("updated").As(&row.Updated),
 	)
 }
 
-func feedingsMapper(rows *[]Feeding) dbmapper.RowMapper {
+func seedSourcesMapper(rows *[]SeedSource) dbmapper.RowMapper {
 	return func() *dbmapper.MappedColumns {
-		row := Feeding{}
-		return feedingMapper(&row).Then(func() error {
+		row := SeedSource{}
+		return seedSourceMapper(&row).Then(func() error {
 			*rows = append(*rows, row)
 			return nil
 		})
 	}
 }
 
-//growth summary
-func (repo *BatchRepository) UpdateGrowthBatchCycleAndInsertGrowthSummaryTransaction(batchCycle *BatchCycle, cutoff *CutOff) (*CutOff, error) {
-	if tx, err := repo.DB.Begin(); err != nil {
-		return nil, err
-	} else if _, err := repo.UpdateGrowthBatchCycleByIDTransaction(tx, batchCycle); err != nil {
-		tx.Rollback()
-		return nil, err
-	} else if _, err := repo.InsertGrowthSummaryTransaction(tx, cutoff); err != nil {
-		tx.Rollback()
-		return nil, err
-	} else if err := tx.Commit(); err != nil {
-		tx.Rollback()
+//pool device
+func (repo *BatchRepository) ResolveGrowthPoolDeviceByPoolID(poolId uuid.UUID) (*[]PoolDevice, error) {
+	query := dbmapper.Prepare(selectGrowthPoolDevice + " WHERE growth_pool_id = :poolId ORDER BY device_id ASC").With(
+		dbmapper.Param("poolId", poolId),
+	)
+	if err := query.Error(); err != nil {
 		return nil, err
-	} else if result, err := repo.ResolveGrowthSummaryByID(cutoff.ID); err != nil {
+	}
+	devices := make([]PoolDevice, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(poolDevicesMapper(&devices))
+
+	if err != nil {
 		return nil, err
-	} else {
-		result.BatchID = batchCycle.BatchID
-		return result, nil
 	}
+	return &devices, nil
 }
 
-func (repo *BatchRepository) ResolveGrowthSummaryByBatchCycleID(cycleId uuid.UUID) (*CutOff, error) {
-	query := dbmapper.Prepare(selectGrowthSummary + " WHERE growth_batch_cycle_id = :cycleId").With(
-		dbmapper.Param("cycleId", cycleId),
+func (repo *BatchRepository) ResolveGrowthPoolDeviceByDeviceID(deviceId string) (*PoolDevice, error) {
+	query := dbmapper.Prepare(selectGrowthPoolDevice + " WHERE device_id = :deviceId").With(
+		dbmapper.Param("deviceId", deviceId),
 	)
 	if err := query.Error(); err != nil {
 		return nil, err
 	}
-	cutoffs := make([]CutOff, 0)
-	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(cutoffsMapper(&cutoffs))
+	devices := make([]PoolDevice, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(poolDevicesMapper(&devices))
 
 	if err != nil {
 		return nil, err
-	} else if len(cutoffs) < 1 {
-		return nil, nil
-	} else {
-		return &cutoffs[0], nil
 	}
+	if len(devices) < 1 {
+		return nil, fmt.Errorf("device %s is not assigned to any pool", deviceId)
+	}
+	return &devices[0], nil
 }
 
-func (repo *BatchRepository) ResolveGrowthSummaryByID(summaryId uuid.UUID) (*CutOff, error) {
-	query := dbmapper.Prepare(selectGrowthSummary + " WHERE id = :summaryId").With(
-		dbmapper.Param("summaryId", summaryId),
+func (repo *BatchRepository) ResolveGrowthPoolDeviceByID(id uuid.UUID) (*PoolDevice, error) {
+	query := dbmapper.Prepare(selectGrowthPoolDevice + " WHERE id = :id").With(
+		dbmapper.Param("id", id),
 	)
 	if err := query.Error(); err != nil {
 		return nil, err
 	}
-	cutoffs := make([]CutOff, 0)
-	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(cutoffsMapper(&cutoffs))
+	devices := make([]PoolDevice, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(poolDevicesMapper(&devices))
 
 	if err != nil {
 		return nil, err
-	} else {
-		return &cutoffs[0], nil
 	}
+	if len(devices) < 1 {
+		return nil, fmt.Errorf("pool device with id %s not found", id)
+	}
+	return &devices[0], nil
 }
 
-func (repo *BatchRepository) InsertGrowthSummary(cutoff *CutOff) (*CutOff, error) {
+func (repo *BatchRepository) InsertGrowthPoolDevice(device *PoolDevice) (*PoolDevice, error) {
 	//prepare query and params
-	insert := dbmapper.Prepare(insertGrowthSummary).With(
-		dbmapper.Param("id", cutoff.ID),
-		dbmapper.Param("cycleId", cutoff.BatchCycleID),
-		dbmapper.Param("summary_date", cutoff.SummaryDate),
-		dbmapper.Param("weight", cutoff.Weight),
-		dbmapper.Param("amount", cutoff.Amount),
-		dbmapper.Param("adg", cutoff.ADG),
-		dbmapper.Param("fcr", cutoff.FCR),
-		dbmapper.Param("sr", cutoff.SR),
+	insert := dbmapper.Prepare(insertGrowthPoolDevice).With(
+		dbmapper.Param("id", device.ID),
+		dbmapper.Param("poolId", device.PoolID),
+		dbmapper.Param("device_id", device.DeviceID),
+		dbmapper.Param("remarks", device.Remarks),
 	)
-	log.Print("insert:", insert.SQL(), "\n")
-	log.Print("params:", insert.Params(), "\n")
 	//validate query
 	if err := insert.Error(); err != nil {
 		return nil, err
 	} else if _, err := repo.DB.Exec(insert.SQL(), insert.Params()...); err != nil {
 		return nil, err
-	} else if result, err := repo.ResolveGrowthSummaryByID(cutoff.ID); err != nil {
+	} else if result, err := repo.ResolveGrowthPoolDeviceByID(device.ID); err != nil {
 		return nil, err
 	} else {
-		result.BatchID = cutoff.BatchID
 		return result, nil
 	}
 }
 
-func (repo *BatchRepository) InsertGrowthSummaryTransaction(tx *sql.Tx, cutoff *CutOff) (*CutOff, error) {
-	//prepare query and params
-	insert := dbmapper.Prepare(insertGrowthSummary).With(
+func (repo *BatchRepository) RemoveGrowthPoolDeviceByID(id uuid.UUID) (*PoolDevice, error) {
+	device, err := repo.ResolveGrowthPoolDeviceByID(id)
+	if err != nil {
+		return nil, err
+	}
+	remover := dbmapper.Prepare(deleteGrowthPoolDevice).With(
+		dbmapper.Param("id", id),
+	)
+	if err := remover.Error(); err != nil {
+		return nil, err
+	} else if _, err := repo.DB.Exec(remover.SQL(), remover.Params()...); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+func poolDeviceMapper(row *PoolDevice) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("id").As(&row.ID),
+		dbmapper.Column("growth_pool_id").As(&row.PoolID),
+		dbmapper.Column("device_id").As(&row.DeviceID),
+		dbmapper.Column("remarks").As(&row.Remarks),
+		dbmapper.Column("created").As(&row.Created),
+	)
+}
+
+func poolDevicesMapper(rows *[]PoolDevice) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := PoolDevice{}
+		return poolDeviceMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+//pool water quality
+func (repo *BatchRepository) ResolveGrowthWaterQualityByPoolID(poolId uuid.UUID, from time.Time, to time.Time) (*[]WaterQuality, error) {
+	query := dbmapper.Prepare(selectGrowthWaterQuality+" WHERE growth_pool_id = :poolId AND measured_at >= :from AND measured_at < :to ORDER BY measured_at ASC").With(
+		dbmapper.Param("poolId", poolId),
+		dbmapper.Param("from", from),
+		dbmapper.Param("to", to),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	readings := make([]WaterQuality, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(waterQualitiesMapper(&readings))
+
+	if err != nil {
+		return nil, err
+	}
+	return &readings, nil
+}
+
+func (repo *BatchRepository) ResolveGrowthWaterQualityByID(id uuid.UUID) (*WaterQuality, error) {
+	query := dbmapper.Prepare(selectGrowthWaterQuality + " WHERE id = :id").With(
+		dbmapper.Param("id", id),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	readings := make([]WaterQuality, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(waterQualitiesMapper(&readings))
+
+	if err != nil {
+		return nil, err
+	}
+	if len(readings) < 1 {
+		return nil, fmt.Errorf("water quality with id %s not found", id)
+	}
+	return &readings[0], nil
+}
+
+func (repo *BatchRepository) InsertGrowthWaterQuality(waterQuality *WaterQuality) (*WaterQuality, error) {
+	if tx, err := repo.DB.Begin(); err != nil {
+		return nil, err
+	} else if _, err := repo.InsertGrowthWaterQualityTransaction(tx, waterQuality); err != nil {
+		tx.Rollback()
+		return nil, err
+	} else if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return nil, err
+	} else if result, err := repo.ResolveGrowthWaterQualityByID(waterQuality.ID); err != nil {
+		return nil, err
+	} else {
+		return result, nil
+	}
+}
+
+//InsertGrowthWaterQualityBatch stores many readings in one transaction
+func (repo *BatchRepository) InsertGrowthWaterQualityBatch(waterQualities *[]WaterQuality) (*[]WaterQuality, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	for _, waterQuality := range *waterQualities {
+		if _, err := repo.InsertGrowthWaterQualityTransaction(tx, &waterQuality); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return waterQualities, nil
+}
+
+func (repo *BatchRepository) InsertGrowthWaterQualityTransaction(tx *sql.Tx, waterQuality *WaterQuality) (*WaterQuality, error) {
+	//prepare query and params
+	insert := dbmapper.Prepare(insertGrowthWaterQuality).With(
+		dbmapper.Param("id", waterQuality.ID),
+		dbmapper.Param("poolId", waterQuality.PoolID),
+		dbmapper.Param("measured_at", waterQuality.MeasuredAt),
+		dbmapper.Param("dissolved_oxygen", waterQuality.DissolvedOxygen),
+		dbmapper.Param("ph", waterQuality.PH),
+		dbmapper.Param("temperature", waterQuality.Temperature),
+		dbmapper.Param("ammonia", waterQuality.Ammonia),
+		dbmapper.Param("nitrite", waterQuality.Nitrite),
+		dbmapper.Param("salinity", waterQuality.Salinity),
+		dbmapper.Param("transparency", waterQuality.Transparency),
+		dbmapper.Param("out_of_range", waterQuality.OutOfRange),
+		dbmapper.Param("out_of_range_parameters", strings.Join(waterQuality.OutOfRangeList, ",")),
+		dbmapper.Param("remarks", waterQuality.Remarks),
+	)
+	//validate query
+	if err := insert.Error(); err != nil {
+		return nil, err
+	} else if _, err := tx.Exec(insert.SQL(), insert.Params()...); err != nil {
+		return nil, err
+	} else {
+		return waterQuality, nil
+	}
+}
+
+func waterQualityMapper(row *WaterQuality, outOfRange *string) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("id").As(&row.ID),
+		dbmapper.Column("growth_pool_id").As(&row.PoolID),
+		dbmapper.Column("measured_at").As(&row.MeasuredAt),
+		dbmapper.Column("dissolved_oxygen").As(&row.DissolvedOxygen),
+		dbmapper.Column("ph").As(&row.PH),
+		dbmapper.Column("temperature").As(&row.Temperature),
+		dbmapper.Column("ammonia").As(&row.Ammonia),
+		dbmapper.Column("nitrite").As(&row.Nitrite),
+		dbmapper.Column("salinity").As(&row.Salinity),
+		dbmapper.Column("transparency").As(&row.Transparency),
+		dbmapper.Column("out_of_range").As(&row.OutOfRange),
+		dbmapper.Column("out_of_range_parameters").As(outOfRange),
+		dbmapper.Column("remarks").As(&row.Remarks),
+		dbmapper.Column("created").As(&row.Created),
+	)
+}
+
+func waterQualitiesMapper(rows *[]WaterQuality) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := WaterQuality{}
+		var outOfRange string
+		return waterQualityMapper(&row, &outOfRange).Then(func() error {
+			//out of range parameters are stored comma separated
+			row.OutOfRangeList = make([]string, 0)
+			if outOfRange != "" {
+				row.OutOfRangeList = strings.Split(outOfRange, ",")
+			}
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+//pool water quality range
+func (repo *BatchRepository) ResolveGrowthWaterQualityRange() (*[]WaterQualityRange, error) {
+	query := dbmapper.Prepare(selectGrowthWaterQualityRange)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	ranges := make([]WaterQualityRange, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(waterQualityRangesMapper(&ranges))
+
+	if err != nil {
+		return nil, err
+	}
+	return &ranges, nil
+}
+
+func (repo *BatchRepository) ReplaceGrowthWaterQualityRange(ranges *[]WaterQualityRange) (*[]WaterQualityRange, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range *ranges {
+		remover := dbmapper.Prepare(deleteGrowthWaterQualityRange).With(
+			dbmapper.Param("parameter", r.Parameter),
+		)
+		insert := dbmapper.Prepare(insertGrowthWaterQualityRange).With(
+			dbmapper.Param("parameter", r.Parameter),
+			dbmapper.Param("min", r.Min),
+			dbmapper.Param("max", r.Max),
+		)
+		if err := remover.Error(); err != nil {
+			tx.Rollback()
+			return nil, err
+		} else if err := insert.Error(); err != nil {
+			tx.Rollback()
+			return nil, err
+		} else if _, err := tx.Exec(remover.SQL(), remover.Params()...); err != nil {
+			tx.Rollback()
+			return nil, err
+		} else if _, err := tx.Exec(insert.SQL(), insert.Params()...); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return repo.ResolveGrowthWaterQualityRange()
+}
+
+func waterQualityRangeMapper(row *WaterQualityRange) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("parameter").As(&row.Parameter),
+		dbmapper.Column("min_value").As(&row.Min),
+		dbmapper.Column("max_value").As(&row.Max),
+		dbmapper.Column("updated").As(&row.Updated),
+	)
+}
+
+func waterQualityRangesMapper(rows *[]WaterQualityRange) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := WaterQualityRange{}
+		return waterQualityRangeMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+//batch cycle
+func (repo *BatchRepository) ResolveGrowthBatchCyclePage(batchId uuid.UUID, page int32, limit int32, include []string) (*[]BatchCycle, int32, int32, int32, error) {
+	var start int32
+	var end int32
+
+	start = page * limit
+	end = limit
+
+	//get data by given page
+	var query dbmapper.QueryMapper
+	query = dbmapper.Prepare(selectGrowthBatchCycle+" WHERE growth_batch_id=:batchId ORDER BY created ASC LIMIT :end OFFSET :start").With(
+		dbmapper.Param("batchId", batchId),
+		dbmapper.Param("start", start),
+		dbmapper.Param("end", end),
+	)
+
+	if err := query.Error(); err != nil {
+		//log.Print(err.Error())
+		return nil, page, limit, 0, err
+	}
+
+	batchCycles := make([]BatchCycle, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(batchCyclesMapper(&batchCycles))
+
+	if err != nil {
+		return nil, page, limit, 0, err
+	}
+
+	if err := repo.populateBatchCycles(batchCycles, include); err != nil {
+		return nil, page, limit, 0, err
+	}
+
+	//get total batch cycle
+	var summary dbmapper.QueryMapper
+	summary = dbmapper.Prepare("SELECT COUNT(*) AS total FROM growth_batch_cycle WHERE growth_batch_id=:batchId").With(
+		dbmapper.Param("batchId", batchId),
+	)
+
+	if err := summary.Error(); err != nil {
+		//log.Print(err.Error())
+		return nil, page, limit, 0, err
+	}
+
+	var batchCyclesCount int32
+	total := make([]int32, 0)
+	err = Parse(repo.DB.Query(summary.SQL(), summary.Params()...)).Map(dbmapper.Int32("total", &total))
+	if err != nil {
+		return nil, page, limit, 0, err
+	} else {
+		batchCyclesCount = total[0]
+	}
+	return &batchCycles, page, limit, batchCyclesCount, nil
+
+}
+
+func (repo *BatchRepository) ResolveGrowthBatchCycleByID(batchId uuid.UUID, cycleId uuid.UUID) (*BatchCycle, error) {
+	query := dbmapper.Prepare(selectGrowthBatchCycle+" WHERE id = :cycleId and growth_batch_id = :batchId").With(
+		dbmapper.Param("cycleId", cycleId),
+		dbmapper.Param("batchId", batchId),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	batchCycles := make([]BatchCycle, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(batchCyclesMapper(&batchCycles))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(batchCycles) < 1 {
+		return nil, fmt.Errorf("growth batch cycle with batchId %s and cycleId %s not found", batchId, cycleId)
+	} else if err := repo.populateBatchCycles(batchCycles, CycleIncludes); err != nil {
+		return nil, err
+	} else {
+		return &batchCycles[0], nil
+	}
+}
+
+//populateBatchCycles attaches the batch, pool and seed source of every cycle plus the
+//included relations with their death causes, it runs one query per table
+//whatever the number of cycles
+func (repo *BatchRepository) populateBatchCycles(batchCycles []BatchCycle, include []string) error {
+	if len(batchCycles) < 1 {
+		return nil
+	}
+	var cycleIds, batchIds, poolIds, seedSourceIds []uuid.UUID
+	for _, batchCycle := range batchCycles {
+		cycleIds = append(cycleIds, batchCycle.ID)
+		batchIds = append(batchIds, batchCycle.BatchID)
+		poolIds = append(poolIds, batchCycle.PoolID)
+		if batchCycle.SeedSourceID.Valid {
+			seedSourceIds = append(seedSourceIds, batchCycle.SeedSourceID.UUID)
+		}
+	}
+
+	batches := make(map[uuid.UUID]Batch)
+	if rows, err := repo.resolveGrowthBatchByIDs(batchIds); err != nil {
+		return err
+	} else {
+		for _, row := range *rows {
+			batches[row.ID] = row
+		}
+	}
+	pools := make(map[uuid.UUID]Pool)
+	if rows, err := repo.resolveGrowthPoolByIDs(poolIds); err != nil {
+		return err
+	} else {
+		for _, row := range *rows {
+			pools[row.ID] = row
+		}
+	}
+	seedSources := make(map[uuid.UUID]SeedSource)
+	if rows, err := repo.resolveGrowthSeedSourceByIDs(seedSourceIds); err != nil {
+		return err
+	} else {
+		for _, row := range *rows {
+			seedSources[row.ID] = row
+		}
+	}
+	feedings := make(map[uuid.UUID][]Feeding)
+	if hasInclude(include, Cycle_Include_Feeding) {
+		if rows, err := repo.resolveGrowthFeedingByBatchCycleIDs(cycleIds); err != nil {
+			return err
+		} else {
+			for _, row := range *rows {
+				feedings[row.BatchCycleID] = append(feedings[row.BatchCycleID], row)
+			}
+		}
+	}
+	deaths := make(map[uuid.UUID][]Death)
+	if hasInclude(include, Cycle_Include_Deaths) {
+		rows, err := repo.resolveGrowthDeathByBatchCycleIDs(cycleIds)
+		if err != nil {
+			return err
+		}
+		var causeIds []uuid.UUID
+		for _, row := range *rows {
+			causeIds = append(causeIds, row.CauseID)
+		}
+		causes := make(map[uuid.UUID]DeathCause)
+		if rows, err := repo.resolveGrowthDeathCauseByIDs(causeIds); err != nil {
+			return err
+		} else {
+			for _, row := range *rows {
+				causes[row.ID] = row
+			}
+		}
+		for _, row := range *rows {
+			row.Cause = causes[row.CauseID]
+			deaths[row.BatchCycleID] = append(deaths[row.BatchCycleID], row)
+		}
+	}
+	cutoffs := make(map[uuid.UUID]CutOff)
+	if hasInclude(include, Cycle_Include_CutOff) {
+		if rows, err := repo.resolveGrowthSummaryByBatchCycleIDs(cycleIds); err != nil {
+			return err
+		} else {
+			for _, row := range *rows {
+				cutoffs[row.BatchCycleID] = row
+			}
+		}
+	}
+
+	for i := range batchCycles {
+		batchCycle := &batchCycles[i]
+		if batch, ok := batches[batchCycle.BatchID]; !ok {
+			return fmt.Errorf("growth batch with id %s not found", batchCycle.BatchID)
+		} else {
+			batchCycle.Batch = batch
+		}
+		if pool, ok := pools[batchCycle.PoolID]; !ok {
+			return fmt.Errorf("growth pool with id %s not found", batchCycle.PoolID)
+		} else {
+			batchCycle.Pool = pool
+		}
+		if batchCycle.SeedSourceID.Valid {
+			if seedSource, ok := seedSources[batchCycle.SeedSourceID.UUID]; !ok {
+				return fmt.Errorf("growth seed source with id %s not found", batchCycle.SeedSourceID.UUID)
+			} else {
+				batchCycle.SeedSource = &seedSource
+			}
+		}
+		if hasInclude(include, Cycle_Include_Feeding) {
+			batchCycle.Feeding = make([]Feeding, 0)
+			batchCycle.Feeding = append(batchCycle.Feeding, feedings[batchCycle.ID]...)
+		}
+		if hasInclude(include, Cycle_Include_Deaths) {
+			batchCycle.Deaths = make([]Death, 0)
+			batchCycle.Deaths = append(batchCycle.Deaths, deaths[batchCycle.ID]...)
+		}
+		if cutoff, ok := cutoffs[batchCycle.ID]; ok {
+			batchCycle.CutOff = cutoff
+			batchCycle.CutOff.BatchID = batchCycle.BatchID
+		}
+	}
+	return nil
+}
+
+//uuidParams turns ids into the values of an IN (:ids) param, each id once
+func uuidParams(ids []uuid.UUID) []interface{} {
+	seen := make(map[uuid.UUID]bool)
+	params := make([]interface{}, 0)
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			params = append(params, id.String())
+		}
+	}
+	return params
+}
+
+func (repo *BatchRepository) resolveGrowthBatchByIDs(ids []uuid.UUID) (*[]Batch, error) {
+	batches := make([]Batch, 0)
+	if len(ids) < 1 {
+		return &batches, nil
+	}
+	query := dbmapper.Prepare(selectGrowthBatch + " WHERE id IN (:ids)").With(
+		dbmapper.Param("ids", uuidParams(ids)...),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(batchesMapper(&batches))
+
+	if err != nil {
+		return nil, err
+	}
+	return &batches, nil
+}
+
+func (repo *BatchRepository) resolveGrowthPoolByIDs(ids []uuid.UUID) (*[]Pool, error) {
+	pools := make([]Pool, 0)
+	if len(ids) < 1 {
+		return &pools, nil
+	}
+	query := dbmapper.Prepare(selectGrowthPool + " WHERE id IN (:ids)").With(
+		dbmapper.Param("ids", uuidParams(ids)...),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(poolsMapper(&pools))
+
+	if err != nil {
+		return nil, err
+	}
+	return &pools, nil
+}
+
+//resolveGrowthSeedSourceByIDs reads the seed sources then their hatcheries in a second query
+func (repo *BatchRepository) resolveGrowthSeedSourceByIDs(ids []uuid.UUID) (*[]SeedSource, error) {
+	seedSources := make([]SeedSource, 0)
+	if len(ids) < 1 {
+		return &seedSources, nil
+	}
+	query := dbmapper.Prepare(selectGrowthSeedSource + " WHERE id IN (:ids)").With(
+		dbmapper.Param("ids", uuidParams(ids)...),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(seedSourcesMapper(&seedSources))
+
+	if err != nil {
+		return nil, err
+	} else if len(seedSources) < 1 {
+		return &seedSources, nil
+	}
+
+	var hatcheryIds []uuid.UUID
+	for _, seedSource := range seedSources {
+		hatcheryIds = append(hatcheryIds, seedSource.HatcheryID)
+	}
+	query = dbmapper.Prepare(selectGrowthHatchery + " WHERE id IN (:ids)").With(
+		dbmapper.Param("ids", uuidParams(hatcheryIds)...),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	hatcheries := make([]Hatchery, 0)
+	err = Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(hatcheriesMapper(&hatcheries))
+
+	if err != nil {
+		return nil, err
+	}
+	for i := range seedSources {
+		found := false
+		for _, hatchery := range hatcheries {
+			if hatchery.ID == seedSources[i].HatcheryID {
+				seedSources[i].Hatchery = hatchery
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("growth hatchery with id %s not found", seedSources[i].HatcheryID)
+		}
+	}
+	return &seedSources, nil
+}
+
+func (repo *BatchRepository) resolveGrowthFeedingByBatchCycleIDs(ids []uuid.UUID) (*[]Feeding, error) {
+	feedings := make([]Feeding, 0)
+	if len(ids) < 1 {
+		return &feedings, nil
+	}
+	query := dbmapper.Prepare(selectGrowthFeeding + " WHERE growth_batch_cycle_id IN (:ids) ORDER BY created ASC").With(
+		dbmapper.Param("ids", uuidParams(ids)...),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(feedingsMapper(&feedings))
+
+	if err != nil {
+		return nil, err
+	}
+	return &feedings, nil
+}
+
+func (repo *BatchRepository) resolveGrowthDeathByBatchCycleIDs(ids []uuid.UUID) (*[]Death, error) {
+	deaths := make([]Death, 0)
+	if len(ids) < 1 {
+		return &deaths, nil
+	}
+	query := dbmapper.Prepare(selectGrowthDeath + " WHERE growth_batch_cycle_id IN (:ids) ORDER BY created ASC").With(
+		dbmapper.Param("ids", uuidParams(ids)...),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(deathsMapper(&deaths))
+
+	if err != nil {
+		return nil, err
+	}
+	return &deaths, nil
+}
+
+func (repo *BatchRepository) resolveGrowthDeathCauseByIDs(ids []uuid.UUID) (*[]DeathCause, error) {
+	causes := make([]DeathCause, 0)
+	if len(ids) < 1 {
+		return &causes, nil
+	}
+	query := dbmapper.Prepare(selectGrowthDeathCause + " WHERE id IN (:ids)").With(
+		dbmapper.Param("ids", uuidParams(ids)...),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(deathCausesMapper(&causes))
+
+	if err != nil {
+		return nil, err
+	}
+	return &causes, nil
+}
+
+func (repo *BatchRepository) resolveGrowthSummaryByBatchCycleIDs(ids []uuid.UUID) (*[]CutOff, error) {
+	cutoffs := make([]CutOff, 0)
+	if len(ids) < 1 {
+		return &cutoffs, nil
+	}
+	query := dbmapper.Prepare(selectGrowthSummary + " WHERE growth_batch_cycle_id IN (:ids)").With(
+		dbmapper.Param("ids", uuidParams(ids)...),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(cutoffsMapper(&cutoffs))
+
+	if err != nil {
+		return nil, err
+	}
+	return &cutoffs, nil
+}
+
+//ResolveGrowthOpenBatchCycle lists every cycle not finished yet with its batch, pool and deaths
+func (repo *BatchRepository) ResolveGrowthOpenBatchCycle() (*[]BatchCycle, error) {
+	query := dbmapper.Prepare(selectGrowthBatchCycle + " WHERE cycle_finish IS NULL ORDER BY cycle_start ASC")
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	batchCycles := make([]BatchCycle, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(batchCyclesMapper(&batchCycles))
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i, batchCycle := range batchCycles {
+		if batch, err := repo.ResolveGrowthBatchByID(batchCycle.BatchID); err != nil {
+			return nil, err
+		} else {
+			batchCycles[i].Batch = *batch
+		}
+
+		if pool, err := repo.ResolveGrowthPoolByID(batchCycle.PoolID); err != nil {
+			return nil, err
+		} else {
+			batchCycles[i].Pool = *pool
+		}
+
+		if deaths, err := repo.ResolveGrowthDeathByBatchCycleID(batchCycle.ID); err != nil {
+			return nil, err
+		} else {
+			batchCycles[i].Deaths = *deaths
+		}
+	}
+	return &batchCycles, nil
+}
+
+func (repo *BatchRepository) InsertGrowthBatchCycle(batchCycle *BatchCycle) (*BatchCycle, error) {
+
+	//prepare query and params
+	insert := dbmapper.Prepare(insertGrowthBatchCycle).With(
+		dbmapper.Param("id", batchCycle.ID),
+		dbmapper.Param("batch", batchCycle.Batch.ID),
+		dbmapper.Param("pool", batchCycle.Pool.ID),
+		dbmapper.Param("seed_source", batchCycle.SeedSourceID),
+		dbmapper.Param("start", batchCycle.Start),
+		dbmapper.Param("weight", batchCycle.Weight),
+		dbmapper.Param("amount", batchCycle.Amount),
+		dbmapper.Param("seed_cost", batchCycle.SeedCost),
+	)
+	//validate query
+	if err := insert.Error(); err != nil {
+		return nil, err
+	} else {
+		//insert to database
+		if _, err := repo.DB.Exec(insert.SQL(), insert.Params()...); err != nil {
+			return nil, err
+		} else {
+			//find inserted data from database based on generated id
+			res, err := repo.ResolveGrowthBatchCycleByID(batchCycle.BatchID, batchCycle.ID)
+			return res, err
+		}
+	}
+}
+
+func (repo *BatchRepository) InsertGrowthBatchCycleTransaction(tx *sql.Tx, batchCycle *BatchCycle) (*BatchCycle, error) {
+	//prepare query and params
+	insert := dbmapper.Prepare(insertGrowthBatchCycle).With(
+		dbmapper.Param("id", batchCycle.ID),
+		dbmapper.Param("batch", batchCycle.Batch.ID),
+		dbmapper.Param("pool", batchCycle.Pool.ID),
+		dbmapper.Param("seed_source", batchCycle.SeedSourceID),
+		dbmapper.Param("start", batchCycle.Start),
+		dbmapper.Param("weight", batchCycle.Weight),
+		dbmapper.Param("amount", batchCycle.Amount),
+		dbmapper.Param("seed_cost", batchCycle.SeedCost),
+	)
+	//validate query
+	if err := insert.Error(); err != nil {
+		return nil, err
+	} else if _, err := tx.Exec(insert.SQL(), insert.Params()...); err != nil {
+		return nil, err
+	} else {
+		return batchCycle, nil
+	}
+}
+
+func (repo *BatchRepository) UpdateGrowthBatchCycleByIDTransaction(tx *sql.Tx, batchCycle *BatchCycle) (*BatchCycle, error) {
+	updater := dbmapper.Prepare(updateGrowthBatchCycle).With(
+		dbmapper.Param("batch", batchCycle.Batch.ID),
+		dbmapper.Param("pool", batchCycle.Pool.ID),
+		dbmapper.Param("seed_source", batchCycle.SeedSourceID),
+		dbmapper.Param("start", batchCycle.Start),
+		dbmapper.Param("finish", batchCycle.Finish),
+		dbmapper.Param("weight", batchCycle.Weight),
+		dbmapper.Param("amount", batchCycle.Amount),
+		dbmapper.Param("seed_cost", batchCycle.SeedCost),
+		dbmapper.Param("id", batchCycle.ID),
+	)
+	//validate query
+	if err := updater.Error(); err != nil {
+		return nil, err
+	} else {
+		//update to database
+		if _, err := tx.Exec(updater.SQL(), updater.Params()...); err != nil {
+			return nil, err
+		} else {
+			return batchCycle, nil
+		}
+	}
+}
+
+func (repo *BatchRepository) UpdateGrowthBatchCycleByID(batchCycle *BatchCycle) (*BatchCycle, error) {
+	//find whether if data exist
+	_, err := repo.ResolveGrowthBatchCycleByID(batchCycle.BatchID, batchCycle.ID)
+
+	if err != nil {
+		return nil, err
+	} else {
+		//update
+		//prepare query and params
+		updater := dbmapper.Prepare(updateGrowthBatchCycle).With(
+			dbmapper.Param("batch", batchCycle.Batch.ID),
+			dbmapper.Param("pool", batchCycle.Pool.ID),
+			dbmapper.Param("seed_source", batchCycle.SeedSourceID),
+			dbmapper.Param("start", batchCycle.Start),
+			dbmapper.Param("finish", batchCycle.Finish),
+			dbmapper.Param("weight", batchCycle.Weight),
+			dbmapper.Param("amount", batchCycle.Amount),
+			dbmapper.Param("seed_cost", batchCycle.SeedCost),
+			dbmapper.Param("id", batchCycle.ID),
+		)
+		//validate query
+		if err := updater.Error(); err != nil {
+			return nil, err
+		} else {
+			//update to database
+			if _, err := repo.DB.Exec(updater.SQL(), updater.Params()...); err != nil {
+				return nil, err
+			} else {
+				//find inserted data from database based on generated id
+				res, err := repo.ResolveGrowthBatchCycleByID(batchCycle.BatchID, batchCycle.ID)
+				return res, err
+			}
+		}
+	}
+}
+
+func batchCycleMapper(row *BatchCycle) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("id").As(&row.ID),
+		dbmapper.Column("growth_batch_id").As(&row.BatchID),
+		dbmapper.Column("growth_pool_id").As(&row.PoolID),
+		dbmapper.Column("growth_seed_source_id").As(&row.SeedSourceID),
+		dbmapper.Column("cycle_start").As(&row.Start),
+		dbmapper.Column("cycle_finish").As(&row.Finish),
+		dbmapper.Column("weight").As(&row.Weight),
+		dbmapper.Column("amount").As(&row.Amount),
+		dbmapper.Column("seed_cost").As(&row.SeedCost),
+		dbmapper.Column("created").As(&row.Created),
+		dbmapper.Column("updated").As(&row.Updated),
+	)
+}
+
+func batchCyclesMapper(rows *[]BatchCycle) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := BatchCycle{}
+		return batchCycleMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+//growth death
+func (repo *BatchRepository) ResolveGrowthDeathByBatchCycleID(cycleId uuid.UUID) (*[]Death, error) {
+	query := dbmapper.Prepare(selectGrowthDeath + " WHERE growth_batch_cycle_id = :cycleId ORDER BY created ASC").With(
+		dbmapper.Param("cycleId", cycleId),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	deaths := make([]Death, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(deathsMapper(&deaths))
+
+	if err != nil {
+		return nil, err
+	}
+	return &deaths, nil
+}
+
+func (repo *BatchRepository) ResolveGrowthDeathByID(deathId uuid.UUID) (*Death, error) {
+	query := dbmapper.Prepare(selectGrowthDeath + " WHERE id = :deathId").With(
+		dbmapper.Param("deathId", deathId),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	deaths := make([]Death, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(deathsMapper(&deaths))
+
+	if err != nil {
+		return nil, err
+	}
+	return &deaths[0], nil
+}
+
+func (repo *BatchRepository) InsertGrowthDeath(death *Death) (*Death, error) {
+	//prepare query and params
+	insert := dbmapper.Prepare(insertGrowthDeath).With(
+		dbmapper.Param("id", death.ID),
+		dbmapper.Param("cycleId", death.BatchCycleID),
+		dbmapper.Param("causeId", death.CauseID),
+		dbmapper.Param("death_date", death.DeathDate),
+		dbmapper.Param("weight", death.Weight),
+		dbmapper.Param("amount", death.Amount),
+		dbmapper.Param("remarks", death.Remarks),
+	)
+	//validate query
+	if err := insert.Error(); err != nil {
+		return nil, err
+	} else if _, err := repo.DB.Exec(insert.SQL(), insert.Params()...); err != nil {
+		return nil, err
+	} else if result, err := repo.ResolveGrowthDeathByID(death.ID); err != nil {
+		return nil, err
+	} else {
+		return result, nil
+	}
+}
+
+func (repo *BatchRepository) InsertGrowthDeathTransaction(tx *sql.Tx, death *Death) (*Death, error) {
+	//prepare query and params
+	insert := dbmapper.Prepare(insertGrowthDeath).With(
+		dbmapper.Param("id", death.ID),
+		dbmapper.Param("cycleId", death.BatchCycleID),
+		dbmapper.Param("causeId", death.CauseID),
+		dbmapper.Param("death_date", death.DeathDate),
+		dbmapper.Param("weight", death.Weight),
+		dbmapper.Param("amount", death.Amount),
+		dbmapper.Param("remarks", death.Remarks),
+	)
+	//validate query
+	if err := insert.Error(); err != nil {
+		return nil, err
+	} else if _, err := tx.Exec(insert.SQL(), insert.Params()...); err != nil {
+		return nil, err
+	} else {
+		return death, nil
+	}
+}
+
+func deathMapper(row *Death) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("id").As(&row.ID),
+		dbmapper.Column("growth_batch_cycle_id").As(&row.BatchCycleID),
+		dbmapper.Column("growth_death_cause_id").As(&row.CauseID),
+		dbmapper.Column("death_date").As(&row.DeathDate),
+		dbmapper.Column("weight").As(&row.Weight),
+		dbmapper.Column("amount").As(&row.Amount),
+		dbmapper.Column("created").As(&row.Created),
+		dbmapper.Column("remarks").As(&row.Remarks),
+	)
+}
+
+func deathsMapper(rows *[]Death) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := Death{}
+		return deathMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+//growth death cause
+func (repo *BatchRepository) ResolveGrowthDeathCause(deleted string) (*[]DeathCause, error) {
+	where := ""
+	if deleted == Deleted_True {
+		where = " WHERE deleted = 1"
+	} else if deleted == Deleted_False {
+		where = " WHERE deleted = 0"
+	}
+	query := dbmapper.Prepare(selectGrowthDeathCause + where + " ORDER BY name ASC")
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	causes := make([]DeathCause, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(deathCausesMapper(&causes))
+
+	if err != nil {
+		return nil, err
+	}
+	return &causes, nil
+}
+
+func (repo *BatchRepository) ResolveGrowthDeathCauseByID(id uuid.UUID) (*DeathCause, error) {
+	query := dbmapper.Prepare(selectGrowthDeathCause + " WHERE id = :id").With(
+		dbmapper.Param("id", id),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	causes := make([]DeathCause, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(deathCausesMapper(&causes))
+
+	if err != nil {
+		return nil, err
+	}
+	if len(causes) < 1 {
+		return nil, fmt.Errorf("death cause with id %s not found", id)
+	}
+	return &causes[0], nil
+}
+
+func (repo *BatchRepository) InsertGrowthDeathCause(cause *DeathCause) (*DeathCause, error) {
+	//prepare query and params
+	insert := dbmapper.Prepare(insertGrowthDeathCause).With(
+		dbmapper.Param("id", cause.ID),
+		dbmapper.Param("name", cause.Name),
+		dbmapper.Param("description", cause.Description),
+		dbmapper.Param("deleted", cause.Deleted),
+	)
+	//validate query
+	if err := insert.Error(); err != nil {
+		return nil, err
+	} else if _, err := repo.DB.Exec(insert.SQL(), insert.Params()...); err != nil {
+		return nil, err
+	} else if result, err := repo.ResolveGrowthDeathCauseByID(cause.ID); err != nil {
+		return nil, err
+	} else {
+		return result, nil
+	}
+}
+
+func (repo *BatchRepository) UpdateGrowthDeathCauseByID(cause *DeathCause) (*DeathCause, error) {
+	//find whether if data exist
+	if _, err := repo.ResolveGrowthDeathCauseByID(cause.ID); err != nil {
+		return nil, err
+	}
+	updater := dbmapper.Prepare(updateGrowthDeathCause).With(
+		dbmapper.Param("name", cause.Name),
+		dbmapper.Param("description", cause.Description),
+		dbmapper.Param("deleted", cause.Deleted),
+		dbmapper.Param("id", cause.ID),
+	)
+	//validate query
+	if err := updater.Error(); err != nil {
+		return nil, err
+	} else if _, err := repo.DB.Exec(updater.SQL(), updater.Params()...); err != nil {
+		return nil, err
+	} else if result, err := repo.ResolveGrowthDeathCauseByID(cause.ID); err != nil {
+		return nil, err
+	} else {
+		return result, nil
+	}
+}
+
+func (repo *BatchRepository) RemoveGrowthDeathCauseByID(id uuid.UUID) (*DeathCause, error) {
+	//find whether if data exist
+	if _, err := repo.ResolveGrowthDeathCauseByID(id); err != nil {
+		return nil, err
+	}
+	remover := dbmapper.Prepare(deleteGrowthDeathCause).With(
+		dbmapper.Param("id", id),
+	)
+	//validate query
+	if err := remover.Error(); err != nil {
+		return nil, err
+	} else if _, err := repo.DB.Exec(remover.SQL(), remover.Params()...); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func deathCauseMapper(row *DeathCause) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("id").As(&row.ID),
+		dbmapper.Column("name").As(&row.Name),
+		dbmapper.Column("description").As(&row.Description),
+		dbmapper.Column("deleted").As(&row.Deleted),
+		dbmapper.Column("created").As(&row.Created),
+		dbmapper.Column("updated").As(&row.Updated),
+	)
+}
+
+func deathCausesMapper(rows *[]DeathCause) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := DeathCause{}
+		return deathCauseMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+//mortality report, to is exclusive
+func (repo *BatchRepository) ResolveGrowthMortality(from time.Time, to time.Time, batchId uuid.UUID, poolId uuid.UUID) (*[]MortalityRecord, error) {
+	where := ""
+	params := []*dbmapper.QueryParam{
+		dbmapper.Param("from", from),
+		dbmapper.Param("to", to),
+		dbmapper.Param("until", to),
+	}
+	if batchId != uuid.Nil {
+		where = where + " AND bc.growth_batch_id = :batchId"
+		params = append(params, dbmapper.Param("batchId", batchId))
+	}
+	if poolId != uuid.Nil {
+		where = where + " AND bc.growth_pool_id = :poolId"
+		params = append(params, dbmapper.Param("poolId", poolId))
+	}
+	query := dbmapper.Prepare(selectGrowthMortality + where + " ORDER BY d.growth_batch_cycle_id ASC, d.death_date ASC").With(params...)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	records := make([]MortalityRecord, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(mortalityRecordsMapper(&records))
+
+	if err != nil {
+		return nil, err
+	}
+	return &records, nil
+}
+
+func mortalityRecordMapper(row *MortalityRecord) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("id").As(&row.DeathID),
+		dbmapper.Column("growth_batch_cycle_id").As(&row.BatchCycleID),
+		dbmapper.Column("death_date").As(&row.DeathDate),
+		dbmapper.Column("amount").As(&row.Amount),
+		dbmapper.Column("weight").As(&row.Weight),
+		dbmapper.Column("cause_id").As(&row.CauseID),
+		dbmapper.Column("cause_name").As(&row.CauseName),
+		dbmapper.Column("batch_id").As(&row.BatchID),
+		dbmapper.Column("batch_name").As(&row.BatchName),
+		dbmapper.Column("pool_id").As(&row.PoolID),
+		dbmapper.Column("pool_name").As(&row.PoolName),
+		dbmapper.Column("stocked").As(&row.Stocked),
+		dbmapper.Column("cycle_start").As(&row.Start),
+	)
+}
+
+func mortalityRecordsMapper(rows *[]MortalityRecord) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := MortalityRecord{}
+		return mortalityRecordMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+//feeding
+func (repo *BatchRepository) ResolveGrowthFeedingByBatchCycleID(cycleId uuid.UUID) (*[]Feeding, error) {
+	query := dbmapper.Prepare(selectGrowthFeeding + " WHERE growth_batch_cycle_id = :cycleId ORDER BY created ASC").With(
+		dbmapper.Param("cycleId", cycleId),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	feedings := make([]Feeding, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(feedingsMapper(&feedings))
+
+	if err != nil {
+		return nil, err
+	}
+	return &feedings, nil
+}
+
+func (repo *BatchRepository) ResolveGrowthFeedingByID(FeedingId uuid.UUID) (*Feeding, error) {
+	query := dbmapper.Prepare(selectGrowthFeeding + " WHERE id = :FeedingId").With(
+		dbmapper.Param("FeedingId", FeedingId),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	feedings := make([]Feeding, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(feedingsMapper(&feedings))
+
+	if err != nil {
+		return nil, err
+	} else {
+		return &feedings[0], nil
+	}
+}
+
+//InsertGrowthFeeding stores a feeding with the usages taking it off the feed lots and
+//the cost history it changes, stock that does not cover the feeding fails it as well
+func (repo *BatchRepository) InsertGrowthFeeding(feeding *Feeding) (*Feeding, error) {
+	if tx, err := repo.DB.Begin(); err != nil {
+		return nil, err
+	} else if usages, err := repo.FeedRepository.ConsumeFeedLotTransaction(tx, feeding.FeedType.ID, feed.Lot_Usage_Feeding, feeding.ID, feeding.Qty); err != nil {
+		tx.Rollback()
+		return nil, err
+	} else if _, err := repo.InsertGrowthFeedingTransaction(tx, feeding); err != nil {
+		tx.Rollback()
+		return nil, err
+	} else if _, err := repo.FeedRepository.RecalculateFeedTypeCostTransaction(tx, feeding.FeedType.ID); err != nil {
+		tx.Rollback()
+		return nil, err
+	} else if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return nil, err
+	} else if result, err := repo.ResolveGrowthFeedingByID(feeding.ID); err != nil {
+		return nil, err
+	} else {
+		result.Lots = *usages
+		return result, nil
+	}
+}
+
+func (repo *BatchRepository) InsertGrowthFeedingTransaction(tx *sql.Tx, feeding *Feeding) (*Feeding, error) {
+	//prepare query and params
+	insert := dbmapper.Prepare(insertGrowthFeeding).With(
+		dbmapper.Param("id", feeding.ID),
+		dbmapper.Param("cycleId", feeding.BatchCycleID),
+		dbmapper.Param("feedTypeId", feeding.FeedType.ID),
+		dbmapper.Param("feeding_date", feeding.FeedingDate),
+		dbmapper.Param("qty", feeding.Qty),
+		dbmapper.Param("remarks", feeding.Remarks),
+	)
+	//validate query
+	if err := insert.Error(); err != nil {
+		return nil, err
+	} else if _, err := tx.Exec(insert.SQL(), insert.Params()...); err != nil {
+		return nil, err
+	} else {
+		return feeding, nil
+	}
+}
+
+func feedingMapper(row *Feeding) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("id").As(&row.ID),
+		dbmapper.Column("growth_batch_cycle_id").As(&row.BatchCycleID),
+		dbmapper.Column("feed_type_id").As(&row.FeedTypeID),
+		dbmapper.Column("feeding_date").As(&row.FeedingDate),
+		dbmapper.Column("qty").As(&row.Qty),
+		dbmapper.Column("remarks").As(&row.Remarks),
+		dbmapper.Column("created").As(&row.Created),
+	)
+}
+
+func feedingsMapper(rows *[]Feeding) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := Feeding{}
+		return feedingMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+//growth summary
+func (repo *BatchRepository) UpdateGrowthBatchCycleAndInsertGrowthSummaryTransaction(batchCycle *BatchCycle, cutoff *CutOff) (*CutOff, error) {
+	if tx, err := repo.DB.Begin(); err != nil {
+		return nil, err
+	} else if _, err := repo.UpdateGrowthBatchCycleByIDTransaction(tx, batchCycle); err != nil {
+		tx.Rollback()
+		return nil, err
+	} else if _, err := repo.InsertGrowthSummaryTransaction(tx, cutoff); err != nil {
+		tx.Rollback()
+		return nil, err
+	} else if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return nil, err
+	} else if result, err := repo.ResolveGrowthSummaryByID(cutoff.ID); err != nil {
+		return nil, err
+	} else {
+		result.BatchID = batchCycle.BatchID
+		return result, nil
+	}
+}
+
+func (repo *BatchRepository) ResolveGrowthSummaryByBatchCycleID(cycleId uuid.UUID) (*CutOff, error) {
+	query := dbmapper.Prepare(selectGrowthSummary + " WHERE growth_batch_cycle_id = :cycleId").With(
+		dbmapper.Param("cycleId", cycleId),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	cutoffs := make([]CutOff, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(cutoffsMapper(&cutoffs))
+
+	if err != nil {
+		return nil, err
+	} else if len(cutoffs) < 1 {
+		return nil, nil
+	} else {
+		return &cutoffs[0], nil
+	}
+}
+
+func (repo *BatchRepository) ResolveGrowthSummaryByID(summaryId uuid.UUID) (*CutOff, error) {
+	query := dbmapper.Prepare(selectGrowthSummary + " WHERE id = :summaryId").With(
+		dbmapper.Param("summaryId", summaryId),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	cutoffs := make([]CutOff, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(cutoffsMapper(&cutoffs))
+
+	if err != nil {
+		return nil, err
+	} else {
+		return &cutoffs[0], nil
+	}
+}
+
+func (repo *BatchRepository) InsertGrowthSummary(cutoff *CutOff) (*CutOff, error) {
+	//prepare query and params
+	insert := dbmapper.Prepare(insertGrowthSummary).With(
+		dbmapper.Param("id", cutoff.ID),
+		dbmapper.Param("cycleId", cutoff.BatchCycleID),
+		dbmapper.Param("summary_date", cutoff.SummaryDate),
+		dbmapper.Param("weight", cutoff.Weight),
+		dbmapper.Param("amount", cutoff.Amount),
+		dbmapper.Param("adg", cutoff.ADG),
+		dbmapper.Param("fcr", cutoff.FCR),
+		dbmapper.Param("sr", cutoff.SR),
+	)
+	log.Print("insert:", insert.SQL(), "\n")
+	log.Print("params:", insert.Params(), "\n")
+	//validate query
+	if err := insert.Error(); err != nil {
+		return nil, err
+	} else if _, err := repo.DB.Exec(insert.SQL(), insert.Params()...); err != nil {
+		return nil, err
+	} else if result, err := repo.ResolveGrowthSummaryByID(cutoff.ID); err != nil {
+		return nil, err
+	} else {
+		result.BatchID = cutoff.BatchID
+		return result, nil
+	}
+}
+
+func (repo *BatchRepository) InsertGrowthSummaryTransaction(tx *sql.Tx, cutoff *CutOff) (*CutOff, error) {
+	//prepare query and params
+	insert := dbmapper.Prepare(insertGrowthSummary).With(
 		dbmapper.Param("id", cutoff.ID),
 		dbmapper.Param("cycleId", cutoff.BatchCycleID),
 		dbmapper.Param("summary_date", cutoff.SummaryDate),
@@ -1021,219 +2635,773 @@ func (repo *BatchRepository) InsertGrowthSummaryTransaction(tx *sql.Tx, cutoff *
 		dbmapper.Param("sr", cutoff.SR),
 	)
 	//validate query
-	if err := insert.Error(); err != nil {
+	if err := insert.Error(); err != nil {
+		return nil, err
+	} else if _, err := tx.Exec(insert.SQL(), insert.Params()...); err != nil {
+		return nil, err
+	} else {
+		return cutoff, nil
+	}
+}
+
+func cutoffMapper(row *CutOff) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("id").As(&row.ID),
+		dbmapper.Column("growth_batch_cycle_id").As(&row.BatchCycleID),
+		dbmapper.Column("summary_date").As(&row.SummaryDate),
+		dbmapper.Column("weight").As(&row.Weight),
+		dbmapper.Column("amount").As(&row.Amount),
+		dbmapper.Column("adg").As(&row.ADG),
+		dbmapper.Column("fcr").As(&row.FCR),
+		dbmapper.Column("sr").As(&row.SR),
+		dbmapper.Column("created").As(&row.Created),
+	)
+}
+
+func cutoffsMapper(rows *[]CutOff) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := CutOff{}
+		return cutoffMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+//growth sales
+func (repo *BatchRepository) ResolveGrowthSalesByID(salesId uuid.UUID) (*Sales, error) {
+	query := dbmapper.Prepare(selectGrowthSales + " WHERE id = :salesId").With(
+		dbmapper.Param("salesId", salesId),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	sales := make([]Sales, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(salesMapper(&sales))
+
+	if err != nil {
+		return nil, err
+	} else if detail, err := repo.ResolveGrowthSalesDetailBySalesID(salesId); err != nil {
+		return nil, err
+	} else {
+		sales[0].Detail = *detail
+		return &sales[0], nil
+	}
+}
+
+func (repo *BatchRepository) ResolveGrowthSales(from time.Time, to time.Time) (*[]Sales, error) {
+	query := dbmapper.Prepare(selectGrowthSales+" WHERE sales_date >= :from AND sales_date < :to ORDER BY sales_date ASC").With(
+		dbmapper.Param("from", from),
+		dbmapper.Param("to", to),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	sales := make([]Sales, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(salesMapper(&sales))
+
+	if err != nil {
+		return nil, err
+	}
+	return &sales, nil
+}
+
+func (repo *BatchRepository) InsertGrowthSales(sales *Sales) (*Sales, error) {
+	//prepare query and params
+	insert := dbmapper.Prepare(insertGrowthSales).With(
+		dbmapper.Param("id", sales.ID),
+		dbmapper.Param("sales_date", sales.SalesDate),
+		dbmapper.Param("qty", sales.Qty),
+		dbmapper.Param("reference", sales.Reference),
+	)
+	//validate query
+	if err := insert.Error(); err != nil {
+		return nil, err
+	} else if _, err := repo.DB.Exec(insert.SQL(), insert.Params()...); err != nil {
+		return nil, err
+	} else if result, err := repo.ResolveGrowthSalesByID(sales.ID); err != nil {
+		return nil, err
+	} else {
+		return result, nil
+	}
+}
+
+func (repo *BatchRepository) UpdateGrowthSalesByID(sales *Sales) (*Sales, error) {
+	//find whether if data exist
+	if _, err := repo.ResolveGrowthSalesByID(sales.ID); err != nil {
+		return nil, err
+	} else {
+		//update
+		//prepare query and params
+		updater := dbmapper.Prepare(updateGrowthSales).With(
+			dbmapper.Param("sales_date", sales.SalesDate),
+			dbmapper.Param("qty", sales.Qty),
+			dbmapper.Param("reference", sales.Reference),
+			dbmapper.Param("id", sales.ID),
+		)
+		//validate query
+		if err := updater.Error(); err != nil {
+			return nil, err
+		} else {
+			//update to database
+			if _, err := repo.DB.Exec(updater.SQL(), updater.Params()...); err != nil {
+				return nil, err
+			} else {
+				//find inserted data from database based on generated id
+				res, err := repo.ResolveGrowthSalesByID(sales.ID)
+				return res, err
+			}
+		}
+	}
+}
+
+func saleMapper(row *Sales) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("id").As(&row.ID),
+		dbmapper.Column("sales_date").As(&row.SalesDate),
+		dbmapper.Column("qty").As(&row.Qty),
+		dbmapper.Column("reference").As(&row.Reference),
+		dbmapper.Column("created").As(&row.Created),
+		dbmapper.Column("updated").As(&row.Updated),
+	)
+}
+
+func salesMapper(rows *[]Sales) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := Sales{}
+		return saleMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+//growth sales detail
+func (repo *BatchRepository) ResolveGrowthSalesDetailBySalesID(salesId uuid.UUID) (*[]SalesDetail, error) {
+	query := dbmapper.Prepare(selectGrowthSalesDetail + " WHERE sales_id = :salesId").With(
+		dbmapper.Param("salesId", salesId),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	detail := make([]SalesDetail, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(salesDetailsMapper(&detail))
+
+	if err != nil {
+		return nil, err
+	} else {
+		return &detail, nil
+	}
+}
+
+func (repo *BatchRepository) ResolveGrowthSalesDetailByBatchCycleID(cycleId uuid.UUID) (*[]SalesDetail, error) {
+	query := dbmapper.Prepare(selectGrowthSalesDetail + " WHERE growth_batch_cycle_id = :cycleId").With(
+		dbmapper.Param("cycleId", cycleId),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	detail := make([]SalesDetail, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(salesDetailsMapper(&detail))
+
+	if err != nil {
+		return nil, err
+	} else {
+		return &detail, nil
+	}
+}
+
+func salesDetailMapper(row *SalesDetail) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("id").As(&row.ID),
+		dbmapper.Column("sales_id").As(&row.SalesID),
+		dbmapper.Column("growth_batch_cycle_id").As(&row.BatchCycleID),
+		dbmapper.Column("amount").As(&row.Amount),
+		dbmapper.Column("weight").As(&row.Weight),
+		dbmapper.Column("price").As(&row.Price),
+		dbmapper.Column("created").As(&row.Created),
+		dbmapper.Column("updated").As(&row.Updated),
+	)
+}
+
+func salesDetailsMapper(rows *[]SalesDetail) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := SalesDetail{}
+		return salesDetailMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+func (repo *BatchRepository) UpdateGrowthBatchCycleInsertGrowthSummaryAndInsertSalesDetail(batchCycle *[]BatchCycle, cutoff *[]CutOff, sales *Sales) (*Sales, error) {
+	if tx, err := repo.DB.Begin(); err != nil {
+		return nil, err
+	} else {
+		for _, detail := range sales.Detail {
+			if _, err := repo.InsertGrowthSalesDetailTransaction(tx, &detail); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+		for _, bc := range *batchCycle {
+			if _, err := repo.UpdateGrowthBatchCycleByIDTransaction(tx, &bc); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+		for _, c := range *cutoff {
+			if _, err := repo.InsertGrowthSummaryTransaction(tx, &c); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			tx.Rollback()
+			return nil, err
+		} else if result, err := repo.ResolveGrowthSalesByID(sales.ID); err != nil {
+			return nil, err
+		} else {
+			return result, nil
+		}
+	}
+}
+
+func (repo *BatchRepository) InsertGrowthSalesDetailTransaction(tx *sql.Tx, detail *SalesDetail) (*SalesDetail, error) {
+	//prepare query and params
+	insert := dbmapper.Prepare(insertGrowthSalesDetail).With(
+		dbmapper.Param("id", detail.ID),
+		dbmapper.Param("sales_id", detail.SalesID),
+		dbmapper.Param("batch_cycle_id", detail.BatchCycleID),
+		dbmapper.Param("weight", detail.Weight),
+		dbmapper.Param("amount", detail.Amount),
+		dbmapper.Param("price", detail.Price),
+	)
+	//validate query
+	if err := insert.Error(); err != nil {
+		return nil, err
+	} else if _, err := tx.Exec(insert.SQL(), insert.Params()...); err != nil {
+		return nil, err
+	} else {
+		return detail, nil
+	}
+}
+
+//growth cost
+func (repo *BatchRepository) ResolveGrowthCostByBatchCycleID(cycleId uuid.UUID) (*[]Cost, error) {
+	query := dbmapper.Prepare(selectGrowthCost + " WHERE growth_batch_cycle_id = :cycleId ORDER BY cost_date ASC").With(
+		dbmapper.Param("cycleId", cycleId),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	costs := make([]Cost, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(costsMapper(&costs))
+
+	if err != nil {
+		return nil, err
+	}
+	return &costs, nil
+}
+
+func (repo *BatchRepository) ResolveGrowthCostByID(costId uuid.UUID) (*Cost, error) {
+	query := dbmapper.Prepare(selectGrowthCost + " WHERE id = :costId").With(
+		dbmapper.Param("costId", costId),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	costs := make([]Cost, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(costsMapper(&costs))
+
+	if err != nil {
+		return nil, err
+	}
+	if len(costs) < 1 {
+		return nil, fmt.Errorf("growth cost with id %s not found", costId)
+	}
+	return &costs[0], nil
+}
+
+func (repo *BatchRepository) InsertGrowthCost(cost *Cost) (*Cost, error) {
+	//prepare query and params
+	insert := dbmapper.Prepare(insertGrowthCost).With(
+		dbmapper.Param("id", cost.ID),
+		dbmapper.Param("cycleId", cost.BatchCycleID),
+		dbmapper.Param("cost_date", cost.CostDate),
+		dbmapper.Param("description", cost.Description),
+		dbmapper.Param("amount", cost.Amount),
+	)
+	//validate query
+	if err := insert.Error(); err != nil {
+		return nil, err
+	} else if _, err := repo.DB.Exec(insert.SQL(), insert.Params()...); err != nil {
+		return nil, err
+	} else if result, err := repo.ResolveGrowthCostByID(cost.ID); err != nil {
+		return nil, err
+	} else {
+		return result, nil
+	}
+}
+
+func costMapper(row *Cost) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("id").As(&row.ID),
+		dbmapper.Column("growth_batch_cycle_id").As(&row.BatchCycleID),
+		dbmapper.Column("cost_date").As(&row.CostDate),
+		dbmapper.Column("description").As(&row.Description),
+		dbmapper.Column("amount").As(&row.Amount),
+		dbmapper.Column("created").As(&row.Created),
+	)
+}
+
+func costsMapper(rows *[]Cost) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := Cost{}
+		return costMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+//growth treatment
+func (repo *BatchRepository) ResolveGrowthTreatmentByBatchCycleID(cycleId uuid.UUID) (*[]Treatment, error) {
+	query := dbmapper.Prepare(selectGrowthTreatment + " WHERE growth_batch_cycle_id = :cycleId ORDER BY start_date ASC").With(
+		dbmapper.Param("cycleId", cycleId),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	treatments := make([]Treatment, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(treatmentsMapper(&treatments))
+
+	if err != nil {
+		return nil, err
+	}
+	return &treatments, nil
+}
+
+func (repo *BatchRepository) ResolveGrowthTreatmentByID(treatmentId uuid.UUID) (*Treatment, error) {
+	query := dbmapper.Prepare(selectGrowthTreatment + " WHERE id = :treatmentId").With(
+		dbmapper.Param("treatmentId", treatmentId),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	treatments := make([]Treatment, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(treatmentsMapper(&treatments))
+
+	if err != nil {
+		return nil, err
+	}
+	if len(treatments) < 1 {
+		return nil, fmt.Errorf("growth treatment with id %s not found", treatmentId)
+	}
+	return &treatments[0], nil
+}
+
+func (repo *BatchRepository) InsertGrowthTreatment(treatment *Treatment) (*Treatment, error) {
+	//prepare query and params
+	insert := dbmapper.Prepare(insertGrowthTreatment).With(
+		dbmapper.Param("id", treatment.ID),
+		dbmapper.Param("cycleId", treatment.BatchCycleID),
+		dbmapper.Param("product", treatment.Product),
+		dbmapper.Param("dose", treatment.Dose),
+		dbmapper.Param("unit", treatment.Unit),
+		dbmapper.Param("method", treatment.Method),
+		dbmapper.Param("start_date", treatment.StartDate),
+		dbmapper.Param("end_date", treatment.EndDate),
+		dbmapper.Param("withdrawal_days", treatment.WithdrawalDays),
+		dbmapper.Param("remarks", treatment.Remarks),
+	)
+	//validate query
+	if err := insert.Error(); err != nil {
+		return nil, err
+	} else if _, err := repo.DB.Exec(insert.SQL(), insert.Params()...); err != nil {
+		return nil, err
+	} else if result, err := repo.ResolveGrowthTreatmentByID(treatment.ID); err != nil {
+		return nil, err
+	} else {
+		return result, nil
+	}
+}
+
+func (repo *BatchRepository) UpdateGrowthTreatmentByID(treatment *Treatment) (*Treatment, error) {
+	//prepare query and params
+	update := dbmapper.Prepare(updateGrowthTreatment).With(
+		dbmapper.Param("id", treatment.ID),
+		dbmapper.Param("product", treatment.Product),
+		dbmapper.Param("dose", treatment.Dose),
+		dbmapper.Param("unit", treatment.Unit),
+		dbmapper.Param("method", treatment.Method),
+		dbmapper.Param("start_date", treatment.StartDate),
+		dbmapper.Param("end_date", treatment.EndDate),
+		dbmapper.Param("withdrawal_days", treatment.WithdrawalDays),
+		dbmapper.Param("remarks", treatment.Remarks),
+	)
+	//validate query
+	if err := update.Error(); err != nil {
 		return nil, err
-	} else if _, err := tx.Exec(insert.SQL(), insert.Params()...); err != nil {
+	} else if _, err := repo.DB.Exec(update.SQL(), update.Params()...); err != nil {
+		return nil, err
+	} else if result, err := repo.ResolveGrowthTreatmentByID(treatment.ID); err != nil {
 		return nil, err
 	} else {
-		return cutoff, nil
+		return result, nil
 	}
 }
 
-func cutoffMapper(row *CutOff) *dbmapper.MappedColumns {
-	return dbmapper.Columns(
-		dbmapper.Column("id").As(&row.ID),
-		dbmapper.Column("growth_batch_cycle_id").As(&row.BatchCycleID),
-		dbmapper.Column("summary_date").As(&row.SummaryDate),
-		dbmapper.Column("weight").As(&row.Weight),
-		dbmapper.Column("amount").As(&row.Amount),
-		dbmapper.Column("adg").As(&row.ADG),
-		dbmapper.Column("fcr").As(&row.FCR),
-		dbmapper.Column("sr").As(&row.SR),
-		dbmapper.Column("created").As(&row.Created),
+func (repo *BatchRepository) ResolveGrowthTreatmentOverrideByBatchCycleID(cycleId uuid.UUID) (*[]TreatmentOverride, error) {
+	query := dbmapper.Prepare(selectGrowthTreatmentOverride + " WHERE growth_batch_cycle_id = :cycleId ORDER BY created ASC").With(
+		dbmapper.Param("cycleId", cycleId),
 	)
-}
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	overrides := make([]TreatmentOverride, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(treatmentOverridesMapper(&overrides))
 
-func cutoffsMapper(rows *[]CutOff) dbmapper.RowMapper {
-	return func() *dbmapper.MappedColumns {
-		row := CutOff{}
-		return cutoffMapper(&row).Then(func() error {
-			*rows = append(*rows, row)
-			return nil
-		})
+	if err != nil {
+		return nil, err
 	}
+	return &overrides, nil
 }
 
-//growth sales
-func (repo *BatchRepository) ResolveGrowthSalesByID(salesId uuid.UUID) (*Sales, error) {
-	query := dbmapper.Prepare(selectGrowthSales + " WHERE id = :salesId").With(
-		dbmapper.Param("salesId", salesId),
+func (repo *BatchRepository) ResolveGrowthTreatmentOverrideByID(overrideId uuid.UUID) (*TreatmentOverride, error) {
+	query := dbmapper.Prepare(selectGrowthTreatmentOverride + " WHERE id = :overrideId").With(
+		dbmapper.Param("overrideId", overrideId),
 	)
 	if err := query.Error(); err != nil {
 		return nil, err
 	}
-	sales := make([]Sales, 0)
-	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(salesMapper(&sales))
+	overrides := make([]TreatmentOverride, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(treatmentOverridesMapper(&overrides))
 
 	if err != nil {
 		return nil, err
-	} else if detail, err := repo.ResolveGrowthSalesDetailBySalesID(salesId); err != nil {
-		return nil, err
-	} else {
-		sales[0].Detail = *detail
-		return &sales[0], nil
 	}
+	if len(overrides) < 1 {
+		return nil, fmt.Errorf("growth treatment override with id %s not found", overrideId)
+	}
+	return &overrides[0], nil
 }
 
-func (repo *BatchRepository) InsertGrowthSales(sales *Sales) (*Sales, error) {
+func (repo *BatchRepository) InsertGrowthTreatmentOverride(override *TreatmentOverride) (*TreatmentOverride, error) {
 	//prepare query and params
-	insert := dbmapper.Prepare(insertGrowthSales).With(
-		dbmapper.Param("id", sales.ID),
-		dbmapper.Param("sales_date", sales.SalesDate),
-		dbmapper.Param("qty", sales.Qty),
-		dbmapper.Param("reference", sales.Reference),
+	insert := dbmapper.Prepare(insertGrowthTreatmentOverride).With(
+		dbmapper.Param("id", override.ID),
+		dbmapper.Param("cycleId", override.BatchCycleID),
+		dbmapper.Param("treatmentId", override.TreatmentID),
+		dbmapper.Param("authorized_by", override.AuthorizedBy),
+		dbmapper.Param("reason", override.Reason),
 	)
 	//validate query
 	if err := insert.Error(); err != nil {
 		return nil, err
 	} else if _, err := repo.DB.Exec(insert.SQL(), insert.Params()...); err != nil {
 		return nil, err
-	} else if result, err := repo.ResolveGrowthSalesByID(sales.ID); err != nil {
+	} else if result, err := repo.ResolveGrowthTreatmentOverrideByID(override.ID); err != nil {
 		return nil, err
 	} else {
 		return result, nil
 	}
 }
 
-func (repo *BatchRepository) UpdateGrowthSalesByID(sales *Sales) (*Sales, error) {
-	//find whether if data exist
-	if _, err := repo.ResolveGrowthSalesByID(sales.ID); err != nil {
-		return nil, err
-	} else {
-		//update
-		//prepare query and params
-		updater := dbmapper.Prepare(updateGrowthSales).With(
-			dbmapper.Param("sales_date", sales.SalesDate),
-			dbmapper.Param("qty", sales.Qty),
-			dbmapper.Param("reference", sales.Reference),
-			dbmapper.Param("id", sales.ID),
-		)
-		//validate query
-		if err := updater.Error(); err != nil {
-			return nil, err
-		} else {
-			//update to database
-			if _, err := repo.DB.Exec(updater.SQL(), updater.Params()...); err != nil {
-				return nil, err
-			} else {
-				//find inserted data from database based on generated id
-				res, err := repo.ResolveGrowthSalesByID(sales.ID)
-				return res, err
-			}
-		}
+func treatmentMapper(row *Treatment) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("id").As(&row.ID),
+		dbmapper.Column("growth_batch_cycle_id").As(&row.BatchCycleID),
+		dbmapper.Column("product").As(&row.Product),
+		dbmapper.Column("dose").As(&row.Dose),
+		dbmapper.Column("unit").As(&row.Unit),
+		dbmapper.Column("method").As(&row.Method),
+		dbmapper.Column("start_date").As(&row.StartDate),
+		dbmapper.Column("end_date").As(&row.EndDate),
+		dbmapper.Column("withdrawal_days").As(&row.WithdrawalDays),
+		dbmapper.Column("remarks").As(&row.Remarks),
+		dbmapper.Column("created").As(&row.Created),
+		dbmapper.Column("updated").As(&row.Updated),
+	)
+}
+
+func treatmentsMapper(rows *[]Treatment) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := Treatment{}
+		return treatmentMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
 	}
 }
 
-func saleMapper(row *Sales) *dbmapper.MappedColumns {
+func treatmentOverrideMapper(row *TreatmentOverride) *dbmapper.MappedColumns {
 	return dbmapper.Columns(
 		dbmapper.Column("id").As(&row.ID),
-		dbmapper.Column("sales_date").As(&row.SalesDate),
-		dbmapper.Column("qty").As(&row.Qty),
-		dbmapper.Column("reference").As(&row.Reference),
+		dbmapper.Column("growth_batch_cycle_id").As(&row.BatchCycleID),
+		dbmapper.Column("growth_treatment_id").As(&row.TreatmentID),
+		dbmapper.Column("authorized_by").As(&row.AuthorizedBy),
+		dbmapper.Column("reason").As(&row.Reason),
 		dbmapper.Column("created").As(&row.Created),
-		dbmapper.Column("updated").As(&row.Updated),
 	)
 }
 
-func salesMapper(rows *[]Sales) dbmapper.RowMapper {
+func treatmentOverridesMapper(rows *[]TreatmentOverride) dbmapper.RowMapper {
 	return func() *dbmapper.MappedColumns {
-		row := Sales{}
-		return saleMapper(&row).Then(func() error {
+		row := TreatmentOverride{}
+		return treatmentOverrideMapper(&row).Then(func() error {
 			*rows = append(*rows, row)
 			return nil
 		})
 	}
 }
 
-//growth sales detail
-func (repo *BatchRepository) ResolveGrowthSalesDetailBySalesID(salesId uuid.UUID) (*[]SalesDetail, error) {
-	query := dbmapper.Prepare(selectGrowthSalesDetail + " WHERE sales_id = :salesId").With(
+//sales trace
+func (repo *BatchRepository) ResolveGrowthSalesTraceBySalesID(salesId uuid.UUID) (*[]SalesTrace, error) {
+	query := dbmapper.Prepare(selectGrowthSalesTrace).With(
 		dbmapper.Param("salesId", salesId),
 	)
 	if err := query.Error(); err != nil {
 		return nil, err
 	}
-	detail := make([]SalesDetail, 0)
-	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(salesDetailsMapper(&detail))
+	traces := make([]SalesTrace, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(salesTracesMapper(&traces))
 
 	if err != nil {
 		return nil, err
-	} else {
-		return &detail, nil
 	}
+	return &traces, nil
 }
 
-func salesDetailMapper(row *SalesDetail) *dbmapper.MappedColumns {
+func salesTraceMapper(row *SalesTrace) *dbmapper.MappedColumns {
 	return dbmapper.Columns(
-		dbmapper.Column("id").As(&row.ID),
-		dbmapper.Column("sales_id").As(&row.SalesID),
+		dbmapper.Column("sales_detail_id").As(&row.SalesDetailID),
 		dbmapper.Column("growth_batch_cycle_id").As(&row.BatchCycleID),
 		dbmapper.Column("amount").As(&row.Amount),
 		dbmapper.Column("weight").As(&row.Weight),
-		dbmapper.Column("created").As(&row.Created),
-		dbmapper.Column("updated").As(&row.Updated),
+		dbmapper.Column("growth_batch_id").As(&row.BatchID),
+		dbmapper.Column("growth_pool_id").As(&row.PoolID),
+		dbmapper.Column("growth_seed_source_id").As(&row.SeedSourceID),
+		dbmapper.Column("cycle_start").As(&row.Start),
 	)
 }
 
-func salesDetailsMapper(rows *[]SalesDetail) dbmapper.RowMapper {
+func salesTracesMapper(rows *[]SalesTrace) dbmapper.RowMapper {
 	return func() *dbmapper.MappedColumns {
-		row := SalesDetail{}
-		return salesDetailMapper(&row).Then(func() error {
+		row := SalesTrace{}
+		return salesTraceMapper(&row).Then(func() error {
 			*rows = append(*rows, row)
 			return nil
 		})
 	}
 }
 
-func (repo *BatchRepository) UpdateGrowthBatchCycleInsertGrowthSummaryAndInsertSalesDetail(batchCycle *[]BatchCycle, cutoff *[]CutOff, sales *Sales) (*Sales, error) {
-	if tx, err := repo.DB.Begin(); err != nil {
+//growth sampling
+func (repo *BatchRepository) ResolveGrowthSamplingByBatchCycleID(cycleId uuid.UUID) (*[]Sampling, error) {
+	query := dbmapper.Prepare(selectGrowthSampling + " WHERE growth_batch_cycle_id = :cycleId ORDER BY sampling_date ASC").With(
+		dbmapper.Param("cycleId", cycleId),
+	)
+	if err := query.Error(); err != nil {
 		return nil, err
-	} else {
-		for _, detail := range sales.Detail {
-			if _, err := repo.InsertGrowthSalesDetailTransaction(tx, &detail); err != nil {
-				tx.Rollback()
-				return nil, err
-			}
-		}
-		for _, bc := range *batchCycle {
-			if _, err := repo.UpdateGrowthBatchCycleByIDTransaction(tx, &bc); err != nil {
-				tx.Rollback()
-				return nil, err
-			}
-		}
-		for _, c := range *cutoff {
-			if _, err := repo.InsertGrowthSummaryTransaction(tx, &c); err != nil {
-				tx.Rollback()
-				return nil, err
-			}
-		}
+	}
+	samplings := make([]Sampling, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(samplingsMapper(&samplings))
 
-		if err := tx.Commit(); err != nil {
-			tx.Rollback()
-			return nil, err
-		} else if result, err := repo.ResolveGrowthSalesByID(sales.ID); err != nil {
-			return nil, err
-		} else {
-			return result, nil
-		}
+	if err != nil {
+		return nil, err
 	}
+	return &samplings, nil
 }
 
-func (repo *BatchRepository) InsertGrowthSalesDetailTransaction(tx *sql.Tx, detail *SalesDetail) (*SalesDetail, error) {
+func (repo *BatchRepository) ResolveGrowthSamplingByID(samplingId uuid.UUID) (*Sampling, error) {
+	query := dbmapper.Prepare(selectGrowthSampling + " WHERE id = :samplingId").With(
+		dbmapper.Param("samplingId", samplingId),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	samplings := make([]Sampling, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(samplingsMapper(&samplings))
+
+	if err != nil {
+		return nil, err
+	}
+	if len(samplings) < 1 {
+		return nil, fmt.Errorf("growth sampling with id %s not found", samplingId)
+	}
+	return &samplings[0], nil
+}
+
+func (repo *BatchRepository) InsertGrowthSampling(sampling *Sampling) (*Sampling, error) {
 	//prepare query and params
-	insert := dbmapper.Prepare(insertGrowthSalesDetail).With(
-		dbmapper.Param("id", detail.ID),
-		dbmapper.Param("sales_id", detail.SalesID),
-		dbmapper.Param("batch_cycle_id", detail.BatchCycleID),
-		dbmapper.Param("weight", detail.Weight),
-		dbmapper.Param("amount", detail.Amount),
+	insert := dbmapper.Prepare(insertGrowthSampling).With(
+		dbmapper.Param("id", sampling.ID),
+		dbmapper.Param("cycleId", sampling.BatchCycleID),
+		dbmapper.Param("sampling_date", sampling.SamplingDate),
+		dbmapper.Param("sample_size", sampling.SampleSize),
+		dbmapper.Param("average_weight", sampling.AverageWeight),
+		dbmapper.Param("remarks", sampling.Remarks),
 	)
 	//validate query
 	if err := insert.Error(); err != nil {
 		return nil, err
 	} else if _, err := repo.DB.Exec(insert.SQL(), insert.Params()...); err != nil {
 		return nil, err
+	} else if result, err := repo.ResolveGrowthSamplingByID(sampling.ID); err != nil {
+		return nil, err
 	} else {
-		return detail, nil
+		return result, nil
+	}
+}
+
+func samplingMapper(row *Sampling) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("id").As(&row.ID),
+		dbmapper.Column("growth_batch_cycle_id").As(&row.BatchCycleID),
+		dbmapper.Column("sampling_date").As(&row.SamplingDate),
+		dbmapper.Column("sample_size").As(&row.SampleSize),
+		dbmapper.Column("average_weight").As(&row.AverageWeight),
+		dbmapper.Column("remarks").As(&row.Remarks),
+		dbmapper.Column("created").As(&row.Created),
+	)
+}
+
+func samplingsMapper(rows *[]Sampling) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := Sampling{}
+		return samplingMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+//cycle report
+func (repo *BatchRepository) ResolveGrowthCycleReport() (*[]CycleReport, error) {
+	query := dbmapper.Prepare(selectGrowthCycleReport)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	reports := make([]CycleReport, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(cycleReportsMapper(&reports))
+
+	if err != nil {
+		return nil, err
+	}
+	return &reports, nil
+}
+
+func cycleReportMapper(row *CycleReport) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("id").As(&row.BatchCycleID),
+		dbmapper.Column("batch_id").As(&row.BatchID),
+		dbmapper.Column("batch_name").As(&row.BatchName),
+		dbmapper.Column("pool_id").As(&row.PoolID),
+		dbmapper.Column("pool_name").As(&row.PoolName),
+		dbmapper.Column("pool_area").As(&row.PoolArea),
+		dbmapper.Column("species_id").As(&row.SpeciesID),
+		dbmapper.Column("species_name").As(&row.SpeciesName),
+		dbmapper.Column("cycle_start").As(&row.Start),
+		dbmapper.Column("stocked").As(&row.Stocked),
+		dbmapper.Column("summary_date").As(&row.SummaryDate),
+		dbmapper.Column("harvested").As(&row.Harvested),
+		dbmapper.Column("harvested_amount").As(&row.HarvestedAmount),
+		dbmapper.Column("adg").As(&row.ADG),
+		dbmapper.Column("fcr").As(&row.FCR),
+		dbmapper.Column("sr").As(&row.SR),
+	)
+}
+
+func cycleReportsMapper(rows *[]CycleReport) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := CycleReport{}
+		return cycleReportMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+//export
+func deletedFilter(deleted string) string {
+	if deleted == Deleted_True {
+		return " WHERE deleted = 1"
+	} else if deleted == Deleted_False {
+		return " WHERE deleted = 0"
+	}
+	return ""
+}
+
+func (repo *BatchRepository) StreamGrowthBatch(deleted string, fn func(*Batch) error) error {
+	query := dbmapper.Prepare(selectGrowthBatch + deletedFilter(deleted) + " ORDER BY name ASC")
+	if err := query.Error(); err != nil {
+		return err
+	}
+	return Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(func() *dbmapper.MappedColumns {
+		row := Batch{}
+		return batchMapper(&row).Then(func() error {
+			return fn(&row)
+		})
+	})
+}
+
+func (repo *BatchRepository) StreamGrowthPool(deleted string, fn func(*Pool) error) error {
+	query := dbmapper.Prepare(selectGrowthPool + deletedFilter(deleted) + " ORDER BY name ASC")
+	if err := query.Error(); err != nil {
+		return err
+	}
+	return Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(func() *dbmapper.MappedColumns {
+		row := Pool{}
+		return poolMapper(&row).Then(func() error {
+			return fn(&row)
+		})
+	})
+}
+
+func (repo *BatchRepository) StreamGrowthBatchCycle(batchId uuid.UUID, fn func(*BatchCycle) error) error {
+	query := dbmapper.Prepare(selectGrowthBatchCycle + " WHERE growth_batch_id = :batchId ORDER BY created ASC").With(
+		dbmapper.Param("batchId", batchId),
+	)
+	if err := query.Error(); err != nil {
+		return err
+	}
+	return Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(func() *dbmapper.MappedColumns {
+		row := BatchCycle{}
+		return batchCycleMapper(&row).Then(func() error {
+			return fn(&row)
+		})
+	})
+}
+
+func (repo *BatchRepository) StreamGrowthSalesDetail(from time.Time, to time.Time, fn func(*Sales, *SalesDetail) error) error {
+	query := dbmapper.Prepare(selectGrowthSalesLine).With(
+		dbmapper.Param("from", from),
+		dbmapper.Param("to", to),
+	)
+	if err := query.Error(); err != nil {
+		return err
 	}
+	return Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(func() *dbmapper.MappedColumns {
+		sales := Sales{}
+		detail := SalesDetail{}
+		return salesLineMapper(&sales, &detail).Then(func() error {
+			return fn(&sales, &detail)
+		})
+	})
+}
+
+func salesLineMapper(sales *Sales, detail *SalesDetail) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("id").As(&detail.ID),
+		dbmapper.Column("sales_id").As(&detail.SalesID),
+		dbmapper.Column("growth_batch_cycle_id").As(&detail.BatchCycleID),
+		dbmapper.Column("amount").As(&detail.Amount),
+		dbmapper.Column("weight").As(&detail.Weight),
+		dbmapper.Column("price").As(&detail.Price),
+		dbmapper.Column("created").As(&detail.Created),
+		dbmapper.Column("updated").As(&detail.Updated),
+		dbmapper.Column("sales_date").As(&sales.SalesDate),
+		dbmapper.Column("reference").As(&sales.Reference),
+	)
 }
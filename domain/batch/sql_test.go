@@ -0,0 +1,237 @@
+package batch
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/livestockz/api/database/databasetest"
+	uuid "github.com/satori/go.uuid"
+)
+
+func TestBatchPage(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *sql.DB) {
+		repo := &BatchRepository{DB: db}
+		ids := make([]uuid.UUID, 0)
+		for _, name := range []string{"Batch C", "Batch A", "Batch B"} {
+			batch, err := repo.InsertGrowthBatch(&Batch{ID: uuid.Must(uuid.NewV4()), Name: name, Status: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, batch.ID)
+		}
+
+		first, _, _, total, err := repo.ResolveGrowthBatchPage(0, 2, Deleted_Any)
+		if err != nil {
+			t.Fatal(err)
+		} else if total != 3 || len(*first) != 2 || (*first)[0].Name != "Batch A" {
+			t.Fatalf("first page: got %d of %d rows, %v", len(*first), total, *first)
+		}
+		second, _, _, _, err := repo.ResolveGrowthBatchPage(1, 2, Deleted_Any)
+		if err != nil {
+			t.Fatal(err)
+		} else if len(*second) != 1 || (*second)[0].Name != "Batch C" {
+			t.Fatalf("second page: got %v", *second)
+		}
+
+		if _, err := repo.RemoveGrowthBatchByID(ids[0]); err != nil {
+			t.Fatal(err)
+		}
+		if batch, err := repo.ResolveGrowthBatchByID(ids[0]); err != nil {
+			t.Fatal(err)
+		} else if !batch.Deleted {
+			t.Fatal("removed batch is not flagged deleted")
+		}
+		if deleted, _, _, total, err := repo.ResolveGrowthBatchPage(0, 10, Deleted_True); err != nil {
+			t.Fatal(err)
+		} else if total != 1 || len(*deleted) != 1 || (*deleted)[0].ID != ids[0] {
+			t.Fatalf("deleted page: got %d of %d rows", len(*deleted), total)
+		}
+	})
+}
+
+func TestBatchCycleDeath(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *sql.DB) {
+		repo := &BatchRepository{DB: db}
+		batch, err := repo.InsertGrowthBatch(&Batch{ID: uuid.Must(uuid.NewV4()), Name: "Batch", Status: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		pool, err := repo.InsertGrowthPool(&Pool{ID: uuid.Must(uuid.NewV4()), Name: "Pool", Status: Pool_Assigned, Area: 120.5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+		cycle, err := repo.InsertGrowthBatchCycle(&BatchCycle{
+			ID:      uuid.Must(uuid.NewV4()),
+			Batch:   *batch,
+			BatchID: batch.ID,
+			Pool:    *pool,
+			PoolID:  pool.ID,
+			Start:   start,
+			Weight:  12.5,
+			Amount:  1000,
+		})
+		if err != nil {
+			t.Fatal(err)
+		} else if cycle.Amount != 1000 || cycle.Weight != 12.5 || cycle.Finish.Valid {
+			t.Fatalf("cycle read back as %+v", cycle)
+		} else if cycle.Start.Format("2006-01-02") != "2024-03-01" {
+			t.Fatalf("cycle start read back as %s", cycle.Start)
+		}
+
+		causes, err := repo.ResolveGrowthDeathCause(Deleted_False)
+		if err != nil {
+			t.Fatal(err)
+		} else if len(*causes) != 5 {
+			t.Fatalf("expected the 5 seeded death causes, got %d", len(*causes))
+		}
+		cause := (*causes)[0]
+		if _, err := repo.InsertGrowthDeath(&Death{
+			ID:           uuid.Must(uuid.NewV4()),
+			BatchCycleID: cycle.ID,
+			CauseID:      cause.ID,
+			DeathDate:    start.AddDate(0, 0, 3),
+			Weight:       0.4,
+			Amount:       10,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		deaths, err := repo.ResolveGrowthDeathByBatchCycleID(cycle.ID)
+		if err != nil {
+			t.Fatal(err)
+		} else if len(*deaths) != 1 || (*deaths)[0].Amount != 10 || (*deaths)[0].CauseID != cause.ID {
+			t.Fatalf("deaths read back as %+v", *deaths)
+		}
+		if page, _, _, _, err := repo.ResolveGrowthBatchCyclePage(batch.ID, 0, 10, []string{Cycle_Include_Deaths}); err != nil {
+			t.Fatal(err)
+		} else if deaths := (*page)[0].Deaths; len(deaths) != 1 || deaths[0].Cause.Name != cause.Name {
+			t.Fatalf("page read the deaths back as %+v", deaths)
+		}
+	})
+}
+
+func TestSalesCutOff(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *sql.DB) {
+		repo := &BatchRepository{DB: db}
+		batch, err := repo.InsertGrowthBatch(&Batch{ID: uuid.Must(uuid.NewV4()), Name: "Batch", Status: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		pool, err := repo.InsertGrowthPool(&Pool{ID: uuid.Must(uuid.NewV4()), Name: "Pool", Status: Pool_Assigned})
+		if err != nil {
+			t.Fatal(err)
+		}
+		start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+		cycle, err := repo.InsertGrowthBatchCycle(&BatchCycle{
+			ID:      uuid.Must(uuid.NewV4()),
+			Batch:   *batch,
+			BatchID: batch.ID,
+			Pool:    *pool,
+			PoolID:  pool.ID,
+			Start:   start,
+			Weight:  12.5,
+			Amount:  1000,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sales, err := repo.InsertGrowthSales(&Sales{ID: uuid.Must(uuid.NewV4()), SalesDate: start.AddDate(0, 3, 0), Reference: "INV-1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		//a cutoff of a cycle that does not exist fails the whole transaction
+		sales.Detail = []SalesDetail{{ID: uuid.Must(uuid.NewV4()), SalesID: sales.ID, BatchID: batch.ID, BatchCycleID: cycle.ID, Weight: 400, Amount: 950, Price: 2.5}}
+		cycles := []BatchCycle{}
+		cutoffs := []CutOff{{ID: uuid.Must(uuid.NewV4()), BatchID: batch.ID, BatchCycleID: uuid.Must(uuid.NewV4()), SummaryDate: sales.SalesDate, Weight: 400, Amount: 950}}
+		if _, err := repo.UpdateGrowthBatchCycleInsertGrowthSummaryAndInsertSalesDetail(&cycles, &cutoffs, sales); err == nil {
+			t.Fatal("cutoff of an unknown cycle was stored")
+		} else if detail, err := repo.ResolveGrowthSalesDetailBySalesID(sales.ID); err != nil {
+			t.Fatal(err)
+		} else if len(*detail) != 0 {
+			t.Fatalf("sales detail kept after a rollback: %+v", *detail)
+		}
+
+		cycle.Finish = null.TimeFrom(sales.SalesDate)
+		cycles = []BatchCycle{*cycle}
+		cutoffs[0].BatchCycleID = cycle.ID
+		result, err := repo.UpdateGrowthBatchCycleInsertGrowthSummaryAndInsertSalesDetail(&cycles, &cutoffs, sales)
+		if err != nil {
+			t.Fatal(err)
+		} else if len(result.Detail) != 1 || result.Detail[0].Amount != 950 || result.Detail[0].Price != 2.5 {
+			t.Fatalf("sales detail read back as %+v", result.Detail)
+		}
+		if finished, err := repo.ResolveGrowthBatchCycleByID(batch.ID, cycle.ID); err != nil {
+			t.Fatal(err)
+		} else if !finished.Finish.Valid {
+			t.Fatal("sold cycle is not finished")
+		}
+		if cutoff, err := repo.ResolveGrowthSummaryByBatchCycleID(cycle.ID); err != nil {
+			t.Fatal(err)
+		} else if cutoff.Amount != 950 || cutoff.Weight != 400 {
+			t.Fatalf("cutoff read back as %+v", cutoff)
+		}
+	})
+}
+
+func TestBatchCyclePage(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *sql.DB) {
+		repo := &BatchRepository{DB: db}
+		batch, err := repo.InsertGrowthBatch(&Batch{ID: uuid.Must(uuid.NewV4()), Name: "Batch", Status: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+		cycles := make([]BatchCycle, 0)
+		for _, name := range []string{"Pool A", "Pool B", "Pool C"} {
+			pool, err := repo.InsertGrowthPool(&Pool{ID: uuid.Must(uuid.NewV4()), Name: name, Status: Pool_Assigned})
+			if err != nil {
+				t.Fatal(err)
+			}
+			cycle, err := repo.InsertGrowthBatchCycle(&BatchCycle{ID: uuid.Must(uuid.NewV4()), Batch: *batch, BatchID: batch.ID, Pool: *pool, PoolID: pool.ID, Start: start, Weight: 10, Amount: 1000})
+			if err != nil {
+				t.Fatal(err)
+			}
+			cycles = append(cycles, *cycle)
+		}
+		causes, err := repo.ResolveGrowthDeathCause(Deleted_False)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, amount := range []float64{10, 5} {
+			if _, err := repo.InsertGrowthDeath(&Death{ID: uuid.Must(uuid.NewV4()), BatchCycleID: cycles[1].ID, CauseID: (*causes)[0].ID, DeathDate: start.AddDate(0, 0, 3), Amount: amount}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := repo.InsertGrowthSummary(&CutOff{ID: uuid.Must(uuid.NewV4()), BatchCycleID: cycles[2].ID, SummaryDate: start.AddDate(0, 2, 0), Weight: 40, Amount: 900}); err != nil {
+			t.Fatal(err)
+		}
+
+		page, _, _, total, err := repo.ResolveGrowthBatchCyclePage(batch.ID, 0, 10, CycleIncludes)
+		if err != nil {
+			t.Fatal(err)
+		} else if total != 3 || len(*page) != 3 {
+			t.Fatalf("got %d of %d cycles", len(*page), total)
+		}
+		for i, cycle := range *page {
+			if cycle.Batch.ID != batch.ID || cycle.Pool.ID != cycles[i].PoolID || cycle.Feeding == nil {
+				t.Fatalf("cycle %d loaded as %+v", i, cycle)
+			}
+		}
+		if deaths := (*page)[1].Deaths; len((*page)[0].Deaths) != 0 || len(deaths) != 2 || deaths[0].Amount != 10 {
+			t.Fatalf("deaths loaded as %+v", deaths)
+		}
+		if cutoff := (*page)[2].CutOff; (*page)[1].CutOff.ID != uuid.Nil || cutoff.Amount != 900 || cutoff.BatchID != batch.ID {
+			t.Fatalf("cut off loaded as %+v", cutoff)
+		}
+
+		page, _, _, _, err = repo.ResolveGrowthBatchCyclePage(batch.ID, 0, 10, []string{Cycle_Include_Deaths})
+		if err != nil {
+			t.Fatal(err)
+		} else if cycle := (*page)[2]; cycle.Feeding != nil || cycle.CutOff.ID != uuid.Nil || len((*page)[1].Deaths) != 2 {
+			t.Fatalf("deaths only page loaded %+v", *page)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package dashboard
+
+import (
+	"time"
+
+	"github.com/satori/go.uuid"
+)
+
+//Dashboard is the farm home screen, quantities are kg and amounts are heads
+type Dashboard struct {
+	Date      time.Time       `json:"date"`
+	Pools     []PoolStatus    `json:"pools"`
+	Cycles    []OpenCycle     `json:"cycles"`
+	Biomass   float64         `json:"biomass"`
+	Feeding   FeedConsumption `json:"feeding"`
+	FeedStock []FeedStock     `json:"feed_stock"`
+	Deaths    []RecentDeath   `json:"deaths"`
+	Sales     SalesSummary    `json:"sales"`
+}
+
+type PoolStatus struct {
+	Status string `json:"status"`
+	Total  int32  `json:"total"`
+}
+
+//OpenCycle weight is the latest sampled average in grams per head, or the
+//stocking average when the cycle has not been sampled
+type OpenCycle struct {
+	ID        uuid.UUID `json:"id"`
+	BatchID   uuid.UUID `json:"batch_id"`
+	BatchName string    `json:"batch_name"`
+	PoolID    uuid.UUID `json:"pool_id"`
+	PoolName  string    `json:"pool_name"`
+	Start     time.Time `json:"start"`
+	Stocked   float64   `json:"stocked"`
+	Alive     float64   `json:"alive"`
+	Weight    float64   `json:"weight"`
+	Biomass   float64   `json:"biomass"`
+}
+
+type FeedConsumption struct {
+	Today       float64 `json:"today"`
+	MonthToDate float64 `json:"month_to_date"`
+}
+
+type FeedStock struct {
+	FeedTypeID uuid.UUID `json:"feed_type_id"`
+	Name       string    `json:"name"`
+	Qty        float64   `json:"qty"`
+}
+
+type RecentDeath struct {
+	ID        uuid.UUID `json:"id"`
+	DeathDate time.Time `json:"death_date"`
+	Amount    float64   `json:"amount"`
+	Weight    float64   `json:"weight"`
+	Cause     string    `json:"cause"`
+	BatchName string    `json:"batch_name"`
+	PoolName  string    `json:"pool_name"`
+}
+
+type SalesSummary struct {
+	Weight  float64 `json:"weight"`
+	Revenue float64 `json:"revenue"`
+}
@@ -0,0 +1,69 @@
+package dashboard
+
+import (
+	"fmt"
+	"time"
+)
+
+type Service interface {
+	ResolveDashboard(date time.Time) (*Dashboard, error)
+}
+
+//deaths of the last week shown on the dashboard
+const (
+	recentDeathDays  = 7
+	recentDeathLimit = 10
+)
+
+type DashboardService struct {
+	DashboardRepository Repository `inject:"dashboardRepository"`
+}
+
+func (svc *DashboardService) ResolveDashboard(date time.Time) (*Dashboard, error) {
+	today := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	month := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+	tomorrow := today.AddDate(0, 0, 1)
+	dashboard := Dashboard{Date: today}
+
+	if pools, err := svc.DashboardRepository.ResolvePoolStatus(); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+		dashboard.Pools = *pools
+	}
+
+	if cycles, err := svc.DashboardRepository.ResolveOpenCycle(); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+		//weight is grams per head and biomass is kg
+		for i, cycle := range *cycles {
+			(*cycles)[i].Biomass = cycle.Alive * cycle.Weight / 1000
+			dashboard.Biomass = dashboard.Biomass + (*cycles)[i].Biomass
+		}
+		dashboard.Cycles = *cycles
+	}
+
+	if feeding, err := svc.DashboardRepository.ResolveFeedConsumption(today, month, tomorrow); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+		dashboard.Feeding = *feeding
+	}
+
+	if stocks, err := svc.DashboardRepository.ResolveFeedStock(); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+		dashboard.FeedStock = *stocks
+	}
+
+	if deaths, err := svc.DashboardRepository.ResolveRecentDeath(today.AddDate(0, 0, -recentDeathDays), recentDeathLimit); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+		dashboard.Deaths = *deaths
+	}
+
+	if sales, err := svc.DashboardRepository.ResolveSalesSummary(month, tomorrow); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+		dashboard.Sales = *sales
+	}
+	return &dashboard, nil
+}
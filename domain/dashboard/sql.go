@@ -0,0 +1,271 @@
+package dashboard
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/ncrypthic/dbmapper"
+	. "github.com/ncrypthic/dbmapper/dialects/mysql"
+)
+
+type Repository interface {
+	ResolvePoolStatus() (*[]PoolStatus, error)
+	ResolveOpenCycle() (*[]OpenCycle, error)
+	ResolveFeedConsumption(today time.Time, month time.Time, until time.Time) (*FeedConsumption, error)
+	ResolveFeedStock() (*[]FeedStock, error)
+	ResolveRecentDeath(since time.Time, limit int32) (*[]RecentDeath, error)
+	ResolveSalesSummary(from time.Time, to time.Time) (*SalesSummary, error)
+}
+
+const (
+	selectPoolStatus = `SELECT status, COUNT(*) AS total FROM growth_pool WHERE deleted = 0 GROUP BY status ORDER BY status ASC`
+	//open cycle, alive heads are the stocked ones minus deaths and sold heads
+	selectOpenCycle = `SELECT bc.id, b.id AS batch_id, b.name AS batch_name, p.id AS pool_id, p.name AS pool_name, bc.cycle_start, bc.amount AS stocked,
+		bc.amount - COALESCE(d.amount, 0) - COALESCE(sd.amount, 0) AS alive,
+		COALESCE(s.average_weight, bc.weight / NULLIF(bc.amount, 0) * 1000, 0) AS weight
+		FROM growth_batch_cycle bc
+		JOIN growth_batch b ON b.id = bc.growth_batch_id
+		JOIN growth_pool p ON p.id = bc.growth_pool_id
+		LEFT JOIN (SELECT growth_batch_cycle_id, SUM(amount) AS amount FROM growth_death GROUP BY growth_batch_cycle_id) d ON d.growth_batch_cycle_id = bc.id
+		LEFT JOIN (SELECT growth_batch_cycle_id, SUM(amount) AS amount FROM growth_sales_detail GROUP BY growth_batch_cycle_id) sd ON sd.growth_batch_cycle_id = bc.id
+		LEFT JOIN (SELECT gs.growth_batch_cycle_id, AVG(gs.average_weight) AS average_weight FROM growth_sampling gs
+			JOIN (SELECT growth_batch_cycle_id, MAX(sampling_date) AS sampling_date FROM growth_sampling GROUP BY growth_batch_cycle_id) latest
+			ON latest.growth_batch_cycle_id = gs.growth_batch_cycle_id AND latest.sampling_date = gs.sampling_date
+			GROUP BY gs.growth_batch_cycle_id) s ON s.growth_batch_cycle_id = bc.id
+		WHERE bc.cycle_finish IS NULL ORDER BY bc.cycle_start ASC`
+	selectFeedConsumption = `SELECT COALESCE(SUM(CASE WHEN feeding_date >= :today THEN qty ELSE 0 END), 0) AS today, COALESCE(SUM(qty), 0) AS month_to_date
+		FROM growth_feeding WHERE feeding_date >= :month AND feeding_date < :until`
+	//feed stock, the same ledger the feed domain keeps of incomings, feedings and adjustments
+	selectFeedStock = `SELECT ft.id AS feed_type_id, ft.name, COALESCE(SUM(stock.qty), 0) AS qty FROM feed_type ft
+		LEFT JOIN (SELECT feed_type_id, qty FROM feed_incoming
+		UNION ALL SELECT feed_type_id, 0 - qty AS qty FROM growth_feeding
+		UNION ALL SELECT feed_type_id, qty FROM feed_adjustment) stock ON stock.feed_type_id = ft.id
+		WHERE ft.deleted = 0 GROUP BY ft.id, ft.name ORDER BY ft.name ASC`
+	selectRecentDeath = `SELECT d.id, d.death_date, d.amount, d.weight, c.name AS cause, b.name AS batch_name, p.name AS pool_name
+		FROM growth_death d
+		JOIN growth_death_cause c ON c.id = d.growth_death_cause_id
+		JOIN growth_batch_cycle bc ON bc.id = d.growth_batch_cycle_id
+		JOIN growth_batch b ON b.id = bc.growth_batch_id
+		JOIN growth_pool p ON p.id = bc.growth_pool_id
+		WHERE d.death_date >= :since ORDER BY d.death_date DESC, d.created DESC LIMIT :limit`
+	selectSalesSummary = `SELECT COALESCE(SUM(sd.weight), 0) AS weight, COALESCE(SUM(sd.weight * sd.price), 0) AS revenue
+		FROM growth_sales_detail sd JOIN growth_sales s ON s.id = sd.sales_id
+		WHERE s.sales_date >= :from AND s.sales_date < :to`
+)
+
+type DashboardRepository struct {
+	DB *sql.DB `inject:"db"`
+}
+
+func (repo *DashboardRepository) ResolvePoolStatus() (*[]PoolStatus, error) {
+	query := dbmapper.Prepare(selectPoolStatus)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	statuses := make([]PoolStatus, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(poolStatusesMapper(&statuses))
+
+	if err != nil {
+		return nil, err
+	}
+	return &statuses, nil
+}
+
+func (repo *DashboardRepository) ResolveOpenCycle() (*[]OpenCycle, error) {
+	query := dbmapper.Prepare(selectOpenCycle)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	cycles := make([]OpenCycle, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(openCyclesMapper(&cycles))
+
+	if err != nil {
+		return nil, err
+	}
+	return &cycles, nil
+}
+
+func (repo *DashboardRepository) ResolveFeedConsumption(today time.Time, month time.Time, until time.Time) (*FeedConsumption, error) {
+	query := dbmapper.Prepare(selectFeedConsumption).With(
+		dbmapper.Param("today", today),
+		dbmapper.Param("month", month),
+		dbmapper.Param("until", until),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	consumptions := make([]FeedConsumption, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(feedConsumptionsMapper(&consumptions))
+
+	if err != nil {
+		return nil, err
+	} else if len(consumptions) < 1 {
+		return &FeedConsumption{}, nil
+	}
+	return &consumptions[0], nil
+}
+
+func (repo *DashboardRepository) ResolveFeedStock() (*[]FeedStock, error) {
+	query := dbmapper.Prepare(selectFeedStock)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	stocks := make([]FeedStock, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(feedStocksMapper(&stocks))
+
+	if err != nil {
+		return nil, err
+	}
+	return &stocks, nil
+}
+
+func (repo *DashboardRepository) ResolveRecentDeath(since time.Time, limit int32) (*[]RecentDeath, error) {
+	query := dbmapper.Prepare(selectRecentDeath).With(
+		dbmapper.Param("since", since),
+		dbmapper.Param("limit", limit),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	deaths := make([]RecentDeath, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(recentDeathsMapper(&deaths))
+
+	if err != nil {
+		return nil, err
+	}
+	return &deaths, nil
+}
+
+func (repo *DashboardRepository) ResolveSalesSummary(from time.Time, to time.Time) (*SalesSummary, error) {
+	query := dbmapper.Prepare(selectSalesSummary).With(
+		dbmapper.Param("from", from),
+		dbmapper.Param("to", to),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	summaries := make([]SalesSummary, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(salesSummariesMapper(&summaries))
+
+	if err != nil {
+		return nil, err
+	} else if len(summaries) < 1 {
+		return &SalesSummary{}, nil
+	}
+	return &summaries[0], nil
+}
+
+func poolStatusMapper(row *PoolStatus) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("status").As(&row.Status),
+		dbmapper.Column("total").As(&row.Total),
+	)
+}
+
+func poolStatusesMapper(rows *[]PoolStatus) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := PoolStatus{}
+		return poolStatusMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+func openCycleMapper(row *OpenCycle) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("id").As(&row.ID),
+		dbmapper.Column("batch_id").As(&row.BatchID),
+		dbmapper.Column("batch_name").As(&row.BatchName),
+		dbmapper.Column("pool_id").As(&row.PoolID),
+		dbmapper.Column("pool_name").As(&row.PoolName),
+		dbmapper.Column("cycle_start").As(&row.Start),
+		dbmapper.Column("stocked").As(&row.Stocked),
+		dbmapper.Column("alive").As(&row.Alive),
+		dbmapper.Column("weight").As(&row.Weight),
+	)
+}
+
+func openCyclesMapper(rows *[]OpenCycle) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := OpenCycle{}
+		return openCycleMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+func feedConsumptionMapper(row *FeedConsumption) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("today").As(&row.Today),
+		dbmapper.Column("month_to_date").As(&row.MonthToDate),
+	)
+}
+
+func feedConsumptionsMapper(rows *[]FeedConsumption) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := FeedConsumption{}
+		return feedConsumptionMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+func feedStockMapper(row *FeedStock) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("feed_type_id").As(&row.FeedTypeID),
+		dbmapper.Column("name").As(&row.Name),
+		dbmapper.Column("qty").As(&row.Qty),
+	)
+}
+
+func feedStocksMapper(rows *[]FeedStock) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := FeedStock{}
+		return feedStockMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+func recentDeathMapper(row *RecentDeath) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("id").As(&row.ID),
+		dbmapper.Column("death_date").As(&row.DeathDate),
+		dbmapper.Column("amount").As(&row.Amount),
+		dbmapper.Column("weight").As(&row.Weight),
+		dbmapper.Column("cause").As(&row.Cause),
+		dbmapper.Column("batch_name").As(&row.BatchName),
+		dbmapper.Column("pool_name").As(&row.PoolName),
+	)
+}
+
+func recentDeathsMapper(rows *[]RecentDeath) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := RecentDeath{}
+		return recentDeathMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+func salesSummaryMapper(row *SalesSummary) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("weight").As(&row.Weight),
+		dbmapper.Column("revenue").As(&row.Revenue),
+	)
+}
+
+func salesSummariesMapper(rows *[]SalesSummary) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := SalesSummary{}
+		return salesSummaryMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
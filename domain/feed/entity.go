@@ -14,12 +14,64 @@ const (
 	Feed_Incoming   string = "incoming"
 	Feed_Outgoing   string = "outgoing"
 	Feed_Adjustment string = "adjustment"
+	//feed lot usage reference
+	Lot_Usage_Feeding    string = "feeding"
+	Lot_Usage_Adjustment string = "adjustment"
+	Lot_Usage_Incoming   string = "incoming"
+	//feed adjustment reason
+	Adjustment_Reason_Manual    string = "manual"
+	Adjustment_Reason_Stocktake string = "stocktake"
+	//stocktake status
+	Stocktake_Open     string = "open"
+	Stocktake_Approved string = "approved"
+	//feed stage
+	Feed_Stage_Starter  string = "starter"
+	Feed_Stage_Grower   string = "grower"
+	Feed_Stage_Finisher string = "finisher"
+	//every feed qty is stored in the base unit
+	Unit_Base string = "kg"
 )
 
+//BaseUnits converts the units every feed type understands to the base unit
+var BaseUnits = map[string]float64{
+	"kg":  1,
+	"g":   0.001,
+	"ton": 1000,
+}
+
 type FeedType struct {
+	ID           uuid.UUID      `json:"id"`
+	Name         string         `json:"name"`
+	Unit         string         `json:"unit"`
+	Status       int32          `json:"status"`
+	AverageCost  float64        `json:"average_cost"`
+	Protein      float64        `json:"protein"`
+	Fat          float64        `json:"fat"`
+	PelletSize   float64        `json:"pellet_size"`
+	Stage        string         `json:"stage"`
+	ReorderPoint float64        `json:"reorder_point"`
+	ReorderQty   float64        `json:"reorder_qty"`
+	Units        []FeedTypeUnit `json:"units,omitempty"`
+	Deleted      bool           `json:"deleted"`
+	Created      time.Time      `json:"created"`
+	Updated      null.Time      `json:"updated"`
+}
+
+type FeedTypeUnit struct {
+	ID         uuid.UUID `json:"id"`
+	FeedTypeID uuid.UUID `json:"feed_type_id"`
+	Unit       string    `json:"unit"`
+	Factor     float64   `json:"factor"`
+	Created    time.Time `json:"created"`
+	Updated    null.Time `json:"updated"`
+}
+
+type Supplier struct {
 	ID      uuid.UUID `json:"id"`
 	Name    string    `json:"name"`
-	Unit    string    `json:"unit"`
+	Contact string    `json:"contact"`
+	Phone   string    `json:"phone"`
+	Address string    `json:"address"`
 	Status  int32     `json:"status"`
 	Deleted bool      `json:"deleted"`
 	Created time.Time `json:"created"`
@@ -27,19 +79,129 @@ type FeedType struct {
 }
 
 type FeedIncoming struct {
-	ID         uuid.UUID `json:"id"`
-	FeedType   FeedType  `json:"feed_type"`
-	FeedTypeID uuid.UUID `json:"-"`
-	Qty        float64   `json:"qty"`
-	Remarks    string    `json:"remarks"`
-	Created    time.Time `json:"created"`
+	ID           uuid.UUID     `json:"id"`
+	FeedType     FeedType      `json:"feed_type"`
+	FeedTypeID   uuid.UUID     `json:"-"`
+	Supplier     *Supplier     `json:"supplier"`
+	SupplierID   uuid.NullUUID `json:"-"`
+	IncomingDate time.Time     `json:"incoming_date"`
+	Qty          float64       `json:"qty"`
+	Unit         string        `json:"unit,omitempty"`
+	Price        float64       `json:"price"`
+	Invoice      string        `json:"invoice"`
+	LotNumber    string        `json:"lot_number"`
+	Manufactured null.Time     `json:"manufactured"`
+	Expired      null.Time     `json:"expired"`
+	Remarks      string        `json:"remarks"`
+	Created      time.Time     `json:"created"`
+}
+
+type FeedLot struct {
+	ID             uuid.UUID `json:"id"`
+	FeedType       FeedType  `json:"feed_type"`
+	FeedTypeID     uuid.UUID `json:"-"`
+	FeedIncomingID uuid.UUID `json:"feed_incoming_id"`
+	LotNumber      string    `json:"lot_number"`
+	Manufactured   null.Time `json:"manufactured"`
+	Expired        null.Time `json:"expired"`
+	Qty            float64   `json:"qty"`
+	Remaining      float64   `json:"remaining"`
+	Created        time.Time `json:"created"`
+	Updated        null.Time `json:"updated"`
+}
+
+type FeedLotUsage struct {
+	ID            uuid.UUID `json:"id"`
+	FeedLotID     uuid.UUID `json:"feed_lot_id"`
+	LotNumber     string    `json:"lot_number"`
+	Expired       null.Time `json:"expired"`
+	ReferenceType string    `json:"reference_type"`
+	ReferenceID   uuid.UUID `json:"reference_id"`
+	Qty           float64   `json:"qty"`
+	Created       time.Time `json:"created"`
+}
+
+type FeedTypeCost struct {
+	ID             uuid.UUID `json:"id"`
+	FeedTypeID     uuid.UUID `json:"feed_type_id"`
+	FeedIncomingID uuid.UUID `json:"feed_incoming_id"`
+	CostDate       time.Time `json:"cost_date"`
+	Stock          float64   `json:"stock"`
+	Qty            float64   `json:"qty"`
+	Price          float64   `json:"price"`
+	AverageCost    float64   `json:"average_cost"`
+	Created        time.Time `json:"created"`
+}
+
+type FeedMovement struct {
+	MovementDate time.Time `json:"movement_date"`
+	Qty          float64   `json:"qty"`
 }
 
 type FeedAdjustment struct {
-	ID         uuid.UUID `json:"id"`
-	FeedType   FeedType  `json:"feed_type"`
-	FeedTypeID uuid.UUID `json:"-"`
+	ID          uuid.UUID  `json:"id"`
+	FeedType    FeedType   `json:"feed_type"`
+	FeedTypeID  uuid.UUID  `json:"-"`
+	Qty         float64    `json:"qty"`
+	Unit        string     `json:"unit,omitempty"`
+	Reason      string     `json:"reason"`
+	StocktakeID *uuid.UUID `json:"stocktake_id"`
+	Remarks     string     `json:"remarks"`
+	Created     time.Time  `json:"created"`
+}
+
+type FeedStock struct {
+	FeedTypeID uuid.UUID `json:"feed_type_id"`
 	Qty        float64   `json:"qty"`
-	Remarks    string    `json:"remarks"`
-	Created    time.Time `json:"created"`
+}
+
+type FeedAlert struct {
+	FeedType          FeedType   `json:"feed_type"`
+	Stock             float64    `json:"stock"`
+	DailyConsumption  float64    `json:"daily_consumption"`
+	DaysOfCover       null.Float `json:"days_of_cover"`
+	StockoutDate      null.Time  `json:"stockout_date"`
+	BelowReorderPoint bool       `json:"below_reorder_point"`
+	RunningOut        bool       `json:"running_out"`
+	SuggestedQty      float64    `json:"suggested_qty"`
+}
+
+type Stocktake struct {
+	ID            uuid.UUID         `json:"id"`
+	StocktakeDate time.Time         `json:"stocktake_date"`
+	Status        string            `json:"status"`
+	Remarks       string            `json:"remarks"`
+	Approved      null.Time         `json:"approved"`
+	Created       time.Time         `json:"created"`
+	Updated       null.Time         `json:"updated"`
+	Detail        []StocktakeDetail `json:"detail"`
+}
+
+type StocktakeDetail struct {
+	ID          uuid.UUID `json:"id"`
+	StocktakeID uuid.UUID `json:"stocktake_id"`
+	FeedType    FeedType  `json:"feed_type"`
+	FeedTypeID  uuid.UUID `json:"-"`
+	CountedQty  float64   `json:"counted_qty"`
+	Unit        string    `json:"unit,omitempty"`
+	SystemQty   float64   `json:"system_qty"`
+	Variance    float64   `json:"variance"`
+	Created     time.Time `json:"created"`
+	Updated     null.Time `json:"updated"`
+}
+
+type SupplierPurchase struct {
+	SupplierID uuid.UUID `json:"supplier_id"`
+	Name       string    `json:"name"`
+	Incoming   int32     `json:"incoming"`
+	Qty        float64   `json:"qty"`
+	Amount     float64   `json:"amount"`
+}
+
+type SupplierPerformance struct {
+	SupplierID uuid.UUID `json:"supplier_id"`
+	Name       string    `json:"name"`
+	Cycles     int32     `json:"cycles"`
+	FeedQty    float64   `json:"feed_qty"`
+	FCR        float64   `json:"fcr"`
 }
@@ -0,0 +1,1222 @@
+package feed
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/guregu/null"
+	uuid "github.com/satori/go.uuid"
+)
+
+//MemoryRepository keeps the feed tables in memory for tests and demos. It follows
+//FeedRepository on ordering, paging totals, soft delete, foreign keys and not found
+//errors, the zero value is ready to use and the transaction methods ignore the tx.
+type MemoryRepository struct {
+	mu          sync.Mutex
+	feedtypes   []FeedType
+	units       []FeedTypeUnit
+	suppliers   []Supplier
+	incomings   []FeedIncoming
+	lots        []FeedLot
+	usages      []FeedLotUsage
+	costs       []FeedTypeCost
+	adjustments []FeedAdjustment
+	stocktakes  []Stocktake
+	details     []StocktakeDetail
+	//growth feedings and summaries, recorded by the batch memory repository
+	feedings  []memoryFeeding
+	summaries []memorySummary
+}
+
+//NewMemoryRepository returns an empty MemoryRepository, the feed tables hold
+//no seeded rows
+func NewMemoryRepository() *MemoryRepository {
+	return new(MemoryRepository)
+}
+
+type memoryFeeding struct {
+	BatchCycleID uuid.UUID
+	FeedTypeID   uuid.UUID
+	FeedingDate  time.Time
+	Qty          float64
+}
+
+type memorySummary struct {
+	BatchCycleID uuid.UUID
+	SummaryDate  time.Time
+	FCR          float64
+}
+
+//memoryPage returns the rows LIMIT :end OFFSET :start picks out of total rows
+func memoryPage(page int32, limit int32, total int) (int, int) {
+	start := int(page) * int(limit)
+	end := start + int(limit)
+	if start < 0 {
+		start = 0
+	}
+	if start > total {
+		start = total
+	}
+	if end < start {
+		end = start
+	} else if end > total {
+		end = total
+	}
+	return start, end
+}
+
+func memoryDeleted(deleted string, value bool) bool {
+	if deleted == Deleted_True {
+		return value
+	} else if deleted == Deleted_False {
+		return !value
+	}
+	return true
+}
+
+func memoryContains(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+//RecordGrowthFeeding books feed going out of stock like a growth_feeding row, the
+//lots it is taken off are consumed before
+func (repo *MemoryRepository) RecordGrowthFeeding(cycleId uuid.UUID, feedTypeId uuid.UUID, feedingDate time.Time, qty float64) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if _, err := repo.feedtype(feedTypeId); err != nil {
+		return err
+	}
+	repo.feedings = append(repo.feedings, memoryFeeding{BatchCycleID: cycleId, FeedTypeID: feedTypeId, FeedingDate: feedingDate, Qty: qty})
+	_, err := repo.recalculateFeedTypeCost(feedTypeId)
+	return err
+}
+
+//RecordGrowthSummary keeps the fcr a cycle was cut off with for the supplier report
+func (repo *MemoryRepository) RecordGrowthSummary(cycleId uuid.UUID, summaryDate time.Time, fcr float64) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.summaries = append(repo.summaries, memorySummary{BatchCycleID: cycleId, SummaryDate: summaryDate, FCR: fcr})
+}
+
+//feedtype
+func (repo *MemoryRepository) feedtype(id uuid.UUID) (*FeedType, error) {
+	for _, row := range repo.feedtypes {
+		if row.ID == id {
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("growth feedtype with id %s not found", id)
+}
+
+func (repo *MemoryRepository) sortedFeedTypes(deleted string) []FeedType {
+	feedtypes := make([]FeedType, 0)
+	for _, row := range repo.feedtypes {
+		if memoryDeleted(deleted, row.Deleted) {
+			feedtypes = append(feedtypes, row)
+		}
+	}
+	sort.SliceStable(feedtypes, func(i, j int) bool {
+		return feedtypes[i].Name < feedtypes[j].Name
+	})
+	return feedtypes
+}
+
+func (repo *MemoryRepository) ResolveFeedTypePage(page int32, limit int32, deleted string, stage string, pelletSize null.Float) (*[]FeedType, int32, int32, int32, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	feedtypes := make([]FeedType, 0)
+	for _, row := range repo.sortedFeedTypes(deleted) {
+		if (stage == "" || row.Stage == stage) && (!pelletSize.Valid || row.PelletSize == pelletSize.Float64) {
+			feedtypes = append(feedtypes, row)
+		}
+	}
+	start, end := memoryPage(page, limit, len(feedtypes))
+	result := feedtypes[start:end]
+	return &result, page, limit, int32(len(feedtypes)), nil
+}
+
+func (repo *MemoryRepository) ResolveFeedTypeByIDs(ids []uuid.UUID) (*[]FeedType, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	feedtypes := make([]FeedType, 0)
+	for _, row := range repo.feedtypes {
+		if memoryContains(ids, row.ID) {
+			feedtypes = append(feedtypes, row)
+		}
+	}
+	return &feedtypes, nil
+}
+
+func (repo *MemoryRepository) ResolveActiveFeedType() (*[]FeedType, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	feedtypes := repo.sortedFeedTypes(Deleted_False)
+	return &feedtypes, nil
+}
+
+func (repo *MemoryRepository) ResolveFeedTypeByID(id uuid.UUID) (*FeedType, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.feedtype(id)
+}
+
+func (repo *MemoryRepository) InsertFeedType(feedtype *FeedType) (*FeedType, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.insertFeedType(feedtype)
+	return repo.feedtype(feedtype.ID)
+}
+
+func (repo *MemoryRepository) InsertFeedTypeTransaction(tx *sql.Tx, feedtype *FeedType) (*FeedType, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.insertFeedType(feedtype)
+	return feedtype, nil
+}
+
+func (repo *MemoryRepository) insertFeedType(feedtype *FeedType) {
+	row := *feedtype
+	row.AverageCost = 0
+	row.Units = nil
+	row.Created = time.Now()
+	row.Updated = null.Time{}
+	repo.feedtypes = append(repo.feedtypes, row)
+}
+
+func (repo *MemoryRepository) UpdateFeedTypeByID(feedtype *FeedType) (*FeedType, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for i, row := range repo.feedtypes {
+		if row.ID == feedtype.ID {
+			row.Name = feedtype.Name
+			row.Unit = feedtype.Unit
+			row.Status = feedtype.Status
+			row.Protein = feedtype.Protein
+			row.Fat = feedtype.Fat
+			row.PelletSize = feedtype.PelletSize
+			row.Stage = feedtype.Stage
+			row.ReorderPoint = feedtype.ReorderPoint
+			row.ReorderQty = feedtype.ReorderQty
+			row.Deleted = feedtype.Deleted
+			row.Updated = null.TimeFrom(time.Now())
+			repo.feedtypes[i] = row
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("growth feedtype with id %s not found", feedtype.ID)
+}
+
+func (repo *MemoryRepository) RemoveFeedTypeByID(id uuid.UUID) (*FeedType, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for i := range repo.feedtypes {
+		if repo.feedtypes[i].ID == id {
+			repo.feedtypes[i].Deleted = true
+			repo.feedtypes[i].Updated = null.TimeFrom(time.Now())
+			return nil, nil
+		}
+	}
+	return nil, fmt.Errorf("growth feedtype with id %s not found", id)
+}
+
+func (repo *MemoryRepository) RemoveFeedTypeByIDs(ids []uuid.UUID) (*[]FeedType, error) {
+	for _, v := range ids {
+		if _, err := repo.RemoveFeedTypeByID(v); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+//feed type unit
+func (repo *MemoryRepository) ResolveFeedTypeUnitByFeedTypeID(feedTypeId uuid.UUID) (*[]FeedTypeUnit, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	units := make([]FeedTypeUnit, 0)
+	for _, row := range repo.units {
+		if row.FeedTypeID == feedTypeId {
+			units = append(units, row)
+		}
+	}
+	sort.SliceStable(units, func(i, j int) bool {
+		return units[i].Unit < units[j].Unit
+	})
+	return &units, nil
+}
+
+func (repo *MemoryRepository) feedTypeUnit(id uuid.UUID) (*FeedTypeUnit, error) {
+	for _, row := range repo.units {
+		if row.ID == id {
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("feed type unit with id %s not found", id)
+}
+
+func (repo *MemoryRepository) ResolveFeedTypeUnitByID(id uuid.UUID) (*FeedTypeUnit, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.feedTypeUnit(id)
+}
+
+func (repo *MemoryRepository) InsertFeedTypeUnit(unit *FeedTypeUnit) (*FeedTypeUnit, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if _, err := repo.feedtype(unit.FeedTypeID); err != nil {
+		return nil, err
+	}
+	for _, row := range repo.units {
+		if row.FeedTypeID == unit.FeedTypeID && row.Unit == unit.Unit {
+			return nil, fmt.Errorf("feed type %s already has unit %s", unit.FeedTypeID, unit.Unit)
+		}
+	}
+	row := *unit
+	row.Created = time.Now()
+	row.Updated = null.Time{}
+	repo.units = append(repo.units, row)
+	return &row, nil
+}
+
+func (repo *MemoryRepository) UpdateFeedTypeUnitByID(unit *FeedTypeUnit) (*FeedTypeUnit, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for i, row := range repo.units {
+		if row.ID == unit.ID {
+			row.Unit = unit.Unit
+			row.Factor = unit.Factor
+			row.Updated = null.TimeFrom(time.Now())
+			repo.units[i] = row
+		}
+	}
+	return repo.feedTypeUnit(unit.ID)
+}
+
+func (repo *MemoryRepository) RemoveFeedTypeUnitByID(id uuid.UUID) (*FeedTypeUnit, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for i, row := range repo.units {
+		if row.ID == id {
+			repo.units = append(repo.units[:i], repo.units[i+1:]...)
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("feed type unit with id %s not found", id)
+}
+
+//supplier
+func (repo *MemoryRepository) supplier(id uuid.UUID) (*Supplier, error) {
+	for _, row := range repo.suppliers {
+		if row.ID == id {
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("feed supplier with id %s not found", id)
+}
+
+func (repo *MemoryRepository) ResolveSupplierPage(page int32, limit int32, deleted string) (*[]Supplier, int32, int32, int32, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	suppliers := make([]Supplier, 0)
+	for _, row := range repo.suppliers {
+		if memoryDeleted(deleted, row.Deleted) {
+			suppliers = append(suppliers, row)
+		}
+	}
+	sort.SliceStable(suppliers, func(i, j int) bool {
+		return suppliers[i].Name < suppliers[j].Name
+	})
+	start, end := memoryPage(page, limit, len(suppliers))
+	result := suppliers[start:end]
+	return &result, page, limit, int32(len(suppliers)), nil
+}
+
+func (repo *MemoryRepository) ResolveSupplierByIDs(ids []uuid.UUID) (*[]Supplier, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	suppliers := make([]Supplier, 0)
+	for _, row := range repo.suppliers {
+		if memoryContains(ids, row.ID) {
+			suppliers = append(suppliers, row)
+		}
+	}
+	return &suppliers, nil
+}
+
+func (repo *MemoryRepository) ResolveSupplierByID(id uuid.UUID) (*Supplier, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.supplier(id)
+}
+
+func (repo *MemoryRepository) InsertSupplier(supplier *Supplier) (*Supplier, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	row := *supplier
+	row.Created = time.Now()
+	row.Updated = null.Time{}
+	repo.suppliers = append(repo.suppliers, row)
+	return &row, nil
+}
+
+func (repo *MemoryRepository) UpdateSupplierByID(supplier *Supplier) (*Supplier, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for i, row := range repo.suppliers {
+		if row.ID == supplier.ID {
+			row.Name = supplier.Name
+			row.Contact = supplier.Contact
+			row.Phone = supplier.Phone
+			row.Address = supplier.Address
+			row.Status = supplier.Status
+			row.Deleted = supplier.Deleted
+			row.Updated = null.TimeFrom(time.Now())
+			repo.suppliers[i] = row
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("feed supplier with id %s not found", supplier.ID)
+}
+
+func (repo *MemoryRepository) RemoveSupplierByID(id uuid.UUID) (*Supplier, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for i := range repo.suppliers {
+		if repo.suppliers[i].ID == id {
+			repo.suppliers[i].Deleted = true
+			repo.suppliers[i].Updated = null.TimeFrom(time.Now())
+			return nil, nil
+		}
+	}
+	return nil, fmt.Errorf("feed supplier with id %s not found", id)
+}
+
+func (repo *MemoryRepository) RemoveSupplierByIDs(ids []uuid.UUID) (*[]Supplier, error) {
+	for _, v := range ids {
+		if _, err := repo.RemoveSupplierByID(v); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+//supplier report, from and to are inclusive like BETWEEN
+func (repo *MemoryRepository) ResolveSupplierPurchase(from time.Time, to time.Time) (*[]SupplierPurchase, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	purchases := make([]SupplierPurchase, 0)
+	for _, supplier := range repo.suppliers {
+		purchase := SupplierPurchase{SupplierID: supplier.ID, Name: supplier.Name}
+		for _, incoming := range repo.incomings {
+			if incoming.SupplierID.Valid && incoming.SupplierID.UUID == supplier.ID && !incoming.IncomingDate.Before(from) && !incoming.IncomingDate.After(to) {
+				purchase.Incoming = purchase.Incoming + 1
+				purchase.Qty = purchase.Qty + incoming.Qty
+				purchase.Amount = purchase.Amount + incoming.Qty*incoming.Price
+			}
+		}
+		if purchase.Incoming > 0 {
+			purchases = append(purchases, purchase)
+		}
+	}
+	sort.SliceStable(purchases, func(i, j int) bool {
+		return purchases[i].Amount > purchases[j].Amount
+	})
+	return &purchases, nil
+}
+
+//ResolveSupplierPerformance weights the fcr of every cycle by how much of the
+//supplier's feed types it consumed
+func (repo *MemoryRepository) ResolveSupplierPerformance(from time.Time, to time.Time) (*[]SupplierPerformance, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	performances := make([]SupplierPerformance, 0)
+	for _, supplier := range repo.suppliers {
+		feedtypes := make([]uuid.UUID, 0)
+		for _, incoming := range repo.incomings {
+			if incoming.SupplierID.Valid && incoming.SupplierID.UUID == supplier.ID && !memoryContains(feedtypes, incoming.FeedTypeID) {
+				feedtypes = append(feedtypes, incoming.FeedTypeID)
+			}
+		}
+		performance := SupplierPerformance{SupplierID: supplier.ID, Name: supplier.Name}
+		cycles := make([]uuid.UUID, 0)
+		var weighted float64
+		for _, feeding := range repo.feedings {
+			if !memoryContains(feedtypes, feeding.FeedTypeID) {
+				continue
+			}
+			for _, summary := range repo.summaries {
+				if summary.BatchCycleID == feeding.BatchCycleID && !summary.SummaryDate.Before(from) && !summary.SummaryDate.After(to) {
+					if !memoryContains(cycles, summary.BatchCycleID) {
+						cycles = append(cycles, summary.BatchCycleID)
+					}
+					performance.FeedQty = performance.FeedQty + feeding.Qty
+					weighted = weighted + feeding.Qty*summary.FCR
+				}
+			}
+		}
+		if len(cycles) > 0 {
+			performance.Cycles = int32(len(cycles))
+			if performance.FeedQty != 0 {
+				performance.FCR = weighted / performance.FeedQty
+			}
+			performances = append(performances, performance)
+		}
+	}
+	sort.SliceStable(performances, func(i, j int) bool {
+		return performances[i].FCR < performances[j].FCR
+	})
+	return &performances, nil
+}
+
+//feed incoming
+func (repo *MemoryRepository) feedIncoming(id uuid.UUID) (*FeedIncoming, error) {
+	for _, row := range repo.incomings {
+		if row.ID == id {
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("feed incoming with id %s not found", id)
+}
+
+//populateFeedIncoming attaches the feed type and supplier like FeedRepository does
+func (repo *MemoryRepository) populateFeedIncoming(feedIncoming *FeedIncoming) error {
+	if feedtype, err := repo.feedtype(feedIncoming.FeedTypeID); err != nil {
+		return err
+	} else {
+		feedIncoming.FeedType = *feedtype
+	}
+	if feedIncoming.SupplierID.Valid {
+		if supplier, err := repo.supplier(feedIncoming.SupplierID.UUID); err != nil {
+			return err
+		} else {
+			feedIncoming.Supplier = supplier
+		}
+	}
+	return nil
+}
+
+func (repo *MemoryRepository) ResolveFeedIncomingPage(page int32, limit int32) (*[]FeedIncoming, int32, int32, int32, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	feedIncomings := make([]FeedIncoming, len(repo.incomings))
+	copy(feedIncomings, repo.incomings)
+	sort.SliceStable(feedIncomings, func(i, j int) bool {
+		return feedIncomings[i].Created.Before(feedIncomings[j].Created)
+	})
+	start, end := memoryPage(page, limit, len(feedIncomings))
+	result := feedIncomings[start:end]
+	for i := range result {
+		if err := repo.populateFeedIncoming(&result[i]); err != nil {
+			return nil, page, limit, 0, err
+		}
+	}
+	return &result, page, limit, int32(len(feedIncomings)), nil
+}
+
+func (repo *MemoryRepository) ResolveFeedIncomingByID(id uuid.UUID) (*FeedIncoming, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if feedIncoming, err := repo.feedIncoming(id); err != nil {
+		return nil, err
+	} else if err := repo.populateFeedIncoming(feedIncoming); err != nil {
+		return nil, err
+	} else {
+		return feedIncoming, nil
+	}
+}
+
+func (repo *MemoryRepository) InsertFeedIncoming(feedIncoming *FeedIncoming, lot *FeedLot) (*FeedIncoming, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	var usages *[]FeedLotUsage
+	if feedIncoming.Qty < 0 {
+		planned, err := repo.planFeedLotUsage(feedIncoming.FeedType.ID, Lot_Usage_Incoming, feedIncoming.ID, 0-feedIncoming.Qty)
+		if err != nil {
+			return nil, err
+		}
+		usages = planned
+	}
+	if err := repo.insertFeedIncoming(feedIncoming); err != nil {
+		return nil, err
+	}
+	if lot != nil {
+		if err := repo.insertFeedLot(lot); err != nil {
+			//roll the incoming back with the lot
+			repo.incomings = repo.incomings[:len(repo.incomings)-1]
+			return nil, err
+		}
+	}
+	repo.insertFeedLotUsage(usages)
+	if _, err := repo.recalculateFeedTypeCost(feedIncoming.FeedType.ID); err != nil {
+		return nil, err
+	}
+	feedIncoming, err := repo.feedIncoming(feedIncoming.ID)
+	if err != nil {
+		return nil, err
+	} else if err := repo.populateFeedIncoming(feedIncoming); err != nil {
+		return nil, err
+	}
+	return feedIncoming, nil
+}
+
+func (repo *MemoryRepository) InsertFeedIncomingTransaction(tx *sql.Tx, feedIncoming *FeedIncoming) (*FeedIncoming, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if err := repo.insertFeedIncoming(feedIncoming); err != nil {
+		return nil, err
+	}
+	return feedIncoming, nil
+}
+
+func (repo *MemoryRepository) insertFeedIncoming(feedIncoming *FeedIncoming) error {
+	row := *feedIncoming
+	row.FeedTypeID = feedIncoming.FeedType.ID
+	row.FeedType = FeedType{}
+	row.SupplierID = uuid.NullUUID{}
+	row.Supplier = nil
+	row.Unit = ""
+	row.Created = time.Now()
+	if _, err := repo.feedtype(row.FeedTypeID); err != nil {
+		return err
+	}
+	if feedIncoming.Supplier != nil {
+		if _, err := repo.supplier(feedIncoming.Supplier.ID); err != nil {
+			return err
+		}
+		row.SupplierID = uuid.NullUUID{UUID: feedIncoming.Supplier.ID, Valid: true}
+	}
+	repo.incomings = append(repo.incomings, row)
+	return nil
+}
+
+func (repo *MemoryRepository) ResolveFeedIncomingByFeedTypeID(feedTypeId uuid.UUID) (*[]FeedIncoming, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	feedIncomings := repo.feedIncomingByFeedType(feedTypeId)
+	return &feedIncomings, nil
+}
+
+func (repo *MemoryRepository) feedIncomingByFeedType(feedTypeId uuid.UUID) []FeedIncoming {
+	feedIncomings := make([]FeedIncoming, 0)
+	for _, row := range repo.incomings {
+		if row.FeedTypeID == feedTypeId {
+			feedIncomings = append(feedIncomings, row)
+		}
+	}
+	sort.SliceStable(feedIncomings, func(i, j int) bool {
+		if !feedIncomings[i].IncomingDate.Equal(feedIncomings[j].IncomingDate) {
+			return feedIncomings[i].IncomingDate.Before(feedIncomings[j].IncomingDate)
+		}
+		return feedIncomings[i].Created.Before(feedIncomings[j].Created)
+	})
+	return feedIncomings
+}
+
+//feed movement, feedings are going out of stock while adjustments are signed
+func (repo *MemoryRepository) ResolveFeedMovementByFeedTypeID(feedTypeId uuid.UUID) (*[]FeedMovement, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	movements := repo.feedMovement(feedTypeId)
+	return &movements, nil
+}
+
+func (repo *MemoryRepository) feedMovement(feedTypeId uuid.UUID) []FeedMovement {
+	movements := make([]FeedMovement, 0)
+	for _, feeding := range repo.feedings {
+		if feeding.FeedTypeID == feedTypeId {
+			movements = append(movements, FeedMovement{MovementDate: feeding.FeedingDate, Qty: 0 - feeding.Qty})
+		}
+	}
+	for _, adjustment := range repo.adjustments {
+		if adjustment.FeedTypeID == feedTypeId {
+			movements = append(movements, FeedMovement{MovementDate: adjustment.Created, Qty: adjustment.Qty})
+		}
+	}
+	sort.SliceStable(movements, func(i, j int) bool {
+		return movements[i].MovementDate.Before(movements[j].MovementDate)
+	})
+	return movements
+}
+
+//feed type cost
+func (repo *MemoryRepository) ResolveFeedTypeCostByFeedTypeIDs(ids []uuid.UUID) (*[]FeedTypeCost, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	costs := make([]FeedTypeCost, 0)
+	for _, row := range repo.costs {
+		if memoryContains(ids, row.FeedTypeID) {
+			costs = append(costs, row)
+		}
+	}
+	sort.SliceStable(costs, func(i, j int) bool {
+		if costs[i].FeedTypeID != costs[j].FeedTypeID {
+			return costs[i].FeedTypeID.String() < costs[j].FeedTypeID.String()
+		} else if !costs[i].CostDate.Equal(costs[j].CostDate) {
+			return costs[i].CostDate.Before(costs[j].CostDate)
+		}
+		return costs[i].Created.Before(costs[j].Created)
+	})
+	return &costs, nil
+}
+
+func (repo *MemoryRepository) RecalculateFeedTypeCost(feedTypeId uuid.UUID) (*[]FeedTypeCost, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.recalculateFeedTypeCost(feedTypeId)
+}
+
+func (repo *MemoryRepository) RecalculateFeedTypeCostTransaction(tx *sql.Tx, feedTypeId uuid.UUID) (*[]FeedTypeCost, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.recalculateFeedTypeCost(feedTypeId)
+}
+
+//recalculateFeedTypeCost replaces the cost history of a feed type, every write
+//changing its stock calls it before releasing the lock
+func (repo *MemoryRepository) recalculateFeedTypeCost(feedTypeId uuid.UUID) (*[]FeedTypeCost, error) {
+	if _, err := repo.feedtype(feedTypeId); err != nil {
+		return nil, err
+	}
+	costs, averageCost := replayFeedTypeCost(feedTypeId, repo.feedIncomingByFeedType(feedTypeId), repo.feedMovement(feedTypeId))
+	kept := make([]FeedTypeCost, 0)
+	for _, row := range repo.costs {
+		if row.FeedTypeID != feedTypeId {
+			kept = append(kept, row)
+		}
+	}
+	for _, cost := range costs {
+		cost.Created = time.Now()
+		kept = append(kept, cost)
+	}
+	repo.costs = kept
+	for i := range repo.feedtypes {
+		if repo.feedtypes[i].ID == feedTypeId {
+			repo.feedtypes[i].AverageCost = averageCost
+		}
+	}
+	return &costs, nil
+}
+
+//feed lot, first expiring first out and lots without expiry date last
+func sortFeedLotFEFO(lots []FeedLot) {
+	sort.SliceStable(lots, func(i, j int) bool {
+		if lots[i].Expired.Valid != lots[j].Expired.Valid {
+			return lots[i].Expired.Valid
+		} else if lots[i].Expired.Valid && !lots[i].Expired.Time.Equal(lots[j].Expired.Time) {
+			return lots[i].Expired.Time.Before(lots[j].Expired.Time)
+		}
+		return lots[i].Created.Before(lots[j].Created)
+	})
+}
+
+func (repo *MemoryRepository) ResolveFeedLotByID(id uuid.UUID) (*FeedLot, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for _, row := range repo.lots {
+		if row.ID == id {
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("feed lot with id %s not found", id)
+}
+
+//planFeedLotUsage plans qty of a feed type on its lots and stock as they are now,
+//nothing is taken off until the usages are inserted
+func (repo *MemoryRepository) planFeedLotUsage(feedTypeId uuid.UUID, referenceType string, referenceId uuid.UUID, qty float64) (*[]FeedLotUsage, error) {
+	lots := make([]FeedLot, 0)
+	for _, row := range repo.lots {
+		if row.FeedTypeID == feedTypeId && row.Remaining > 0 {
+			lots = append(lots, row)
+		}
+	}
+	sortFeedLotFEFO(lots)
+	stock, _ := repo.feedStock(feedTypeId)
+	return planFeedLotUsage(feedTypeId, lots, stock.Qty, referenceType, referenceId, qty)
+}
+
+func (repo *MemoryRepository) ResolveAvailableFeedLot(expiredBefore null.Time) (*[]FeedLot, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	lots := make([]FeedLot, 0)
+	for _, row := range repo.lots {
+		if row.Remaining > 0 && (!expiredBefore.Valid || (row.Expired.Valid && !row.Expired.Time.After(expiredBefore.Time))) {
+			if feedtype, err := repo.feedtype(row.FeedTypeID); err == nil {
+				row.FeedType = *feedtype
+			}
+			lots = append(lots, row)
+		}
+	}
+	sortFeedLotFEFO(lots)
+	return &lots, nil
+}
+
+func (repo *MemoryRepository) InsertFeedLotTransaction(tx *sql.Tx, lot *FeedLot) (*FeedLot, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if err := repo.insertFeedLot(lot); err != nil {
+		return nil, err
+	}
+	return lot, nil
+}
+
+func (repo *MemoryRepository) insertFeedLot(lot *FeedLot) error {
+	if _, err := repo.feedtype(lot.FeedTypeID); err != nil {
+		return err
+	} else if _, err := repo.feedIncoming(lot.FeedIncomingID); err != nil {
+		return err
+	}
+	row := *lot
+	row.FeedType = FeedType{}
+	row.Created = time.Now()
+	row.Updated = null.Time{}
+	repo.lots = append(repo.lots, row)
+	return nil
+}
+
+//feed lot usage
+func (repo *MemoryRepository) ResolveFeedLotUsageByReferenceIDs(ids []uuid.UUID) (*[]FeedLotUsage, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	usages := make([]FeedLotUsage, 0)
+	for _, usage := range repo.usages {
+		if !memoryContains(ids, usage.ReferenceID) {
+			continue
+		}
+		for _, lot := range repo.lots {
+			if lot.ID == usage.FeedLotID {
+				usage.LotNumber = lot.LotNumber
+				usage.Expired = lot.Expired
+				usages = append(usages, usage)
+			}
+		}
+	}
+	sort.SliceStable(usages, func(i, j int) bool {
+		return usages[i].Created.Before(usages[j].Created)
+	})
+	return &usages, nil
+}
+
+func (repo *MemoryRepository) ConsumeFeedLotTransaction(tx *sql.Tx, feedTypeId uuid.UUID, referenceType string, referenceId uuid.UUID, qty float64) (*[]FeedLotUsage, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if _, err := repo.feedtype(feedTypeId); err != nil {
+		return nil, err
+	}
+	usages, err := repo.planFeedLotUsage(feedTypeId, referenceType, referenceId, qty)
+	if err != nil {
+		return nil, err
+	}
+	repo.insertFeedLotUsage(usages)
+	return usages, nil
+}
+
+//checkFeedLotUsage checks every lot still has enough remaining qty before any of
+//it is taken, so a short lot leaves every lot and the row using them untouched
+func (repo *MemoryRepository) checkFeedLotUsage(usages *[]FeedLotUsage) error {
+	if usages == nil {
+		return nil
+	}
+	remaining := make(map[uuid.UUID]float64)
+	for _, lot := range repo.lots {
+		remaining[lot.ID] = lot.Remaining
+	}
+	for _, usage := range *usages {
+		if available, ok := remaining[usage.FeedLotID]; !ok || available < usage.Qty {
+			return fmt.Errorf("feed lot %s %s does not have enough remaining qty", usage.LotNumber, usage.FeedLotID)
+		} else {
+			remaining[usage.FeedLotID] = available - usage.Qty
+		}
+	}
+	return nil
+}
+
+func (repo *MemoryRepository) insertFeedLotUsage(usages *[]FeedLotUsage) {
+	if usages == nil {
+		return
+	}
+	for _, usage := range *usages {
+		for i := range repo.lots {
+			if repo.lots[i].ID == usage.FeedLotID {
+				repo.lots[i].Remaining = repo.lots[i].Remaining - usage.Qty
+				repo.lots[i].Updated = null.TimeFrom(time.Now())
+			}
+		}
+		usage.LotNumber = ""
+		usage.Expired = null.Time{}
+		usage.Created = time.Now()
+		repo.usages = append(repo.usages, usage)
+	}
+}
+
+//feed adjustment
+func (repo *MemoryRepository) feedAdjustment(id uuid.UUID) (*FeedAdjustment, error) {
+	for _, row := range repo.adjustments {
+		if row.ID == id {
+			if feedtype, err := repo.feedtype(row.FeedTypeID); err != nil {
+				return nil, err
+			} else {
+				row.FeedType = *feedtype
+			}
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("feed adjustment with id %s not found", id)
+}
+
+func (repo *MemoryRepository) ResolveFeedAdjustmentPage(page int32, limit int32) (*[]FeedAdjustment, int32, int32, int32, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	feedAdjustments := make([]FeedAdjustment, len(repo.adjustments))
+	copy(feedAdjustments, repo.adjustments)
+	sort.SliceStable(feedAdjustments, func(i, j int) bool {
+		return feedAdjustments[i].Created.Before(feedAdjustments[j].Created)
+	})
+	start, end := memoryPage(page, limit, len(feedAdjustments))
+	result := feedAdjustments[start:end]
+	for i := range result {
+		if feedtype, err := repo.feedtype(result[i].FeedTypeID); err != nil {
+			return nil, page, limit, 0, err
+		} else {
+			result[i].FeedType = *feedtype
+		}
+	}
+	return &result, page, limit, int32(len(feedAdjustments)), nil
+}
+
+func (repo *MemoryRepository) ResolveFeedAdjustmentByID(id uuid.UUID) (*FeedAdjustment, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.feedAdjustment(id)
+}
+
+func (repo *MemoryRepository) InsertFeedAdjustment(feedAdjustment *FeedAdjustment) (*FeedAdjustment, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if err := repo.validateFeedAdjustment(feedAdjustment); err != nil {
+		return nil, err
+	}
+	usages, err := repo.planFeedAdjustment(feedAdjustment)
+	if err != nil {
+		return nil, err
+	}
+	repo.insertFeedAdjustment(feedAdjustment)
+	repo.insertFeedLotUsage(usages)
+	if _, err := repo.recalculateFeedTypeCost(feedAdjustment.FeedType.ID); err != nil {
+		return nil, err
+	}
+	return repo.feedAdjustment(feedAdjustment.ID)
+}
+
+func (repo *MemoryRepository) InsertFeedAdjustmentTransaction(tx *sql.Tx, feedAdjustment *FeedAdjustment) (*FeedAdjustment, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if err := repo.validateFeedAdjustment(feedAdjustment); err != nil {
+		return nil, err
+	}
+	repo.insertFeedAdjustment(feedAdjustment)
+	return feedAdjustment, nil
+}
+
+func (repo *MemoryRepository) validateFeedAdjustment(feedAdjustment *FeedAdjustment) error {
+	if _, err := repo.feedtype(feedAdjustment.FeedType.ID); err != nil {
+		return err
+	} else if feedAdjustment.StocktakeID != nil {
+		if _, err := repo.stocktake(*feedAdjustment.StocktakeID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//planFeedAdjustment plans the usages taking missing feed off the lots
+func (repo *MemoryRepository) planFeedAdjustment(feedAdjustment *FeedAdjustment) (*[]FeedLotUsage, error) {
+	if feedAdjustment.Qty >= 0 {
+		return nil, nil
+	}
+	return repo.planFeedLotUsage(feedAdjustment.FeedType.ID, Lot_Usage_Adjustment, feedAdjustment.ID, 0-feedAdjustment.Qty)
+}
+
+func (repo *MemoryRepository) insertFeedAdjustment(feedAdjustment *FeedAdjustment) {
+	row := *feedAdjustment
+	row.FeedTypeID = feedAdjustment.FeedType.ID
+	row.FeedType = FeedType{}
+	row.Unit = ""
+	if feedAdjustment.StocktakeID != nil {
+		stocktakeID := *feedAdjustment.StocktakeID
+		row.StocktakeID = &stocktakeID
+	}
+	if row.Created.IsZero() {
+		row.Created = time.Now()
+	}
+	repo.adjustments = append(repo.adjustments, row)
+}
+
+//feed stock, the system ledger of incomings, feedings and adjustments
+func (repo *MemoryRepository) ResolveFeedStockByFeedTypeIDs(ids []uuid.UUID) (*[]FeedStock, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	stocks := make([]FeedStock, 0)
+	seen := make([]uuid.UUID, 0)
+	for _, id := range ids {
+		if memoryContains(seen, id) {
+			continue
+		}
+		seen = append(seen, id)
+		if stock, found := repo.feedStock(id); found {
+			stocks = append(stocks, stock)
+		}
+	}
+	return &stocks, nil
+}
+
+//feedStock sums the ledger of a feed type, found is false when it has no rows
+func (repo *MemoryRepository) feedStock(id uuid.UUID) (FeedStock, bool) {
+	stock := FeedStock{FeedTypeID: id}
+	found := false
+	for _, row := range repo.incomings {
+		if row.FeedTypeID == id {
+			stock.Qty = stock.Qty + row.Qty
+			found = true
+		}
+	}
+	for _, row := range repo.feedings {
+		if row.FeedTypeID == id {
+			stock.Qty = stock.Qty - row.Qty
+			found = true
+		}
+	}
+	for _, row := range repo.adjustments {
+		if row.FeedTypeID == id {
+			stock.Qty = stock.Qty + row.Qty
+			found = true
+		}
+	}
+	return stock, found
+}
+
+func (repo *MemoryRepository) ResolveFeedConsumptionByFeedTypeIDs(ids []uuid.UUID, from time.Time) (*[]FeedStock, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	consumptions := make([]FeedStock, 0)
+	for _, row := range repo.feedings {
+		if !memoryContains(ids, row.FeedTypeID) || row.FeedingDate.Before(from) {
+			continue
+		}
+		found := false
+		for i := range consumptions {
+			if consumptions[i].FeedTypeID == row.FeedTypeID {
+				consumptions[i].Qty = consumptions[i].Qty + row.Qty
+				found = true
+			}
+		}
+		if !found {
+			consumptions = append(consumptions, FeedStock{FeedTypeID: row.FeedTypeID, Qty: row.Qty})
+		}
+	}
+	return &consumptions, nil
+}
+
+//export
+func (repo *MemoryRepository) StreamFeedType(deleted string, fn func(*FeedType) error) error {
+	repo.mu.Lock()
+	feedtypes := repo.sortedFeedTypes(deleted)
+	repo.mu.Unlock()
+	for _, row := range feedtypes {
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (repo *MemoryRepository) StreamFeedIncoming(fn func(*FeedIncoming) error) error {
+	repo.mu.Lock()
+	feedIncomings := make([]FeedIncoming, len(repo.incomings))
+	copy(feedIncomings, repo.incomings)
+	repo.mu.Unlock()
+	sort.SliceStable(feedIncomings, func(i, j int) bool {
+		return feedIncomings[i].Created.Before(feedIncomings[j].Created)
+	})
+	for _, row := range feedIncomings {
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (repo *MemoryRepository) StreamFeedAdjustment(fn func(*FeedAdjustment) error) error {
+	repo.mu.Lock()
+	feedAdjustments := make([]FeedAdjustment, len(repo.adjustments))
+	copy(feedAdjustments, repo.adjustments)
+	repo.mu.Unlock()
+	sort.SliceStable(feedAdjustments, func(i, j int) bool {
+		return feedAdjustments[i].Created.Before(feedAdjustments[j].Created)
+	})
+	for _, row := range feedAdjustments {
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//stocktake
+func (repo *MemoryRepository) stocktake(id uuid.UUID) (*Stocktake, error) {
+	for _, row := range repo.stocktakes {
+		if row.ID == id {
+			row.Detail = make([]StocktakeDetail, 0)
+			for _, detail := range repo.details {
+				if detail.StocktakeID == id {
+					if feedtype, err := repo.feedtype(detail.FeedTypeID); err == nil {
+						detail.FeedType = *feedtype
+					}
+					row.Detail = append(row.Detail, detail)
+				}
+			}
+			sort.SliceStable(row.Detail, func(i, j int) bool {
+				return row.Detail[i].Created.Before(row.Detail[j].Created)
+			})
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("stocktake with id %s not found", id)
+}
+
+func (repo *MemoryRepository) ResolveStocktakePage(page int32, limit int32) (*[]Stocktake, int32, int32, int32, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	stocktakes := make([]Stocktake, len(repo.stocktakes))
+	copy(stocktakes, repo.stocktakes)
+	sort.SliceStable(stocktakes, func(i, j int) bool {
+		if !stocktakes[i].StocktakeDate.Equal(stocktakes[j].StocktakeDate) {
+			return stocktakes[i].StocktakeDate.After(stocktakes[j].StocktakeDate)
+		}
+		return stocktakes[i].Created.After(stocktakes[j].Created)
+	})
+	start, end := memoryPage(page, limit, len(stocktakes))
+	result := stocktakes[start:end]
+	return &result, page, limit, int32(len(stocktakes)), nil
+}
+
+func (repo *MemoryRepository) ResolveStocktakeByID(id uuid.UUID) (*Stocktake, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.stocktake(id)
+}
+
+func (repo *MemoryRepository) InsertStocktake(stocktake *Stocktake) (*Stocktake, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	row := *stocktake
+	row.Detail = nil
+	row.Approved = null.Time{}
+	row.Created = time.Now()
+	row.Updated = null.Time{}
+	repo.stocktakes = append(repo.stocktakes, row)
+	return repo.stocktake(row.ID)
+}
+
+//UpdateStocktakeByID only changes a stocktake that is still open
+func (repo *MemoryRepository) UpdateStocktakeByID(stocktake *Stocktake) (*Stocktake, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for i := range repo.stocktakes {
+		if repo.stocktakes[i].ID == stocktake.ID && repo.stocktakes[i].Status == Stocktake_Open {
+			repo.stocktakes[i].StocktakeDate = stocktake.StocktakeDate
+			repo.stocktakes[i].Remarks = stocktake.Remarks
+			repo.stocktakes[i].Updated = null.TimeFrom(time.Now())
+		}
+	}
+	return repo.stocktake(stocktake.ID)
+}
+
+func (repo *MemoryRepository) ReplaceStocktakeDetailByStocktakeID(stocktakeId uuid.UUID, details *[]StocktakeDetail) (*Stocktake, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if _, err := repo.stocktake(stocktakeId); err != nil {
+		return nil, err
+	}
+	for _, detail := range *details {
+		if _, err := repo.feedtype(detail.FeedType.ID); err != nil {
+			return nil, err
+		}
+	}
+	kept := make([]StocktakeDetail, 0)
+	for _, row := range repo.details {
+		if row.StocktakeID != stocktakeId {
+			kept = append(kept, row)
+		}
+	}
+	for _, detail := range *details {
+		detail.StocktakeID = stocktakeId
+		detail.FeedTypeID = detail.FeedType.ID
+		detail.FeedType = FeedType{}
+		detail.Unit = ""
+		detail.Created = time.Now()
+		detail.Updated = null.Time{}
+		kept = append(kept, detail)
+	}
+	repo.details = kept
+	return repo.stocktake(stocktakeId)
+}
+
+//ApproveStocktakeByID validates the adjustments before it freezes the variance of
+//every counted feed type, a stocktake that is no longer open fails the approval
+func (repo *MemoryRepository) ApproveStocktakeByID(stocktake *Stocktake, adjustments *[]FeedAdjustment) (*Stocktake, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	index := -1
+	for i := range repo.stocktakes {
+		if repo.stocktakes[i].ID == stocktake.ID && repo.stocktakes[i].Status == Stocktake_Open {
+			index = i
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("stocktake with id %s is not open", stocktake.ID)
+	}
+	usages := make([]FeedLotUsage, 0)
+	for _, adjustment := range *adjustments {
+		if err := repo.validateFeedAdjustment(&adjustment); err != nil {
+			return nil, err
+		} else if planned, err := repo.planFeedAdjustment(&adjustment); err != nil {
+			return nil, err
+		} else if planned != nil {
+			usages = append(usages, *planned...)
+		}
+	}
+	//adjustments of one feed type are planned on the same lots, they have to fit together
+	if err := repo.checkFeedLotUsage(&usages); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	repo.stocktakes[index].Status = Stocktake_Approved
+	repo.stocktakes[index].Approved = null.TimeFrom(now)
+	repo.stocktakes[index].Updated = null.TimeFrom(now)
+	for _, detail := range stocktake.Detail {
+		for i := range repo.details {
+			if repo.details[i].ID == detail.ID {
+				repo.details[i].SystemQty = detail.SystemQty
+				repo.details[i].Variance = detail.Variance
+				repo.details[i].Updated = null.TimeFrom(now)
+			}
+		}
+	}
+	for _, adjustment := range *adjustments {
+		repo.insertFeedAdjustment(&adjustment)
+	}
+	repo.insertFeedLotUsage(&usages)
+	for _, adjustment := range *adjustments {
+		if _, err := repo.recalculateFeedTypeCost(adjustment.FeedType.ID); err != nil {
+			return nil, err
+		}
+	}
+	return repo.stocktake(stocktake.ID)
+}
@@ -2,25 +2,63 @@ package feed
 
 import (
 	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/guregu/null"
 	uuid "github.com/satori/go.uuid"
 )
 
 type Service interface {
-	ResolveFeedTypePage(page int32, limit int32, deleted string) (*[]FeedType, int32, int32, int32, error)
+	ResolveFeedTypePage(page int32, limit int32, deleted string, stage string, pelletSize null.Float) (*[]FeedType, int32, int32, int32, error)
 	ResolveFeedTypeByIDs([]uuid.UUID) (*[]FeedType, error)
 	ResolveFeedTypeByID(uuid.UUID) (*FeedType, error)
 	StoreFeedType(*FeedType) (*FeedType, error)
 	RemoveFeedTypeByID(uuid.UUID) (*FeedType, error)
 	RemoveFeedTypeByIDs([]uuid.UUID) (*[]FeedType, error)
 
+	ResolveFeedTypeUnitByFeedTypeID(uuid.UUID) (*[]FeedTypeUnit, error)
+	StoreFeedTypeUnit(*FeedTypeUnit) (*FeedTypeUnit, error)
+	RemoveFeedTypeUnitByID(feedTypeId uuid.UUID, id uuid.UUID) (*FeedTypeUnit, error)
+	ConvertToBaseUnit(feedTypeId uuid.UUID, qty float64, unit string) (float64, error)
+
+	ResolveSupplierPage(page int32, limit int32, deleted string) (*[]Supplier, int32, int32, int32, error)
+	ResolveSupplierByID(uuid.UUID) (*Supplier, error)
+	StoreSupplier(*Supplier) (*Supplier, error)
+	RemoveSupplierByID(uuid.UUID) (*Supplier, error)
+	RemoveSupplierByIDs([]uuid.UUID) (*[]Supplier, error)
+	ResolveSupplierPurchase(from time.Time, to time.Time) (*[]SupplierPurchase, error)
+	ResolveSupplierPerformance(from time.Time, to time.Time) (*[]SupplierPerformance, error)
+
 	ResolveFeedIncomingPage(page int32, limit int32) (*[]FeedIncoming, int32, int32, int32, error)
 	ResolveFeedIncomingByID(uuid.UUID) (*FeedIncoming, error)
 	StoreFeedIncoming(*FeedIncoming) (*FeedIncoming, error)
+	RecalculateFeedTypeCost(feedTypeId uuid.UUID) (*[]FeedTypeCost, error)
+
+	ResolveFeedTypeCostByFeedTypeID(uuid.UUID) (*[]FeedTypeCost, error)
+	ResolveFeedTypeCostByFeedTypeIDs([]uuid.UUID) (*[]FeedTypeCost, error)
+
+	ResolveFeedAlert(horizon int, window int) (*[]FeedAlert, error)
+
+	ResolveFeedLot(expiredBefore null.Time) (*[]FeedLot, error)
+	ResolveFeedLotUsageByReferenceIDs([]uuid.UUID) (*[]FeedLotUsage, error)
 
 	ResolveFeedAdjustmentPage(page int32, limit int32) (*[]FeedAdjustment, int32, int32, int32, error)
 	ResolveFeedAdjustmentByID(uuid.UUID) (*FeedAdjustment, error)
 	StoreFeedAdjustment(*FeedAdjustment) (*FeedAdjustment, error)
+
+	ResolveStocktakePage(page int32, limit int32) (*[]Stocktake, int32, int32, int32, error)
+	ResolveStocktakeByID(uuid.UUID) (*Stocktake, error)
+	StoreStocktake(*Stocktake) (*Stocktake, error)
+	StoreStocktakeCount(stocktakeId uuid.UUID, details []StocktakeDetail) (*Stocktake, error)
+	PreviewStocktakeVariance(uuid.UUID) (*Stocktake, error)
+	ApproveStocktake(uuid.UUID) (*Stocktake, error)
+
+	StreamFeedType(deleted string, fn func(*FeedType) error) error
+	StreamFeedIncoming(fn func(*FeedIncoming) error) error
+	StreamFeedAdjustment(fn func(*FeedAdjustment) error) error
 }
 
 type FeedService struct {
@@ -28,8 +66,8 @@ type FeedService struct {
 }
 
 //feed type
-func (svc *FeedService) ResolveFeedTypePage(page int32, limit int32, deleted string) (*[]FeedType, int32, int32, int32, error) {
-	if feedtypes, page, limit, total, err := svc.FeedRepository.ResolveFeedTypePage(page, limit, deleted); err != nil {
+func (svc *FeedService) ResolveFeedTypePage(page int32, limit int32, deleted string, stage string, pelletSize null.Float) (*[]FeedType, int32, int32, int32, error) {
+	if feedtypes, page, limit, total, err := svc.FeedRepository.ResolveFeedTypePage(page, limit, deleted, stage, pelletSize); err != nil {
 		return nil, 0, 0, 0, err
 	} else {
 		return feedtypes, page, limit, total, nil
@@ -47,12 +85,27 @@ func (svc *FeedService) ResolveFeedTypeByIDs(ids []uuid.UUID) (*[]FeedType, erro
 func (svc *FeedService) ResolveFeedTypeByID(id uuid.UUID) (*FeedType, error) {
 	if feedtype, err := svc.FeedRepository.ResolveFeedTypeByID(id); err != nil {
 		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else if units, err := svc.FeedRepository.ResolveFeedTypeUnitByFeedTypeID(id); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
 	} else {
+		feedtype.Units = *units
 		return feedtype, nil
 	}
 }
 
 func (svc *FeedService) StoreFeedType(feedtype *FeedType) (*FeedType, error) {
+	if feedtype.ReorderPoint < 0 || feedtype.ReorderQty < 0 {
+		return nil, fmt.Errorf("Reorder point and reorder qty cannot be negative")
+	}
+	if feedtype.Protein < 0 || feedtype.Protein > 100 || feedtype.Fat < 0 || feedtype.Fat > 100 {
+		return nil, fmt.Errorf("Protein and fat must be a percentage between 0 and 100")
+	}
+	if feedtype.PelletSize < 0 {
+		return nil, fmt.Errorf("Pellet size cannot be negative")
+	}
+	if feedtype.Stage != "" && feedtype.Stage != Feed_Stage_Starter && feedtype.Stage != Feed_Stage_Grower && feedtype.Stage != Feed_Stage_Finisher {
+		return nil, fmt.Errorf("Unknown feed stage %s", feedtype.Stage)
+	}
 	if feedtype.ID == uuid.Nil {
 		feedtype.ID = uuid.Must(uuid.NewV4())
 		if result, err := svc.FeedRepository.InsertFeedType(feedtype); err != nil {
@@ -86,6 +139,165 @@ func (svc *FeedService) RemoveFeedTypeByIDs(ids []uuid.UUID) (*[]FeedType, error
 	}
 }
 
+//feed type unit
+func (svc *FeedService) ResolveFeedTypeUnitByFeedTypeID(feedTypeId uuid.UUID) (*[]FeedTypeUnit, error) {
+	if _, err := svc.FeedRepository.ResolveFeedTypeByID(feedTypeId); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else if units, err := svc.FeedRepository.ResolveFeedTypeUnitByFeedTypeID(feedTypeId); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+		return units, nil
+	}
+}
+
+//StoreFeedTypeUnit sets how many base units one unit of a feed type weighs,
+//storing a unit the feed type already has replaces its factor
+func (svc *FeedService) StoreFeedTypeUnit(unit *FeedTypeUnit) (*FeedTypeUnit, error) {
+	unit.Unit = normalizeUnit(unit.Unit)
+	if unit.Unit == "" || unit.Factor <= 0 {
+		return nil, fmt.Errorf("Unit and a factor bigger than 0 must be provided")
+	}
+	if _, ok := BaseUnits[unit.Unit]; ok {
+		return nil, fmt.Errorf("Unit %s is a base unit and cannot be redefined", unit.Unit)
+	}
+	units, err := svc.ResolveFeedTypeUnitByFeedTypeID(unit.FeedTypeID)
+	if err != nil {
+		return nil, err
+	}
+	for _, current := range *units {
+		if current.Unit == unit.Unit {
+			unit.ID = current.ID
+			return svc.FeedRepository.UpdateFeedTypeUnitByID(unit)
+		}
+	}
+	unit.ID = uuid.Must(uuid.NewV4())
+	return svc.FeedRepository.InsertFeedTypeUnit(unit)
+}
+
+//RemoveFeedTypeUnitByID removes a unit of the feed type it belongs to
+func (svc *FeedService) RemoveFeedTypeUnitByID(feedTypeId uuid.UUID, id uuid.UUID) (*FeedTypeUnit, error) {
+	unit, err := svc.FeedRepository.ResolveFeedTypeUnitByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else if unit.FeedTypeID != feedTypeId {
+		return nil, fmt.Errorf("Unit %s does not belong to feed type %s", id, feedTypeId)
+	}
+	if unit, err := svc.FeedRepository.RemoveFeedTypeUnitByID(id); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+		return unit, nil
+	}
+}
+
+//ConvertToBaseUnit converts qty of a feed type from unit to the base unit,
+//qty without unit is taken in the unit of the feed type
+func (svc *FeedService) ConvertToBaseUnit(feedTypeId uuid.UUID, qty float64, unit string) (float64, error) {
+	factor, err := svc.unitFactor(feedTypeId, unit)
+	if err != nil {
+		return 0, err
+	}
+	return qty * factor, nil
+}
+
+func (svc *FeedService) unitFactor(feedTypeId uuid.UUID, unit string) (float64, error) {
+	name := normalizeUnit(unit)
+	if factor, ok := BaseUnits[name]; ok {
+		return factor, nil
+	}
+	feedtype, err := svc.FeedRepository.ResolveFeedTypeByID(feedTypeId)
+	if err != nil {
+		return 0, err
+	}
+	//a feed type declared in a unit other than kg, g or ton needs a factor for it
+	if name == "" {
+		name = normalizeUnit(feedtype.Unit)
+		if factor, ok := BaseUnits[name]; ok {
+			return factor, nil
+		}
+	}
+	units, err := svc.FeedRepository.ResolveFeedTypeUnitByFeedTypeID(feedTypeId)
+	if err != nil {
+		return 0, err
+	}
+	for _, u := range *units {
+		if u.Unit == name {
+			return u.Factor, nil
+		}
+	}
+	return 0, fmt.Errorf("Unit %s of feed type %s has no conversion to %s", name, feedtype.Name, Unit_Base)
+}
+
+func normalizeUnit(unit string) string {
+	return strings.ToLower(strings.TrimSpace(unit))
+}
+
+//supplier
+func (svc *FeedService) ResolveSupplierPage(page int32, limit int32, deleted string) (*[]Supplier, int32, int32, int32, error) {
+	if suppliers, page, limit, total, err := svc.FeedRepository.ResolveSupplierPage(page, limit, deleted); err != nil {
+		return nil, 0, 0, 0, err
+	} else {
+		return suppliers, page, limit, total, nil
+	}
+}
+
+func (svc *FeedService) ResolveSupplierByID(id uuid.UUID) (*Supplier, error) {
+	if supplier, err := svc.FeedRepository.ResolveSupplierByID(id); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+		return supplier, nil
+	}
+}
+
+func (svc *FeedService) StoreSupplier(supplier *Supplier) (*Supplier, error) {
+	if supplier.ID == uuid.Nil {
+		supplier.ID = uuid.Must(uuid.NewV4())
+		if result, err := svc.FeedRepository.InsertSupplier(supplier); err != nil {
+			return nil, err
+		} else {
+			return result, nil
+		}
+	} else {
+		//update
+		if result, err := svc.FeedRepository.UpdateSupplierByID(supplier); err != nil {
+			return nil, err
+		} else {
+			return result, nil
+		}
+	}
+}
+
+func (svc *FeedService) RemoveSupplierByID(id uuid.UUID) (*Supplier, error) {
+	if _, err := svc.FeedRepository.RemoveSupplierByID(id); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+		return nil, nil
+	}
+}
+
+func (svc *FeedService) RemoveSupplierByIDs(ids []uuid.UUID) (*[]Supplier, error) {
+	if _, err := svc.FeedRepository.RemoveSupplierByIDs(ids); err != nil {
+		return nil, err
+	} else {
+		return nil, nil
+	}
+}
+
+func (svc *FeedService) ResolveSupplierPurchase(from time.Time, to time.Time) (*[]SupplierPurchase, error) {
+	if purchases, err := svc.FeedRepository.ResolveSupplierPurchase(from, to); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+		return purchases, nil
+	}
+}
+
+func (svc *FeedService) ResolveSupplierPerformance(from time.Time, to time.Time) (*[]SupplierPerformance, error) {
+	if performances, err := svc.FeedRepository.ResolveSupplierPerformance(from, to); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+		return performances, nil
+	}
+}
+
 //feed incoming
 func (svc *FeedService) ResolveFeedIncomingPage(page int32, limit int32) (*[]FeedIncoming, int32, int32, int32, error) {
 	if feedIncomings, page, limit, total, err := svc.FeedRepository.ResolveFeedIncomingPage(page, limit); err != nil {
@@ -105,13 +317,258 @@ func (svc *FeedService) ResolveFeedIncomingByID(id uuid.UUID) (*FeedIncoming, er
 
 func (svc *FeedService) StoreFeedIncoming(feedIncoming *FeedIncoming) (*FeedIncoming, error) {
 	feedIncoming.ID = uuid.Must(uuid.NewV4())
-	if result, err := svc.FeedRepository.InsertFeedIncoming(feedIncoming); err != nil {
+	//qty and price per unit are stored in the base unit
+	if factor, err := svc.unitFactor(feedIncoming.FeedType.ID, feedIncoming.Unit); err != nil {
+		return nil, err
+	} else {
+		feedIncoming.Qty = feedIncoming.Qty * factor
+		feedIncoming.Price = feedIncoming.Price / factor
+		feedIncoming.Unit = Unit_Base
+	}
+	if feedIncoming.IncomingDate.IsZero() {
+		feedIncoming.IncomingDate = time.Now()
+	}
+	if feedIncoming.Supplier != nil {
+		if supplier, err := svc.FeedRepository.ResolveSupplierByID(feedIncoming.Supplier.ID); err != nil {
+			return nil, err
+		} else if supplier.Deleted {
+			return nil, fmt.Errorf("Supplier %s has been deleted.", supplier.Name)
+		} else if supplier.Status == 0 {
+			return nil, fmt.Errorf("Supplier %s is inactive.", supplier.Name)
+		}
+	}
+	//returned feed is taken off the lots by the repository in the transaction
+	//storing the incoming
+	if result, err := svc.FeedRepository.InsertFeedIncoming(feedIncoming, IncomingFeedLot(feedIncoming)); err != nil {
 		return nil, err
 	} else {
 		return result, nil
 	}
 }
 
+//IncomingFeedLot returns the lot a received incoming opens, returned feed opens none
+func IncomingFeedLot(feedIncoming *FeedIncoming) *FeedLot {
+	if feedIncoming.Qty <= 0 {
+		return nil
+	}
+	return &FeedLot{
+		ID:             uuid.Must(uuid.NewV4()),
+		FeedTypeID:     feedIncoming.FeedType.ID,
+		FeedIncomingID: feedIncoming.ID,
+		LotNumber:      feedIncoming.LotNumber,
+		Manufactured:   feedIncoming.Manufactured,
+		Expired:        feedIncoming.Expired,
+		Qty:            feedIncoming.Qty,
+		Remaining:      feedIncoming.Qty,
+	}
+}
+
+//feed type cost
+func (svc *FeedService) ResolveFeedTypeCostByFeedTypeID(id uuid.UUID) (*[]FeedTypeCost, error) {
+	if _, err := svc.FeedRepository.ResolveFeedTypeByID(id); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else if costs, err := svc.FeedRepository.ResolveFeedTypeCostByFeedTypeIDs([]uuid.UUID{id}); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+		return costs, nil
+	}
+}
+
+func (svc *FeedService) ResolveFeedTypeCostByFeedTypeIDs(ids []uuid.UUID) (*[]FeedTypeCost, error) {
+	if costs, err := svc.FeedRepository.ResolveFeedTypeCostByFeedTypeIDs(ids); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+		return costs, nil
+	}
+}
+
+//RecalculateFeedTypeCost rebuilds the cost history of a feed type. Storing an
+//incoming, feeding or adjustment already rebuilds it in the same transaction.
+func (svc *FeedService) RecalculateFeedTypeCost(feedTypeId uuid.UUID) (*[]FeedTypeCost, error) {
+	if costs, err := svc.FeedRepository.RecalculateFeedTypeCost(feedTypeId); err != nil {
+		return nil, err
+	} else {
+		return costs, nil
+	}
+}
+
+//replayFeedTypeCost replays incomings and stock movements of a feed type in date
+//order into its moving weighted-average cost history and current average cost
+func replayFeedTypeCost(feedTypeId uuid.UUID, incomings []FeedIncoming, movements []FeedMovement) ([]FeedTypeCost, float64) {
+	var stock float64
+	var averageCost float64
+	next := 0
+	costs := make([]FeedTypeCost, 0)
+	for _, incoming := range incomings {
+		//apply stock movements happened before the incoming date
+		for next < len(movements) && movements[next].MovementDate.Before(incoming.IncomingDate) {
+			stock = stock + movements[next].Qty
+			next++
+		}
+		//returned feed leaves the average cost untouched
+		if incoming.Qty > 0 {
+			if stock <= 0 {
+				averageCost = incoming.Price
+			} else {
+				averageCost = ((stock * averageCost) + (incoming.Qty * incoming.Price)) / (stock + incoming.Qty)
+			}
+		}
+		costs = append(costs, FeedTypeCost{
+			ID:             uuid.Must(uuid.NewV4()),
+			FeedTypeID:     feedTypeId,
+			FeedIncomingID: incoming.ID,
+			CostDate:       incoming.IncomingDate,
+			Stock:          stock,
+			Qty:            incoming.Qty,
+			Price:          incoming.Price,
+			AverageCost:    averageCost,
+		})
+		stock = stock + incoming.Qty
+	}
+	return costs, averageCost
+}
+
+//FeedTypeCostAt returns the average cost of a feed type in effect on the given date,
+//costs must be ordered by cost date. Feed used before its first costed incoming
+//is valued at the first known cost.
+func FeedTypeCostAt(costs []FeedTypeCost, feedTypeId uuid.UUID, date time.Time) float64 {
+	var averageCost float64
+	found := false
+	for _, cost := range costs {
+		if cost.FeedTypeID != feedTypeId {
+			continue
+		}
+		if !found || !cost.CostDate.After(date) {
+			averageCost = cost.AverageCost
+			found = true
+		}
+	}
+	return averageCost
+}
+
+//feed alert
+//ResolveFeedAlert lists feed types at or below their reorder point or whose stock
+//runs out within horizon days at the average daily consumption of the last window days
+func (svc *FeedService) ResolveFeedAlert(horizon int, window int) (*[]FeedAlert, error) {
+	feedtypes, err := svc.FeedRepository.ResolveActiveFeedType()
+	if err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	}
+	var ids []uuid.UUID
+	for _, feedtype := range *feedtypes {
+		ids = append(ids, feedtype.ID)
+	}
+	stocks, err := svc.FeedRepository.ResolveFeedStockByFeedTypeIDs(ids)
+	if err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	}
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	consumptions, err := svc.FeedRepository.ResolveFeedConsumptionByFeedTypeIDs(ids, today.AddDate(0, 0, 0-window))
+	if err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	}
+
+	alerts := make([]FeedAlert, 0)
+	for _, feedtype := range *feedtypes {
+		alert := FeedAlert{FeedType: feedtype}
+		for _, stock := range *stocks {
+			if stock.FeedTypeID == feedtype.ID {
+				alert.Stock = stock.Qty
+			}
+		}
+		for _, consumption := range *consumptions {
+			if consumption.FeedTypeID == feedtype.ID {
+				alert.DailyConsumption = consumption.Qty / float64(window)
+			}
+		}
+		if alert.DailyConsumption > 0 {
+			days := math.Max(alert.Stock, 0) / alert.DailyConsumption
+			alert.DaysOfCover = null.FloatFrom(days)
+			alert.StockoutDate = null.TimeFrom(today.AddDate(0, 0, int(days)))
+			alert.RunningOut = days <= float64(horizon)
+		}
+		alert.BelowReorderPoint = feedtype.ReorderPoint > 0 && alert.Stock <= feedtype.ReorderPoint
+		if !alert.BelowReorderPoint && !alert.RunningOut {
+			continue
+		}
+		//without a reorder qty, order enough to get back above the reorder point through the horizon
+		if feedtype.ReorderQty > 0 {
+			alert.SuggestedQty = feedtype.ReorderQty
+		} else {
+			alert.SuggestedQty = math.Max(feedtype.ReorderPoint+(alert.DailyConsumption*float64(horizon))-alert.Stock, 0)
+		}
+		alerts = append(alerts, alert)
+	}
+
+	//most urgent first, feed types without consumption go last
+	sort.SliceStable(alerts, func(i, j int) bool {
+		if alerts[i].DaysOfCover.Valid != alerts[j].DaysOfCover.Valid {
+			return alerts[i].DaysOfCover.Valid
+		}
+		return alerts[i].DaysOfCover.Float64 < alerts[j].DaysOfCover.Float64
+	})
+	return &alerts, nil
+}
+
+//feed lot
+func (svc *FeedService) ResolveFeedLot(expiredBefore null.Time) (*[]FeedLot, error) {
+	if lots, err := svc.FeedRepository.ResolveAvailableFeedLot(expiredBefore); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+		return lots, nil
+	}
+}
+
+func (svc *FeedService) ResolveFeedLotUsageByReferenceIDs(ids []uuid.UUID) (*[]FeedLotUsage, error) {
+	if usages, err := svc.FeedRepository.ResolveFeedLotUsageByReferenceIDs(ids); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+		return usages, nil
+	}
+}
+
+//planFeedLotUsage splits qty of a feed type over its available lots, first expiring
+//first out. Stock received before lots were tracked or added by an adjustment has no
+//lot, what the lots cannot cover comes out of it and fails once that is short too.
+//The repositories plan inside the transaction storing the row the usages belong to.
+func planFeedLotUsage(feedTypeId uuid.UUID, lots []FeedLot, stock float64, referenceType string, referenceId uuid.UUID, qty float64) (*[]FeedLotUsage, error) {
+	untracked := stock
+	for _, lot := range lots {
+		untracked = untracked - lot.Remaining
+	}
+	usages := make([]FeedLotUsage, 0)
+	for _, lot := range lots {
+		if qty <= 0 {
+			break
+		}
+		used := lot.Remaining
+		if qty < used {
+			used = qty
+		}
+		usages = append(usages, FeedLotUsage{
+			ID:            uuid.Must(uuid.NewV4()),
+			FeedLotID:     lot.ID,
+			LotNumber:     lot.LotNumber,
+			Expired:       lot.Expired,
+			ReferenceType: referenceType,
+			ReferenceID:   referenceId,
+			Qty:           used,
+		})
+		qty = qty - used
+	}
+	//stock is summed from stored decimals, a rounding difference is not a shortage
+	if qty > untracked+1e-6 {
+		return nil, fmt.Errorf("Feed type %s does not have enough stock, %v %s is missing", feedTypeId, qty-math.Max(untracked, 0), Unit_Base)
+	}
+	return &usages, nil
+}
+
+//ProteinIntake returns kg of protein in qty kg of a feed type
+func ProteinIntake(feedtype FeedType, qty float64) float64 {
+	return qty * feedtype.Protein / 100
+}
+
 //feed adjustment
 func (svc *FeedService) ResolveFeedAdjustmentPage(page int32, limit int32) (*[]FeedAdjustment, int32, int32, int32, error) {
 	if feedAdjustments, page, limit, total, err := svc.FeedRepository.ResolveFeedAdjustmentPage(page, limit); err != nil {
@@ -131,9 +588,187 @@ func (svc *FeedService) ResolveFeedAdjustmentByID(id uuid.UUID) (*FeedAdjustment
 
 func (svc *FeedService) StoreFeedAdjustment(feedAdjustment *FeedAdjustment) (*FeedAdjustment, error) {
 	feedAdjustment.ID = uuid.Must(uuid.NewV4())
+	//stocktake adjustments are only booked by approving a stocktake
+	feedAdjustment.Reason = Adjustment_Reason_Manual
+	feedAdjustment.StocktakeID = nil
+	if qty, err := svc.ConvertToBaseUnit(feedAdjustment.FeedType.ID, feedAdjustment.Qty, feedAdjustment.Unit); err != nil {
+		return nil, err
+	} else {
+		feedAdjustment.Qty = qty
+		feedAdjustment.Unit = Unit_Base
+	}
+	//missing feed is taken off the lots in the same transaction as the adjustment
 	if result, err := svc.FeedRepository.InsertFeedAdjustment(feedAdjustment); err != nil {
 		return nil, err
 	} else {
 		return result, nil
 	}
 }
+
+//stocktake
+func (svc *FeedService) ResolveStocktakePage(page int32, limit int32) (*[]Stocktake, int32, int32, int32, error) {
+	if stocktakes, page, limit, total, err := svc.FeedRepository.ResolveStocktakePage(page, limit); err != nil {
+		return nil, 0, 0, 0, err
+	} else {
+		return stocktakes, page, limit, total, nil
+	}
+}
+
+func (svc *FeedService) ResolveStocktakeByID(id uuid.UUID) (*Stocktake, error) {
+	if stocktake, err := svc.FeedRepository.ResolveStocktakeByID(id); err != nil {
+		return nil, fmt.Errorf("found an error: %s", err.Error())
+	} else {
+		return stocktake, nil
+	}
+}
+
+func (svc *FeedService) StoreStocktake(stocktake *Stocktake) (*Stocktake, error) {
+	if stocktake.StocktakeDate.IsZero() {
+		stocktake.StocktakeDate = time.Now()
+	}
+	if stocktake.ID == uuid.Nil {
+		stocktake.ID = uuid.Must(uuid.NewV4())
+		stocktake.Status = Stocktake_Open
+		if result, err := svc.FeedRepository.InsertStocktake(stocktake); err != nil {
+			return nil, err
+		} else {
+			return result, nil
+		}
+	} else if current, err := svc.FeedRepository.ResolveStocktakeByID(stocktake.ID); err != nil {
+		return nil, err
+	} else if current.Status != Stocktake_Open {
+		return nil, fmt.Errorf("stocktake with id %s is already %s", current.ID, current.Status)
+	} else if result, err := svc.FeedRepository.UpdateStocktakeByID(stocktake); err != nil {
+		return nil, err
+	} else {
+		return result, nil
+	}
+}
+
+//StoreStocktakeCount records counted qty per feed type on an open stocktake,
+//a feed type counted again replaces its previous count
+func (svc *FeedService) StoreStocktakeCount(stocktakeId uuid.UUID, details []StocktakeDetail) (*Stocktake, error) {
+	stocktake, err := svc.FeedRepository.ResolveStocktakeByID(stocktakeId)
+	if err != nil {
+		return nil, err
+	}
+	if stocktake.Status != Stocktake_Open {
+		return nil, fmt.Errorf("stocktake with id %s is already %s", stocktake.ID, stocktake.Status)
+	}
+
+	counts := stocktake.Detail
+	for _, detail := range details {
+		counted, err := svc.ConvertToBaseUnit(detail.FeedType.ID, detail.CountedQty, detail.Unit)
+		if err != nil {
+			return nil, err
+		}
+		detail.CountedQty = counted
+		found := false
+		for i := range counts {
+			if counts[i].FeedTypeID == detail.FeedType.ID {
+				counts[i].CountedQty = detail.CountedQty
+				found = true
+			}
+		}
+		if !found {
+			counts = append(counts, StocktakeDetail{
+				FeedTypeID: detail.FeedType.ID,
+				CountedQty: detail.CountedQty,
+			})
+		}
+	}
+	newCounts := make([]StocktakeDetail, 0)
+	for _, count := range counts {
+		count.ID = uuid.Must(uuid.NewV4())
+		count.StocktakeID = stocktake.ID
+		count.FeedType.ID = count.FeedTypeID
+		newCounts = append(newCounts, count)
+	}
+	if result, err := svc.FeedRepository.ReplaceStocktakeDetailByStocktakeID(stocktake.ID, &newCounts); err != nil {
+		return nil, err
+	} else {
+		return result, nil
+	}
+}
+
+//PreviewStocktakeVariance compares counted qty of an open stocktake against the
+//system ledger, an approved stocktake keeps the variance frozen at approval
+func (svc *FeedService) PreviewStocktakeVariance(id uuid.UUID) (*Stocktake, error) {
+	stocktake, err := svc.FeedRepository.ResolveStocktakeByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if stocktake.Status != Stocktake_Open {
+		return stocktake, nil
+	}
+
+	var ids []uuid.UUID
+	for _, detail := range stocktake.Detail {
+		ids = append(ids, detail.FeedTypeID)
+	}
+	stocks, err := svc.FeedRepository.ResolveFeedStockByFeedTypeIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range stocktake.Detail {
+		stocktake.Detail[i].SystemQty = 0
+		for _, stock := range *stocks {
+			if stock.FeedTypeID == stocktake.Detail[i].FeedTypeID {
+				stocktake.Detail[i].SystemQty = stock.Qty
+			}
+		}
+		stocktake.Detail[i].Variance = stocktake.Detail[i].CountedQty - stocktake.Detail[i].SystemQty
+	}
+	return stocktake, nil
+}
+
+//ApproveStocktake books a stocktake adjustment for every feed type whose count
+//differs from the system ledger and closes the stocktake
+func (svc *FeedService) ApproveStocktake(id uuid.UUID) (*Stocktake, error) {
+	stocktake, err := svc.PreviewStocktakeVariance(id)
+	if err != nil {
+		return nil, err
+	}
+	if stocktake.Status != Stocktake_Open {
+		return nil, fmt.Errorf("stocktake with id %s is already %s", stocktake.ID, stocktake.Status)
+	}
+
+	adjustments := make([]FeedAdjustment, 0)
+	for _, detail := range stocktake.Detail {
+		if detail.Variance == 0 {
+			continue
+		}
+		adjustment := FeedAdjustment{
+			ID:          uuid.Must(uuid.NewV4()),
+			FeedType:    detail.FeedType,
+			FeedTypeID:  detail.FeedTypeID,
+			Qty:         detail.Variance,
+			Reason:      Adjustment_Reason_Stocktake,
+			StocktakeID: &stocktake.ID,
+			Remarks:     fmt.Sprintf("Stocktake %s", stocktake.StocktakeDate.Format("2006-01-02")),
+			Created:     stocktake.StocktakeDate,
+		}
+		adjustment.FeedType.ID = detail.FeedTypeID
+		adjustments = append(adjustments, adjustment)
+	}
+
+	//missing stock is taken off the lots like any other negative adjustment
+	if result, err := svc.FeedRepository.ApproveStocktakeByID(stocktake, &adjustments); err != nil {
+		return nil, err
+	} else {
+		return result, nil
+	}
+}
+
+//export
+func (svc *FeedService) StreamFeedType(deleted string, fn func(*FeedType) error) error {
+	return svc.FeedRepository.StreamFeedType(deleted, fn)
+}
+
+func (svc *FeedService) StreamFeedIncoming(fn func(*FeedIncoming) error) error {
+	return svc.FeedRepository.StreamFeedIncoming(fn)
+}
+
+func (svc *FeedService) StreamFeedAdjustment(fn func(*FeedAdjustment) error) error {
+	return svc.FeedRepository.StreamFeedAdjustment(fn)
+}
@@ -0,0 +1,187 @@
+package feed
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	uuid "github.com/satori/go.uuid"
+)
+
+func TestResolveFeedTypeByID(t *testing.T) {
+	svc := &FeedService{FeedRepository: new(MemoryRepository)}
+	feedtype, err := svc.StoreFeedType(&FeedType{Name: "Pellet", Unit: "kg", Status: 1, Stage: Feed_Stage_Grower})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.StoreFeedTypeUnit(&FeedTypeUnit{FeedTypeID: feedtype.ID, Unit: "Sack", Factor: 25}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := svc.ResolveFeedTypeByID(feedtype.ID)
+	if err != nil {
+		t.Fatal(err)
+	} else if result.Name != "Pellet" || len(result.Units) != 1 || result.Units[0].Unit != "sack" {
+		t.Fatalf("feed type read back as %+v", result)
+	}
+	if qty, err := svc.ConvertToBaseUnit(feedtype.ID, 2, "sack"); err != nil {
+		t.Fatal(err)
+	} else if qty != 50 {
+		t.Fatalf("2 sacks converted to %v kg", qty)
+	}
+}
+
+func TestResolveFeedTypeByIDNotFound(t *testing.T) {
+	svc := &FeedService{FeedRepository: new(MemoryRepository)}
+	id := uuid.Must(uuid.NewV4())
+	if _, err := svc.ResolveFeedTypeByID(id); err == nil || !strings.Contains(err.Error(), id.String()) {
+		t.Fatalf("unknown feed type answered with %v", err)
+	}
+}
+
+func TestRemoveFeedType(t *testing.T) {
+	svc := &FeedService{FeedRepository: new(MemoryRepository)}
+	for _, name := range []string{"Pellet 2", "Pellet 1"} {
+		if _, err := svc.StoreFeedType(&FeedType{Name: name, Unit: "kg", Status: 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	page, _, _, _, err := svc.ResolveFeedTypePage(0, 10, Deleted_Any, "", null.Float{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.RemoveFeedTypeByID((*page)[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if page, _, _, total, err := svc.ResolveFeedTypePage(0, 10, Deleted_False, "", null.Float{}); err != nil {
+		t.Fatal(err)
+	} else if total != 1 || (*page)[0].Name != "Pellet 2" {
+		t.Fatalf("got %d feed types left", total)
+	}
+	if _, _, _, total, err := svc.ResolveFeedTypePage(0, 10, Deleted_True, "", null.Float{}); err != nil {
+		t.Fatal(err)
+	} else if total != 1 {
+		t.Fatalf("got %d removed feed types", total)
+	}
+}
+
+func TestStoreFeedIncomingDeclaredUnit(t *testing.T) {
+	svc := &FeedService{FeedRepository: new(MemoryRepository)}
+	feedtype, err := svc.StoreFeedType(&FeedType{Name: "Pellet", Unit: "Sack", Status: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.StoreFeedIncoming(&FeedIncoming{FeedType: *feedtype, Qty: 4, Price: 300000}); err == nil {
+		t.Fatal("stored an incoming in the declared unit without factor")
+	}
+	if _, err := svc.StoreFeedIncoming(&FeedIncoming{FeedType: *feedtype, Qty: 4, Unit: "bag"}); err == nil {
+		t.Fatal("stored an incoming in a unit without factor")
+	}
+	if _, err := svc.StoreFeedTypeUnit(&FeedTypeUnit{FeedTypeID: feedtype.ID, Unit: "sack", Factor: 25}); err != nil {
+		t.Fatal(err)
+	}
+	if result, err := svc.StoreFeedIncoming(&FeedIncoming{FeedType: *feedtype, Qty: 4, Price: 300000}); err != nil {
+		t.Fatal(err)
+	} else if result.Qty != 100 || result.Price != 12000 {
+		t.Fatalf("incoming stored %v at %v", result.Qty, result.Price)
+	}
+}
+
+func TestRemoveFeedTypeUnitOfOtherFeedType(t *testing.T) {
+	svc := &FeedService{FeedRepository: new(MemoryRepository)}
+	pellet, err := svc.StoreFeedType(&FeedType{Name: "Pellet", Unit: "kg", Status: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	crumble, err := svc.StoreFeedType(&FeedType{Name: "Crumble", Unit: "kg", Status: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	unit, err := svc.StoreFeedTypeUnit(&FeedTypeUnit{FeedTypeID: pellet.ID, Unit: "sack", Factor: 25})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.RemoveFeedTypeUnitByID(crumble.ID, unit.ID); err == nil {
+		t.Fatal("removed a unit through another feed type")
+	}
+	if _, err := svc.RemoveFeedTypeUnitByID(pellet.ID, unit.ID); err != nil {
+		t.Fatal(err)
+	} else if units, err := svc.ResolveFeedTypeUnitByFeedTypeID(pellet.ID); err != nil {
+		t.Fatal(err)
+	} else if len(*units) != 0 {
+		t.Fatalf("got %d units left", len(*units))
+	}
+}
+
+func TestStoreFeedIncomingSupplier(t *testing.T) {
+	svc := &FeedService{FeedRepository: new(MemoryRepository)}
+	feedtype, err := svc.StoreFeedType(&FeedType{Name: "Pellet", Unit: "kg", Status: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	active, err := svc.StoreSupplier(&Supplier{Name: "Active", Status: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	inactive, err := svc.StoreSupplier(&Supplier{Name: "Inactive", Status: 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deleted, err := svc.StoreSupplier(&Supplier{Name: "Deleted", Status: 1})
+	if err != nil {
+		t.Fatal(err)
+	} else if _, err := svc.RemoveSupplierByID(deleted.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.StoreFeedIncoming(&FeedIncoming{FeedType: *feedtype, Supplier: active, Qty: 10}); err != nil {
+		t.Fatal(err)
+	}
+	for _, supplier := range []*Supplier{inactive, deleted} {
+		if _, err := svc.StoreFeedIncoming(&FeedIncoming{FeedType: *feedtype, Supplier: supplier, Qty: 10}); err == nil || !strings.Contains(err.Error(), supplier.Name) {
+			t.Fatalf("incoming from supplier %s answered %v", supplier.Name, err)
+		}
+	}
+}
+
+func TestStoreFeedIncomingBackdated(t *testing.T) {
+	svc := &FeedService{FeedRepository: new(MemoryRepository)}
+	feedtype, err := svc.StoreFeedType(&FeedType{Name: "Pellet", Unit: "kg", Status: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, incoming := range []FeedIncoming{
+		{IncomingDate: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Qty: 100, Price: 10000},
+		{IncomingDate: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), Qty: 100, Price: 20000},
+	} {
+		incoming.FeedType = *feedtype
+		if _, err := svc.StoreFeedIncoming(&incoming); err != nil {
+			t.Fatal(err)
+		}
+	}
+	later := time.Date(2024, 4, 15, 0, 0, 0, 0, time.UTC)
+	if costs, err := svc.ResolveFeedTypeCostByFeedTypeID(feedtype.ID); err != nil {
+		t.Fatal(err)
+	} else if cost := FeedTypeCostAt(*costs, feedtype.ID, later); cost != 15000 {
+		t.Fatalf("cost in april was %v", cost)
+	}
+
+	if _, err := svc.StoreFeedIncoming(&FeedIncoming{FeedType: *feedtype, IncomingDate: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Qty: 100, Price: 4000}); err != nil {
+		t.Fatal(err)
+	}
+	costs, err := svc.ResolveFeedTypeCostByFeedTypeID(feedtype.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost := FeedTypeCostAt(*costs, feedtype.ID, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)); cost != 7000 {
+		t.Fatalf("cost in march after the backdated incoming was %v", cost)
+	}
+	if cost := FeedTypeCostAt(*costs, feedtype.ID, later); math.Abs(cost-34000.0/3) > 1e-6 {
+		t.Fatalf("cost in april after the backdated incoming was %v", cost)
+	}
+	if result, err := svc.ResolveFeedTypeByID(feedtype.ID); err != nil {
+		t.Fatal(err)
+	} else if math.Abs(result.AverageCost-34000.0/3) > 1e-6 {
+		t.Fatalf("feed type average cost is %v", result.AverageCost)
+	}
+}
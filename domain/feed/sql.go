@@ -3,7 +3,9 @@ package feed
 import (
 	"database/sql"
 	"fmt"
+	"time"
 
+	"github.com/guregu/null"
 	"github.com/ncrypthic/dbmapper"
 	. "github.com/ncrypthic/dbmapper/dialects/mysql"
 	uuid "github.com/satori/go.uuid"
@@ -11,36 +13,141 @@ import (
 
 type Repository interface {
 	//feedtype
-	ResolveFeedTypePage(page int32, limit int32, deleted string) (*[]FeedType, int32, int32, int32, error)
+	ResolveFeedTypePage(page int32, limit int32, deleted string, stage string, pelletSize null.Float) (*[]FeedType, int32, int32, int32, error)
 	ResolveFeedTypeByIDs(ids []uuid.UUID) (*[]FeedType, error)
+	ResolveActiveFeedType() (*[]FeedType, error)
 	ResolveFeedTypeByID(id uuid.UUID) (*FeedType, error)
 	InsertFeedType(feedtype *FeedType) (*FeedType, error)
+	InsertFeedTypeTransaction(tx *sql.Tx, feedtype *FeedType) (*FeedType, error)
 	UpdateFeedTypeByID(feedtype *FeedType) (*FeedType, error)
 	RemoveFeedTypeByID(id uuid.UUID) (*FeedType, error)
 	RemoveFeedTypeByIDs(ids []uuid.UUID) (*[]FeedType, error)
+	//feed type unit
+	ResolveFeedTypeUnitByFeedTypeID(feedTypeId uuid.UUID) (*[]FeedTypeUnit, error)
+	ResolveFeedTypeUnitByID(id uuid.UUID) (*FeedTypeUnit, error)
+	InsertFeedTypeUnit(unit *FeedTypeUnit) (*FeedTypeUnit, error)
+	UpdateFeedTypeUnitByID(unit *FeedTypeUnit) (*FeedTypeUnit, error)
+	RemoveFeedTypeUnitByID(id uuid.UUID) (*FeedTypeUnit, error)
+	//supplier
+	ResolveSupplierPage(page int32, limit int32, deleted string) (*[]Supplier, int32, int32, int32, error)
+	ResolveSupplierByIDs(ids []uuid.UUID) (*[]Supplier, error)
+	ResolveSupplierByID(id uuid.UUID) (*Supplier, error)
+	InsertSupplier(supplier *Supplier) (*Supplier, error)
+	UpdateSupplierByID(supplier *Supplier) (*Supplier, error)
+	RemoveSupplierByID(id uuid.UUID) (*Supplier, error)
+	RemoveSupplierByIDs(ids []uuid.UUID) (*[]Supplier, error)
+	ResolveSupplierPurchase(from time.Time, to time.Time) (*[]SupplierPurchase, error)
+	ResolveSupplierPerformance(from time.Time, to time.Time) (*[]SupplierPerformance, error)
 	//feed incoming
 	ResolveFeedIncomingPage(page int32, limit int32) (*[]FeedIncoming, int32, int32, int32, error)
 	ResolveFeedIncomingByID(id uuid.UUID) (*FeedIncoming, error)
-	InsertFeedIncoming(feedIncoming *FeedIncoming) (*FeedIncoming, error)
+	InsertFeedIncoming(feedIncoming *FeedIncoming, lot *FeedLot) (*FeedIncoming, error)
+	InsertFeedIncomingTransaction(tx *sql.Tx, feedIncoming *FeedIncoming) (*FeedIncoming, error)
+	ResolveFeedIncomingByFeedTypeID(feedTypeId uuid.UUID) (*[]FeedIncoming, error)
+	//feed movement
+	ResolveFeedMovementByFeedTypeID(feedTypeId uuid.UUID) (*[]FeedMovement, error)
+	//feed type cost
+	ResolveFeedTypeCostByFeedTypeIDs(ids []uuid.UUID) (*[]FeedTypeCost, error)
+	RecalculateFeedTypeCost(feedTypeId uuid.UUID) (*[]FeedTypeCost, error)
+	RecalculateFeedTypeCostTransaction(tx *sql.Tx, feedTypeId uuid.UUID) (*[]FeedTypeCost, error)
+	//feed lot
+	ResolveFeedLotByID(id uuid.UUID) (*FeedLot, error)
+	ResolveAvailableFeedLot(expiredBefore null.Time) (*[]FeedLot, error)
+	InsertFeedLotTransaction(tx *sql.Tx, lot *FeedLot) (*FeedLot, error)
+	//feed lot usage
+	ResolveFeedLotUsageByReferenceIDs(ids []uuid.UUID) (*[]FeedLotUsage, error)
+	ConsumeFeedLotTransaction(tx *sql.Tx, feedTypeId uuid.UUID, referenceType string, referenceId uuid.UUID, qty float64) (*[]FeedLotUsage, error)
 	//feed adjustment
 	ResolveFeedAdjustmentPage(page int32, limit int32) (*[]FeedAdjustment, int32, int32, int32, error)
 	ResolveFeedAdjustmentByID(id uuid.UUID) (*FeedAdjustment, error)
 	InsertFeedAdjustment(feedAdjustment *FeedAdjustment) (*FeedAdjustment, error)
+	InsertFeedAdjustmentTransaction(tx *sql.Tx, feedAdjustment *FeedAdjustment) (*FeedAdjustment, error)
+	//feed stock
+	ResolveFeedStockByFeedTypeIDs(ids []uuid.UUID) (*[]FeedStock, error)
+	ResolveFeedConsumptionByFeedTypeIDs(ids []uuid.UUID, from time.Time) (*[]FeedStock, error)
+	//export, rows are handed over one by one as they are read
+	StreamFeedType(deleted string, fn func(*FeedType) error) error
+	StreamFeedIncoming(fn func(*FeedIncoming) error) error
+	StreamFeedAdjustment(fn func(*FeedAdjustment) error) error
+	//stocktake
+	ResolveStocktakePage(page int32, limit int32) (*[]Stocktake, int32, int32, int32, error)
+	ResolveStocktakeByID(id uuid.UUID) (*Stocktake, error)
+	InsertStocktake(stocktake *Stocktake) (*Stocktake, error)
+	UpdateStocktakeByID(stocktake *Stocktake) (*Stocktake, error)
+	ReplaceStocktakeDetailByStocktakeID(stocktakeId uuid.UUID, details *[]StocktakeDetail) (*Stocktake, error)
+	ApproveStocktakeByID(stocktake *Stocktake, adjustments *[]FeedAdjustment) (*Stocktake, error)
 }
 
 const (
 	//feedtype
-	selectFeedType         = `SELECT id, name, unit, status, deleted, created, updated FROM feed_type`
-	selectMultipleFeedType = `SELECT id, name, unit, status, deleted, created, updated FROM feed_type WHERE id IN (:ids)`
-	insertFeedType         = `INSERT INTO feed_type(id, name, unit, status, deleted, created) VALUES (:id ,:name, :unit, :status, :deleted, NOW())`
-	updateFeedType         = `UPDATE feed_type SET name = :name, unit = :unit, status = :status, deleted = :deleted, updated = NOW() WHERE id = :id`
-	deleteFeedType         = `UPDATE feed_type SET deleted = 1, updated = NOW() WHERE id = :id`
+	selectFeedType         = `SELECT id, name, unit, status, average_cost, protein, fat, pellet_size, stage, reorder_point, reorder_qty, deleted, created, updated FROM feed_type`
+	selectMultipleFeedType = `SELECT id, name, unit, status, average_cost, protein, fat, pellet_size, stage, reorder_point, reorder_qty, deleted, created, updated FROM feed_type WHERE id IN (:ids)`
+	insertFeedType         = `INSERT INTO feed_type(id, name, unit, status, protein, fat, pellet_size, stage, reorder_point, reorder_qty, deleted, created) VALUES (:id ,:name, :unit, :status, :protein, :fat, :pellet_size, :stage, :reorder_point, :reorder_qty, :deleted, CURRENT_TIMESTAMP)`
+	updateFeedType         = `UPDATE feed_type SET name = :name, unit = :unit, status = :status, protein = :protein, fat = :fat, pellet_size = :pellet_size, stage = :stage, reorder_point = :reorder_point, reorder_qty = :reorder_qty, deleted = :deleted, updated = CURRENT_TIMESTAMP WHERE id = :id`
+	deleteFeedType         = `UPDATE feed_type SET deleted = 1, updated = CURRENT_TIMESTAMP WHERE id = :id`
+	//locks the feed type row so its stock is taken off by one transaction at a time
+	lockFeedType = `UPDATE feed_type SET average_cost = average_cost WHERE id = :id`
+	//feed type unit
+	selectFeedTypeUnit = `SELECT id, feed_type_id, unit, factor, created, updated FROM feed_type_unit`
+	insertFeedTypeUnit = `INSERT INTO feed_type_unit(id, feed_type_id, unit, factor, created) VALUES (:id, :feedtype, :unit, :factor, CURRENT_TIMESTAMP)`
+	updateFeedTypeUnit = `UPDATE feed_type_unit SET unit = :unit, factor = :factor, updated = CURRENT_TIMESTAMP WHERE id = :id`
+	deleteFeedTypeUnit = `DELETE FROM feed_type_unit WHERE id = :id`
+	//supplier
+	selectSupplier = `SELECT id, name, contact, phone, address, status, deleted, created, updated FROM feed_supplier`
+	insertSupplier = `INSERT INTO feed_supplier(id, name, contact, phone, address, status, deleted, created) VALUES (:id ,:name, :contact, :phone, :address, :status, :deleted, CURRENT_TIMESTAMP)`
+	updateSupplier = `UPDATE feed_supplier SET name = :name, contact = :contact, phone = :phone, address = :address, status = :status, deleted = :deleted, updated = CURRENT_TIMESTAMP WHERE id = :id`
+	deleteSupplier = `UPDATE feed_supplier SET deleted = 1, updated = CURRENT_TIMESTAMP WHERE id = :id`
+	//supplier report
+	selectSupplierPurchase = `SELECT s.id AS supplier_id, s.name, COUNT(fi.id) AS incoming, SUM(fi.qty) AS qty, SUM(fi.qty * fi.price) AS amount
+		FROM feed_incoming fi JOIN feed_supplier s ON s.id = fi.feed_supplier_id
+		WHERE fi.incoming_date BETWEEN :from AND :to
+		GROUP BY s.id, s.name ORDER BY amount DESC`
+	//every cycle's fcr is weighted by how much of the supplier's feed types it consumed
+	selectSupplierPerformance = `SELECT s.id AS supplier_id, s.name, COUNT(DISTINCT gs.growth_batch_cycle_id) AS cycles, SUM(f.qty) AS feed_qty, SUM(f.qty * gs.fcr) / SUM(f.qty) AS fcr
+		FROM feed_supplier s
+		JOIN (SELECT DISTINCT feed_supplier_id, feed_type_id FROM feed_incoming) fi ON fi.feed_supplier_id = s.id
+		JOIN growth_feeding f ON f.feed_type_id = fi.feed_type_id
+		JOIN growth_summary gs ON gs.growth_batch_cycle_id = f.growth_batch_cycle_id
+		WHERE gs.summary_date BETWEEN :from AND :to
+		GROUP BY s.id, s.name ORDER BY fcr ASC`
 	//feed incoming
-	selectFeedIncoming = `SELECT id, feed_type_id, qty, remarks, created FROM feed_incoming`
-	insertFeedIncoming = `INSERT INTO feed_incoming(id, feed_type_id, qty, remarks, created) VALUES (:id ,:feedtype, :qty, :remarks, NOW())`
+	selectFeedIncoming = `SELECT id, feed_type_id, feed_supplier_id, incoming_date, qty, price, invoice, lot_number, manufactured, expired, remarks, created FROM feed_incoming`
+	//incomings of a feed type in the order their cost is replayed
+	selectFeedIncomingByFeedType = selectFeedIncoming + ` WHERE feed_type_id = :feedTypeId ORDER BY incoming_date ASC, created ASC`
+	insertFeedIncoming           = `INSERT INTO feed_incoming(id, feed_type_id, feed_supplier_id, incoming_date, qty, price, invoice, lot_number, manufactured, expired, remarks, created) VALUES (:id ,:feedtype, :supplier, :incoming_date, :qty, :price, :invoice, :lot_number, :manufactured, :expired, :remarks, CURRENT_TIMESTAMP)`
+	//feed lot, first expiring first out and lots without expiry date last
+	selectFeedLot      = `SELECT id, feed_type_id, feed_incoming_id, lot_number, manufactured, expired, qty, remaining, created, updated FROM feed_lot`
+	orderFeedLotFEFO   = ` ORDER BY expired IS NULL ASC, expired ASC, created ASC`
+	insertFeedLot      = `INSERT INTO feed_lot(id, feed_type_id, feed_incoming_id, lot_number, manufactured, expired, qty, remaining, created) VALUES (:id, :feedtype, :incoming, :lot_number, :manufactured, :expired, :qty, :remaining, CURRENT_TIMESTAMP)`
+	consumeFeedLot     = `UPDATE feed_lot SET remaining = remaining - :qty, updated = CURRENT_TIMESTAMP WHERE id = :id AND remaining >= :available`
+	selectFeedLotUsage = `SELECT u.id, u.feed_lot_id, l.lot_number, l.expired, u.reference_type, u.reference_id, u.qty, u.created FROM feed_lot_usage u JOIN feed_lot l ON l.id = u.feed_lot_id`
+	insertFeedLotUsage = `INSERT INTO feed_lot_usage(id, feed_lot_id, reference_type, reference_id, qty, created) VALUES (:id, :lot, :reference_type, :reference_id, :qty, CURRENT_TIMESTAMP)`
+	//feed movement, feedings are going out of stock while adjustments are signed
+	selectFeedMovement = `SELECT feeding_date AS movement_date, 0 - qty AS qty FROM growth_feeding WHERE feed_type_id = :feedingFeedTypeId UNION ALL SELECT created AS movement_date, qty FROM feed_adjustment WHERE feed_type_id = :adjustmentFeedTypeId ORDER BY movement_date ASC`
+	//feed type cost
+	selectFeedTypeCost        = `SELECT id, feed_type_id, feed_incoming_id, cost_date, stock, qty, price, average_cost, created FROM feed_type_cost`
+	insertFeedTypeCost        = `INSERT INTO feed_type_cost(id, feed_type_id, feed_incoming_id, cost_date, stock, qty, price, average_cost, created) VALUES (:id, :feedtype, :incoming, :cost_date, :stock, :qty, :price, :average_cost, CURRENT_TIMESTAMP)`
+	deleteFeedTypeCost        = `DELETE FROM feed_type_cost WHERE feed_type_id = :feedtype`
+	updateFeedTypeAverageCost = `UPDATE feed_type SET average_cost = :average_cost WHERE id = :id`
 	//feed adjustment
-	selectFeedAdjustment = `SELECT id, feed_type_id, qty, remarks, created FROM feed_adjustment`
-	insertFeedAdjustment = `INSERT INTO feed_adjustment(id, feed_type_id, qty, remarks, created) VALUES (:id ,:feedtype, :qty, :remarks, NOW())`
+	selectFeedAdjustment = `SELECT id, feed_type_id, qty, reason, feed_stocktake_id, remarks, created FROM feed_adjustment`
+	insertFeedAdjustment = `INSERT INTO feed_adjustment(id, feed_type_id, qty, reason, feed_stocktake_id, remarks, created) VALUES (:id ,:feedtype, :qty, :reason, :stocktake, :remarks, :created)`
+	//feed stock, the system ledger of incomings, feedings and adjustments
+	selectFeedStock = `SELECT feed_type_id, SUM(qty) AS qty FROM (
+		SELECT feed_type_id, qty FROM feed_incoming
+		UNION ALL SELECT feed_type_id, 0 - qty AS qty FROM growth_feeding
+		UNION ALL SELECT feed_type_id, qty FROM feed_adjustment) stock
+		WHERE feed_type_id IN (:ids) GROUP BY feed_type_id`
+	selectFeedConsumption = `SELECT feed_type_id, SUM(qty) AS qty FROM growth_feeding WHERE feed_type_id IN (:ids) AND feeding_date >= :from GROUP BY feed_type_id`
+	//stocktake
+	selectStocktake       = `SELECT id, stocktake_date, status, remarks, approved, created, updated FROM feed_stocktake`
+	insertStocktake       = `INSERT INTO feed_stocktake(id, stocktake_date, status, remarks, created) VALUES (:id, :stocktake_date, :status, :remarks, CURRENT_TIMESTAMP)`
+	updateStocktake       = `UPDATE feed_stocktake SET stocktake_date = :stocktake_date, remarks = :remarks, updated = CURRENT_TIMESTAMP WHERE id = :id AND status = :status`
+	approveStocktake      = `UPDATE feed_stocktake SET status = :approved, approved = CURRENT_TIMESTAMP, updated = CURRENT_TIMESTAMP WHERE id = :id AND status = :open`
+	selectStocktakeDetail = `SELECT id, feed_stocktake_id, feed_type_id, counted_qty, system_qty, variance, created, updated FROM feed_stocktake_detail`
+	insertStocktakeDetail = `INSERT INTO feed_stocktake_detail(id, feed_stocktake_id, feed_type_id, counted_qty, system_qty, variance, created) VALUES (:id, :stocktake, :feedtype, :counted_qty, :system_qty, :variance, CURRENT_TIMESTAMP)`
+	updateStocktakeDetail = `UPDATE feed_stocktake_detail SET system_qty = :system_qty, variance = :variance, updated = CURRENT_TIMESTAMP WHERE id = :id`
+	deleteStocktakeDetail = `DELETE FROM feed_stocktake_detail WHERE feed_stocktake_id = :stocktake`
 )
 
 type FeedRepository struct {
@@ -48,31 +155,38 @@ type FeedRepository struct {
 }
 
 //feedtype
-func (repo *FeedRepository) ResolveFeedTypePage(page int32, limit int32, deleted string) (*[]FeedType, int32, int32, int32, error) {
+func (repo *FeedRepository) ResolveFeedTypePage(page int32, limit int32, deleted string, stage string, pelletSize null.Float) (*[]FeedType, int32, int32, int32, error) {
 	var start int32
 	var end int32
 
 	start = page * limit
 	end = limit
 
+	//filter by deleted status, stage and pellet size
+	where := " WHERE 1 = 1"
+	var params []*dbmapper.QueryParam
+	if deleted == Deleted_True {
+		where = where + " AND deleted = 1"
+	} else if deleted == Deleted_False {
+		where = where + " AND deleted = 0"
+	}
+	if stage != "" {
+		where = where + " AND stage = :stage"
+		params = append(params, dbmapper.Param("stage", stage))
+	}
+	if pelletSize.Valid {
+		where = where + " AND pellet_size = :pellet_size"
+		params = append(params, dbmapper.Param("pellet_size", pelletSize.Float64))
+	}
+
 	//get data by given page
 	var query dbmapper.QueryMapper
-	if deleted == Deleted_Any {
-		query = dbmapper.Prepare(selectFeedType+" ORDER BY name ASC LIMIT :start, :end").With(
-			dbmapper.Param("start", start),
-			dbmapper.Param("end", end),
-		)
-	} else if deleted == Deleted_True {
-		query = dbmapper.Prepare(selectFeedType+" WHERE deleted = 1 ORDER BY name ASC LIMIT :start, :end").With(
-			dbmapper.Param("start", start),
-			dbmapper.Param("end", end),
-		)
-	} else if deleted == Deleted_False {
-		query = dbmapper.Prepare(selectFeedType+" WHERE deleted = 0 ORDER BY name ASC LIMIT :start, :end").With(
+	query = dbmapper.Prepare(selectFeedType + where + " ORDER BY name ASC LIMIT :end OFFSET :start").With(
+		append(params,
 			dbmapper.Param("start", start),
 			dbmapper.Param("end", end),
-		)
-	}
+		)...,
+	)
 	if err := query.Error(); err != nil {
 		return nil, page, limit, 0, err
 	}
@@ -86,13 +200,7 @@ func (repo *FeedRepository) ResolveFeedTypePage(page int32, limit int32, deleted
 
 	//get total feedtype
 	var summary dbmapper.QueryMapper
-	if deleted == Deleted_Any {
-		summary = dbmapper.Prepare("SELECT COUNT(*) AS total FROM feed_type")
-	} else if deleted == Deleted_True {
-		summary = dbmapper.Prepare("SELECT COUNT(*) AS total FROM feed_type WHERE deleted = 1")
-	} else if deleted == Deleted_False {
-		summary = dbmapper.Prepare("SELECT COUNT(*) AS total FROM feed_type WHERE deleted = 0")
-	}
+	summary = dbmapper.Prepare("SELECT COUNT(*) AS total FROM feed_type" + where).With(params...)
 
 	if err := summary.Error(); err != nil {
 		return nil, page, limit, 0, err
@@ -100,7 +208,7 @@ func (repo *FeedRepository) ResolveFeedTypePage(page int32, limit int32, deleted
 
 	var feedtypesCount int32
 	total := make([]int32, 0)
-	err = Parse(repo.DB.Query(summary.SQL())).Map(dbmapper.Int32("total", &total))
+	err = Parse(repo.DB.Query(summary.SQL(), summary.Params()...)).Map(dbmapper.Int32("total", &total))
 	if err != nil {
 		return nil, page, limit, 0, err
 	} else {
@@ -131,6 +239,21 @@ func (repo *FeedRepository) ResolveFeedTypeByIDs(ids []uuid.UUID) (*[]FeedType,
 	}
 }
 
+func (repo *FeedRepository) ResolveActiveFeedType() (*[]FeedType, error) {
+	query := dbmapper.Prepare(selectFeedType + " WHERE deleted = 0 ORDER BY name ASC")
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	feedtypes := make([]FeedType, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(feedtypesMapper(&feedtypes))
+
+	if err != nil {
+		return nil, err
+	} else {
+		return &feedtypes, nil
+	}
+}
+
 func (repo *FeedRepository) ResolveFeedTypeByID(id uuid.UUID) (*FeedType, error) {
 	query := dbmapper.Prepare(selectFeedType + " WHERE id = :id").With(
 		dbmapper.Param("id", id),
@@ -159,6 +282,12 @@ func (repo *FeedRepository) InsertFeedType(feedtype *FeedType) (*FeedType, error
 		dbmapper.Param("name", feedtype.Name),
 		dbmapper.Param("unit", feedtype.Unit),
 		dbmapper.Param("status", feedtype.Status),
+		dbmapper.Param("protein", feedtype.Protein),
+		dbmapper.Param("fat", feedtype.Fat),
+		dbmapper.Param("pellet_size", feedtype.PelletSize),
+		dbmapper.Param("stage", feedtype.Stage),
+		dbmapper.Param("reorder_point", feedtype.ReorderPoint),
+		dbmapper.Param("reorder_qty", feedtype.ReorderQty),
 		dbmapper.Param("deleted", feedtype.Deleted),
 	)
 	//validate query
@@ -176,6 +305,31 @@ func (repo *FeedRepository) InsertFeedType(feedtype *FeedType) (*FeedType, error
 	}
 }
 
+func (repo *FeedRepository) InsertFeedTypeTransaction(tx *sql.Tx, feedtype *FeedType) (*FeedType, error) {
+	//prepare query and params
+	insert := dbmapper.Prepare(insertFeedType).With(
+		dbmapper.Param("id", feedtype.ID),
+		dbmapper.Param("name", feedtype.Name),
+		dbmapper.Param("unit", feedtype.Unit),
+		dbmapper.Param("status", feedtype.Status),
+		dbmapper.Param("protein", feedtype.Protein),
+		dbmapper.Param("fat", feedtype.Fat),
+		dbmapper.Param("pellet_size", feedtype.PelletSize),
+		dbmapper.Param("stage", feedtype.Stage),
+		dbmapper.Param("reorder_point", feedtype.ReorderPoint),
+		dbmapper.Param("reorder_qty", feedtype.ReorderQty),
+		dbmapper.Param("deleted", feedtype.Deleted),
+	)
+	//validate query
+	if err := insert.Error(); err != nil {
+		return nil, err
+	} else if _, err := tx.Exec(insert.SQL(), insert.Params()...); err != nil {
+		return nil, err
+	} else {
+		return feedtype, nil
+	}
+}
+
 func (repo *FeedRepository) UpdateFeedTypeByID(feedtype *FeedType) (*FeedType, error) {
 	//find whether if data exist
 	_, err := repo.ResolveFeedTypeByID(feedtype.ID)
@@ -188,6 +342,12 @@ func (repo *FeedRepository) UpdateFeedTypeByID(feedtype *FeedType) (*FeedType, e
 			dbmapper.Param("name", feedtype.Name),
 			dbmapper.Param("unit", feedtype.Unit),
 			dbmapper.Param("status", feedtype.Status),
+			dbmapper.Param("protein", feedtype.Protein),
+			dbmapper.Param("fat", feedtype.Fat),
+			dbmapper.Param("pellet_size", feedtype.PelletSize),
+			dbmapper.Param("stage", feedtype.Stage),
+			dbmapper.Param("reorder_point", feedtype.ReorderPoint),
+			dbmapper.Param("reorder_qty", feedtype.ReorderQty),
 			dbmapper.Param("deleted", feedtype.Deleted),
 			dbmapper.Param("id", feedtype.ID),
 		)
@@ -244,6 +404,13 @@ func feedtypeMapper(row *FeedType) *dbmapper.MappedColumns {
 		dbmapper.Column("name").As(&row.Name),
 		dbmapper.Column("unit").As(&row.Unit),
 		dbmapper.Column("status").As(&row.Status),
+		dbmapper.Column("average_cost").As(&row.AverageCost),
+		dbmapper.Column("protein").As(&row.Protein),
+		dbmapper.Column("fat").As(&row.Fat),
+		dbmapper.Column("pellet_size").As(&row.PelletSize),
+		dbmapper.Column("stage").As(&row.Stage),
+		dbmapper.Column("reorder_point").As(&row.ReorderPoint),
+		dbmapper.Column("reorder_qty").As(&row.ReorderQty),
 		dbmapper.Column("deleted").As(&row.Deleted),
 		dbmapper.Column("created").As(&row.Created),
 		dbmapper.Column("updated").As(&row.Updated),
@@ -260,135 +427,118 @@ func feedtypesMapper(rows *[]FeedType) dbmapper.RowMapper {
 	}
 }
 
-//feed incoming
-func (repo *FeedRepository) ResolveFeedIncomingPage(page int32, limit int32) (*[]FeedIncoming, int32, int32, int32, error) {
-	var start int32
-	var end int32
-
-	start = page * limit
-	end = limit
-
-	//get data by given page
-	var query dbmapper.QueryMapper
-	query = dbmapper.Prepare(selectFeedIncoming+" ORDER BY created ASC LIMIT :start, :end").With(
-		dbmapper.Param("start", start),
-		dbmapper.Param("end", end),
+//feed type unit
+func (repo *FeedRepository) ResolveFeedTypeUnitByFeedTypeID(feedTypeId uuid.UUID) (*[]FeedTypeUnit, error) {
+	query := dbmapper.Prepare(selectFeedTypeUnit + " WHERE feed_type_id = :feedtype ORDER BY unit ASC").With(
+		dbmapper.Param("feedtype", feedTypeId),
 	)
-
 	if err := query.Error(); err != nil {
-		return nil, page, limit, 0, err
-	}
-
-	feedIncomings := make([]FeedIncoming, 0)
-	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(feedIncomingsMapper(&feedIncomings))
-
-	if err != nil {
-		return nil, page, limit, 0, err
-	}
-
-	var newFeedIncomings []FeedIncoming
-	for _, feedIncoming := range feedIncomings {
-		if feedType, err := repo.ResolveFeedTypeByID(feedIncoming.FeedTypeID); err != nil {
-			return nil, page, limit, 0, err
-		} else {
-			feedIncoming.FeedType = *feedType
-			newFeedIncomings = append(newFeedIncomings, feedIncoming)
-		}
-	}
-
-	//get total feed
-	var summary dbmapper.QueryMapper
-	summary = dbmapper.Prepare("SELECT COUNT(*) AS total FROM feed_incoming")
-
-	if err := summary.Error(); err != nil {
-		return nil, page, limit, 0, err
+		return nil, err
 	}
+	units := make([]FeedTypeUnit, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(feedTypeUnitsMapper(&units))
 
-	var feedIncomingsCount int32
-	total := make([]int32, 0)
-	err = Parse(repo.DB.Query(summary.SQL())).Map(dbmapper.Int32("total", &total))
 	if err != nil {
-		//log.Print(err.Error())
-		return nil, page, limit, 0, err
-	} else {
-		feedIncomingsCount = total[0]
+		return nil, err
 	}
-	return &newFeedIncomings, page, limit, feedIncomingsCount, nil
-
+	return &units, nil
 }
 
-func (repo *FeedRepository) ResolveFeedIncomingByID(id uuid.UUID) (*FeedIncoming, error) {
-	query := dbmapper.Prepare(selectFeedIncoming + " WHERE id = :id").With(
+func (repo *FeedRepository) ResolveFeedTypeUnitByID(id uuid.UUID) (*FeedTypeUnit, error) {
+	query := dbmapper.Prepare(selectFeedTypeUnit + " WHERE id = :id").With(
 		dbmapper.Param("id", id),
 	)
 	if err := query.Error(); err != nil {
 		return nil, err
 	}
-	feedIncomings := make([]FeedIncoming, 0)
-	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(feedIncomingsMapper(&feedIncomings))
+	units := make([]FeedTypeUnit, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(feedTypeUnitsMapper(&units))
 
 	if err != nil {
 		return nil, err
 	}
-	if len(feedIncomings) < 1 {
-		return nil, fmt.Errorf("feed incoming with id %s not found", id)
+	if len(units) < 1 {
+		return nil, fmt.Errorf("feed type unit with id %s not found", id)
 	}
+	return &units[0], nil
+}
 
-	if feedtype, err := repo.ResolveFeedTypeByID(feedIncomings[0].FeedTypeID); err != nil {
+func (repo *FeedRepository) InsertFeedTypeUnit(unit *FeedTypeUnit) (*FeedTypeUnit, error) {
+	//prepare query and params
+	insert := dbmapper.Prepare(insertFeedTypeUnit).With(
+		dbmapper.Param("id", unit.ID),
+		dbmapper.Param("feedtype", unit.FeedTypeID),
+		dbmapper.Param("unit", unit.Unit),
+		dbmapper.Param("factor", unit.Factor),
+	)
+	//validate query
+	if err := insert.Error(); err != nil {
+		return nil, err
+	} else if _, err := repo.DB.Exec(insert.SQL(), insert.Params()...); err != nil {
 		return nil, err
 	} else {
-		feedIncomings[0].FeedType = *feedtype
+		res, err := repo.ResolveFeedTypeUnitByID(unit.ID)
+		return res, err
 	}
-
-	return &feedIncomings[0], nil
 }
 
-func (repo *FeedRepository) InsertFeedIncoming(feedIncoming *FeedIncoming) (*FeedIncoming, error) {
-
+func (repo *FeedRepository) UpdateFeedTypeUnitByID(unit *FeedTypeUnit) (*FeedTypeUnit, error) {
 	//prepare query and params
-	insert := dbmapper.Prepare(insertFeedIncoming).With(
-		dbmapper.Param("id", feedIncoming.ID),
-		dbmapper.Param("feedtype", feedIncoming.FeedType.ID),
-		dbmapper.Param("qty", feedIncoming.Qty),
-		dbmapper.Param("remarks", feedIncoming.Remarks),
+	updater := dbmapper.Prepare(updateFeedTypeUnit).With(
+		dbmapper.Param("unit", unit.Unit),
+		dbmapper.Param("factor", unit.Factor),
+		dbmapper.Param("id", unit.ID),
 	)
 	//validate query
-	if err := insert.Error(); err != nil {
+	if err := updater.Error(); err != nil {
+		return nil, err
+	} else if _, err := repo.DB.Exec(updater.SQL(), updater.Params()...); err != nil {
 		return nil, err
 	} else {
-		//insert to database
-		if _, err := repo.DB.Exec(insert.SQL(), insert.Params()...); err != nil {
-			return nil, err
-		} else {
-			//find inserted data from database based on generated id
-			res, err := repo.ResolveFeedIncomingByID(feedIncoming.ID)
-			return res, err
-		}
+		res, err := repo.ResolveFeedTypeUnitByID(unit.ID)
+		return res, err
 	}
 }
 
-func feedIncomingMapper(row *FeedIncoming) *dbmapper.MappedColumns {
+func (repo *FeedRepository) RemoveFeedTypeUnitByID(id uuid.UUID) (*FeedTypeUnit, error) {
+	unit, err := repo.ResolveFeedTypeUnitByID(id)
+	if err != nil {
+		return nil, err
+	}
+	remover := dbmapper.Prepare(deleteFeedTypeUnit).With(
+		dbmapper.Param("id", id),
+	)
+	if err := remover.Error(); err != nil {
+		return nil, err
+	} else if _, err := repo.DB.Exec(remover.SQL(), remover.Params()...); err != nil {
+		return nil, err
+	}
+	return unit, nil
+}
+
+func feedTypeUnitMapper(row *FeedTypeUnit) *dbmapper.MappedColumns {
 	return dbmapper.Columns(
 		dbmapper.Column("id").As(&row.ID),
 		dbmapper.Column("feed_type_id").As(&row.FeedTypeID),
-		dbmapper.Column("qty").As(&row.Qty),
-		dbmapper.Column("remarks").As(&row.Remarks),
+		dbmapper.Column("unit").As(&row.Unit),
+		dbmapper.Column("factor").As(&row.Factor),
 		dbmapper.Column("created").As(&row.Created),
+		dbmapper.Column("updated").As(&row.Updated),
 	)
 }
 
-func feedIncomingsMapper(rows *[]FeedIncoming) dbmapper.RowMapper {
+func feedTypeUnitsMapper(rows *[]FeedTypeUnit) dbmapper.RowMapper {
 	return func() *dbmapper.MappedColumns {
-		row := FeedIncoming{}
-		return feedIncomingMapper(&row).Then(func() error {
+		row := FeedTypeUnit{}
+		return feedTypeUnitMapper(&row).Then(func() error {
 			*rows = append(*rows, row)
 			return nil
 		})
 	}
 }
 
-//feed adjustment
-func (repo *FeedRepository) ResolveFeedAdjustmentPage(page int32, limit int32) (*[]FeedAdjustment, int32, int32, int32, error) {
+//supplier
+func (repo *FeedRepository) ResolveSupplierPage(page int32, limit int32, deleted string) (*[]Supplier, int32, int32, int32, error) {
 	var start int32
 	var end int32
 
@@ -397,86 +547,114 @@ func (repo *FeedRepository) ResolveFeedAdjustmentPage(page int32, limit int32) (
 
 	//get data by given page
 	var query dbmapper.QueryMapper
-	query = dbmapper.Prepare(selectFeedAdjustment+" ORDER BY created ASC LIMIT :start, :end").With(
-		dbmapper.Param("start", start),
-		dbmapper.Param("end", end),
-	)
-
+	if deleted == Deleted_Any {
+		query = dbmapper.Prepare(selectSupplier+" ORDER BY name ASC LIMIT :end OFFSET :start").With(
+			dbmapper.Param("start", start),
+			dbmapper.Param("end", end),
+		)
+	} else if deleted == Deleted_True {
+		query = dbmapper.Prepare(selectSupplier+" WHERE deleted = 1 ORDER BY name ASC LIMIT :end OFFSET :start").With(
+			dbmapper.Param("start", start),
+			dbmapper.Param("end", end),
+		)
+	} else if deleted == Deleted_False {
+		query = dbmapper.Prepare(selectSupplier+" WHERE deleted = 0 ORDER BY name ASC LIMIT :end OFFSET :start").With(
+			dbmapper.Param("start", start),
+			dbmapper.Param("end", end),
+		)
+	}
 	if err := query.Error(); err != nil {
 		return nil, page, limit, 0, err
 	}
 
-	feedAdjustments := make([]FeedAdjustment, 0)
-	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(feedAdjustmentsMapper(&feedAdjustments))
+	suppliers := make([]Supplier, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(suppliersMapper(&suppliers))
 
 	if err != nil {
 		return nil, page, limit, 0, err
 	}
 
-	var newFeedAdjustments []FeedAdjustment
-	for _, feedAdjustment := range feedAdjustments {
-		if feedType, err := repo.ResolveFeedTypeByID(feedAdjustment.FeedTypeID); err != nil {
-			return nil, page, limit, 0, err
-		} else {
-			feedAdjustment.FeedType = *feedType
-			newFeedAdjustments = append(newFeedAdjustments, feedAdjustment)
-		}
-	}
-
-	//get total feed
+	//get total supplier
 	var summary dbmapper.QueryMapper
-	summary = dbmapper.Prepare("SELECT COUNT(*) AS total FROM feed_adjustment")
+	if deleted == Deleted_Any {
+		summary = dbmapper.Prepare("SELECT COUNT(*) AS total FROM feed_supplier")
+	} else if deleted == Deleted_True {
+		summary = dbmapper.Prepare("SELECT COUNT(*) AS total FROM feed_supplier WHERE deleted = 1")
+	} else if deleted == Deleted_False {
+		summary = dbmapper.Prepare("SELECT COUNT(*) AS total FROM feed_supplier WHERE deleted = 0")
+	}
 
 	if err := summary.Error(); err != nil {
 		return nil, page, limit, 0, err
 	}
 
-	var feedsCount int32
+	var suppliersCount int32
 	total := make([]int32, 0)
 	err = Parse(repo.DB.Query(summary.SQL())).Map(dbmapper.Int32("total", &total))
 	if err != nil {
-		//log.Print(err.Error())
 		return nil, page, limit, 0, err
 	} else {
-		feedsCount = total[0]
+		suppliersCount = total[0]
 	}
-	return &newFeedAdjustments, page, limit, feedsCount, nil
+	return &suppliers, page, limit, suppliersCount, nil
 
 }
 
-func (repo *FeedRepository) ResolveFeedAdjustmentByID(id uuid.UUID) (*FeedAdjustment, error) {
-	query := dbmapper.Prepare(selectFeedAdjustment + " WHERE id = :id").With(
-		dbmapper.Param("id", id),
+func (repo *FeedRepository) ResolveSupplierByIDs(ids []uuid.UUID) (*[]Supplier, error) {
+	suppliers := make([]Supplier, 0)
+	if len(ids) < 1 {
+		return &suppliers, nil
+	}
+	var newIDs []interface{}
+	for _, id := range ids {
+		newIDs = append(newIDs, id.String())
+	}
+	query := dbmapper.Prepare(selectSupplier + " WHERE id IN (:ids)").With(
+		dbmapper.Param("ids", newIDs...),
 	)
 	if err := query.Error(); err != nil {
 		return nil, err
 	}
-	feedAdjustments := make([]FeedAdjustment, 0)
-	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(feedAdjustmentsMapper(&feedAdjustments))
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(suppliersMapper(&suppliers))
 
 	if err != nil {
 		return nil, err
+	} else {
+		return &suppliers, nil
 	}
-	if len(feedAdjustments) < 1 {
-		return nil, fmt.Errorf("feed adjustment with id %s not found", id)
-	}
-	if feedtype, err := repo.ResolveFeedTypeByID(feedAdjustments[0].FeedTypeID); err != nil {
+}
+
+func (repo *FeedRepository) ResolveSupplierByID(id uuid.UUID) (*Supplier, error) {
+	query := dbmapper.Prepare(selectSupplier + " WHERE id = :id").With(
+		dbmapper.Param("id", id),
+	)
+	if err := query.Error(); err != nil {
 		return nil, err
-	} else {
-		feedAdjustments[0].FeedType = *feedtype
 	}
+	suppliers := make([]Supplier, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(suppliersMapper(&suppliers))
 
-	return &feedAdjustments[0], nil
+	if err != nil {
+		return nil, err
+	}
+	if len(suppliers) < 1 {
+		return nil, fmt.Errorf("feed supplier with id %s not found", id)
+	}
+	return &suppliers[0], nil
 }
 
-func (repo *FeedRepository) InsertFeedAdjustment(feedAdjustment *FeedAdjustment) (*FeedAdjustment, error) {
+func (repo *FeedRepository) InsertSupplier(supplier *Supplier) (*Supplier, error) {
 
+	//insert
 	//prepare query and params
-	insert := dbmapper.Prepare(insertFeedAdjustment).With(
-		dbmapper.Param("id", feedAdjustment.ID),
-		dbmapper.Param("feedtype", feedAdjustment.FeedType.ID),
-		dbmapper.Param("qty", feedAdjustment.Qty),
-		dbmapper.Param("remarks", feedAdjustment.Remarks),
+	insert := dbmapper.Prepare(insertSupplier).With(
+		dbmapper.Param("id", supplier.ID),
+		dbmapper.Param("name", supplier.Name),
+		dbmapper.Param("contact", supplier.Contact),
+		dbmapper.Param("phone", supplier.Phone),
+		dbmapper.Param("address", supplier.Address),
+		dbmapper.Param("status", supplier.Status),
+		dbmapper.Param("deleted", supplier.Deleted),
 	)
 	//validate query
 	if err := insert.Error(); err != nil {
@@ -487,28 +665,1349 @@ func (repo *FeedRepository) InsertFeedAdjustment(feedAdjustment *FeedAdjustment)
 			return nil, err
 		} else {
 			//find inserted data from database based on generated id
-			res, err := repo.ResolveFeedAdjustmentByID(feedAdjustment.ID)
+			res, err := repo.ResolveSupplierByID(supplier.ID)
 			return res, err
 		}
 	}
 }
 
-func feedAdjustmentMapper(row *FeedAdjustment) *dbmapper.MappedColumns {
-	return dbmapper.Columns(
-		dbmapper.Column("id").As(&row.ID),
-		dbmapper.Column("feed_type_id").As(&row.FeedTypeID),
-		dbmapper.Column("qty").As(&row.Qty),
-		dbmapper.Column("remarks").As(&row.Remarks),
-		dbmapper.Column("created").As(&row.Created),
-	)
+func (repo *FeedRepository) UpdateSupplierByID(supplier *Supplier) (*Supplier, error) {
+	//find whether if data exist
+	_, err := repo.ResolveSupplierByID(supplier.ID)
+
+	if err != nil {
+		return nil, err
+	} else {
+		//update
+		updater := dbmapper.Prepare(updateSupplier).With(
+			dbmapper.Param("name", supplier.Name),
+			dbmapper.Param("contact", supplier.Contact),
+			dbmapper.Param("phone", supplier.Phone),
+			dbmapper.Param("address", supplier.Address),
+			dbmapper.Param("status", supplier.Status),
+			dbmapper.Param("deleted", supplier.Deleted),
+			dbmapper.Param("id", supplier.ID),
+		)
+		//validate query
+		if err := updater.Error(); err != nil {
+			return nil, err
+		} else {
+			//update to database
+			if _, err := repo.DB.Exec(updater.SQL(), updater.Params()...); err != nil {
+				return nil, err
+			} else {
+				//find inserted data from database based on generated id
+				res, err := repo.ResolveSupplierByID(supplier.ID)
+				return res, err
+			}
+		}
+	}
 }
 
-func feedAdjustmentsMapper(rows *[]FeedAdjustment) dbmapper.RowMapper {
-	return func() *dbmapper.MappedColumns {
-		row := FeedAdjustment{}
-		return feedAdjustmentMapper(&row).Then(func() error {
-			*rows = append(*rows, row)
+func (repo *FeedRepository) RemoveSupplierByID(id uuid.UUID) (*Supplier, error) {
+	//find whether if data exist
+	if _, err := repo.ResolveSupplierByID(id); err != nil {
+		return nil, err
+	} else {
+		remover := dbmapper.Prepare(deleteSupplier).With(
+			dbmapper.Param("id", id),
+		)
+		//validate query
+		if err := remover.Error(); err != nil {
+			return nil, err
+		} else {
+			//update to database
+			if _, err := repo.DB.Exec(remover.SQL(), remover.Params()...); err != nil {
+				return nil, err
+			} else {
+				return nil, nil
+			}
+		}
+	}
+}
+
+func (repo *FeedRepository) RemoveSupplierByIDs(ids []uuid.UUID) (*[]Supplier, error) {
+	for _, v := range ids {
+		if _, err := repo.RemoveSupplierByID(v); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func supplierMapper(row *Supplier) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("id").As(&row.ID),
+		dbmapper.Column("name").As(&row.Name),
+		dbmapper.Column("contact").As(&row.Contact),
+		dbmapper.Column("phone").As(&row.Phone),
+		dbmapper.Column("address").As(&row.Address),
+		dbmapper.Column("status").As(&row.Status),
+		dbmapper.Column("deleted").As(&row.Deleted),
+		dbmapper.Column("created").As(&row.Created),
+		dbmapper.Column("updated").As(&row.Updated),
+	)
+}
+
+func suppliersMapper(rows *[]Supplier) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := Supplier{}
+		return supplierMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+//supplier report
+func (repo *FeedRepository) ResolveSupplierPurchase(from time.Time, to time.Time) (*[]SupplierPurchase, error) {
+	query := dbmapper.Prepare(selectSupplierPurchase).With(
+		dbmapper.Param("from", from),
+		dbmapper.Param("to", to),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	purchases := make([]SupplierPurchase, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(supplierPurchasesMapper(&purchases))
+
+	if err != nil {
+		return nil, err
+	}
+	return &purchases, nil
+}
+
+func supplierPurchaseMapper(row *SupplierPurchase) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("supplier_id").As(&row.SupplierID),
+		dbmapper.Column("name").As(&row.Name),
+		dbmapper.Column("incoming").As(&row.Incoming),
+		dbmapper.Column("qty").As(&row.Qty),
+		dbmapper.Column("amount").As(&row.Amount),
+	)
+}
+
+func supplierPurchasesMapper(rows *[]SupplierPurchase) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := SupplierPurchase{}
+		return supplierPurchaseMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+func (repo *FeedRepository) ResolveSupplierPerformance(from time.Time, to time.Time) (*[]SupplierPerformance, error) {
+	query := dbmapper.Prepare(selectSupplierPerformance).With(
+		dbmapper.Param("from", from),
+		dbmapper.Param("to", to),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	performances := make([]SupplierPerformance, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(supplierPerformancesMapper(&performances))
+
+	if err != nil {
+		return nil, err
+	}
+	return &performances, nil
+}
+
+func supplierPerformanceMapper(row *SupplierPerformance) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("supplier_id").As(&row.SupplierID),
+		dbmapper.Column("name").As(&row.Name),
+		dbmapper.Column("cycles").As(&row.Cycles),
+		dbmapper.Column("feed_qty").As(&row.FeedQty),
+		dbmapper.Column("fcr").As(&row.FCR),
+	)
+}
+
+func supplierPerformancesMapper(rows *[]SupplierPerformance) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := SupplierPerformance{}
+		return supplierPerformanceMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+//feed incoming
+func (repo *FeedRepository) ResolveFeedIncomingPage(page int32, limit int32) (*[]FeedIncoming, int32, int32, int32, error) {
+	var start int32
+	var end int32
+
+	start = page * limit
+	end = limit
+
+	//get data by given page
+	var query dbmapper.QueryMapper
+	query = dbmapper.Prepare(selectFeedIncoming+" ORDER BY created ASC LIMIT :end OFFSET :start").With(
+		dbmapper.Param("start", start),
+		dbmapper.Param("end", end),
+	)
+
+	if err := query.Error(); err != nil {
+		return nil, page, limit, 0, err
+	}
+
+	feedIncomings := make([]FeedIncoming, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(feedIncomingsMapper(&feedIncomings))
+
+	if err != nil {
+		return nil, page, limit, 0, err
+	}
+
+	var newFeedIncomings []FeedIncoming
+	for _, feedIncoming := range feedIncomings {
+		if feedType, err := repo.ResolveFeedTypeByID(feedIncoming.FeedTypeID); err != nil {
+			return nil, page, limit, 0, err
+		} else {
+			feedIncoming.FeedType = *feedType
+		}
+		if feedIncoming.SupplierID.Valid {
+			if supplier, err := repo.ResolveSupplierByID(feedIncoming.SupplierID.UUID); err != nil {
+				return nil, page, limit, 0, err
+			} else {
+				feedIncoming.Supplier = supplier
+			}
+		}
+		newFeedIncomings = append(newFeedIncomings, feedIncoming)
+	}
+
+	//get total feed
+	var summary dbmapper.QueryMapper
+	summary = dbmapper.Prepare("SELECT COUNT(*) AS total FROM feed_incoming")
+
+	if err := summary.Error(); err != nil {
+		return nil, page, limit, 0, err
+	}
+
+	var feedIncomingsCount int32
+	total := make([]int32, 0)
+	err = Parse(repo.DB.Query(summary.SQL())).Map(dbmapper.Int32("total", &total))
+	if err != nil {
+		//log.Print(err.Error())
+		return nil, page, limit, 0, err
+	} else {
+		feedIncomingsCount = total[0]
+	}
+	return &newFeedIncomings, page, limit, feedIncomingsCount, nil
+
+}
+
+func (repo *FeedRepository) ResolveFeedIncomingByID(id uuid.UUID) (*FeedIncoming, error) {
+	query := dbmapper.Prepare(selectFeedIncoming + " WHERE id = :id").With(
+		dbmapper.Param("id", id),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	feedIncomings := make([]FeedIncoming, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(feedIncomingsMapper(&feedIncomings))
+
+	if err != nil {
+		return nil, err
+	}
+	if len(feedIncomings) < 1 {
+		return nil, fmt.Errorf("feed incoming with id %s not found", id)
+	}
+
+	if feedtype, err := repo.ResolveFeedTypeByID(feedIncomings[0].FeedTypeID); err != nil {
+		return nil, err
+	} else {
+		feedIncomings[0].FeedType = *feedtype
+	}
+	if feedIncomings[0].SupplierID.Valid {
+		if supplier, err := repo.ResolveSupplierByID(feedIncomings[0].SupplierID.UUID); err != nil {
+			return nil, err
+		} else {
+			feedIncomings[0].Supplier = supplier
+		}
+	}
+
+	return &feedIncomings[0], nil
+}
+
+//InsertFeedIncoming stores an incoming with the lot it opens or, for returned feed,
+//the usages taking it off the lots
+func (repo *FeedRepository) InsertFeedIncoming(feedIncoming *FeedIncoming, lot *FeedLot) (*FeedIncoming, error) {
+	if tx, err := repo.DB.Begin(); err != nil {
+		return nil, err
+	} else {
+		if feedIncoming.Qty < 0 {
+			if _, err := repo.ConsumeFeedLotTransaction(tx, feedIncoming.FeedType.ID, Lot_Usage_Incoming, feedIncoming.ID, 0-feedIncoming.Qty); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+		if _, err := repo.InsertFeedIncomingTransaction(tx, feedIncoming); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if lot != nil {
+			if _, err := repo.InsertFeedLotTransaction(tx, lot); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+		if _, err := repo.RecalculateFeedTypeCostTransaction(tx, feedIncoming.FeedType.ID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		//find inserted data from database based on generated id
+		res, err := repo.ResolveFeedIncomingByID(feedIncoming.ID)
+		return res, err
+	}
+}
+
+func (repo *FeedRepository) InsertFeedIncomingTransaction(tx *sql.Tx, feedIncoming *FeedIncoming) (*FeedIncoming, error) {
+	var supplierID interface{}
+	if feedIncoming.Supplier != nil {
+		supplierID = feedIncoming.Supplier.ID
+	}
+
+	//prepare query and params
+	insert := dbmapper.Prepare(insertFeedIncoming).With(
+		dbmapper.Param("id", feedIncoming.ID),
+		dbmapper.Param("feedtype", feedIncoming.FeedType.ID),
+		dbmapper.Param("supplier", supplierID),
+		dbmapper.Param("incoming_date", feedIncoming.IncomingDate),
+		dbmapper.Param("qty", feedIncoming.Qty),
+		dbmapper.Param("price", feedIncoming.Price),
+		dbmapper.Param("invoice", feedIncoming.Invoice),
+		dbmapper.Param("lot_number", feedIncoming.LotNumber),
+		dbmapper.Param("manufactured", feedIncoming.Manufactured),
+		dbmapper.Param("expired", feedIncoming.Expired),
+		dbmapper.Param("remarks", feedIncoming.Remarks),
+	)
+	//validate query
+	if err := insert.Error(); err != nil {
+		return nil, err
+	} else if _, err := tx.Exec(insert.SQL(), insert.Params()...); err != nil {
+		return nil, err
+	} else {
+		return feedIncoming, nil
+	}
+}
+
+func feedIncomingMapper(row *FeedIncoming) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("id").As(&row.ID),
+		dbmapper.Column("feed_type_id").As(&row.FeedTypeID),
+		dbmapper.Column("feed_supplier_id").As(&row.SupplierID),
+		dbmapper.Column("incoming_date").As(&row.IncomingDate),
+		dbmapper.Column("qty").As(&row.Qty),
+		dbmapper.Column("price").As(&row.Price),
+		dbmapper.Column("invoice").As(&row.Invoice),
+		dbmapper.Column("lot_number").As(&row.LotNumber),
+		dbmapper.Column("manufactured").As(&row.Manufactured),
+		dbmapper.Column("expired").As(&row.Expired),
+		dbmapper.Column("remarks").As(&row.Remarks),
+		dbmapper.Column("created").As(&row.Created),
+	)
+}
+
+func feedIncomingsMapper(rows *[]FeedIncoming) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := FeedIncoming{}
+		return feedIncomingMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+func (repo *FeedRepository) ResolveFeedIncomingByFeedTypeID(feedTypeId uuid.UUID) (*[]FeedIncoming, error) {
+	query := dbmapper.Prepare(selectFeedIncomingByFeedType).With(
+		dbmapper.Param("feedTypeId", feedTypeId),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	feedIncomings := make([]FeedIncoming, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(feedIncomingsMapper(&feedIncomings))
+
+	if err != nil {
+		return nil, err
+	}
+	return &feedIncomings, nil
+}
+
+//feed movement
+func (repo *FeedRepository) ResolveFeedMovementByFeedTypeID(feedTypeId uuid.UUID) (*[]FeedMovement, error) {
+	query := dbmapper.Prepare(selectFeedMovement).With(
+		dbmapper.Param("feedingFeedTypeId", feedTypeId),
+		dbmapper.Param("adjustmentFeedTypeId", feedTypeId),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	movements := make([]FeedMovement, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(feedMovementsMapper(&movements))
+
+	if err != nil {
+		return nil, err
+	}
+	return &movements, nil
+}
+
+func feedMovementMapper(row *FeedMovement) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("movement_date").As(&row.MovementDate),
+		dbmapper.Column("qty").As(&row.Qty),
+	)
+}
+
+func feedMovementsMapper(rows *[]FeedMovement) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := FeedMovement{}
+		return feedMovementMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+//feed type cost
+func (repo *FeedRepository) ResolveFeedTypeCostByFeedTypeIDs(ids []uuid.UUID) (*[]FeedTypeCost, error) {
+	costs := make([]FeedTypeCost, 0)
+	if len(ids) < 1 {
+		return &costs, nil
+	}
+	var newIDs []interface{}
+	for _, id := range ids {
+		newIDs = append(newIDs, id.String())
+	}
+	query := dbmapper.Prepare(selectFeedTypeCost + " WHERE feed_type_id IN (:ids) ORDER BY feed_type_id ASC, cost_date ASC, created ASC").With(
+		dbmapper.Param("ids", newIDs...),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(feedTypeCostsMapper(&costs))
+
+	if err != nil {
+		return nil, err
+	}
+	return &costs, nil
+}
+
+func (repo *FeedRepository) RecalculateFeedTypeCost(feedTypeId uuid.UUID) (*[]FeedTypeCost, error) {
+	if tx, err := repo.DB.Begin(); err != nil {
+		return nil, err
+	} else if costs, err := repo.RecalculateFeedTypeCostTransaction(tx, feedTypeId); err != nil {
+		tx.Rollback()
+		return nil, err
+	} else if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return nil, err
+	} else {
+		return costs, nil
+	}
+}
+
+//RecalculateFeedTypeCostTransaction replays the incomings and stock movements of a
+//feed type read inside tx and replaces its cost history, so a backdated row moves
+//every later average cost along with it
+func (repo *FeedRepository) RecalculateFeedTypeCostTransaction(tx *sql.Tx, feedTypeId uuid.UUID) (*[]FeedTypeCost, error) {
+	incomingQuery := dbmapper.Prepare(selectFeedIncomingByFeedType).With(
+		dbmapper.Param("feedTypeId", feedTypeId),
+	)
+	movementQuery := dbmapper.Prepare(selectFeedMovement).With(
+		dbmapper.Param("feedingFeedTypeId", feedTypeId),
+		dbmapper.Param("adjustmentFeedTypeId", feedTypeId),
+	)
+	if err := incomingQuery.Error(); err != nil {
+		return nil, err
+	} else if err := movementQuery.Error(); err != nil {
+		return nil, err
+	}
+	incomings := make([]FeedIncoming, 0)
+	movements := make([]FeedMovement, 0)
+	if err := Parse(tx.Query(incomingQuery.SQL(), incomingQuery.Params()...)).Map(feedIncomingsMapper(&incomings)); err != nil {
+		return nil, err
+	} else if err := Parse(tx.Query(movementQuery.SQL(), movementQuery.Params()...)).Map(feedMovementsMapper(&movements)); err != nil {
+		return nil, err
+	}
+	costs, averageCost := replayFeedTypeCost(feedTypeId, incomings, movements)
+
+	remover := dbmapper.Prepare(deleteFeedTypeCost).With(
+		dbmapper.Param("feedtype", feedTypeId),
+	)
+	if err := remover.Error(); err != nil {
+		return nil, err
+	}
+	updater := dbmapper.Prepare(updateFeedTypeAverageCost).With(
+		dbmapper.Param("average_cost", averageCost),
+		dbmapper.Param("id", feedTypeId),
+	)
+	if err := updater.Error(); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(remover.SQL(), remover.Params()...); err != nil {
+		return nil, err
+	}
+	for _, cost := range costs {
+		insert := dbmapper.Prepare(insertFeedTypeCost).With(
+			dbmapper.Param("id", cost.ID),
+			dbmapper.Param("feedtype", cost.FeedTypeID),
+			dbmapper.Param("incoming", cost.FeedIncomingID),
+			dbmapper.Param("cost_date", cost.CostDate),
+			dbmapper.Param("stock", cost.Stock),
+			dbmapper.Param("qty", cost.Qty),
+			dbmapper.Param("price", cost.Price),
+			dbmapper.Param("average_cost", cost.AverageCost),
+		)
+		if err := insert.Error(); err != nil {
+			return nil, err
+		} else if _, err := tx.Exec(insert.SQL(), insert.Params()...); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := tx.Exec(updater.SQL(), updater.Params()...); err != nil {
+		return nil, err
+	}
+	return &costs, nil
+}
+
+func feedTypeCostMapper(row *FeedTypeCost) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("id").As(&row.ID),
+		dbmapper.Column("feed_type_id").As(&row.FeedTypeID),
+		dbmapper.Column("feed_incoming_id").As(&row.FeedIncomingID),
+		dbmapper.Column("cost_date").As(&row.CostDate),
+		dbmapper.Column("stock").As(&row.Stock),
+		dbmapper.Column("qty").As(&row.Qty),
+		dbmapper.Column("price").As(&row.Price),
+		dbmapper.Column("average_cost").As(&row.AverageCost),
+		dbmapper.Column("created").As(&row.Created),
+	)
+}
+
+func feedTypeCostsMapper(rows *[]FeedTypeCost) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := FeedTypeCost{}
+		return feedTypeCostMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+//feed lot
+func (repo *FeedRepository) ResolveFeedLotByID(id uuid.UUID) (*FeedLot, error) {
+	query := dbmapper.Prepare(selectFeedLot + " WHERE id = :id").With(
+		dbmapper.Param("id", id),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	lots := make([]FeedLot, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(feedLotsMapper(&lots))
+
+	if err != nil {
+		return nil, err
+	}
+	if len(lots) < 1 {
+		return nil, fmt.Errorf("feed lot with id %s not found", id)
+	}
+	return &lots[0], nil
+}
+
+func (repo *FeedRepository) resolveAvailableFeedLotByFeedTypeIDTransaction(tx *sql.Tx, feedTypeId uuid.UUID) (*[]FeedLot, error) {
+	query := dbmapper.Prepare(selectFeedLot + " WHERE feed_type_id = :feedTypeId AND remaining > 0" + orderFeedLotFEFO).With(
+		dbmapper.Param("feedTypeId", feedTypeId),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	lots := make([]FeedLot, 0)
+	err := Parse(tx.Query(query.SQL(), query.Params()...)).Map(feedLotsMapper(&lots))
+
+	if err != nil {
+		return nil, err
+	}
+	return &lots, nil
+}
+
+func (repo *FeedRepository) ResolveAvailableFeedLot(expiredBefore null.Time) (*[]FeedLot, error) {
+	var query dbmapper.QueryMapper
+	if expiredBefore.Valid {
+		query = dbmapper.Prepare(selectFeedLot + " WHERE remaining > 0 AND expired IS NOT NULL AND expired <= :expired" + orderFeedLotFEFO).With(
+			dbmapper.Param("expired", expiredBefore.Time),
+		)
+	} else {
+		query = dbmapper.Prepare(selectFeedLot + " WHERE remaining > 0" + orderFeedLotFEFO)
+	}
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	lots := make([]FeedLot, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(feedLotsMapper(&lots))
+
+	if err != nil {
+		return nil, err
+	}
+
+	//populate feed type of each lot
+	var ids []uuid.UUID
+	for _, lot := range lots {
+		ids = append(ids, lot.FeedTypeID)
+	}
+	if len(ids) < 1 {
+		return &lots, nil
+	}
+	feedtypes, err := repo.ResolveFeedTypeByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	feedtypeByID := make(map[uuid.UUID]FeedType)
+	for _, feedtype := range *feedtypes {
+		feedtypeByID[feedtype.ID] = feedtype
+	}
+	for i := range lots {
+		lots[i].FeedType = feedtypeByID[lots[i].FeedTypeID]
+	}
+	return &lots, nil
+}
+
+func (repo *FeedRepository) InsertFeedLotTransaction(tx *sql.Tx, lot *FeedLot) (*FeedLot, error) {
+	//prepare query and params
+	insert := dbmapper.Prepare(insertFeedLot).With(
+		dbmapper.Param("id", lot.ID),
+		dbmapper.Param("feedtype", lot.FeedTypeID),
+		dbmapper.Param("incoming", lot.FeedIncomingID),
+		dbmapper.Param("lot_number", lot.LotNumber),
+		dbmapper.Param("manufactured", lot.Manufactured),
+		dbmapper.Param("expired", lot.Expired),
+		dbmapper.Param("qty", lot.Qty),
+		dbmapper.Param("remaining", lot.Remaining),
+	)
+	//validate query
+	if err := insert.Error(); err != nil {
+		return nil, err
+	} else if _, err := tx.Exec(insert.SQL(), insert.Params()...); err != nil {
+		return nil, err
+	} else {
+		return lot, nil
+	}
+}
+
+func feedLotMapper(row *FeedLot) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("id").As(&row.ID),
+		dbmapper.Column("feed_type_id").As(&row.FeedTypeID),
+		dbmapper.Column("feed_incoming_id").As(&row.FeedIncomingID),
+		dbmapper.Column("lot_number").As(&row.LotNumber),
+		dbmapper.Column("manufactured").As(&row.Manufactured),
+		dbmapper.Column("expired").As(&row.Expired),
+		dbmapper.Column("qty").As(&row.Qty),
+		dbmapper.Column("remaining").As(&row.Remaining),
+		dbmapper.Column("created").As(&row.Created),
+		dbmapper.Column("updated").As(&row.Updated),
+	)
+}
+
+func feedLotsMapper(rows *[]FeedLot) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := FeedLot{}
+		return feedLotMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+//feed lot usage
+func (repo *FeedRepository) ResolveFeedLotUsageByReferenceIDs(ids []uuid.UUID) (*[]FeedLotUsage, error) {
+	usages := make([]FeedLotUsage, 0)
+	if len(ids) < 1 {
+		return &usages, nil
+	}
+	var newIDs []interface{}
+	for _, id := range ids {
+		newIDs = append(newIDs, id.String())
+	}
+	query := dbmapper.Prepare(selectFeedLotUsage + " WHERE u.reference_id IN (:ids) ORDER BY u.created ASC").With(
+		dbmapper.Param("ids", newIDs...),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(feedLotUsagesMapper(&usages))
+
+	if err != nil {
+		return nil, err
+	}
+	return &usages, nil
+}
+
+//ConsumeFeedLotTransaction takes qty of a feed type off its lots first expiring first
+//out. The feed type is locked before its lots and stock are read, so the usages are
+//planned on what no other transaction can take anymore, and a lot that still comes
+//up short or stock that does not cover qty fails the whole transaction.
+func (repo *FeedRepository) ConsumeFeedLotTransaction(tx *sql.Tx, feedTypeId uuid.UUID, referenceType string, referenceId uuid.UUID, qty float64) (*[]FeedLotUsage, error) {
+	lock := dbmapper.Prepare(lockFeedType).With(
+		dbmapper.Param("id", feedTypeId),
+	)
+	query := dbmapper.Prepare(selectFeedStock).With(
+		dbmapper.Param("ids", feedTypeId.String()),
+	)
+	stocks := make([]FeedStock, 0)
+	var stock float64
+	if err := lock.Error(); err != nil {
+		return nil, err
+	} else if err := query.Error(); err != nil {
+		return nil, err
+	} else if _, err := tx.Exec(lock.SQL(), lock.Params()...); err != nil {
+		return nil, err
+	} else if err := Parse(tx.Query(query.SQL(), query.Params()...)).Map(feedStocksMapper(&stocks)); err != nil {
+		return nil, err
+	}
+	for _, row := range stocks {
+		stock = stock + row.Qty
+	}
+	lots, err := repo.resolveAvailableFeedLotByFeedTypeIDTransaction(tx, feedTypeId)
+	if err != nil {
+		return nil, err
+	}
+	usages, err := planFeedLotUsage(feedTypeId, *lots, stock, referenceType, referenceId, qty)
+	if err != nil {
+		return nil, err
+	}
+	for _, usage := range *usages {
+		updater := dbmapper.Prepare(consumeFeedLot).With(
+			dbmapper.Param("qty", usage.Qty),
+			dbmapper.Param("id", usage.FeedLotID),
+			dbmapper.Param("available", usage.Qty),
+		)
+		insert := dbmapper.Prepare(insertFeedLotUsage).With(
+			dbmapper.Param("id", usage.ID),
+			dbmapper.Param("lot", usage.FeedLotID),
+			dbmapper.Param("reference_type", usage.ReferenceType),
+			dbmapper.Param("reference_id", usage.ReferenceID),
+			dbmapper.Param("qty", usage.Qty),
+		)
+		if err := updater.Error(); err != nil {
+			return nil, err
+		} else if err := insert.Error(); err != nil {
+			return nil, err
+		} else if res, err := tx.Exec(updater.SQL(), updater.Params()...); err != nil {
+			return nil, err
+		} else if affected, err := res.RowsAffected(); err != nil {
+			return nil, err
+		} else if affected < 1 {
+			return nil, fmt.Errorf("feed lot %s %s does not have enough remaining qty", usage.LotNumber, usage.FeedLotID)
+		} else if _, err := tx.Exec(insert.SQL(), insert.Params()...); err != nil {
+			return nil, err
+		}
+	}
+	return usages, nil
+}
+
+func feedLotUsageMapper(row *FeedLotUsage) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("id").As(&row.ID),
+		dbmapper.Column("feed_lot_id").As(&row.FeedLotID),
+		dbmapper.Column("lot_number").As(&row.LotNumber),
+		dbmapper.Column("expired").As(&row.Expired),
+		dbmapper.Column("reference_type").As(&row.ReferenceType),
+		dbmapper.Column("reference_id").As(&row.ReferenceID),
+		dbmapper.Column("qty").As(&row.Qty),
+		dbmapper.Column("created").As(&row.Created),
+	)
+}
+
+func feedLotUsagesMapper(rows *[]FeedLotUsage) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := FeedLotUsage{}
+		return feedLotUsageMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+//feed adjustment
+func (repo *FeedRepository) ResolveFeedAdjustmentPage(page int32, limit int32) (*[]FeedAdjustment, int32, int32, int32, error) {
+	var start int32
+	var end int32
+
+	start = page * limit
+	end = limit
+
+	//get data by given page
+	var query dbmapper.QueryMapper
+	query = dbmapper.Prepare(selectFeedAdjustment+" ORDER BY created ASC LIMIT :end OFFSET :start").With(
+		dbmapper.Param("start", start),
+		dbmapper.Param("end", end),
+	)
+
+	if err := query.Error(); err != nil {
+		return nil, page, limit, 0, err
+	}
+
+	feedAdjustments := make([]FeedAdjustment, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(feedAdjustmentsMapper(&feedAdjustments))
+
+	if err != nil {
+		return nil, page, limit, 0, err
+	}
+
+	var newFeedAdjustments []FeedAdjustment
+	for _, feedAdjustment := range feedAdjustments {
+		if feedType, err := repo.ResolveFeedTypeByID(feedAdjustment.FeedTypeID); err != nil {
+			return nil, page, limit, 0, err
+		} else {
+			feedAdjustment.FeedType = *feedType
+			newFeedAdjustments = append(newFeedAdjustments, feedAdjustment)
+		}
+	}
+
+	//get total feed
+	var summary dbmapper.QueryMapper
+	summary = dbmapper.Prepare("SELECT COUNT(*) AS total FROM feed_adjustment")
+
+	if err := summary.Error(); err != nil {
+		return nil, page, limit, 0, err
+	}
+
+	var feedsCount int32
+	total := make([]int32, 0)
+	err = Parse(repo.DB.Query(summary.SQL())).Map(dbmapper.Int32("total", &total))
+	if err != nil {
+		//log.Print(err.Error())
+		return nil, page, limit, 0, err
+	} else {
+		feedsCount = total[0]
+	}
+	return &newFeedAdjustments, page, limit, feedsCount, nil
+
+}
+
+func (repo *FeedRepository) ResolveFeedAdjustmentByID(id uuid.UUID) (*FeedAdjustment, error) {
+	query := dbmapper.Prepare(selectFeedAdjustment + " WHERE id = :id").With(
+		dbmapper.Param("id", id),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	feedAdjustments := make([]FeedAdjustment, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(feedAdjustmentsMapper(&feedAdjustments))
+
+	if err != nil {
+		return nil, err
+	}
+	if len(feedAdjustments) < 1 {
+		return nil, fmt.Errorf("feed adjustment with id %s not found", id)
+	}
+	if feedtype, err := repo.ResolveFeedTypeByID(feedAdjustments[0].FeedTypeID); err != nil {
+		return nil, err
+	} else {
+		feedAdjustments[0].FeedType = *feedtype
+	}
+
+	return &feedAdjustments[0], nil
+}
+
+//InsertFeedAdjustment stores an adjustment with the usages taking missing feed off the lots
+func (repo *FeedRepository) InsertFeedAdjustment(feedAdjustment *FeedAdjustment) (*FeedAdjustment, error) {
+	if tx, err := repo.DB.Begin(); err != nil {
+		return nil, err
+	} else if err := repo.consumeFeedAdjustmentTransaction(tx, feedAdjustment); err != nil {
+		tx.Rollback()
+		return nil, err
+	} else if _, err := repo.InsertFeedAdjustmentTransaction(tx, feedAdjustment); err != nil {
+		tx.Rollback()
+		return nil, err
+	} else if _, err := repo.RecalculateFeedTypeCostTransaction(tx, feedAdjustment.FeedType.ID); err != nil {
+		tx.Rollback()
+		return nil, err
+	} else if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return nil, err
+	} else {
+		//find inserted data from database based on generated id
+		res, err := repo.ResolveFeedAdjustmentByID(feedAdjustment.ID)
+		return res, err
+	}
+}
+
+//consumeFeedAdjustmentTransaction takes missing feed off the lots before its
+//adjustment is stored, the stock it is planned on does not count it yet
+func (repo *FeedRepository) consumeFeedAdjustmentTransaction(tx *sql.Tx, feedAdjustment *FeedAdjustment) error {
+	if feedAdjustment.Qty >= 0 {
+		return nil
+	}
+	_, err := repo.ConsumeFeedLotTransaction(tx, feedAdjustment.FeedType.ID, Lot_Usage_Adjustment, feedAdjustment.ID, 0-feedAdjustment.Qty)
+	return err
+}
+
+func (repo *FeedRepository) InsertFeedAdjustmentTransaction(tx *sql.Tx, feedAdjustment *FeedAdjustment) (*FeedAdjustment, error) {
+	var stocktakeID interface{}
+	if feedAdjustment.StocktakeID != nil {
+		stocktakeID = *feedAdjustment.StocktakeID
+	}
+	//stocktake adjustments are dated by their stocktake
+	if feedAdjustment.Created.IsZero() {
+		feedAdjustment.Created = time.Now()
+	}
+
+	//prepare query and params
+	insert := dbmapper.Prepare(insertFeedAdjustment).With(
+		dbmapper.Param("id", feedAdjustment.ID),
+		dbmapper.Param("feedtype", feedAdjustment.FeedType.ID),
+		dbmapper.Param("qty", feedAdjustment.Qty),
+		dbmapper.Param("reason", feedAdjustment.Reason),
+		dbmapper.Param("stocktake", stocktakeID),
+		dbmapper.Param("remarks", feedAdjustment.Remarks),
+		dbmapper.Param("created", feedAdjustment.Created),
+	)
+	//validate query
+	if err := insert.Error(); err != nil {
+		return nil, err
+	} else if _, err := tx.Exec(insert.SQL(), insert.Params()...); err != nil {
+		return nil, err
+	} else {
+		return feedAdjustment, nil
+	}
+}
+
+func feedAdjustmentMapper(row *FeedAdjustment, stocktakeID *uuid.NullUUID) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("id").As(&row.ID),
+		dbmapper.Column("feed_type_id").As(&row.FeedTypeID),
+		dbmapper.Column("qty").As(&row.Qty),
+		dbmapper.Column("reason").As(&row.Reason),
+		dbmapper.Column("feed_stocktake_id").As(stocktakeID),
+		dbmapper.Column("remarks").As(&row.Remarks),
+		dbmapper.Column("created").As(&row.Created),
+	)
+}
+
+func feedAdjustmentsMapper(rows *[]FeedAdjustment) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := FeedAdjustment{}
+		var stocktakeID uuid.NullUUID
+		return feedAdjustmentMapper(&row, &stocktakeID).Then(func() error {
+			//manual adjustments have no stocktake
+			if stocktakeID.Valid {
+				row.StocktakeID = &stocktakeID.UUID
+			}
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+//feed stock
+func (repo *FeedRepository) ResolveFeedStockByFeedTypeIDs(ids []uuid.UUID) (*[]FeedStock, error) {
+	stocks := make([]FeedStock, 0)
+	if len(ids) < 1 {
+		return &stocks, nil
+	}
+	var newIDs []interface{}
+	for _, id := range ids {
+		newIDs = append(newIDs, id.String())
+	}
+	query := dbmapper.Prepare(selectFeedStock).With(
+		dbmapper.Param("ids", newIDs...),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(feedStocksMapper(&stocks))
+
+	if err != nil {
+		return nil, err
+	}
+	return &stocks, nil
+}
+
+func (repo *FeedRepository) ResolveFeedConsumptionByFeedTypeIDs(ids []uuid.UUID, from time.Time) (*[]FeedStock, error) {
+	consumptions := make([]FeedStock, 0)
+	if len(ids) < 1 {
+		return &consumptions, nil
+	}
+	var newIDs []interface{}
+	for _, id := range ids {
+		newIDs = append(newIDs, id.String())
+	}
+	query := dbmapper.Prepare(selectFeedConsumption).With(
+		dbmapper.Param("ids", newIDs...),
+		dbmapper.Param("from", from),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(feedStocksMapper(&consumptions))
+
+	if err != nil {
+		return nil, err
+	}
+	return &consumptions, nil
+}
+
+func feedStockMapper(row *FeedStock) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("feed_type_id").As(&row.FeedTypeID),
+		dbmapper.Column("qty").As(&row.Qty),
+	)
+}
+
+func feedStocksMapper(rows *[]FeedStock) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := FeedStock{}
+		return feedStockMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+//stocktake
+func (repo *FeedRepository) ResolveStocktakePage(page int32, limit int32) (*[]Stocktake, int32, int32, int32, error) {
+	var start int32
+	var end int32
+
+	start = page * limit
+	end = limit
+
+	//get data by given page
+	var query dbmapper.QueryMapper
+	query = dbmapper.Prepare(selectStocktake+" ORDER BY stocktake_date DESC, created DESC LIMIT :end OFFSET :start").With(
+		dbmapper.Param("start", start),
+		dbmapper.Param("end", end),
+	)
+
+	if err := query.Error(); err != nil {
+		return nil, page, limit, 0, err
+	}
+
+	stocktakes := make([]Stocktake, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(stocktakesMapper(&stocktakes))
+
+	if err != nil {
+		return nil, page, limit, 0, err
+	}
+
+	//get total stocktake
+	var summary dbmapper.QueryMapper
+	summary = dbmapper.Prepare("SELECT COUNT(*) AS total FROM feed_stocktake")
+
+	if err := summary.Error(); err != nil {
+		return nil, page, limit, 0, err
+	}
+
+	var stocktakesCount int32
+	total := make([]int32, 0)
+	err = Parse(repo.DB.Query(summary.SQL())).Map(dbmapper.Int32("total", &total))
+	if err != nil {
+		return nil, page, limit, 0, err
+	} else {
+		stocktakesCount = total[0]
+	}
+	return &stocktakes, page, limit, stocktakesCount, nil
+}
+
+func (repo *FeedRepository) ResolveStocktakeByID(id uuid.UUID) (*Stocktake, error) {
+	query := dbmapper.Prepare(selectStocktake + " WHERE id = :id").With(
+		dbmapper.Param("id", id),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	stocktakes := make([]Stocktake, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(stocktakesMapper(&stocktakes))
+
+	if err != nil {
+		return nil, err
+	}
+	if len(stocktakes) < 1 {
+		return nil, fmt.Errorf("stocktake with id %s not found", id)
+	}
+	if details, err := repo.ResolveStocktakeDetailByStocktakeID(id); err != nil {
+		return nil, err
+	} else {
+		stocktakes[0].Detail = *details
+	}
+	return &stocktakes[0], nil
+}
+
+func (repo *FeedRepository) InsertStocktake(stocktake *Stocktake) (*Stocktake, error) {
+	//prepare query and params
+	insert := dbmapper.Prepare(insertStocktake).With(
+		dbmapper.Param("id", stocktake.ID),
+		dbmapper.Param("stocktake_date", stocktake.StocktakeDate),
+		dbmapper.Param("status", stocktake.Status),
+		dbmapper.Param("remarks", stocktake.Remarks),
+	)
+	//validate query
+	if err := insert.Error(); err != nil {
+		return nil, err
+	} else if _, err := repo.DB.Exec(insert.SQL(), insert.Params()...); err != nil {
+		return nil, err
+	} else {
+		//find inserted data from database based on generated id
+		res, err := repo.ResolveStocktakeByID(stocktake.ID)
+		return res, err
+	}
+}
+
+func (repo *FeedRepository) UpdateStocktakeByID(stocktake *Stocktake) (*Stocktake, error) {
+	//prepare query and params, only an open stocktake can be changed
+	updater := dbmapper.Prepare(updateStocktake).With(
+		dbmapper.Param("stocktake_date", stocktake.StocktakeDate),
+		dbmapper.Param("remarks", stocktake.Remarks),
+		dbmapper.Param("id", stocktake.ID),
+		dbmapper.Param("status", Stocktake_Open),
+	)
+	//validate query
+	if err := updater.Error(); err != nil {
+		return nil, err
+	} else if _, err := repo.DB.Exec(updater.SQL(), updater.Params()...); err != nil {
+		return nil, err
+	} else {
+		res, err := repo.ResolveStocktakeByID(stocktake.ID)
+		return res, err
+	}
+}
+
+func stocktakeMapper(row *Stocktake) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("id").As(&row.ID),
+		dbmapper.Column("stocktake_date").As(&row.StocktakeDate),
+		dbmapper.Column("status").As(&row.Status),
+		dbmapper.Column("remarks").As(&row.Remarks),
+		dbmapper.Column("approved").As(&row.Approved),
+		dbmapper.Column("created").As(&row.Created),
+		dbmapper.Column("updated").As(&row.Updated),
+	)
+}
+
+func stocktakesMapper(rows *[]Stocktake) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := Stocktake{}
+		return stocktakeMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
+			return nil
+		})
+	}
+}
+
+//stocktake detail
+func (repo *FeedRepository) ResolveStocktakeDetailByStocktakeID(stocktakeId uuid.UUID) (*[]StocktakeDetail, error) {
+	query := dbmapper.Prepare(selectStocktakeDetail + " WHERE feed_stocktake_id = :stocktake ORDER BY created ASC").With(
+		dbmapper.Param("stocktake", stocktakeId),
+	)
+	if err := query.Error(); err != nil {
+		return nil, err
+	}
+	details := make([]StocktakeDetail, 0)
+	err := Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(stocktakeDetailsMapper(&details))
+
+	if err != nil {
+		return nil, err
+	}
+
+	//populate feed type of each detail
+	var ids []uuid.UUID
+	for _, detail := range details {
+		ids = append(ids, detail.FeedTypeID)
+	}
+	if len(ids) < 1 {
+		return &details, nil
+	}
+	feedtypes, err := repo.ResolveFeedTypeByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	feedtypeByID := make(map[uuid.UUID]FeedType)
+	for _, feedtype := range *feedtypes {
+		feedtypeByID[feedtype.ID] = feedtype
+	}
+	for i := range details {
+		details[i].FeedType = feedtypeByID[details[i].FeedTypeID]
+	}
+	return &details, nil
+}
+
+func (repo *FeedRepository) ReplaceStocktakeDetailByStocktakeID(stocktakeId uuid.UUID, details *[]StocktakeDetail) (*Stocktake, error) {
+	remove := dbmapper.Prepare(deleteStocktakeDetail).With(
+		dbmapper.Param("stocktake", stocktakeId),
+	)
+	if err := remove.Error(); err != nil {
+		return nil, err
+	}
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(remove.SQL(), remove.Params()...); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	for _, detail := range *details {
+		insert := dbmapper.Prepare(insertStocktakeDetail).With(
+			dbmapper.Param("id", detail.ID),
+			dbmapper.Param("stocktake", stocktakeId),
+			dbmapper.Param("feedtype", detail.FeedType.ID),
+			dbmapper.Param("counted_qty", detail.CountedQty),
+			dbmapper.Param("system_qty", detail.SystemQty),
+			dbmapper.Param("variance", detail.Variance),
+		)
+		if err := insert.Error(); err != nil {
+			tx.Rollback()
+			return nil, err
+		} else if _, err := tx.Exec(insert.SQL(), insert.Params()...); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return repo.ResolveStocktakeByID(stocktakeId)
+}
+
+//ApproveStocktakeByID freezes the variance of every counted feed type and books the
+//adjustments in one transaction, a stocktake that is no longer open fails the approval
+func (repo *FeedRepository) ApproveStocktakeByID(stocktake *Stocktake, adjustments *[]FeedAdjustment) (*Stocktake, error) {
+	approve := dbmapper.Prepare(approveStocktake).With(
+		dbmapper.Param("approved", Stocktake_Approved),
+		dbmapper.Param("id", stocktake.ID),
+		dbmapper.Param("open", Stocktake_Open),
+	)
+	if err := approve.Error(); err != nil {
+		return nil, err
+	}
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	if res, err := tx.Exec(approve.SQL(), approve.Params()...); err != nil {
+		tx.Rollback()
+		return nil, err
+	} else if affected, err := res.RowsAffected(); err != nil {
+		tx.Rollback()
+		return nil, err
+	} else if affected < 1 {
+		tx.Rollback()
+		return nil, fmt.Errorf("stocktake with id %s is not open", stocktake.ID)
+	}
+	for _, detail := range stocktake.Detail {
+		updater := dbmapper.Prepare(updateStocktakeDetail).With(
+			dbmapper.Param("system_qty", detail.SystemQty),
+			dbmapper.Param("variance", detail.Variance),
+			dbmapper.Param("id", detail.ID),
+		)
+		if err := updater.Error(); err != nil {
+			tx.Rollback()
+			return nil, err
+		} else if _, err := tx.Exec(updater.SQL(), updater.Params()...); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	for _, adjustment := range *adjustments {
+		if err := repo.consumeFeedAdjustmentTransaction(tx, &adjustment); err != nil {
+			tx.Rollback()
+			return nil, err
+		} else if _, err := repo.InsertFeedAdjustmentTransaction(tx, &adjustment); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	for _, adjustment := range *adjustments {
+		if _, err := repo.RecalculateFeedTypeCostTransaction(tx, adjustment.FeedType.ID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return repo.ResolveStocktakeByID(stocktake.ID)
+}
+
+func stocktakeDetailMapper(row *StocktakeDetail) *dbmapper.MappedColumns {
+	return dbmapper.Columns(
+		dbmapper.Column("id").As(&row.ID),
+		dbmapper.Column("feed_stocktake_id").As(&row.StocktakeID),
+		dbmapper.Column("feed_type_id").As(&row.FeedTypeID),
+		dbmapper.Column("counted_qty").As(&row.CountedQty),
+		dbmapper.Column("system_qty").As(&row.SystemQty),
+		dbmapper.Column("variance").As(&row.Variance),
+		dbmapper.Column("created").As(&row.Created),
+		dbmapper.Column("updated").As(&row.Updated),
+	)
+}
+
+func stocktakeDetailsMapper(rows *[]StocktakeDetail) dbmapper.RowMapper {
+	return func() *dbmapper.MappedColumns {
+		row := StocktakeDetail{}
+		return stocktakeDetailMapper(&row).Then(func() error {
+			*rows = append(*rows, row)
 			return nil
 		})
 	}
 }
+
+//export
+func (repo *FeedRepository) StreamFeedType(deleted string, fn func(*FeedType) error) error {
+	where := ""
+	if deleted == Deleted_True {
+		where = " WHERE deleted = 1"
+	} else if deleted == Deleted_False {
+		where = " WHERE deleted = 0"
+	}
+	query := dbmapper.Prepare(selectFeedType + where + " ORDER BY name ASC")
+	if err := query.Error(); err != nil {
+		return err
+	}
+	return Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(func() *dbmapper.MappedColumns {
+		row := FeedType{}
+		return feedtypeMapper(&row).Then(func() error {
+			return fn(&row)
+		})
+	})
+}
+
+func (repo *FeedRepository) StreamFeedIncoming(fn func(*FeedIncoming) error) error {
+	query := dbmapper.Prepare(selectFeedIncoming + " ORDER BY created ASC")
+	if err := query.Error(); err != nil {
+		return err
+	}
+	return Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(func() *dbmapper.MappedColumns {
+		row := FeedIncoming{}
+		return feedIncomingMapper(&row).Then(func() error {
+			return fn(&row)
+		})
+	})
+}
+
+func (repo *FeedRepository) StreamFeedAdjustment(fn func(*FeedAdjustment) error) error {
+	query := dbmapper.Prepare(selectFeedAdjustment + " ORDER BY created ASC")
+	if err := query.Error(); err != nil {
+		return err
+	}
+	return Parse(repo.DB.Query(query.SQL(), query.Params()...)).Map(func() *dbmapper.MappedColumns {
+		row := FeedAdjustment{}
+		var stocktakeID uuid.NullUUID
+		return feedAdjustmentMapper(&row, &stocktakeID).Then(func() error {
+			if stocktakeID.Valid {
+				row.StocktakeID = &stocktakeID.UUID
+			}
+			return fn(&row)
+		})
+	})
+}
@@ -0,0 +1,155 @@
+package feed
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/livestockz/api/database/databasetest"
+	uuid "github.com/satori/go.uuid"
+)
+
+func TestFeedTypePage(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *sql.DB) {
+		repo := &FeedRepository{DB: db}
+		for _, name := range []string{"Pellet 3", "Pellet 1", "Pellet 2"} {
+			if _, err := repo.InsertFeedType(&FeedType{ID: uuid.Must(uuid.NewV4()), Name: name, Unit: "kg", Status: 1, Stage: Feed_Stage_Grower, PelletSize: 2}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		page, _, _, total, err := repo.ResolveFeedTypePage(1, 2, Deleted_Any, "", null.Float{})
+		if err != nil {
+			t.Fatal(err)
+		} else if total != 3 || len(*page) != 1 || (*page)[0].Name != "Pellet 3" {
+			t.Fatalf("second page: got %d of %d rows", len(*page), total)
+		}
+	})
+}
+
+func TestFeedStock(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *sql.DB) {
+		repo := &FeedRepository{DB: db}
+		feedtype, err := repo.InsertFeedType(&FeedType{ID: uuid.Must(uuid.NewV4()), Name: "Pellet", Unit: "kg", Status: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		incoming := &FeedIncoming{
+			ID:           uuid.Must(uuid.NewV4()),
+			FeedType:     *feedtype,
+			FeedTypeID:   feedtype.ID,
+			IncomingDate: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			Qty:          100,
+			Price:        12000,
+		}
+		if _, err := repo.InsertFeedIncoming(incoming, nil); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.InsertFeedAdjustment(&FeedAdjustment{ID: uuid.Must(uuid.NewV4()), FeedType: *feedtype, FeedTypeID: feedtype.ID, Qty: -2.5, Reason: Adjustment_Reason_Manual}); err != nil {
+			t.Fatal(err)
+		}
+		stocks, err := repo.ResolveFeedStockByFeedTypeIDs([]uuid.UUID{feedtype.ID})
+		if err != nil {
+			t.Fatal(err)
+		} else if len(*stocks) != 1 || (*stocks)[0].Qty != 97.5 {
+			t.Fatalf("stock read back as %+v", *stocks)
+		}
+	})
+}
+
+func TestFeedAdjustmentShortLot(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *sql.DB) {
+		repo := &FeedRepository{DB: db}
+		feedtype, err := repo.InsertFeedType(&FeedType{ID: uuid.Must(uuid.NewV4()), Name: "Pellet", Unit: "kg", Status: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		//5 kg were received before lots were tracked
+		for qty, lotted := range map[float64]bool{10: true, 5: false} {
+			incoming := &FeedIncoming{ID: uuid.Must(uuid.NewV4()), FeedType: *feedtype, FeedTypeID: feedtype.ID, IncomingDate: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Qty: qty}
+			var lot *FeedLot
+			if lotted {
+				lot = &FeedLot{ID: uuid.Must(uuid.NewV4()), FeedTypeID: feedtype.ID, FeedIncomingID: incoming.ID, Qty: qty, Remaining: qty}
+			}
+			if _, err := repo.InsertFeedIncoming(incoming, lot); err != nil {
+				t.Fatal(err)
+			}
+		}
+		adjustment := &FeedAdjustment{ID: uuid.Must(uuid.NewV4()), FeedType: *feedtype, FeedTypeID: feedtype.ID, Qty: -12, Reason: Adjustment_Reason_Manual}
+		if _, err := repo.InsertFeedAdjustment(adjustment); err != nil {
+			t.Fatal(err)
+		} else if usages, err := repo.ResolveFeedLotUsageByReferenceIDs([]uuid.UUID{adjustment.ID}); err != nil {
+			t.Fatal(err)
+		} else if len(*usages) != 1 || (*usages)[0].Qty != 10 {
+			t.Fatalf("adjustment drew from %+v", *usages)
+		}
+		//3 kg are left, none of them in a lot
+		short := &FeedAdjustment{ID: uuid.Must(uuid.NewV4()), FeedType: *feedtype, FeedTypeID: feedtype.ID, Qty: -4, Reason: Adjustment_Reason_Manual}
+		if _, err := repo.InsertFeedAdjustment(short); err == nil {
+			t.Fatal("took more than the stock has left")
+		}
+		if _, err := repo.ResolveFeedAdjustmentByID(short.ID); err == nil {
+			t.Fatal("kept the adjustment of a short stock")
+		}
+		if stocks, err := repo.ResolveFeedStockByFeedTypeIDs([]uuid.UUID{feedtype.ID}); err != nil {
+			t.Fatal(err)
+		} else if (*stocks)[0].Qty != 3 {
+			t.Fatalf("stock has %v left", (*stocks)[0].Qty)
+		}
+	})
+}
+
+func TestFeedTypeCostBackdated(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *sql.DB) {
+		repo := &FeedRepository{DB: db}
+		feedtype, err := repo.InsertFeedType(&FeedType{ID: uuid.Must(uuid.NewV4()), Name: "Pellet", Unit: "kg", Status: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, incoming := range []FeedIncoming{
+			{IncomingDate: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Qty: 100, Price: 10000},
+			{IncomingDate: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Qty: 100, Price: 4000},
+		} {
+			incoming.ID = uuid.Must(uuid.NewV4())
+			incoming.FeedType = *feedtype
+			if _, err := repo.InsertFeedIncoming(&incoming, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+		costs, err := repo.ResolveFeedTypeCostByFeedTypeIDs([]uuid.UUID{feedtype.ID})
+		if err != nil {
+			t.Fatal(err)
+		} else if len(*costs) != 2 || (*costs)[1].Stock != 100 || (*costs)[1].AverageCost != 7000 {
+			t.Fatalf("cost history read back as %+v", *costs)
+		}
+	})
+}
+
+func TestApproveStocktakeDate(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *sql.DB) {
+		svc := &FeedService{FeedRepository: &FeedRepository{DB: db}}
+		feedtype, err := svc.StoreFeedType(&FeedType{Name: "Pellet", Unit: "kg", Status: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := svc.StoreFeedIncoming(&FeedIncoming{FeedType: *feedtype, IncomingDate: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Qty: 100, Price: 12000}); err != nil {
+			t.Fatal(err)
+		}
+		stocktake, err := svc.StoreStocktake(&Stocktake{StocktakeDate: time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := svc.StoreStocktakeCount(stocktake.ID, []StocktakeDetail{{FeedType: *feedtype, CountedQty: 90}}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := svc.ApproveStocktake(stocktake.ID); err != nil {
+			t.Fatal(err)
+		}
+		//the variance is booked on the day it was counted, not the day it was approved
+		if adjustments, _, _, _, err := svc.ResolveFeedAdjustmentPage(0, 10); err != nil {
+			t.Fatal(err)
+		} else if len(*adjustments) != 1 || (*adjustments)[0].Qty != -10 || (*adjustments)[0].Created.Format("2006-01-02") != "2024-03-31" {
+			t.Fatalf("stocktake booked %+v", *adjustments)
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package importer
+
+const (
+	//importable entities, named like their export files
+	Entity_Batch        string = "batches"
+	Entity_Pool         string = "pools"
+	Entity_FeedType     string = "feed-types"
+	Entity_Cycle        string = "cycles"
+	Entity_Feeding      string = "feedings"
+	Entity_Death        string = "deaths"
+	Entity_FeedIncoming string = "feed-incoming"
+)
+
+//Entities lists every entity accepted by the import
+var Entities = []string{
+	Entity_Batch,
+	Entity_Pool,
+	Entity_FeedType,
+	Entity_Cycle,
+	Entity_Feeding,
+	Entity_Death,
+	Entity_FeedIncoming,
+}
+
+//ImportResult tells what happened to a file, rows are only written when
+//there is no error and it is not a dry run
+type ImportResult struct {
+	Entity   string     `json:"entity"`
+	DryRun   bool       `json:"dry_run"`
+	Rows     int        `json:"rows"`
+	Imported int        `json:"imported"`
+	Errors   []RowError `json:"errors"`
+}
+
+//RowError points at a cell of the file, Row counts the records with the header as row 1
+type RowError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,156 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guregu/null"
+	uuid "github.com/satori/go.uuid"
+)
+
+//dates are read in the layouts the export writes, then date only
+var dateLayouts = []string{"2006-01-02 15:04:05", "2006-01-02", time.RFC3339}
+
+//readCSV reads the whole file, a header using ; as separator means the
+//numbers were written with a decimal comma like the export does for some locales
+func readCSV(file io.Reader) ([][]string, bool, error) {
+	raw, err := ioutil.ReadAll(file)
+	if err != nil {
+		return nil, false, err
+	}
+	text := strings.TrimPrefix(string(raw), "\ufeff")
+	header := strings.SplitN(text, "\n", 2)[0]
+	decimalComma := strings.Count(header, ";") > strings.Count(header, ",")
+
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if decimalComma {
+		reader.Comma = ';'
+	}
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, false, fmt.Errorf("Unable to read csv: %s", err.Error())
+	} else if len(records) < 1 {
+		return nil, false, fmt.Errorf("The file is empty.")
+	}
+	return records, decimalComma, nil
+}
+
+//record reads the cells of one line by column name and collects what is wrong with them
+type record struct {
+	line         int
+	columns      map[string]int
+	cells        []string
+	decimalComma bool
+	errors       []RowError
+}
+
+func (r *record) fail(column string, format string, args ...interface{}) {
+	r.errors = append(r.errors, RowError{Row: r.line, Column: column, Message: fmt.Sprintf(format, args...)})
+}
+
+func (r *record) text(column string) string {
+	if i, ok := r.columns[column]; ok && i < len(r.cells) {
+		return strings.TrimSpace(r.cells[i])
+	}
+	return ""
+}
+
+func (r *record) required(column string) string {
+	value := r.text(column)
+	if value == "" {
+		r.fail(column, "Value is required.")
+	}
+	return value
+}
+
+func (r *record) number(column string) float64 {
+	value := r.text(column)
+	if value == "" {
+		return 0
+	}
+	//drop the thousands separators before reading the decimal one, 1.234,5
+	//with a decimal comma and 1,234.5 without
+	if r.decimalComma {
+		value = strings.Replace(strings.Replace(value, ".", "", -1), ",", ".", 1)
+	} else {
+		value = strings.Replace(value, ",", "", -1)
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		r.fail(column, "%s is not a number.", value)
+	}
+	return number
+}
+
+func (r *record) integer(column string) int32 {
+	value := r.text(column)
+	if value == "" {
+		return 0
+	}
+	number, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		r.fail(column, "%s is not a whole number.", value)
+	}
+	return int32(number)
+}
+
+func (r *record) boolean(column string) bool {
+	switch strings.ToLower(r.text(column)) {
+	case "", "0", "false", "no":
+		return false
+	case "1", "true", "yes":
+		return true
+	}
+	r.fail(column, "%s is not true or false.", r.text(column))
+	return false
+}
+
+//id is uuid.Nil when the cell is empty
+func (r *record) id(column string) uuid.UUID {
+	value := r.text(column)
+	if value == "" {
+		return uuid.Nil
+	}
+	id, err := uuid.FromString(value)
+	if err != nil {
+		r.fail(column, "%s is not a valid id.", value)
+	}
+	return id
+}
+
+func (r *record) requiredID(column string) uuid.UUID {
+	if r.text(column) == "" {
+		r.fail(column, "Value is required.")
+		return uuid.Nil
+	}
+	return r.id(column)
+}
+
+func (r *record) date(column string) null.Time {
+	value := r.text(column)
+	if value == "" {
+		return null.Time{}
+	}
+	for _, layout := range dateLayouts {
+		if date, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return null.TimeFrom(date)
+		}
+	}
+	r.fail(column, "%s is not a date, use yyyy-mm-dd.", value)
+	return null.Time{}
+}
+
+func (r *record) requiredDate(column string) time.Time {
+	if r.text(column) == "" {
+		r.fail(column, "Value is required.")
+		return time.Time{}
+	}
+	return r.date(column).Time
+}
@@ -0,0 +1,32 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRecordNumber(t *testing.T) {
+	for _, c := range []struct {
+		file   string
+		number float64
+	}{
+		{"qty\n1234.5\n", 1234.5},
+		{"qty\n\"1,234.5\"\n", 1234.5},
+		{"qty;unit\n1234,5;kg\n", 1234.5},
+		{"qty;unit\n1.234,5;kg\n", 1234.5},
+		{"qty;unit\n1.234.567,25;kg\n", 1234567.25},
+	} {
+		records, decimalComma, err := readCSV(strings.NewReader(c.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := &record{line: 2, columns: map[string]int{"qty": 0}, cells: records[1], decimalComma: decimalComma}
+		if number := r.number("qty"); number != c.number || len(r.errors) > 0 {
+			t.Fatalf("%q read as %v with %v", c.file, number, r.errors)
+		}
+	}
+	r := &record{line: 2, columns: map[string]int{"qty": 0}, cells: []string{"1,5,0"}, decimalComma: true}
+	if r.number("qty"); len(r.errors) != 1 {
+		t.Fatal("read 1,5,0 as a number")
+	}
+}
@@ -0,0 +1,583 @@
+package importer
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/livestockz/api/domain/batch"
+	"github.com/livestockz/api/domain/feed"
+	uuid "github.com/satori/go.uuid"
+)
+
+type Service interface {
+	Import(entity string, file io.Reader, dryRun bool) (*ImportResult, error)
+}
+
+//ImportService checks a whole file before writing any of it, the rows then go
+//through the repositories in a single transaction
+type ImportService struct {
+	DB              *sql.DB          `inject:"db"`
+	BatchRepository batch.Repository `inject:"batchRepository"`
+	FeedRepository  feed.Repository  `inject:"feedRepository"`
+	FeedService     feed.Service     `inject:"feedService"`
+}
+
+//insert writes one validated row inside the import transaction
+type insert func(tx *sql.Tx) error
+
+//requiredColumns must be in the header, any other column is optional and
+//unknown ones like created are ignored so an export can be imported back
+var requiredColumns = map[string][]string{
+	Entity_Batch:        {"name"},
+	Entity_Pool:         {"name", "status"},
+	Entity_FeedType:     {"name", "unit"},
+	Entity_Cycle:        {"batch_id", "pool_id", "start", "weight", "amount"},
+	Entity_Feeding:      {"batch_id", "batch_cycle_id", "feed_type_id", "feeding_date", "qty"},
+	Entity_Death:        {"batch_id", "batch_cycle_id", "cause_id", "death_date", "weight", "amount"},
+	Entity_FeedIncoming: {"feed_type_id", "incoming_date", "qty", "price"},
+}
+
+func (svc *ImportService) Import(entity string, file io.Reader, dryRun bool) (*ImportResult, error) {
+	var parse func(r *record, refs *references) insert
+	switch entity {
+	case Entity_Batch:
+		parse = svc.batchRow
+	case Entity_Pool:
+		parse = svc.poolRow
+	case Entity_FeedType:
+		parse = svc.feedTypeRow
+	case Entity_Cycle:
+		parse = svc.cycleRow
+	case Entity_Feeding:
+		parse = svc.feedingRow
+	case Entity_Death:
+		parse = svc.deathRow
+	case Entity_FeedIncoming:
+		parse = svc.feedIncomingRow
+	default:
+		return nil, fmt.Errorf("Unknown import entity %s.", entity)
+	}
+
+	records, decimalComma, err := readCSV(file)
+	if err != nil {
+		return nil, err
+	}
+	result := ImportResult{Entity: entity, DryRun: dryRun, Rows: len(records) - 1, Errors: make([]RowError, 0)}
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range requiredColumns[entity] {
+		if _, ok := columns[name]; !ok {
+			result.Errors = append(result.Errors, RowError{Row: 1, Column: name, Message: "Column is missing."})
+		}
+	}
+	if len(result.Errors) > 0 {
+		return &result, nil
+	}
+
+	//every row is checked so the whole list of errors comes back at once
+	refs := newReferences(svc)
+	inserts := make([]insert, 0, result.Rows)
+	for i, cells := range records[1:] {
+		r := record{line: i + 2, columns: columns, cells: cells, decimalComma: decimalComma}
+		if row := parse(&r, refs); len(r.errors) > 0 {
+			result.Errors = append(result.Errors, r.errors...)
+		} else {
+			inserts = append(inserts, row)
+		}
+	}
+	if len(result.Errors) > 0 || dryRun {
+		return &result, nil
+	}
+
+	if tx, err := svc.DB.Begin(); err != nil {
+		return nil, err
+	} else {
+		for _, row := range inserts {
+			if err := row(tx); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+		//incomings and feedings change the stock every average cost was computed from
+		for feedTypeId := range refs.costs {
+			if _, err := svc.FeedRepository.RecalculateFeedTypeCostTransaction(tx, feedTypeId); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	result.Imported = len(inserts)
+	return &result, nil
+}
+
+//growth
+func (svc *ImportService) batchRow(r *record, refs *references) insert {
+	b := batch.Batch{
+		Name:    r.required("name"),
+		Status:  r.integer("status"),
+		Deleted: r.boolean("deleted"),
+	}
+	if speciesId := r.id("species_id"); speciesId != uuid.Nil {
+		if species, err := refs.species(speciesId); err != nil {
+			r.fail("species_id", "%s", err.Error())
+		} else if species.Deleted {
+			r.fail("species_id", "Species %s has been deleted.", species.Name)
+		} else {
+			b.SpeciesID = uuid.NullUUID{UUID: speciesId, Valid: true}
+		}
+	}
+	b.ID = refs.newID(r, func(id uuid.UUID) bool {
+		_, err := svc.BatchRepository.ResolveGrowthBatchByID(id)
+		return err == nil
+	})
+	return func(tx *sql.Tx) error {
+		_, err := svc.BatchRepository.InsertGrowthBatchTransaction(tx, &b)
+		return err
+	}
+}
+
+func (svc *ImportService) poolRow(r *record, refs *references) insert {
+	p := batch.Pool{
+		Name:    r.required("name"),
+		Status:  r.required("status"),
+		Area:    r.number("area"),
+		Deleted: r.boolean("deleted"),
+	}
+	if p.Status != "" && p.Status != batch.Pool_Assigned && p.Status != batch.Pool_Inactive && p.Status != batch.Pool_Maintenance {
+		r.fail("status", "Invalid pool status %s.", p.Status)
+	}
+	if p.Area < 0 {
+		r.fail("area", "Pool area cannot be negative.")
+	}
+	p.ID = refs.newID(r, func(id uuid.UUID) bool {
+		_, err := svc.BatchRepository.ResolveGrowthPoolByID(id)
+		return err == nil
+	})
+	return func(tx *sql.Tx) error {
+		_, err := svc.BatchRepository.InsertGrowthPoolTransaction(tx, &p)
+		return err
+	}
+}
+
+func (svc *ImportService) cycleRow(r *record, refs *references) insert {
+	bc := batch.BatchCycle{
+		Start:    r.requiredDate("start"),
+		Finish:   r.date("finish"),
+		Weight:   r.number("weight"),
+		Amount:   r.number("amount"),
+		SeedCost: r.number("seed_cost"),
+	}
+	if batchId := r.requiredID("batch_id"); batchId != uuid.Nil {
+		if b, err := refs.batch(batchId); err != nil {
+			r.fail("batch_id", "%s", err.Error())
+		} else if b.Deleted {
+			r.fail("batch_id", "Batch %s has been deleted.", b.Name)
+		}
+		bc.BatchID = batchId
+		bc.Batch.ID = batchId
+	}
+	if poolId := r.requiredID("pool_id"); poolId != uuid.Nil {
+		if p, err := refs.pool(poolId); err != nil {
+			r.fail("pool_id", "%s", err.Error())
+		} else if p.Deleted {
+			r.fail("pool_id", "Pool %s has been deleted.", p.Name)
+		}
+		bc.PoolID = poolId
+		bc.Pool.ID = poolId
+	}
+	if seedSourceId := r.id("seed_source_id"); seedSourceId != uuid.Nil {
+		if _, err := refs.seedSource(seedSourceId); err != nil {
+			r.fail("seed_source_id", "%s", err.Error())
+		}
+		bc.SeedSourceID = uuid.NullUUID{UUID: seedSourceId, Valid: true}
+	}
+	if bc.Weight <= 0 {
+		r.fail("weight", "Stocking weight must be greater than zero.")
+	}
+	if bc.Amount <= 0 {
+		r.fail("amount", "Stocking amount must be greater than zero.")
+	}
+	if bc.SeedCost < 0 {
+		r.fail("seed_cost", "Seed cost cannot be negative.")
+	}
+	if bc.Finish.Valid && !bc.Start.IsZero() && day(bc.Finish.Time).Before(day(bc.Start)) {
+		r.fail("finish", "Cycle finish cannot be before the cycle start.")
+	}
+	bc.ID = refs.newID(r, func(id uuid.UUID) bool {
+		_, err := svc.BatchRepository.ResolveGrowthBatchCycleByID(bc.BatchID, id)
+		return err == nil
+	})
+	return func(tx *sql.Tx) error {
+		if _, err := svc.BatchRepository.InsertGrowthBatchCycleTransaction(tx, &bc); err != nil {
+			return err
+		} else if bc.Finish.Valid {
+			//a closed cycle only gets its finish through the update
+			_, err := svc.BatchRepository.UpdateGrowthBatchCycleByIDTransaction(tx, &bc)
+			return err
+		}
+		return nil
+	}
+}
+
+//feedings are stored in the base unit and draw from the feed lots like a feeding
+//stored through the api, stock the file does not cover fails the import
+func (svc *ImportService) feedingRow(r *record, refs *references) insert {
+	f := batch.Feeding{
+		Qty:     r.number("qty"),
+		Unit:    r.text("unit"),
+		Remarks: r.text("remarks"),
+	}
+	f.BatchCycleID, f.FeedingDate = cycleDate(r, refs, "feeding_date")
+	if f.Qty == 0 {
+		r.fail("qty", "Qty cannot be zero.")
+	}
+	if feedTypeId := r.requiredID("feed_type_id"); feedTypeId != uuid.Nil {
+		if feedtype, err := refs.feedType(feedTypeId); err != nil {
+			r.fail("feed_type_id", "%s", err.Error())
+		} else if feedtype.Deleted {
+			r.fail("feed_type_id", "Feed type %s has been deleted.", feedtype.Name)
+		} else if qty, err := svc.FeedService.ConvertToBaseUnit(feedTypeId, f.Qty, f.Unit); err != nil {
+			r.fail("unit", "%s", err.Error())
+		} else {
+			f.Qty = qty
+			f.Unit = feed.Unit_Base
+		}
+		f.FeedTypeID = feedTypeId
+		f.FeedType.ID = feedTypeId
+		refs.costs[feedTypeId] = true
+	}
+	f.ID = refs.newID(r, func(id uuid.UUID) bool {
+		_, err := svc.BatchRepository.ResolveGrowthFeedingByID(id)
+		return err == nil
+	})
+	return func(tx *sql.Tx) error {
+		if _, err := svc.FeedRepository.ConsumeFeedLotTransaction(tx, f.FeedType.ID, feed.Lot_Usage_Feeding, f.ID, f.Qty); err != nil {
+			return fmt.Errorf("Row %d: %s", r.line, err.Error())
+		}
+		_, err := svc.BatchRepository.InsertGrowthFeedingTransaction(tx, &f)
+		return err
+	}
+}
+
+func (svc *ImportService) deathRow(r *record, refs *references) insert {
+	d := batch.Death{
+		Weight:  r.number("weight"),
+		Amount:  r.number("amount"),
+		Remarks: r.text("remarks"),
+	}
+	d.BatchCycleID, d.DeathDate = cycleDate(r, refs, "death_date")
+	if d.Weight <= 0 {
+		r.fail("weight", "Weight must be greater than zero.")
+	}
+	if d.Amount <= 0 {
+		r.fail("amount", "Amount must be greater than zero.")
+	}
+	if causeId := r.requiredID("cause_id"); causeId != uuid.Nil {
+		if cause, err := refs.cause(causeId); err != nil {
+			r.fail("cause_id", "%s", err.Error())
+		} else if cause.Deleted {
+			r.fail("cause_id", "Death cause %s is no longer in use.", cause.Name)
+		}
+		d.CauseID = causeId
+		d.Cause.ID = causeId
+	}
+	d.ID = refs.newID(r, func(id uuid.UUID) bool {
+		_, err := svc.BatchRepository.ResolveGrowthDeathByID(id)
+		return err == nil
+	})
+	return func(tx *sql.Tx) error {
+		_, err := svc.BatchRepository.InsertGrowthDeathTransaction(tx, &d)
+		return err
+	}
+}
+
+//feed
+func (svc *ImportService) feedTypeRow(r *record, refs *references) insert {
+	f := feed.FeedType{
+		Name:         r.required("name"),
+		Unit:         r.required("unit"),
+		Status:       r.integer("status"),
+		Protein:      r.number("protein"),
+		Fat:          r.number("fat"),
+		PelletSize:   r.number("pellet_size"),
+		Stage:        r.text("stage"),
+		ReorderPoint: r.number("reorder_point"),
+		ReorderQty:   r.number("reorder_qty"),
+		Deleted:      r.boolean("deleted"),
+	}
+	if f.Protein < 0 || f.Protein > 100 {
+		r.fail("protein", "Protein must be a percentage between 0 and 100.")
+	}
+	if f.Fat < 0 || f.Fat > 100 {
+		r.fail("fat", "Fat must be a percentage between 0 and 100.")
+	}
+	if f.PelletSize < 0 {
+		r.fail("pellet_size", "Pellet size cannot be negative.")
+	}
+	if f.Stage != "" && f.Stage != feed.Feed_Stage_Starter && f.Stage != feed.Feed_Stage_Grower && f.Stage != feed.Feed_Stage_Finisher {
+		r.fail("stage", "Unknown feed stage %s.", f.Stage)
+	}
+	if f.ReorderPoint < 0 {
+		r.fail("reorder_point", "Reorder point cannot be negative.")
+	}
+	if f.ReorderQty < 0 {
+		r.fail("reorder_qty", "Reorder qty cannot be negative.")
+	}
+	f.ID = refs.newID(r, func(id uuid.UUID) bool {
+		_, err := svc.FeedRepository.ResolveFeedTypeByID(id)
+		return err == nil
+	})
+	return func(tx *sql.Tx) error {
+		_, err := svc.FeedRepository.InsertFeedTypeTransaction(tx, &f)
+		return err
+	}
+}
+
+//incomings are stored in the base unit and open a feed lot like an incoming stored
+//through the api, returned feed is taken off the lots
+func (svc *ImportService) feedIncomingRow(r *record, refs *references) insert {
+	f := feed.FeedIncoming{
+		IncomingDate: r.requiredDate("incoming_date"),
+		Qty:          r.number("qty"),
+		Unit:         r.text("unit"),
+		Price:        r.number("price"),
+		Invoice:      r.text("invoice"),
+		LotNumber:    r.text("lot_number"),
+		Manufactured: r.date("manufactured"),
+		Expired:      r.date("expired"),
+		Remarks:      r.text("remarks"),
+	}
+	if f.Qty == 0 {
+		r.fail("qty", "Qty must smaller or bigger than 0.")
+	}
+	if f.Price < 0 {
+		r.fail("price", "Price cannot be negative.")
+	}
+	if feedTypeId := r.requiredID("feed_type_id"); feedTypeId != uuid.Nil {
+		if feedtype, err := refs.feedType(feedTypeId); err != nil {
+			r.fail("feed_type_id", "%s", err.Error())
+		} else if feedtype.Deleted {
+			r.fail("feed_type_id", "Feed type %s has been deleted.", feedtype.Name)
+		} else if factor, err := svc.FeedService.ConvertToBaseUnit(feedTypeId, 1, f.Unit); err != nil {
+			r.fail("unit", "%s", err.Error())
+		} else {
+			//price is per unit so it goes the other way
+			f.Qty = f.Qty * factor
+			f.Price = f.Price / factor
+			f.Unit = feed.Unit_Base
+		}
+		f.FeedTypeID = feedTypeId
+		f.FeedType.ID = feedTypeId
+		refs.costs[feedTypeId] = true
+	}
+	if supplierId := r.id("supplier_id"); supplierId != uuid.Nil {
+		if supplier, err := refs.supplier(supplierId); err != nil {
+			r.fail("supplier_id", "%s", err.Error())
+		} else if supplier.Deleted {
+			r.fail("supplier_id", "Supplier %s has been deleted.", supplier.Name)
+		} else if supplier.Status == 0 {
+			r.fail("supplier_id", "Supplier %s is inactive.", supplier.Name)
+		} else {
+			f.Supplier = supplier
+			f.SupplierID = uuid.NullUUID{UUID: supplierId, Valid: true}
+		}
+	}
+	f.ID = refs.newID(r, func(id uuid.UUID) bool {
+		_, err := svc.FeedRepository.ResolveFeedIncomingByID(id)
+		return err == nil
+	})
+	return func(tx *sql.Tx) error {
+		if f.Qty < 0 {
+			if _, err := svc.FeedRepository.ConsumeFeedLotTransaction(tx, f.FeedType.ID, feed.Lot_Usage_Incoming, f.ID, 0-f.Qty); err != nil {
+				return fmt.Errorf("Row %d: %s", r.line, err.Error())
+			}
+		}
+		if _, err := svc.FeedRepository.InsertFeedIncomingTransaction(tx, &f); err != nil {
+			return err
+		} else if lot := feed.IncomingFeedLot(&f); lot != nil {
+			if _, err := svc.FeedRepository.InsertFeedLotTransaction(tx, lot); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+//cycleDate reads the cycle a row belongs to and a date that has to fall within it
+func cycleDate(r *record, refs *references, column string) (uuid.UUID, time.Time) {
+	batchId := r.requiredID("batch_id")
+	cycleId := r.requiredID("batch_cycle_id")
+	date := r.requiredDate(column)
+	if batchId == uuid.Nil || cycleId == uuid.Nil {
+		return cycleId, date
+	}
+	if cycle, err := refs.cycle(batchId, cycleId); err != nil {
+		r.fail("batch_cycle_id", "%s", err.Error())
+	} else if date.IsZero() {
+		return cycleId, date
+	} else if day(date).Before(day(cycle.Start)) {
+		r.fail(column, "Date cannot be before the cycle start.")
+	} else if cycle.Finish.Valid && day(date).After(day(cycle.Finish.Time)) {
+		r.fail(column, "Date cannot be after the cycle finish.")
+	}
+	return cycleId, date
+}
+
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+//references caches what the rows point at so a long file does not resolve
+//the same cycle on every line
+type references struct {
+	svc         *ImportService
+	ids         map[uuid.UUID]int
+	costs       map[uuid.UUID]bool
+	speciesByID map[uuid.UUID]*batch.Species
+	batches     map[uuid.UUID]*batch.Batch
+	pools       map[uuid.UUID]*batch.Pool
+	seedSources map[uuid.UUID]*batch.SeedSource
+	cycles      map[uuid.UUID]*batch.BatchCycle
+	causes      map[uuid.UUID]*batch.DeathCause
+	feedTypes   map[uuid.UUID]*feed.FeedType
+	suppliers   map[uuid.UUID]*feed.Supplier
+}
+
+func newReferences(svc *ImportService) *references {
+	return &references{
+		svc:         svc,
+		ids:         make(map[uuid.UUID]int),
+		costs:       make(map[uuid.UUID]bool),
+		speciesByID: make(map[uuid.UUID]*batch.Species),
+		batches:     make(map[uuid.UUID]*batch.Batch),
+		pools:       make(map[uuid.UUID]*batch.Pool),
+		seedSources: make(map[uuid.UUID]*batch.SeedSource),
+		cycles:      make(map[uuid.UUID]*batch.BatchCycle),
+		causes:      make(map[uuid.UUID]*batch.DeathCause),
+		feedTypes:   make(map[uuid.UUID]*feed.FeedType),
+		suppliers:   make(map[uuid.UUID]*feed.Supplier),
+	}
+}
+
+//newID takes the id column or generates one, a given id must be new to the
+//database and appear once in the file
+func (refs *references) newID(r *record, exists func(uuid.UUID) bool) uuid.UUID {
+	id := r.id("id")
+	if id == uuid.Nil {
+		return uuid.Must(uuid.NewV4())
+	}
+	if line, ok := refs.ids[id]; ok {
+		r.fail("id", "Id %s is repeated from row %d.", id, line)
+	} else if exists(id) {
+		r.fail("id", "Id %s already exists.", id)
+	}
+	refs.ids[id] = r.line
+	return id
+}
+
+func (refs *references) species(id uuid.UUID) (*batch.Species, error) {
+	if species, ok := refs.speciesByID[id]; ok {
+		return species, nil
+	}
+	species, err := refs.svc.BatchRepository.ResolveGrowthSpeciesByID(id)
+	if err != nil {
+		return nil, err
+	}
+	refs.speciesByID[id] = species
+	return species, nil
+}
+
+func (refs *references) batch(id uuid.UUID) (*batch.Batch, error) {
+	if b, ok := refs.batches[id]; ok {
+		return b, nil
+	}
+	b, err := refs.svc.BatchRepository.ResolveGrowthBatchByID(id)
+	if err != nil {
+		return nil, err
+	}
+	refs.batches[id] = b
+	return b, nil
+}
+
+func (refs *references) pool(id uuid.UUID) (*batch.Pool, error) {
+	if p, ok := refs.pools[id]; ok {
+		return p, nil
+	}
+	p, err := refs.svc.BatchRepository.ResolveGrowthPoolByID(id)
+	if err != nil {
+		return nil, err
+	}
+	refs.pools[id] = p
+	return p, nil
+}
+
+func (refs *references) seedSource(id uuid.UUID) (*batch.SeedSource, error) {
+	if s, ok := refs.seedSources[id]; ok {
+		return s, nil
+	}
+	s, err := refs.svc.BatchRepository.ResolveGrowthSeedSourceByID(id)
+	if err != nil {
+		return nil, err
+	}
+	refs.seedSources[id] = s
+	return s, nil
+}
+
+func (refs *references) cycle(batchId uuid.UUID, cycleId uuid.UUID) (*batch.BatchCycle, error) {
+	if bc, ok := refs.cycles[cycleId]; ok && bc.BatchID == batchId {
+		return bc, nil
+	} else if ok {
+		return nil, fmt.Errorf("Batch cycle %s does not belong to batch %s.", cycleId, batchId)
+	}
+	bc, err := refs.svc.BatchRepository.ResolveGrowthBatchCycleByID(batchId, cycleId)
+	if err != nil {
+		return nil, err
+	}
+	refs.cycles[cycleId] = bc
+	return bc, nil
+}
+
+func (refs *references) cause(id uuid.UUID) (*batch.DeathCause, error) {
+	if c, ok := refs.causes[id]; ok {
+		return c, nil
+	}
+	c, err := refs.svc.BatchRepository.ResolveGrowthDeathCauseByID(id)
+	if err != nil {
+		return nil, err
+	}
+	refs.causes[id] = c
+	return c, nil
+}
+
+func (refs *references) feedType(id uuid.UUID) (*feed.FeedType, error) {
+	if f, ok := refs.feedTypes[id]; ok {
+		return f, nil
+	}
+	f, err := refs.svc.FeedRepository.ResolveFeedTypeByID(id)
+	if err != nil {
+		return nil, err
+	}
+	refs.feedTypes[id] = f
+	return f, nil
+}
+
+func (refs *references) supplier(id uuid.UUID) (*feed.Supplier, error) {
+	if s, ok := refs.suppliers[id]; ok {
+		return s, nil
+	}
+	s, err := refs.svc.FeedRepository.ResolveSupplierByID(id)
+	if err != nil {
+		return nil, err
+	}
+	refs.suppliers[id] = s
+	return s, nil
+}
@@ -0,0 +1,66 @@
+package importer
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/livestockz/api/database/databasetest"
+	"github.com/livestockz/api/domain/batch"
+	"github.com/livestockz/api/domain/feed"
+	uuid "github.com/satori/go.uuid"
+)
+
+func TestImportFeedLot(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *sql.DB) {
+		feedRepo := &feed.FeedRepository{DB: db}
+		batchRepo := &batch.BatchRepository{DB: db, FeedRepository: feedRepo}
+		svc := &ImportService{DB: db, BatchRepository: batchRepo, FeedRepository: feedRepo, FeedService: &feed.FeedService{FeedRepository: feedRepo}}
+		feedtype, err := feedRepo.InsertFeedType(&feed.FeedType{ID: uuid.Must(uuid.NewV4()), Name: "Pellet", Unit: "kg", Status: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := batchRepo.InsertGrowthBatch(&batch.Batch{ID: uuid.Must(uuid.NewV4()), Name: "Batch", Status: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		pool, err := batchRepo.InsertGrowthPool(&batch.Pool{ID: uuid.Must(uuid.NewV4()), Name: "Pool", Status: batch.Pool_Assigned})
+		if err != nil {
+			t.Fatal(err)
+		}
+		cycle, err := batchRepo.InsertGrowthBatchCycle(&batch.BatchCycle{ID: uuid.Must(uuid.NewV4()), Batch: *b, BatchID: b.ID, Pool: *pool, PoolID: pool.ID, Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Weight: 10, Amount: 1000})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		incomings := fmt.Sprintf("feed_type_id,incoming_date,qty,price,lot_number,expired\n%[1]s,2024-01-10,30,10000,L-1,2024-06-01\n%[1]s,2024-01-05,20,10000,L-2,2024-03-01\n", feedtype.ID)
+		if result, err := svc.Import(Entity_FeedIncoming, strings.NewReader(incomings), false); err != nil {
+			t.Fatal(err)
+		} else if result.Imported != 2 {
+			t.Fatalf("imported %d incomings with %+v", result.Imported, result.Errors)
+		}
+		//the lot expiring first is used up first
+		feedings := fmt.Sprintf("batch_id,batch_cycle_id,feed_type_id,feeding_date,qty\n%s,%s,%s,2024-02-01,25\n", b.ID, cycle.ID, feedtype.ID)
+		if result, err := svc.Import(Entity_Feeding, strings.NewReader(feedings), false); err != nil {
+			t.Fatal(err)
+		} else if result.Imported != 1 {
+			t.Fatalf("imported %d feedings with %+v", result.Imported, result.Errors)
+		}
+		if lots, err := feedRepo.ResolveAvailableFeedLot(null.Time{}); err != nil {
+			t.Fatal(err)
+		} else if len(*lots) != 1 || (*lots)[0].LotNumber != "L-1" || (*lots)[0].Remaining != 25 {
+			t.Fatalf("lots left %+v", *lots)
+		}
+		feedings = fmt.Sprintf("batch_id,batch_cycle_id,feed_type_id,feeding_date,qty\n%s,%s,%s,2024-02-02,30\n", b.ID, cycle.ID, feedtype.ID)
+		if _, err := svc.Import(Entity_Feeding, strings.NewReader(feedings), false); err == nil {
+			t.Fatal("imported a feeding the stock does not cover")
+		} else if lots, err := feedRepo.ResolveAvailableFeedLot(null.Time{}); err != nil {
+			t.Fatal(err)
+		} else if (*lots)[0].Remaining != 25 {
+			t.Fatalf("failed import left %v in the lot", (*lots)[0].Remaining)
+		}
+	})
+}
@@ -0,0 +1,264 @@
+package handler
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/livestockz/api/domain/batch"
+	"github.com/livestockz/api/domain/feed"
+	"github.com/livestockz/api/utils"
+	uuid "github.com/satori/go.uuid"
+)
+
+//finishExport closes the file, an error before the first row is still answered
+//with json while a later one can only cut the file short
+func finishExport(c *gin.Context, out utils.Exporter, err error) {
+	if err != nil && !out.Started() {
+		utils.Error(c, err)
+		return
+	} else if err != nil {
+		log.Printf("export: %s stopped: %s", c.Request.URL.Path, err)
+	}
+	if err := out.Close(); err != nil {
+		log.Printf("export: %s not closed: %s", c.Request.URL.Path, err)
+	}
+}
+
+//growth collections
+func (h *BatchHandler) exportGrowthBatch(c *gin.Context, format string, deleted string) {
+	out := utils.Export(c, format, "batches", []string{"id", "name", "status", "species_id", "deleted", "created", "updated"})
+	err := h.BatchService.StreamGrowthBatch(deleted, func(b *batch.Batch) error {
+		return out.Write(b.ID, b.Name, b.Status, b.SpeciesID, b.Deleted, b.Created, b.Updated)
+	})
+	finishExport(c, out, err)
+}
+
+func (h *BatchHandler) exportGrowthPool(c *gin.Context, format string, deleted string) {
+	out := utils.Export(c, format, "pools", []string{"id", "name", "status", "area", "deleted", "created", "updated"})
+	err := h.BatchService.StreamGrowthPool(deleted, func(p *batch.Pool) error {
+		return out.Write(p.ID, p.Name, p.Status, p.Area, p.Deleted, p.Created, p.Updated)
+	})
+	finishExport(c, out, err)
+}
+
+func (h *BatchHandler) exportGrowthBatchCycle(c *gin.Context, format string, batchId uuid.UUID) {
+	out := utils.Export(c, format, "cycles", []string{"id", "batch_id", "pool_id", "seed_source_id", "start", "finish", "weight", "amount", "seed_cost", "created", "updated"})
+	err := h.BatchService.StreamGrowthBatchCycle(batchId, func(bc *batch.BatchCycle) error {
+		return out.Write(bc.ID, bc.BatchID, bc.PoolID, bc.SeedSourceID, bc.Start, bc.Finish, bc.Weight, bc.Amount, bc.SeedCost, bc.Created, bc.Updated)
+	})
+	finishExport(c, out, err)
+}
+
+func (h *BatchHandler) exportGrowthSales(c *gin.Context, format string, from, to time.Time) {
+	out := utils.Export(c, format, "sales", []string{"sales_id", "sales_date", "reference", "detail_id", "batch_cycle_id", "amount", "weight", "price", "total"})
+	err := h.BatchService.StreamGrowthSalesDetail(from, to, func(s *batch.Sales, d *batch.SalesDetail) error {
+		return out.Write(d.SalesID, s.SalesDate, s.Reference, d.ID, d.BatchCycleID, d.Amount, d.Weight, d.Price, d.Weight*d.Price)
+	})
+	finishExport(c, out, err)
+}
+
+//growth reports, computed first and written row by row
+func exportMortalityReport(c *gin.Context, format string, report *batch.MortalityReport) {
+	out := utils.Export(c, format, "mortality", []string{"group", "id", "name", "amount", "weight"})
+	var err error
+	groups := []struct {
+		name   string
+		groups []batch.MortalityGroup
+	}{{"cause", report.ByCause}, {"pool", report.ByPool}, {"batch", report.ByBatch}}
+	for _, group := range groups {
+		if err != nil {
+			break
+		}
+		for _, g := range group.groups {
+			if err = out.Write(group.name, g.ID, g.Name, g.Amount, g.Weight); err != nil {
+				break
+			}
+		}
+	}
+	for _, week := range report.ByWeek {
+		if err != nil {
+			break
+		}
+		err = out.Write("week", "", week.Week.Format(dateLayout), week.Amount, week.Weight)
+	}
+	finishExport(c, out, err)
+}
+
+func exportHatcheryPerformance(c *gin.Context, format string, performances *[]batch.HatcheryPerformance) {
+	out := utils.Export(c, format, "hatchery", []string{"rank", "hatchery_id", "name", "seed_sources", "cycles", "stocked", "harvested", "sr", "fcr"})
+	var err error
+	for _, p := range *performances {
+		if err = out.Write(p.Rank, p.HatcheryID, p.Name, p.SeedSources, p.Cycles, p.Stocked, p.Harvested, p.SR, p.FCR); err != nil {
+			break
+		}
+	}
+	finishExport(c, out, err)
+}
+
+func exportCycleReport(c *gin.Context, format string, reports *[]batch.CycleReport) {
+	out := utils.Export(c, format, "cycle-report", []string{"batch_cycle_id", "batch", "pool", "species", "start", "summary_date", "day_of_culture",
+		"stocked", "stocking_density", "harvested", "harvested_amount", "adg", "fcr", "sr",
+		"pool_adg_rank", "pool_fcr_rank", "pool_sr_rank", "species_adg_rank", "species_fcr_rank", "species_sr_rank"})
+	var err error
+	for _, r := range *reports {
+		if err = out.Write(r.BatchCycleID, r.BatchName, r.PoolName, r.SpeciesName, r.Start, r.SummaryDate, r.DayOfCulture,
+			r.Stocked, r.StockingDensity, r.Harvested, r.HarvestedAmount, r.ADG, r.FCR, r.SR,
+			r.PoolRank.ADG.Rank, r.PoolRank.FCR.Rank, r.PoolRank.SR.Rank, r.SpeciesRank.ADG.Rank, r.SpeciesRank.FCR.Rank, r.SpeciesRank.SR.Rank); err != nil {
+			break
+		}
+	}
+	finishExport(c, out, err)
+}
+
+func exportForecast(c *gin.Context, format string, forecast *batch.Forecast) {
+	out := utils.Export(c, format, "forecast", []string{"week", "date", "batch_cycle_id", "batch", "pool", "method", "amount", "weight", "biomass", "harvestable"})
+	var err error
+	for _, cycle := range forecast.Cycles {
+		for _, w := range cycle.Weeks {
+			if err = out.Write(w.Week.Format(dateLayout), w.Date.Format(dateLayout), cycle.BatchCycleID, cycle.Batch.Name, cycle.Pool.Name, cycle.Method, w.Amount, w.Weight, w.Biomass, w.Harvestable); err != nil {
+				break
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	finishExport(c, out, err)
+}
+
+func exportWaterQuality(c *gin.Context, format string, readings *[]batch.WaterQuality) {
+	out := utils.Export(c, format, "water-quality", []string{"id", "pool_id", "measured_at", "dissolved_oxygen", "ph", "temperature", "ammonia", "nitrite", "salinity", "transparency", "out_of_range", "out_of_range_parameters", "remarks", "created"})
+	var err error
+	for _, w := range *readings {
+		if err = out.Write(w.ID, w.PoolID, w.MeasuredAt, w.DissolvedOxygen, w.PH, w.Temperature, w.Ammonia, w.Nitrite, w.Salinity, w.Transparency, w.OutOfRange, strings.Join(w.OutOfRangeList, " "), w.Remarks, w.Created); err != nil {
+			break
+		}
+	}
+	finishExport(c, out, err)
+}
+
+//exportProfitAndLoss writes the feed and cost lines followed by one summary row per figure
+func exportProfitAndLoss(c *gin.Context, format string, pnl *batch.ProfitAndLoss) {
+	out := utils.Export(c, format, "profit-and-loss", []string{"section", "id", "name", "date", "qty", "unit_price", "amount"})
+	var err error
+	for _, f := range pnl.Feed {
+		if err = out.Write("feed", f.FeedType.ID, f.FeedType.Name, "", f.Qty, f.UnitPrice, f.Amount); err != nil {
+			break
+		}
+	}
+	for _, cost := range pnl.Costs {
+		if err != nil {
+			break
+		}
+		err = out.Write("cost", cost.ID, cost.Description, cost.CostDate.Format(dateLayout), "", "", cost.Amount)
+	}
+	summary := []struct {
+		name  string
+		value float64
+	}{
+		{"feed_cost", pnl.FeedCost}, {"protein_intake", pnl.ProteinIntake}, {"seed_cost", pnl.SeedCost}, {"other_cost", pnl.OtherCost},
+		{"total_cost", pnl.TotalCost}, {"harvest_weight", pnl.HarvestWeight}, {"revenue", pnl.Revenue}, {"cost_per_kg", pnl.CostPerKg},
+		{"gross_margin", pnl.GrossMargin}, {"gross_margin_pct", pnl.GrossMarginPct}, {"roi", pnl.ROI},
+	}
+	for _, s := range summary {
+		if err != nil {
+			break
+		}
+		err = out.Write("summary", pnl.BatchCycleID, s.name, "", "", "", s.value)
+	}
+	finishExport(c, out, err)
+}
+
+//feed collections
+func (h *FeedHandler) exportFeedType(c *gin.Context, format string, deleted string) {
+	out := utils.Export(c, format, "feed-types", []string{"id", "name", "unit", "status", "average_cost", "protein", "fat", "pellet_size", "stage", "reorder_point", "reorder_qty", "deleted", "created", "updated"})
+	err := h.FeedService.StreamFeedType(deleted, func(f *feed.FeedType) error {
+		return out.Write(f.ID, f.Name, f.Unit, f.Status, f.AverageCost, f.Protein, f.Fat, f.PelletSize, f.Stage, f.ReorderPoint, f.ReorderQty, f.Deleted, f.Created, f.Updated)
+	})
+	finishExport(c, out, err)
+}
+
+func (h *FeedHandler) exportFeedIncoming(c *gin.Context, format string) {
+	out := utils.Export(c, format, "feed-incoming", []string{"id", "feed_type_id", "supplier_id", "incoming_date", "qty", "price", "invoice", "lot_number", "manufactured", "expired", "remarks", "created"})
+	err := h.FeedService.StreamFeedIncoming(func(f *feed.FeedIncoming) error {
+		return out.Write(f.ID, f.FeedTypeID, f.SupplierID, f.IncomingDate, f.Qty, f.Price, f.Invoice, f.LotNumber, f.Manufactured, f.Expired, f.Remarks, f.Created)
+	})
+	finishExport(c, out, err)
+}
+
+func (h *FeedHandler) exportFeedAdjustment(c *gin.Context, format string) {
+	out := utils.Export(c, format, "feed-adjustments", []string{"id", "feed_type_id", "qty", "reason", "stocktake_id", "remarks", "created"})
+	err := h.FeedService.StreamFeedAdjustment(func(f *feed.FeedAdjustment) error {
+		return out.Write(f.ID, f.FeedTypeID, f.Qty, f.Reason, f.StocktakeID, f.Remarks, f.Created)
+	})
+	finishExport(c, out, err)
+}
+
+//feed reports
+func exportSupplierPurchase(c *gin.Context, format string, purchases *[]feed.SupplierPurchase) {
+	out := utils.Export(c, format, "supplier-purchase", []string{"supplier_id", "name", "incoming", "qty", "amount"})
+	var err error
+	for _, p := range *purchases {
+		if err = out.Write(p.SupplierID, p.Name, p.Incoming, p.Qty, p.Amount); err != nil {
+			break
+		}
+	}
+	finishExport(c, out, err)
+}
+
+func exportSupplierPerformance(c *gin.Context, format string, performances *[]feed.SupplierPerformance) {
+	out := utils.Export(c, format, "supplier-performance", []string{"supplier_id", "name", "cycles", "feed_qty", "fcr"})
+	var err error
+	for _, p := range *performances {
+		if err = out.Write(p.SupplierID, p.Name, p.Cycles, p.FeedQty, p.FCR); err != nil {
+			break
+		}
+	}
+	finishExport(c, out, err)
+}
+
+func exportFeedAlert(c *gin.Context, format string, alerts *[]feed.FeedAlert) {
+	out := utils.Export(c, format, "feed-alerts", []string{"feed_type_id", "name", "stock", "daily_consumption", "days_of_cover", "stockout_date", "below_reorder_point", "running_out", "suggested_qty"})
+	var err error
+	for _, a := range *alerts {
+		if err = out.Write(a.FeedType.ID, a.FeedType.Name, a.Stock, a.DailyConsumption, a.DaysOfCover, a.StockoutDate, a.BelowReorderPoint, a.RunningOut, a.SuggestedQty); err != nil {
+			break
+		}
+	}
+	finishExport(c, out, err)
+}
+
+func exportFeedLot(c *gin.Context, format string, lots *[]feed.FeedLot) {
+	out := utils.Export(c, format, "feed-lots", []string{"id", "feed_type_id", "name", "feed_incoming_id", "lot_number", "manufactured", "expired", "qty", "remaining", "created", "updated"})
+	var err error
+	for _, l := range *lots {
+		if err = out.Write(l.ID, l.FeedType.ID, l.FeedType.Name, l.FeedIncomingID, l.LotNumber, l.Manufactured, l.Expired, l.Qty, l.Remaining, l.Created, l.Updated); err != nil {
+			break
+		}
+	}
+	finishExport(c, out, err)
+}
+
+func exportFeedTypeCost(c *gin.Context, format string, costs *[]feed.FeedTypeCost) {
+	out := utils.Export(c, format, "feed-type-cost", []string{"id", "feed_type_id", "feed_incoming_id", "cost_date", "stock", "qty", "price", "average_cost", "created"})
+	var err error
+	for _, f := range *costs {
+		if err = out.Write(f.ID, f.FeedTypeID, f.FeedIncomingID, f.CostDate, f.Stock, f.Qty, f.Price, f.AverageCost, f.Created); err != nil {
+			break
+		}
+	}
+	finishExport(c, out, err)
+}
+
+func exportStocktakeVariance(c *gin.Context, format string, stocktake *feed.Stocktake) {
+	out := utils.Export(c, format, "stocktake-variance", []string{"stocktake_id", "stocktake_date", "status", "feed_type_id", "name", "unit", "counted_qty", "system_qty", "variance"})
+	var err error
+	for _, d := range stocktake.Detail {
+		if err = out.Write(stocktake.ID, stocktake.StocktakeDate.Format(dateLayout), stocktake.Status, d.FeedType.ID, d.FeedType.Name, d.FeedType.Unit, d.CountedQty, d.SystemQty, d.Variance); err != nil {
+			break
+		}
+	}
+	finishExport(c, out, err)
+}
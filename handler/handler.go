@@ -1,12 +1,20 @@
 package handler
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/guregu/null"
+	"github.com/livestockz/api/config"
 	"github.com/livestockz/api/domain/batch"
+	"github.com/livestockz/api/domain/dashboard"
 	"github.com/livestockz/api/domain/feed"
+	"github.com/livestockz/api/domain/importer"
 	"github.com/livestockz/api/utils"
 	uuid "github.com/satori/go.uuid"
 )
@@ -16,13 +24,62 @@ type BatchHandler struct {
 }
 
 type FeedHandler struct {
-	FeedService feed.Service `inject:"feedService"`
+	FeedService feed.Service  `inject:"feedService"`
+	Config      config.Config `inject:"config"`
+}
+
+type DashboardHandler struct {
+	DashboardService dashboard.Service `inject:"dashboardService"`
+}
+
+type ImportHandler struct {
+	ImportService importer.Service `inject:"importService"`
 }
 
 type UUIDRequestModel struct {
 	Data []string `json:"ids"`
 }
 
+const dateLayout = "2006-01-02"
+
+//parseInclude reads a comma separated list of relations, an empty list loads none of them
+func parseInclude(value string) []string {
+	include := make([]string, 0)
+	for _, relation := range strings.Split(value, ",") {
+		if relation = strings.ToLower(strings.TrimSpace(relation)); relation != "" {
+			include = append(include, relation)
+		}
+	}
+	return include
+}
+
+//parseDateRange reads `from` and `to` query like ?from=2018-01-01&to=2018-01-31,
+//defaults to the current month up to today
+func parseDateRange(c *gin.Context) (time.Time, time.Time, error) {
+	q := c.Request.URL.Query()
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if f := q.Get("from"); f != "" {
+		if date, err := time.ParseInLocation(dateLayout, f, now.Location()); err != nil {
+			return from, to, fmt.Errorf("Invalid from date.")
+		} else {
+			from = date
+		}
+	}
+	if t := q.Get("to"); t != "" {
+		if date, err := time.ParseInLocation(dateLayout, t, now.Location()); err != nil {
+			return from, to, fmt.Errorf("Invalid to date.")
+		} else {
+			to = date
+		}
+	}
+	if to.Before(from) {
+		return from, to, fmt.Errorf("From date must be before to date.")
+	}
+	return from, to, nil
+}
+
 func (h *BatchHandler) HealthHandler(c *gin.Context) {
 	utils.Ok(c, nil)
 }
@@ -44,6 +101,8 @@ func (h *BatchHandler) ResolveGrowthBatchPage(c *gin.Context) {
 
 	if d != batch.Deleted_Any && d != batch.Deleted_False && d != batch.Deleted_True {
 		utils.Error(c, fmt.Errorf("Unknown deleted status"))
+	} else if format := utils.ExportFormat(c); format != "" {
+		h.exportGrowthBatch(c, format, d)
 	} else if batches, p, l, total, err := h.BatchService.ResolveGrowthBatchPage(int32(page), int32(limit), d); err != nil {
 		utils.Error(c, err)
 	} else {
@@ -165,6 +224,8 @@ func (h *BatchHandler) ResolveGrowthPoolPage(c *gin.Context) {
 
 	if d != batch.Deleted_Any && d != batch.Deleted_False && d != batch.Deleted_True {
 		utils.Error(c, fmt.Errorf("Unknown deleted status"))
+	} else if format := utils.ExportFormat(c); format != "" {
+		h.exportGrowthPool(c, format, d)
 	} else if pools, p, l, total, err := h.BatchService.ResolveGrowthPoolPage(int32(page), int32(limit), d); err != nil {
 		utils.Error(c, err)
 	} else {
@@ -187,6 +248,355 @@ func (h *BatchHandler) ResolveGrowthPoolByID(c *gin.Context) {
 	return
 }
 
+//species
+func (h *BatchHandler) ResolveGrowthSpecies(c *gin.Context) {
+	//capture something like this: http://localhost:9090/growth/species?deleted=0
+	d := c.Request.URL.Query().Get("deleted")
+
+	if d != batch.Deleted_Any && d != batch.Deleted_False && d != batch.Deleted_True {
+		utils.Error(c, fmt.Errorf("Unknown deleted status"))
+	} else if species, err := h.BatchService.ResolveGrowthSpecies(d); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Ok(c, species)
+	}
+	return
+}
+
+func (h *BatchHandler) ResolveGrowthSpeciesByID(c *gin.Context) {
+	id := c.Params.ByName("speciesId")
+	uid, err := uuid.FromString(id)
+
+	if err != nil {
+		utils.Error(c, err)
+	} else if species, err := h.BatchService.ResolveGrowthSpeciesByID(uid); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Ok(c, &species)
+	}
+	return
+}
+
+func (h *BatchHandler) StoreGrowthSpecies(c *gin.Context) {
+	var id = c.Params.ByName("speciesId")
+	var species batch.Species
+	c.BindJSON(&species)
+
+	if species.Name == "" {
+		utils.Error(c, fmt.Errorf("Incomplete provided data."))
+	} else if id == "" {
+		if result, err := h.BatchService.StoreGrowthSpecies(&species); err != nil {
+			utils.Error(c, err)
+		} else {
+			utils.Created(c, &result)
+		}
+	} else if uid, err := uuid.FromString(id); err != nil {
+		utils.Error(c, fmt.Errorf("Unable to convert given ID to UUID"))
+	} else if species.ID != uid {
+		utils.Error(c, fmt.Errorf("Inconsistent ID."))
+	} else if result, err := h.BatchService.StoreGrowthSpecies(&species); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Ok(c, &result)
+	}
+	return
+}
+
+func (h *BatchHandler) RemoveGrowthSpeciesByID(c *gin.Context) {
+	id := c.Params.ByName("speciesId")
+	uid, err := uuid.FromString(id)
+
+	if err != nil {
+		utils.Error(c, err)
+	} else if _, err := h.BatchService.RemoveGrowthSpeciesByID(uid); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.NoContent(c)
+	}
+	return
+}
+
+//hatchery
+func (h *BatchHandler) ResolveGrowthHatcheryPage(c *gin.Context) {
+	//capture something like this: http://localhost:9090/growth/hatchery?page=1&limit=10
+	q := c.Request.URL.Query()
+	p := q.Get("page")
+	l := q.Get("limit")
+	d := q.Get("deleted")
+	page, err := strconv.Atoi(p)
+	if err != nil {
+		page = 0
+	}
+	limit, err := strconv.Atoi(l)
+	if err != nil {
+		limit = 10
+	}
+
+	if d != batch.Deleted_Any && d != batch.Deleted_False && d != batch.Deleted_True {
+		utils.Error(c, fmt.Errorf("Unknown deleted status"))
+	} else if hatcheries, p, l, total, err := h.BatchService.ResolveGrowthHatcheryPage(int32(page), int32(limit), d); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Page(c, hatcheries, p, l, total)
+	}
+	return
+}
+
+func (h *BatchHandler) ResolveGrowthHatcheryByID(c *gin.Context) {
+	id := c.Params.ByName("hatcheryId")
+	uid, err := uuid.FromString(id)
+
+	if err != nil {
+		utils.Error(c, err)
+	} else if hatchery, err := h.BatchService.ResolveGrowthHatcheryByID(uid); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Ok(c, &hatchery)
+	}
+	return
+}
+
+func (h *BatchHandler) StoreGrowthHatchery(c *gin.Context) {
+	var id = c.Params.ByName("hatcheryId")
+	var hatchery batch.Hatchery
+	c.BindJSON(&hatchery)
+
+	if hatchery.Name == "" {
+		utils.Error(c, fmt.Errorf("Incomplete provided data."))
+	} else if id == "" {
+		if result, err := h.BatchService.StoreGrowthHatchery(&hatchery); err != nil {
+			utils.Error(c, err)
+		} else {
+			utils.Created(c, &result)
+		}
+	} else if uid, err := uuid.FromString(id); err != nil {
+		utils.Error(c, fmt.Errorf("Unable to convert given ID to UUID"))
+	} else if hatchery.ID != uid {
+		utils.Error(c, fmt.Errorf("Inconsistent ID."))
+	} else if result, err := h.BatchService.StoreGrowthHatchery(&hatchery); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Ok(c, &result)
+	}
+	return
+}
+
+func (h *BatchHandler) RemoveGrowthHatcheryByID(c *gin.Context) {
+	id := c.Params.ByName("hatcheryId")
+	uid, err := uuid.FromString(id)
+
+	if err != nil {
+		utils.Error(c, err)
+	} else if _, err := h.BatchService.RemoveGrowthHatcheryByID(uid); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.NoContent(c)
+	}
+	return
+}
+
+func (h *BatchHandler) ResolveGrowthHatcheryPerformance(c *gin.Context) {
+	//capture something like this: http://localhost:9090/growth/reports/hatchery?from=2018-01-01&to=2018-12-31
+	if from, to, err := parseDateRange(c); err != nil {
+		utils.Error(c, err)
+	} else if performances, err := h.BatchService.ResolveGrowthHatcheryPerformance(from, to); err != nil {
+		utils.Error(c, err)
+	} else if format := utils.ExportFormat(c); format != "" {
+		exportHatcheryPerformance(c, format, performances)
+	} else {
+		utils.Ok(c, performances)
+	}
+	return
+}
+
+//seed source
+func (h *BatchHandler) ResolveGrowthSeedSourcePage(c *gin.Context) {
+	//capture something like this: http://localhost:9090/growth/seed-source?page=1&limit=10&hatchery_id=
+	q := c.Request.URL.Query()
+	p := q.Get("page")
+	l := q.Get("limit")
+	page, err := strconv.Atoi(p)
+	if err != nil {
+		page = 0
+	}
+	limit, err := strconv.Atoi(l)
+	if err != nil {
+		limit = 10
+	}
+	hatcheryId := uuid.Nil
+	if hid := q.Get("hatchery_id"); hid != "" {
+		if hatcheryId, err = uuid.FromString(hid); err != nil {
+			utils.Error(c, fmt.Errorf("Invalid hatchery id."))
+			return
+		}
+	}
+
+	if seedSources, p, l, total, err := h.BatchService.ResolveGrowthSeedSourcePage(int32(page), int32(limit), hatcheryId); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Page(c, seedSources, p, l, total)
+	}
+	return
+}
+
+func (h *BatchHandler) ResolveGrowthSeedSourceByID(c *gin.Context) {
+	id := c.Params.ByName("seedSourceId")
+	uid, err := uuid.FromString(id)
+
+	if err != nil {
+		utils.Error(c, err)
+	} else if seedSource, err := h.BatchService.ResolveGrowthSeedSourceByID(uid); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Ok(c, &seedSource)
+	}
+	return
+}
+
+func (h *BatchHandler) StoreGrowthSeedSource(c *gin.Context) {
+	var id = c.Params.ByName("seedSourceId")
+	var seedSource batch.SeedSource
+	c.BindJSON(&seedSource)
+
+	if seedSource.Hatchery.ID == uuid.Nil || seedSource.Species == "" || seedSource.LotCode == "" {
+		utils.Error(c, fmt.Errorf("Incomplete provided data."))
+	} else if seedSource.StockingSize < 0 {
+		utils.Error(c, fmt.Errorf("Stocking size cannot be negative."))
+	} else if id == "" {
+		if result, err := h.BatchService.StoreGrowthSeedSource(&seedSource); err != nil {
+			utils.Error(c, err)
+		} else {
+			utils.Created(c, &result)
+		}
+	} else if uid, err := uuid.FromString(id); err != nil {
+		utils.Error(c, fmt.Errorf("Unable to convert given ID to UUID"))
+	} else if seedSource.ID != uid {
+		utils.Error(c, fmt.Errorf("Inconsistent ID."))
+	} else if result, err := h.BatchService.StoreGrowthSeedSource(&seedSource); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Ok(c, &result)
+	}
+	return
+}
+
+//pool device
+func (h *BatchHandler) ResolveGrowthPoolDeviceByPoolID(c *gin.Context) {
+	id := c.Params.ByName("poolId")
+	uid, err := uuid.FromString(id)
+
+	if err != nil {
+		utils.Error(c, err)
+	} else if devices, err := h.BatchService.ResolveGrowthPoolDeviceByPoolID(uid); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Ok(c, devices)
+	}
+	return
+}
+
+func (h *BatchHandler) StoreGrowthPoolDevice(c *gin.Context) {
+	id := c.Params.ByName("poolId")
+	uid, err := uuid.FromString(id)
+	if err != nil {
+		utils.Error(c, err)
+		return
+	}
+
+	var device batch.PoolDevice
+	c.BindJSON(&device)
+	device.PoolID = uid
+	if device.DeviceID == "" {
+		utils.Error(c, fmt.Errorf("Incomplete data."))
+	} else if result, err := h.BatchService.StoreGrowthPoolDevice(&device); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Created(c, &result)
+	}
+	return
+}
+
+func (h *BatchHandler) RemoveGrowthPoolDeviceByID(c *gin.Context) {
+	pid := c.Params.ByName("poolId")
+	did := c.Params.ByName("deviceId")
+
+	if poolId, err := uuid.FromString(pid); err != nil {
+		utils.Error(c, err)
+	} else if deviceId, err := uuid.FromString(did); err != nil {
+		utils.Error(c, err)
+	} else if _, err := h.BatchService.RemoveGrowthPoolDeviceByID(poolId, deviceId); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.NoContent(c)
+	}
+	return
+}
+
+//pool water quality
+func (h *BatchHandler) ResolveGrowthWaterQualityByPoolID(c *gin.Context) {
+	//capture something like this: http://localhost:9090/growth/pool/:poolId/water-quality?from=2018-01-01&to=2018-01-31
+	id := c.Params.ByName("poolId")
+	uid, err := uuid.FromString(id)
+
+	if err != nil {
+		utils.Error(c, err)
+	} else if from, to, err := parseDateRange(c); err != nil {
+		utils.Error(c, err)
+	} else if readings, err := h.BatchService.ResolveGrowthWaterQualityByPoolID(uid, from, to); err != nil {
+		utils.Error(c, err)
+	} else if format := utils.ExportFormat(c); format != "" {
+		exportWaterQuality(c, format, readings)
+	} else {
+		utils.Ok(c, readings)
+	}
+	return
+}
+
+func (h *BatchHandler) StoreGrowthWaterQuality(c *gin.Context) {
+	id := c.Params.ByName("poolId")
+	uid, err := uuid.FromString(id)
+	if err != nil {
+		utils.Error(c, err)
+		return
+	}
+
+	var waterQuality batch.WaterQuality
+	c.BindJSON(&waterQuality)
+	waterQuality.PoolID = uid
+	if !waterQuality.DissolvedOxygen.Valid && !waterQuality.PH.Valid && !waterQuality.Temperature.Valid && !waterQuality.Ammonia.Valid &&
+		!waterQuality.Nitrite.Valid && !waterQuality.Salinity.Valid && !waterQuality.Transparency.Valid {
+		utils.Error(c, fmt.Errorf("Incomplete data."))
+	} else if result, err := h.BatchService.StoreGrowthWaterQuality(&waterQuality); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Created(c, &result)
+	}
+	return
+}
+
+func (h *BatchHandler) ResolveGrowthWaterQualityRange(c *gin.Context) {
+	if ranges, err := h.BatchService.ResolveGrowthWaterQualityRange(); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Ok(c, ranges)
+	}
+	return
+}
+
+func (h *BatchHandler) StoreGrowthWaterQualityRange(c *gin.Context) {
+	var ranges []batch.WaterQualityRange
+	c.BindJSON(&ranges)
+
+	if len(ranges) < 1 {
+		utils.Error(c, fmt.Errorf("Incomplete data."))
+	} else if result, err := h.BatchService.StoreGrowthWaterQualityRange(ranges); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Ok(c, result)
+	}
+	return
+}
+
 func (h *BatchHandler) StoreGrowthPool(c *gin.Context) {
 
 	var id = c.Params.ByName("poolId")
@@ -198,6 +608,8 @@ func (h *BatchHandler) StoreGrowthPool(c *gin.Context) {
 			utils.Error(c, fmt.Errorf("Incomplete provided data."))
 		} else if pool.Status != batch.Pool_Assigned && pool.Status != batch.Pool_Inactive && pool.Status != batch.Pool_Maintenance {
 			utils.Error(c, fmt.Errorf("Invalid pool status."))
+		} else if pool.Area < 0 {
+			utils.Error(c, fmt.Errorf("Pool area cannot be negative."))
 		} else if result, err := h.BatchService.StoreGrowthPool(&pool); err != nil {
 			utils.Error(c, err)
 		} else {
@@ -217,6 +629,8 @@ func (h *BatchHandler) StoreGrowthPool(c *gin.Context) {
 			utils.Error(c, fmt.Errorf("Incomplete provided data."))
 		} else if pool.Status != batch.Pool_Assigned && pool.Status != batch.Pool_Inactive && pool.Status != batch.Pool_Maintenance {
 			utils.Error(c, fmt.Errorf("Invalid pool status."))
+		} else if pool.Area < 0 {
+			utils.Error(c, fmt.Errorf("Pool area cannot be negative."))
 		} else if result, err := h.BatchService.StoreGrowthPool(&pool); err != nil {
 			utils.Error(c, err)
 		} else {
@@ -294,7 +708,15 @@ func (h *BatchHandler) ResolveGrowthBatchCyclePage(c *gin.Context) {
 		limit = 10
 	}
 
-	if batchCycles, p, l, total, err := h.BatchService.ResolveGrowthBatchCyclePage(batchId, int32(page), int32(limit)); err != nil {
+	//every relation is loaded unless ?include=feeding,deaths,cutoff narrows them
+	include := batch.CycleIncludes
+	if values, ok := q["include"]; ok {
+		include = parseInclude(values[0])
+	}
+
+	if format := utils.ExportFormat(c); format != "" {
+		h.exportGrowthBatchCycle(c, format, batchId)
+	} else if batchCycles, p, l, total, err := h.BatchService.ResolveGrowthBatchCyclePage(batchId, int32(page), int32(limit), include); err != nil {
 		utils.Error(c, err)
 	} else {
 		utils.Page(c, batchCycles, p, l, total)
@@ -331,7 +753,21 @@ func (h *BatchHandler) StoreGrowthBatchCycle(c *gin.Context) {
 	var cid = c.Params.ByName("cycleId")
 
 	var bc batch.BatchCycle
-	c.BindJSON(&bc)
+	body, err := c.GetRawData()
+	if err != nil {
+		utils.Error(c, err)
+		return
+	} else if err := json.Unmarshal(body, &bc); err != nil {
+		utils.Error(c, err)
+		return
+	}
+	//an update keeps the seed source it has unless seed_source is sent as null
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(body, &fields) == nil {
+		if value, ok := fields["seed_source"]; ok && strings.TrimSpace(string(value)) == "null" {
+			bc.ClearSeedSource = true
+		}
+	}
 	if bid == "" {
 		utils.Error(c, fmt.Errorf("Invalid batch id."))
 	} else if cid == "" {
@@ -363,6 +799,7 @@ func (h *BatchHandler) StoreGrowthBatchCycle(c *gin.Context) {
 			return
 		}
 
+		bc.BatchID = batchId
 		if bc.Batch.ID != batchId {
 			utils.Error(c, fmt.Errorf("Inconsistent ID."))
 		} else if bc.ID != cycleId {
@@ -378,10 +815,50 @@ func (h *BatchHandler) StoreGrowthBatchCycle(c *gin.Context) {
 	}
 }
 
-//growth batch cycle death
-func (h *BatchHandler) StoreGrowthDeath(c *gin.Context) {
-	var bid = c.Params.ByName("batchId")
-	var cid = c.Params.ByName("cycleId")
+//growth batch cycle sampling
+func (h *BatchHandler) ResolveGrowthSamplingByBatchCycleID(c *gin.Context) {
+	bid := c.Params.ByName("batchId")
+	cid := c.Params.ByName("cycleId")
+
+	if batchId, err := uuid.FromString(bid); err != nil {
+		utils.Error(c, err)
+	} else if cycleId, err := uuid.FromString(cid); err != nil {
+		utils.Error(c, err)
+	} else if samplings, err := h.BatchService.ResolveGrowthSamplingByBatchCycleID(batchId, cycleId); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Ok(c, samplings)
+	}
+	return
+}
+
+func (h *BatchHandler) StoreGrowthSampling(c *gin.Context) {
+	var bid = c.Params.ByName("batchId")
+	var cid = c.Params.ByName("cycleId")
+
+	var sampling batch.Sampling
+	c.BindJSON(&sampling)
+
+	if sampling.SampleSize <= 0 || sampling.AverageWeight <= 0 || sampling.SamplingDate.IsZero() {
+		utils.Error(c, fmt.Errorf("Incomplete data."))
+	} else if batchId, err := uuid.FromString(bid); err != nil {
+		utils.Error(c, err)
+	} else if cycleId, err := uuid.FromString(cid); err != nil {
+		utils.Error(c, err)
+	} else if sampling.BatchCycleID != cycleId {
+		utils.Error(c, fmt.Errorf("Inconsistent cycle id."))
+	} else if result, err := h.BatchService.StoreGrowthSampling(batchId, &sampling); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Created(c, &result)
+	}
+	return
+}
+
+//growth batch cycle death
+func (h *BatchHandler) StoreGrowthDeath(c *gin.Context) {
+	var bid = c.Params.ByName("batchId")
+	var cid = c.Params.ByName("cycleId")
 
 	var bcd batch.Death
 	c.BindJSON(&bcd)
@@ -392,6 +869,8 @@ func (h *BatchHandler) StoreGrowthDeath(c *gin.Context) {
 		utils.Error(c, fmt.Errorf("Invalid cycle id."))
 	} else if bcd.Amount == 0 || bcd.Weight == 0 {
 		utils.Error(c, fmt.Errorf("Incomplete data."))
+	} else if bcd.Cause.ID == uuid.Nil {
+		utils.Error(c, fmt.Errorf("Death cause is required."))
 	} else if _, err := uuid.FromString(bid); err != nil {
 		utils.Error(c, err)
 	} else if cycleId, err := uuid.FromString(cid); err != nil {
@@ -406,6 +885,176 @@ func (h *BatchHandler) StoreGrowthDeath(c *gin.Context) {
 	return
 }
 
+//growth death cause
+func (h *BatchHandler) ResolveGrowthDeathCause(c *gin.Context) {
+	//capture something like this: http://localhost:9090/growth/death-cause?deleted=0
+	d := c.Request.URL.Query().Get("deleted")
+
+	if d != batch.Deleted_Any && d != batch.Deleted_False && d != batch.Deleted_True {
+		utils.Error(c, fmt.Errorf("Unknown deleted status"))
+	} else if causes, err := h.BatchService.ResolveGrowthDeathCause(d); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Ok(c, causes)
+	}
+	return
+}
+
+func (h *BatchHandler) ResolveGrowthDeathCauseByID(c *gin.Context) {
+	id := c.Params.ByName("causeId")
+	uid, err := uuid.FromString(id)
+
+	if err != nil {
+		utils.Error(c, err)
+	} else if cause, err := h.BatchService.ResolveGrowthDeathCauseByID(uid); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Ok(c, cause)
+	}
+	return
+}
+
+func (h *BatchHandler) StoreGrowthDeathCause(c *gin.Context) {
+	var id = c.Params.ByName("causeId")
+	var cause batch.DeathCause
+	c.BindJSON(&cause)
+
+	if cause.Name == "" {
+		utils.Error(c, fmt.Errorf("Incomplete provided data."))
+	} else if id == "" {
+		if result, err := h.BatchService.StoreGrowthDeathCause(&cause); err != nil {
+			utils.Error(c, err)
+		} else {
+			utils.Created(c, &result)
+		}
+	} else if uid, err := uuid.FromString(id); err != nil {
+		utils.Error(c, fmt.Errorf("Unable to convert given ID to UUID"))
+	} else if cause.ID != uid {
+		utils.Error(c, fmt.Errorf("Inconsistent ID."))
+	} else if result, err := h.BatchService.StoreGrowthDeathCause(&cause); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Ok(c, &result)
+	}
+	return
+}
+
+func (h *BatchHandler) RemoveGrowthDeathCauseByID(c *gin.Context) {
+	id := c.Params.ByName("causeId")
+	uid, err := uuid.FromString(id)
+
+	if err != nil {
+		utils.Error(c, err)
+	} else if _, err := h.BatchService.RemoveGrowthDeathCauseByID(uid); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.NoContent(c)
+	}
+	return
+}
+
+//growth mortality report
+func (h *BatchHandler) ResolveGrowthMortalityReport(c *gin.Context) {
+	//capture something like this: http://localhost:9090/growth/reports/mortality?from=2018-01-01&to=2018-03-31&batch_id=&pool_id=
+	q := c.Request.URL.Query()
+	batchId := uuid.Nil
+	poolId := uuid.Nil
+	if b := q.Get("batch_id"); b != "" {
+		if id, err := uuid.FromString(b); err != nil {
+			utils.Error(c, fmt.Errorf("Invalid batch id."))
+			return
+		} else {
+			batchId = id
+		}
+	}
+	if p := q.Get("pool_id"); p != "" {
+		if id, err := uuid.FromString(p); err != nil {
+			utils.Error(c, fmt.Errorf("Invalid pool id."))
+			return
+		} else {
+			poolId = id
+		}
+	}
+
+	if from, to, err := parseDateRange(c); err != nil {
+		utils.Error(c, err)
+	} else if report, err := h.BatchService.ResolveGrowthMortalityReport(from, to, batchId, poolId); err != nil {
+		utils.Error(c, err)
+	} else if format := utils.ExportFormat(c); format != "" {
+		exportMortalityReport(c, format, report)
+	} else {
+		utils.Ok(c, report)
+	}
+	return
+}
+
+//growth cycle report
+func (h *BatchHandler) ResolveGrowthCycleReport(c *gin.Context) {
+	//capture something like this: http://localhost:9090/growth/reports/cycles?from=2018-01-01&to=2018-12-31&batch_id=
+	batchId := uuid.Nil
+	if b := c.Request.URL.Query().Get("batch_id"); b != "" {
+		if id, err := uuid.FromString(b); err != nil {
+			utils.Error(c, fmt.Errorf("Invalid batch id."))
+			return
+		} else {
+			batchId = id
+		}
+	}
+
+	if from, to, err := parseDateRange(c); err != nil {
+		utils.Error(c, err)
+	} else if reports, err := h.BatchService.ResolveGrowthCycleReport(from, to, batchId); err != nil {
+		utils.Error(c, err)
+	} else if format := utils.ExportFormat(c); format != "" {
+		exportCycleReport(c, format, reports)
+	} else {
+		utils.Ok(c, reports)
+	}
+	return
+}
+
+//growth sales
+func (h *BatchHandler) ResolveGrowthSales(c *gin.Context) {
+	//capture something like this: http://localhost:9090/growth/sales?from=2018-01-01&to=2018-01-31
+	//exports list every sold detail line of the period
+	if from, to, err := parseDateRange(c); err != nil {
+		utils.Error(c, err)
+	} else if format := utils.ExportFormat(c); format != "" {
+		h.exportGrowthSales(c, format, from, to)
+	} else if sales, err := h.BatchService.ResolveGrowthSales(from, to); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Ok(c, sales)
+	}
+	return
+}
+
+//growth forecast
+func (h *BatchHandler) ResolveGrowthForecast(c *gin.Context) {
+	//capture something like this: http://localhost:9090/growth/forecast?from=2018-01-01&to=2018-03-31
+	//without a period it looks eight weeks ahead from today
+	q := c.Request.URL.Query()
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	to := from.AddDate(0, 0, 55)
+	if q.Get("from") != "" || q.Get("to") != "" {
+		var err error
+		if from, to, err = parseDateRange(c); err != nil {
+			utils.Error(c, err)
+			return
+		}
+	}
+
+	if forecast, err := h.BatchService.ResolveGrowthForecast(from, to); err != nil {
+		utils.Error(c, err)
+	} else if format := utils.ExportFormat(c); format != "" {
+		exportForecast(c, format, forecast)
+	} else {
+		utils.Ok(c, forecast)
+	}
+	return
+}
+
 //growth batch cycle feeding
 func (h *BatchHandler) StoreGrowthFeeding(c *gin.Context) {
 	var bid = c.Params.ByName("batchId")
@@ -535,13 +1184,180 @@ func (h *BatchHandler) StoreGrowthSalesDetail(c *gin.Context) {
 	return
 }
 
+//growth sales trace
+func (h *BatchHandler) ResolveGrowthSalesTraceBySalesID(c *gin.Context) {
+	id := c.Params.ByName("salesId")
+
+	if salesId, err := uuid.FromString(id); err != nil {
+		utils.Error(c, err)
+	} else if traces, err := h.BatchService.ResolveGrowthSalesTraceBySalesID(salesId); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Ok(c, traces)
+	}
+	return
+}
+
+//growth batch cycle cost
+func (h *BatchHandler) ResolveGrowthCostByBatchCycleID(c *gin.Context) {
+	var bid = c.Params.ByName("batchId")
+	var cid = c.Params.ByName("cycleId")
+
+	if batchId, err := uuid.FromString(bid); err != nil {
+		utils.Error(c, err)
+	} else if cycleId, err := uuid.FromString(cid); err != nil {
+		utils.Error(c, err)
+	} else if result, err := h.BatchService.ResolveGrowthCostByBatchCycleID(batchId, cycleId); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Ok(c, result)
+	}
+	return
+}
+
+func (h *BatchHandler) StoreGrowthCost(c *gin.Context) {
+	var bid = c.Params.ByName("batchId")
+	var cid = c.Params.ByName("cycleId")
+
+	var cost batch.Cost
+	c.BindJSON(&cost)
+
+	if bid == "" {
+		utils.Error(c, fmt.Errorf("Invalid batch id."))
+	} else if cid == "" {
+		utils.Error(c, fmt.Errorf("Invalid cycle id."))
+	} else if cost.Amount == 0 || cost.Description == "" {
+		utils.Error(c, fmt.Errorf("Incomplete data."))
+	} else if batchId, err := uuid.FromString(bid); err != nil {
+		utils.Error(c, err)
+	} else if cycleId, err := uuid.FromString(cid); err != nil {
+		utils.Error(c, err)
+	} else if cost.BatchCycleID != cycleId {
+		utils.Error(c, fmt.Errorf("Inconsistent cycle id."))
+	} else if result, err := h.BatchService.StoreGrowthCost(batchId, &cost); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Created(c, &result)
+	}
+	return
+}
+
+//growth batch cycle treatment
+func (h *BatchHandler) ResolveGrowthTreatmentByBatchCycleID(c *gin.Context) {
+	bid := c.Params.ByName("batchId")
+	cid := c.Params.ByName("cycleId")
+
+	if batchId, err := uuid.FromString(bid); err != nil {
+		utils.Error(c, err)
+	} else if cycleId, err := uuid.FromString(cid); err != nil {
+		utils.Error(c, err)
+	} else if result, err := h.BatchService.ResolveGrowthTreatmentByBatchCycleID(batchId, cycleId); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Ok(c, result)
+	}
+	return
+}
+
+func (h *BatchHandler) StoreGrowthTreatment(c *gin.Context) {
+	var bid = c.Params.ByName("batchId")
+	var cid = c.Params.ByName("cycleId")
+	var tid = c.Params.ByName("treatmentId")
+
+	var treatment batch.Treatment
+	c.BindJSON(&treatment)
+
+	if tid != "" {
+		if treatmentId, err := uuid.FromString(tid); err != nil {
+			utils.Error(c, err)
+			return
+		} else {
+			treatment.ID = treatmentId
+		}
+	}
+
+	if treatment.Product == "" || treatment.Dose == 0 || treatment.Unit == "" || treatment.StartDate.IsZero() {
+		utils.Error(c, fmt.Errorf("Incomplete data."))
+	} else if batchId, err := uuid.FromString(bid); err != nil {
+		utils.Error(c, err)
+	} else if cycleId, err := uuid.FromString(cid); err != nil {
+		utils.Error(c, err)
+	} else if treatment.BatchCycleID != cycleId {
+		utils.Error(c, fmt.Errorf("Inconsistent cycle id."))
+	} else if result, err := h.BatchService.StoreGrowthTreatment(batchId, &treatment); err != nil {
+		utils.Error(c, err)
+	} else if tid == "" {
+		utils.Created(c, &result)
+	} else {
+		utils.Ok(c, &result)
+	}
+	return
+}
+
+func (h *BatchHandler) StoreGrowthTreatmentOverride(c *gin.Context) {
+	var bid = c.Params.ByName("batchId")
+	var cid = c.Params.ByName("cycleId")
+	var tid = c.Params.ByName("treatmentId")
+
+	var override batch.TreatmentOverride
+	c.BindJSON(&override)
+
+	if override.AuthorizedBy == "" || override.Reason == "" {
+		utils.Error(c, fmt.Errorf("Override must state who authorized it and why."))
+	} else if batchId, err := uuid.FromString(bid); err != nil {
+		utils.Error(c, err)
+	} else if cycleId, err := uuid.FromString(cid); err != nil {
+		utils.Error(c, err)
+	} else if treatmentId, err := uuid.FromString(tid); err != nil {
+		utils.Error(c, err)
+	} else {
+		override.BatchCycleID = cycleId
+		override.TreatmentID = treatmentId
+		if result, err := h.BatchService.StoreGrowthTreatmentOverride(batchId, &override); err != nil {
+			utils.Error(c, err)
+		} else {
+			utils.Created(c, &result)
+		}
+	}
+	return
+}
+
+//growth batch cycle profit and loss
+func (h *BatchHandler) ResolveGrowthBatchCycleProfitAndLoss(c *gin.Context) {
+	bid := c.Params.ByName("batchId")
+	cid := c.Params.ByName("cycleId")
+
+	if batchId, err := uuid.FromString(bid); err != nil {
+		utils.Error(c, err)
+	} else if cycleId, err := uuid.FromString(cid); err != nil {
+		utils.Error(c, err)
+	} else if result, err := h.BatchService.ResolveGrowthBatchCycleProfitAndLoss(batchId, cycleId); err != nil {
+		utils.Error(c, err)
+	} else if format := utils.ExportFormat(c); format != "" {
+		exportProfitAndLoss(c, format, result)
+	} else {
+		utils.Ok(c, result)
+	}
+	return
+}
+
 //feedtype
 func (h *FeedHandler) ResolveFeedTypePage(c *gin.Context) {
-	//capture something like this: http://localhost:9090/feed/feed-type?page=1&limit=10
+	//capture something like this: http://localhost:9090/feed/feed-type?page=1&limit=10&stage=grower&pellet_size=2
 	q := c.Request.URL.Query()
 	p := q.Get("page")
 	l := q.Get("limit")
 	d := q.Get("deleted")
+	stage := q.Get("stage")
+	var pelletSize null.Float
+	if ps := q.Get("pellet_size"); ps != "" {
+		if size, err := strconv.ParseFloat(ps, 64); err != nil {
+			utils.Error(c, fmt.Errorf("Invalid pellet size."))
+			return
+		} else {
+			pelletSize = null.FloatFrom(size)
+		}
+	}
 	page, err := strconv.Atoi(p)
 	if err != nil {
 		page = 0
@@ -552,7 +1368,11 @@ func (h *FeedHandler) ResolveFeedTypePage(c *gin.Context) {
 	}
 	if d != feed.Deleted_Any && d != feed.Deleted_False && d != feed.Deleted_True {
 		utils.Error(c, fmt.Errorf("Unknown deleted status"))
-	} else if feedtypes, p, l, total, err := h.FeedService.ResolveFeedTypePage(int32(page), int32(limit), d); err != nil {
+	} else if stage != "" && stage != feed.Feed_Stage_Starter && stage != feed.Feed_Stage_Grower && stage != feed.Feed_Stage_Finisher {
+		utils.Error(c, fmt.Errorf("Unknown feed stage"))
+	} else if format := utils.ExportFormat(c); format != "" {
+		h.exportFeedType(c, format, d)
+	} else if feedtypes, p, l, total, err := h.FeedService.ResolveFeedTypePage(int32(page), int32(limit), d, stage, pelletSize); err != nil {
 		utils.Error(c, err)
 	} else {
 		utils.Page(c, feedtypes, p, l, total)
@@ -609,37 +1429,92 @@ func (h *FeedHandler) StoreFeedType(c *gin.Context) {
 	}
 }
 
-func (h *FeedHandler) RemoveFeedTypeByID(c *gin.Context) {
+//feed type unit
+func (h *FeedHandler) ResolveFeedTypeUnitByFeedTypeID(c *gin.Context) {
 	id := c.Params.ByName("id")
 	uid, err := uuid.FromString(id)
 
 	if err != nil {
 		utils.Error(c, err)
-	} else if _, err := h.FeedService.RemoveFeedTypeByID(uid); err != nil {
+	} else if units, err := h.FeedService.ResolveFeedTypeUnitByFeedTypeID(uid); err != nil {
 		utils.Error(c, err)
 	} else {
-		utils.NoContent(c)
+		utils.Ok(c, units)
 	}
 	return
 }
 
-func (h *FeedHandler) RemoveFeedTypeByIDs(c *gin.Context) {
-	//process json like : {"ids":["0b86bef7-0e16-47e6-9463-6a0b583e8d4c","6be6e63c-18f3-48ce-831f-f3210a576945"]}
-	var ids []uuid.UUID
-	reqBody := new(UUIDRequestModel)
-	err := c.Bind(reqBody)
+func (h *FeedHandler) StoreFeedTypeUnit(c *gin.Context) {
+	id := c.Params.ByName("id")
+	uid, err := uuid.FromString(id)
 	if err != nil {
+		utils.Error(c, fmt.Errorf("Unable to convert given ID to UUID"))
+		return
+	}
+
+	var unit feed.FeedTypeUnit
+	c.BindJSON(&unit)
+	unit.FeedTypeID = uid
+	if unit.Unit == "" || unit.Factor <= 0 {
+		utils.Error(c, fmt.Errorf("Incomplete provided data."))
+	} else if result, err := h.FeedService.StoreFeedTypeUnit(&unit); err != nil {
 		utils.Error(c, err)
-	} else if len(reqBody.Data) < 1 {
-		utils.Error(c, fmt.Errorf("No Feed Types to be removed."))
 	} else {
-		for _, v := range reqBody.Data {
-			//convert to UUID
-			id, err := uuid.FromString(v)
-			if err != nil {
-				utils.Error(c, err)
-				return
-			} else {
+		utils.Ok(c, &result)
+	}
+	return
+}
+
+func (h *FeedHandler) RemoveFeedTypeUnitByID(c *gin.Context) {
+	id := c.Params.ByName("id")
+	feedTypeId, err := uuid.FromString(id)
+	if err != nil {
+		utils.Error(c, err)
+		return
+	}
+
+	uid, err := uuid.FromString(c.Params.ByName("unitId"))
+	if err != nil {
+		utils.Error(c, err)
+	} else if _, err := h.FeedService.RemoveFeedTypeUnitByID(feedTypeId, uid); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.NoContent(c)
+	}
+	return
+}
+
+func (h *FeedHandler) RemoveFeedTypeByID(c *gin.Context) {
+	id := c.Params.ByName("id")
+	uid, err := uuid.FromString(id)
+
+	if err != nil {
+		utils.Error(c, err)
+	} else if _, err := h.FeedService.RemoveFeedTypeByID(uid); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.NoContent(c)
+	}
+	return
+}
+
+func (h *FeedHandler) RemoveFeedTypeByIDs(c *gin.Context) {
+	//process json like : {"ids":["0b86bef7-0e16-47e6-9463-6a0b583e8d4c","6be6e63c-18f3-48ce-831f-f3210a576945"]}
+	var ids []uuid.UUID
+	reqBody := new(UUIDRequestModel)
+	err := c.Bind(reqBody)
+	if err != nil {
+		utils.Error(c, err)
+	} else if len(reqBody.Data) < 1 {
+		utils.Error(c, fmt.Errorf("No Feed Types to be removed."))
+	} else {
+		for _, v := range reqBody.Data {
+			//convert to UUID
+			id, err := uuid.FromString(v)
+			if err != nil {
+				utils.Error(c, err)
+				return
+			} else {
 				ids = append(ids, id)
 			}
 		}
@@ -655,6 +1530,170 @@ func (h *FeedHandler) RemoveFeedTypeByIDs(c *gin.Context) {
 	return
 }
 
+func (h *FeedHandler) ResolveFeedTypeCostByFeedTypeID(c *gin.Context) {
+	id := c.Params.ByName("id")
+	uid, err := uuid.FromString(id)
+
+	if err != nil {
+		utils.Error(c, err)
+	} else if costs, err := h.FeedService.ResolveFeedTypeCostByFeedTypeID(uid); err != nil {
+		utils.Error(c, err)
+	} else if format := utils.ExportFormat(c); format != "" {
+		exportFeedTypeCost(c, format, costs)
+	} else {
+		utils.Ok(c, costs)
+	}
+	return
+}
+
+//supplier
+func (h *FeedHandler) ResolveSupplierPage(c *gin.Context) {
+	//capture something like this: http://localhost:9090/feed/supplier?page=1&limit=10
+	q := c.Request.URL.Query()
+	p := q.Get("page")
+	l := q.Get("limit")
+	d := q.Get("deleted")
+	page, err := strconv.Atoi(p)
+	if err != nil {
+		page = 0
+	}
+	limit, err := strconv.Atoi(l)
+	if err != nil {
+		limit = 10
+	}
+	if d != feed.Deleted_Any && d != feed.Deleted_False && d != feed.Deleted_True {
+		utils.Error(c, fmt.Errorf("Unknown deleted status"))
+	} else if suppliers, p, l, total, err := h.FeedService.ResolveSupplierPage(int32(page), int32(limit), d); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Page(c, suppliers, p, l, total)
+	}
+	return
+}
+
+func (h *FeedHandler) ResolveSupplierByID(c *gin.Context) {
+	id := c.Params.ByName("id")
+	uid, err := uuid.FromString(id)
+
+	if err != nil {
+		utils.Error(c, err)
+	} else if supplier, err := h.FeedService.ResolveSupplierByID(uid); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Ok(c, &supplier)
+	}
+	return
+}
+
+func (h *FeedHandler) StoreSupplier(c *gin.Context) {
+
+	var id = c.Params.ByName("id")
+	var supplier feed.Supplier
+	c.BindJSON(&supplier)
+
+	if id == "" {
+		if supplier.Name == "" {
+			utils.Error(c, fmt.Errorf("Incomplete provided data."))
+		} else if result, err := h.FeedService.StoreSupplier(&supplier); err != nil {
+			utils.Error(c, err)
+		} else {
+			utils.Created(c, &result)
+		}
+		return
+	} else {
+		//convert id to UUID
+		//compare uuid to Supplier.ID
+		//save if valid
+		var uid, err = uuid.FromString(id)
+		if err != nil {
+			utils.Error(c, fmt.Errorf("Unable to convert given ID to UUID"))
+		} else if supplier.ID != uid {
+			utils.Error(c, fmt.Errorf("Inconsistent ID."))
+		} else if supplier.Name == "" {
+			utils.Error(c, fmt.Errorf("Incomplete provided data."))
+		} else if result, err := h.FeedService.StoreSupplier(&supplier); err != nil {
+			utils.Error(c, err)
+		} else {
+			utils.Ok(c, &result)
+		}
+		return
+	}
+}
+
+func (h *FeedHandler) RemoveSupplierByID(c *gin.Context) {
+	id := c.Params.ByName("id")
+	uid, err := uuid.FromString(id)
+
+	if err != nil {
+		utils.Error(c, err)
+	} else if _, err := h.FeedService.RemoveSupplierByID(uid); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.NoContent(c)
+	}
+	return
+}
+
+func (h *FeedHandler) RemoveSupplierByIDs(c *gin.Context) {
+	//process json like : {"ids":["0b86bef7-0e16-47e6-9463-6a0b583e8d4c","6be6e63c-18f3-48ce-831f-f3210a576945"]}
+	var ids []uuid.UUID
+	reqBody := new(UUIDRequestModel)
+	err := c.Bind(reqBody)
+	if err != nil {
+		utils.Error(c, err)
+	} else if len(reqBody.Data) < 1 {
+		utils.Error(c, fmt.Errorf("No Suppliers to be removed."))
+	} else {
+		for _, v := range reqBody.Data {
+			//convert to UUID
+			id, err := uuid.FromString(v)
+			if err != nil {
+				utils.Error(c, err)
+				return
+			} else {
+				ids = append(ids, id)
+			}
+		}
+		//process to services
+		_, err := h.FeedService.RemoveSupplierByIDs(ids)
+		if err != nil {
+			utils.Error(c, err)
+		} else {
+			utils.NoContent(c)
+		}
+		return
+	}
+	return
+}
+
+func (h *FeedHandler) ResolveSupplierPurchase(c *gin.Context) {
+	//capture something like this: http://localhost:9090/feed/reports/supplier-purchase?from=2018-01-01&to=2018-01-31
+	if from, to, err := parseDateRange(c); err != nil {
+		utils.Error(c, err)
+	} else if purchases, err := h.FeedService.ResolveSupplierPurchase(from, to); err != nil {
+		utils.Error(c, err)
+	} else if format := utils.ExportFormat(c); format != "" {
+		exportSupplierPurchase(c, format, purchases)
+	} else {
+		utils.Ok(c, purchases)
+	}
+	return
+}
+
+func (h *FeedHandler) ResolveSupplierPerformance(c *gin.Context) {
+	//capture something like this: http://localhost:9090/feed/reports/supplier-performance?from=2018-01-01&to=2018-12-31
+	if from, to, err := parseDateRange(c); err != nil {
+		utils.Error(c, err)
+	} else if performances, err := h.FeedService.ResolveSupplierPerformance(from, to); err != nil {
+		utils.Error(c, err)
+	} else if format := utils.ExportFormat(c); format != "" {
+		exportSupplierPerformance(c, format, performances)
+	} else {
+		utils.Ok(c, performances)
+	}
+	return
+}
+
 //feed incoming
 func (h *FeedHandler) ResolveFeedIncomingPage(c *gin.Context) {
 	//capture something like this: http://localhost:9090/feed/incoming?page=1&limit=10
@@ -670,7 +1709,9 @@ func (h *FeedHandler) ResolveFeedIncomingPage(c *gin.Context) {
 		limit = 10
 	}
 
-	if feeds, p, l, total, err := h.FeedService.ResolveFeedIncomingPage(int32(page), int32(limit)); err != nil {
+	if format := utils.ExportFormat(c); format != "" {
+		h.exportFeedIncoming(c, format)
+	} else if feeds, p, l, total, err := h.FeedService.ResolveFeedIncomingPage(int32(page), int32(limit)); err != nil {
 		utils.Error(c, err)
 	} else {
 		utils.Page(c, feeds, p, l, total)
@@ -698,6 +1739,8 @@ func (h *FeedHandler) StoreFeedIncoming(c *gin.Context) {
 	c.BindJSON(&f)
 	if f.Qty == 0 {
 		utils.Error(c, fmt.Errorf("Qty must smaller or bigger than 0"))
+	} else if f.Price < 0 {
+		utils.Error(c, fmt.Errorf("Price cannot be negative"))
 	} else if result, err := h.FeedService.StoreFeedIncoming(&f); err != nil {
 		utils.Error(c, err)
 	} else {
@@ -706,6 +1749,56 @@ func (h *FeedHandler) StoreFeedIncoming(c *gin.Context) {
 	return
 }
 
+//feed alert
+func (h *FeedHandler) ResolveFeedAlert(c *gin.Context) {
+	//capture something like this: http://localhost:9090/feed/alerts?horizon=14&window=14
+	q := c.Request.URL.Query()
+	horizon, err := strconv.Atoi(q.Get("horizon"))
+	if err != nil || horizon < 0 {
+		horizon = h.Config.FeedAlertHorizon
+	}
+	window, err := strconv.Atoi(q.Get("window"))
+	if err != nil || window < 1 {
+		window = h.Config.FeedAlertWindow
+	}
+	if alerts, err := h.FeedService.ResolveFeedAlert(horizon, window); err != nil {
+		utils.Error(c, err)
+	} else if format := utils.ExportFormat(c); format != "" {
+		exportFeedAlert(c, format, alerts)
+	} else {
+		utils.Ok(c, alerts)
+	}
+	return
+}
+
+//feed lot
+func (h *FeedHandler) ResolveFeedLot(c *gin.Context) {
+	//capture something like this: http://localhost:9090/feed/lots?expiring_within=30d
+	q := c.Request.URL.Query()
+	var expiredBefore null.Time
+	if w := q.Get("expiring_within"); w != "" {
+		//accept days like 30d or 30, or any duration like 72h
+		var within time.Duration
+		if days, err := strconv.Atoi(strings.TrimSuffix(w, "d")); err == nil {
+			within = time.Duration(days) * 24 * time.Hour
+		} else if duration, err := time.ParseDuration(w); err == nil {
+			within = duration
+		} else {
+			utils.Error(c, fmt.Errorf("Invalid expiring_within, use days like 30d."))
+			return
+		}
+		expiredBefore = null.TimeFrom(time.Now().Add(within))
+	}
+	if lots, err := h.FeedService.ResolveFeedLot(expiredBefore); err != nil {
+		utils.Error(c, err)
+	} else if format := utils.ExportFormat(c); format != "" {
+		exportFeedLot(c, format, lots)
+	} else {
+		utils.Ok(c, lots)
+	}
+	return
+}
+
 //feed adjustment
 func (h *FeedHandler) ResolveFeedAdjustmentPage(c *gin.Context) {
 	//capture something like this: http://localhost:9090/feed/feeding?page=1&limit=10
@@ -721,7 +1814,9 @@ func (h *FeedHandler) ResolveFeedAdjustmentPage(c *gin.Context) {
 		limit = 10
 	}
 
-	if feedAdjustments, p, l, total, err := h.FeedService.ResolveFeedAdjustmentPage(int32(page), int32(limit)); err != nil {
+	if format := utils.ExportFormat(c); format != "" {
+		h.exportFeedAdjustment(c, format)
+	} else if feedAdjustments, p, l, total, err := h.FeedService.ResolveFeedAdjustmentPage(int32(page), int32(limit)); err != nil {
 		utils.Error(c, err)
 	} else {
 		utils.Page(c, feedAdjustments, p, l, total)
@@ -757,3 +1852,175 @@ func (h *FeedHandler) StoreFeedAdjustment(c *gin.Context) {
 	}
 	return
 }
+
+//stocktake
+func (h *FeedHandler) ResolveStocktakePage(c *gin.Context) {
+	//capture something like this: http://localhost:9090/feed/stocktake?page=1&limit=10
+	q := c.Request.URL.Query()
+	p := q.Get("page")
+	l := q.Get("limit")
+	page, err := strconv.Atoi(p)
+	if err != nil {
+		page = 0
+	}
+	limit, err := strconv.Atoi(l)
+	if err != nil {
+		limit = 10
+	}
+
+	if stocktakes, p, l, total, err := h.FeedService.ResolveStocktakePage(int32(page), int32(limit)); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Page(c, stocktakes, p, l, total)
+	}
+	return
+}
+
+func (h *FeedHandler) ResolveStocktakeByID(c *gin.Context) {
+	id := c.Params.ByName("id")
+	uid, err := uuid.FromString(id)
+
+	if err != nil {
+		utils.Error(c, err)
+	} else if stocktake, err := h.FeedService.ResolveStocktakeByID(uid); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Ok(c, &stocktake)
+	}
+	return
+}
+
+func (h *FeedHandler) StoreStocktake(c *gin.Context) {
+
+	var id = c.Params.ByName("id")
+	var stocktake feed.Stocktake
+	c.BindJSON(&stocktake)
+
+	if id == "" {
+		if result, err := h.FeedService.StoreStocktake(&stocktake); err != nil {
+			utils.Error(c, err)
+		} else {
+			utils.Created(c, &result)
+		}
+		return
+	} else {
+		var uid, err = uuid.FromString(id)
+		if err != nil {
+			utils.Error(c, fmt.Errorf("Unable to convert given ID to UUID"))
+		} else if stocktake.ID != uid {
+			utils.Error(c, fmt.Errorf("Inconsistent ID."))
+		} else if result, err := h.FeedService.StoreStocktake(&stocktake); err != nil {
+			utils.Error(c, err)
+		} else {
+			utils.Ok(c, &result)
+		}
+		return
+	}
+}
+
+func (h *FeedHandler) StoreStocktakeCount(c *gin.Context) {
+	id := c.Params.ByName("id")
+	uid, err := uuid.FromString(id)
+	if err != nil {
+		utils.Error(c, fmt.Errorf("Unable to convert given ID to UUID"))
+		return
+	}
+
+	var stocktake feed.Stocktake
+	c.BindJSON(&stocktake)
+	if len(stocktake.Detail) < 1 {
+		utils.Error(c, fmt.Errorf("Incomplete provided data."))
+		return
+	}
+	for _, detail := range stocktake.Detail {
+		if detail.FeedType.ID == uuid.Nil {
+			utils.Error(c, fmt.Errorf("Feed type must be provided."))
+			return
+		} else if detail.CountedQty < 0 {
+			utils.Error(c, fmt.Errorf("Counted qty cannot be negative"))
+			return
+		}
+	}
+	if result, err := h.FeedService.StoreStocktakeCount(uid, stocktake.Detail); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Ok(c, &result)
+	}
+	return
+}
+
+func (h *FeedHandler) PreviewStocktakeVariance(c *gin.Context) {
+	id := c.Params.ByName("id")
+	uid, err := uuid.FromString(id)
+
+	if err != nil {
+		utils.Error(c, err)
+	} else if stocktake, err := h.FeedService.PreviewStocktakeVariance(uid); err != nil {
+		utils.Error(c, err)
+	} else if format := utils.ExportFormat(c); format != "" {
+		exportStocktakeVariance(c, format, stocktake)
+	} else {
+		utils.Ok(c, &stocktake)
+	}
+	return
+}
+
+func (h *FeedHandler) ApproveStocktake(c *gin.Context) {
+	id := c.Params.ByName("id")
+	uid, err := uuid.FromString(id)
+
+	if err != nil {
+		utils.Error(c, err)
+	} else if stocktake, err := h.FeedService.ApproveStocktake(uid); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Ok(c, &stocktake)
+	}
+	return
+}
+
+//dashboard
+func (h *DashboardHandler) ResolveDashboard(c *gin.Context) {
+	if result, err := h.DashboardService.ResolveDashboard(time.Now()); err != nil {
+		utils.Error(c, err)
+	} else {
+		utils.Ok(c, result)
+	}
+	return
+}
+
+//import
+func (h *ImportHandler) Import(c *gin.Context) {
+	//capture something like this: http://localhost:9090/import/feedings?dry_run=1
+	entity := c.Params.ByName("entity")
+	dryRun := false
+	if d := c.Request.URL.Query().Get("dry_run"); d != "" {
+		if parsed, err := strconv.ParseBool(d); err != nil {
+			utils.Error(c, fmt.Errorf("Invalid dry_run."))
+			return
+		} else {
+			dryRun = parsed
+		}
+	}
+	//the csv is either a multipart `file` or the whole request body
+	var file io.Reader = c.Request.Body
+	if header, err := c.FormFile("file"); err == nil {
+		if f, err := header.Open(); err != nil {
+			utils.Error(c, err)
+			return
+		} else {
+			defer f.Close()
+			file = f
+		}
+	}
+	if result, err := h.ImportService.Import(entity, file, dryRun); err != nil {
+		utils.Error(c, err)
+	} else if len(result.Errors) > 0 {
+		utils.UnprocessableEntity(c, result)
+	} else if dryRun {
+		utils.Ok(c, result)
+	} else {
+		utils.Created(c, result)
+	}
+	return
+}
@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/livestockz/api/domain/batch"
+	"github.com/livestockz/api/domain/feed"
+	uuid "github.com/satori/go.uuid"
+)
+
+type testServer struct {
+	router   *gin.Engine
+	repo     *batch.MemoryRepository
+	feedRepo *feed.MemoryRepository
+}
+
+//newTestServer serves the routes under test from the in-memory repositories
+func newTestServer() *testServer {
+	gin.SetMode(gin.TestMode)
+	feedRepo := new(feed.MemoryRepository)
+	repo := &batch.MemoryRepository{Feed: feedRepo}
+	feedService := &feed.FeedService{FeedRepository: feedRepo}
+	batchHandler := &BatchHandler{BatchService: &batch.BatchService{BatchRepository: repo, FeedService: feedService}}
+	feedHandler := &FeedHandler{FeedService: feedService}
+
+	router := gin.New()
+	router.POST("/growth/batch/:batchId/cycle/:cycleId/cutoff", batchHandler.StoreGrowthCutOff)
+	router.PUT("/growth/batch/:batchId/cycle/:cycleId", batchHandler.StoreGrowthBatchCycle)
+	router.GET("/growth/batch/:batchId/cycle/:cycleId/cost", batchHandler.ResolveGrowthCostByBatchCycleID)
+	router.POST("/growth/batch/:batchId/cycle/:cycleId/cost", batchHandler.StoreGrowthCost)
+	router.GET("/feed/feed-type/:id", feedHandler.ResolveFeedTypeByID)
+	router.GET("/feed/feed-type/:id/cost-history", feedHandler.ResolveFeedTypeCostByFeedTypeID)
+	return &testServer{router: router, repo: repo, feedRepo: feedRepo}
+}
+
+func (s *testServer) serve(method string, path string, body string) (int, map[string]interface{}) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	return w.Code, response
+}
+
+func TestStoreGrowthCutOff(t *testing.T) {
+	s := newTestServer()
+	b, err := s.repo.InsertGrowthBatch(&batch.Batch{ID: uuid.Must(uuid.NewV4()), Name: "Batch 1", Status: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool, err := s.repo.InsertGrowthPool(&batch.Pool{ID: uuid.Must(uuid.NewV4()), Name: "Pool 1", Status: "active"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cycle, err := s.repo.InsertGrowthBatchCycle(&batch.BatchCycle{ID: uuid.Must(uuid.NewV4()), Batch: *b, BatchID: b.ID, Pool: *pool, Weight: 10, Amount: 1000, Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := fmt.Sprintf("/growth/batch/%s/cycle/%s/cutoff", b.ID, cycle.ID)
+	body := fmt.Sprintf(`{"batch_id":"%s","batch_cycle_id":"%s","summary_date":"2024-03-01T00:00:00Z","weight":40,"amount":900}`, b.ID, cycle.ID)
+
+	if code, _ := s.serve("POST", path, `{"weight":40}`); code != http.StatusInternalServerError {
+		t.Fatalf("incomplete cut off answered %d", code)
+	}
+	code, response := s.serve("POST", path, body)
+	if code != http.StatusOK {
+		t.Fatalf("cut off answered %d with %v", code, response)
+	}
+	data := response["data"].(map[string]interface{})
+	if data["adg"] != 0.5 || data["sr"] != 90.0 {
+		t.Fatalf("cut off answered %v", data)
+	}
+	if code, _ := s.serve("POST", path, body); code != http.StatusInternalServerError {
+		t.Fatalf("second cut off answered %d", code)
+	}
+}
+
+func TestStoreGrowthBatchCycleSeedSource(t *testing.T) {
+	s := newTestServer()
+	b, err := s.repo.InsertGrowthBatch(&batch.Batch{ID: uuid.Must(uuid.NewV4()), Name: "Batch 1", Status: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool, err := s.repo.InsertGrowthPool(&batch.Pool{ID: uuid.Must(uuid.NewV4()), Name: "Pool 1", Status: "active"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hatchery, err := s.repo.InsertGrowthHatchery(&batch.Hatchery{ID: uuid.Must(uuid.NewV4()), Name: "Hatchery 1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	seedSource, err := s.repo.InsertGrowthSeedSource(&batch.SeedSource{ID: uuid.Must(uuid.NewV4()), Hatchery: *hatchery, LotCode: "LOT-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cycle, err := s.repo.InsertGrowthBatchCycle(&batch.BatchCycle{ID: uuid.Must(uuid.NewV4()), Batch: *b, BatchID: b.ID, Pool: *pool, SeedSourceID: uuid.NullUUID{UUID: seedSource.ID, Valid: true}, Weight: 10, Amount: 1000, Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := fmt.Sprintf("/growth/batch/%s/cycle/%s", b.ID, cycle.ID)
+	body := fmt.Sprintf(`{"id":"%s","batch":{"id":"%s"},"pool":{"id":"%s"},"start":"2024-01-01T00:00:00Z","weight":12,"amount":1000`, cycle.ID, b.ID, pool.ID)
+
+	for _, c := range []struct {
+		seedSource string
+		linked     bool
+	}{
+		{"", true},
+		{`,"seed_source":null`, false},
+		{fmt.Sprintf(`,"seed_source":{"id":"%s"}`, seedSource.ID), true},
+	} {
+		if code, response := s.serve("PUT", path, body+c.seedSource+"}"); code != http.StatusOK {
+			t.Fatalf("update with %q answered %d with %v", c.seedSource, code, response)
+		}
+		if stored, err := s.repo.ResolveGrowthBatchCycleByID(b.ID, cycle.ID); err != nil {
+			t.Fatal(err)
+		} else if stored.SeedSourceID.Valid != c.linked || stored.Weight != 12 {
+			t.Fatalf("update with %q stored seed source %v", c.seedSource, stored.SeedSourceID)
+		}
+	}
+	//a cut off body and a mistyped field, the second still decodes into a valid update
+	for _, invalid := range []string{body, body + `,"amount":"1000"}`} {
+		if code, _ := s.serve("PUT", path, invalid); code != http.StatusInternalServerError {
+			t.Fatalf("invalid body %s answered %d", invalid, code)
+		}
+	}
+	if stored, err := s.repo.ResolveGrowthBatchCycleByID(b.ID, cycle.ID); err != nil {
+		t.Fatal(err)
+	} else if !stored.SeedSourceID.Valid {
+		t.Fatal("an invalid body changed the cycle")
+	}
+}
+
+func TestStoreGrowthCostOfOtherBatch(t *testing.T) {
+	s := newTestServer()
+	b, err := s.repo.InsertGrowthBatch(&batch.Batch{ID: uuid.Must(uuid.NewV4()), Name: "Batch 1", Status: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := s.repo.InsertGrowthBatch(&batch.Batch{ID: uuid.Must(uuid.NewV4()), Name: "Batch 2", Status: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool, err := s.repo.InsertGrowthPool(&batch.Pool{ID: uuid.Must(uuid.NewV4()), Name: "Pool 1", Status: "active"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cycle, err := s.repo.InsertGrowthBatchCycle(&batch.BatchCycle{ID: uuid.Must(uuid.NewV4()), Batch: *b, BatchID: b.ID, Pool: *pool, Weight: 10, Amount: 1000, Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := fmt.Sprintf(`{"batch_cycle_id":"%s","cost_date":"2024-02-01T00:00:00Z","description":"Lime","amount":50000}`, cycle.ID)
+
+	if code, _ := s.serve("POST", fmt.Sprintf("/growth/batch/%s/cycle/%s/cost", other.ID, cycle.ID), body); code != http.StatusInternalServerError {
+		t.Fatalf("cost through another batch answered %d", code)
+	}
+	if code, _ := s.serve("GET", fmt.Sprintf("/growth/batch/%s/cycle/%s/cost", other.ID, cycle.ID), ""); code != http.StatusInternalServerError {
+		t.Fatalf("costs through another batch answered %d", code)
+	}
+	if code, response := s.serve("POST", fmt.Sprintf("/growth/batch/%s/cycle/%s/cost", b.ID, cycle.ID), body); code != http.StatusCreated {
+		t.Fatalf("cost answered %d with %v", code, response)
+	}
+	if code, response := s.serve("GET", fmt.Sprintf("/growth/batch/%s/cycle/%s/cost", b.ID, cycle.ID), ""); code != http.StatusOK {
+		t.Fatalf("costs answered %d with %v", code, response)
+	} else if costs, ok := response["data"].([]interface{}); !ok || len(costs) != 1 {
+		t.Fatalf("costs answered %v", response["data"])
+	}
+}
+
+func TestResolveFeedTypeByID(t *testing.T) {
+	s := newTestServer()
+	feedtype, err := s.feedRepo.InsertFeedType(&feed.FeedType{ID: uuid.Must(uuid.NewV4()), Name: "Pellet", Unit: "kg", Status: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.feedRepo.InsertFeedTypeUnit(&feed.FeedTypeUnit{ID: uuid.Must(uuid.NewV4()), FeedTypeID: feedtype.ID, Unit: "sack", Factor: 25}); err != nil {
+		t.Fatal(err)
+	}
+
+	code, response := s.serve("GET", "/feed/feed-type/"+feedtype.ID.String(), "")
+	if code != http.StatusOK {
+		t.Fatalf("feed type answered %d with %v", code, response)
+	}
+	data := response["data"].(map[string]interface{})
+	if units, ok := data["units"].([]interface{}); data["name"] != "Pellet" || !ok || len(units) != 1 {
+		t.Fatalf("feed type answered %v", data)
+	}
+	if code, _ := s.serve("GET", "/feed/feed-type/"+uuid.Must(uuid.NewV4()).String(), ""); code != http.StatusInternalServerError {
+		t.Fatalf("unknown feed type answered %d", code)
+	}
+	if code, _ := s.serve("GET", "/feed/feed-type/pellet", ""); code != http.StatusInternalServerError {
+		t.Fatalf("invalid feed type id answered %d", code)
+	}
+}
+
+func TestResolveFeedTypeCostExport(t *testing.T) {
+	s := newTestServer()
+	feedtype, err := s.feedRepo.InsertFeedType(&feed.FeedType{ID: uuid.Must(uuid.NewV4()), Name: "Pellet", Unit: "kg", Status: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	incoming := &feed.FeedIncoming{ID: uuid.Must(uuid.NewV4()), FeedType: *feedtype, IncomingDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Qty: 100, Price: 10000}
+	if _, err := s.feedRepo.InsertFeedIncoming(incoming, nil); err != nil {
+		t.Fatal(err)
+	}
+	path := "/feed/feed-type/" + feedtype.ID.String() + "/cost-history"
+
+	for _, c := range []struct {
+		query       string
+		accept      string
+		contentType string
+	}{
+		{"?format=csv", "", "text/csv; charset=utf-8"},
+		{"", "text/csv", "text/csv; charset=utf-8"},
+		{"?format=xlsx", "", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"},
+	} {
+		req := httptest.NewRequest("GET", path+c.query, nil)
+		req.Header.Set("Accept", c.accept)
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK || w.Header().Get("Content-Type") != c.contentType {
+			t.Fatalf("%s%s answered %d as %q", path, c.query, w.Code, w.Header().Get("Content-Type"))
+		}
+		if c.contentType != "text/csv; charset=utf-8" {
+			continue
+		} else if lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n"); len(lines) != 2 || !strings.HasPrefix(lines[0], "id,feed_type_id") {
+			t.Fatalf("cost history exported as %q", w.Body.String())
+		}
+	}
+}
@@ -2,14 +2,21 @@ package main
 
 import (
 	"database/sql"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	_ "github.com/go-sql-driver/mysql"
 	"github.com/livestockz/api/config"
+	"github.com/livestockz/api/database"
 	"github.com/livestockz/api/domain/batch"
+	"github.com/livestockz/api/domain/dashboard"
 	"github.com/livestockz/api/domain/feed"
+	"github.com/livestockz/api/domain/importer"
 	"github.com/livestockz/api/handler"
+	"github.com/livestockz/api/telemetry"
 	"github.com/ncrypthic/gocontainer"
 )
 
@@ -17,35 +24,90 @@ func main() {
 	// Setup deps
 	// 1. database
 	cfg := config.Get()
-	db, err := sql.Open("mysql", cfg.DatabaseDSN())
-	if err != nil {
-		panic("Failed connect to database.")
+	var db *sql.DB
+	if cfg.DatabaseDialect != database.Memory {
+		var err error
+		if db, err = database.Open(cfg.DatabaseDialect, cfg.DatabaseDSN()); err != nil {
+			panic("Failed connect to database.")
+		}
+		defer db.Close()
 	}
 
-	defer db.Close()
+	//livestock export, import and migrate run once instead of the api
+	if len(os.Args) > 1 && db == nil {
+		fmt.Fprintln(os.Stderr, "The memory dialect keeps no database to run commands on.")
+		os.Exit(1)
+	} else if len(os.Args) > 1 {
+		if err := runCommand(db, cfg.DatabaseDialect, os.Args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if cfg.AutoMigrate && db != nil {
+		migrations, err := database.Up(db, cfg.DatabaseDialect)
+		if err != nil {
+			panic("Failed to migrate database: " + err.Error())
+		}
+		for _, migration := range migrations {
+			fmt.Printf("applied migration %04d %s\n", migration.Version, migration.Name)
+		}
+	}
 
 	//register Handler
 	batchHandler := new(handler.BatchHandler)
 	feedHandler := new(handler.FeedHandler)
+	dashboardHandler := new(handler.DashboardHandler)
+	importHandler := new(handler.ImportHandler)
 	batchService := new(batch.BatchService)
 	feedService := new(feed.FeedService)
+	ingestor := new(telemetry.Ingestor)
 
 	//register service
 	r := gin.Default()
 	sc := gocontainer.NewContainer()
-	sc.RegisterService("db", db)
 	sc.RegisterService("config", cfg)
 	sc.RegisterService("batchHandler", batchHandler)
 	sc.RegisterService("feedHandler", feedHandler)
 	sc.RegisterService("batchService", batchService)
 	sc.RegisterService("feedService", feedService)
-	sc.RegisterService("batchRepository", new(batch.BatchRepository))
-	sc.RegisterService("feedRepository", new(feed.FeedRepository))
+	if db == nil {
+		//the dashboard and import query the database themselves, they are left
+		//out when batches and feed live in memory
+		feedRepository := feed.NewMemoryRepository()
+		sc.RegisterService("batchRepository", batch.NewMemoryRepository(feedRepository))
+		sc.RegisterService("feedRepository", feedRepository)
+	} else {
+		sc.RegisterService("db", db)
+		sc.RegisterService("dashboardHandler", dashboardHandler)
+		sc.RegisterService("importHandler", importHandler)
+		sc.RegisterService("dashboardService", new(dashboard.DashboardService))
+		sc.RegisterService("importService", new(importer.ImportService))
+		sc.RegisterService("batchRepository", new(batch.BatchRepository))
+		sc.RegisterService("feedRepository", new(feed.FeedRepository))
+		sc.RegisterService("dashboardRepository", new(dashboard.DashboardRepository))
+	}
+	if cfg.MQTTEnabled {
+		sc.RegisterService("telemetryIngestor", ingestor)
+	}
 	sc.HandleGracefulShutdown(3 * time.Second)
 	if err := sc.Ready(); err != nil {
 		//log.Print(err)
 		panic("Failed to start service container")
 	}
+	if cfg.MQTTEnabled {
+		if err := ingestor.Start(); err != nil {
+			panic("Failed to start telemetry ingestion")
+		}
+		//r.Run never returns, stop on a signal so the last batch is flushed
+		go func() {
+			signals := make(chan os.Signal, 1)
+			signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+			<-signals
+			ingestor.Stop()
+			os.Exit(0)
+		}()
+	}
 
 	growth := r.Group("/growth")
 	{
@@ -63,19 +125,72 @@ func main() {
 		growth.PUT("/pool/:poolId", batchHandler.StoreGrowthPool)
 		growth.DELETE("/pool", batchHandler.RemoveGrowthPoolByIDs)
 		growth.DELETE("/pool/:poolId", batchHandler.RemoveGrowthPoolByID)
+		//species
+		growth.GET("/species", batchHandler.ResolveGrowthSpecies)
+		growth.GET("/species/:speciesId", batchHandler.ResolveGrowthSpeciesByID)
+		growth.POST("/species", batchHandler.StoreGrowthSpecies)
+		growth.PUT("/species/:speciesId", batchHandler.StoreGrowthSpecies)
+		growth.DELETE("/species/:speciesId", batchHandler.RemoveGrowthSpeciesByID)
+		//hatchery
+		growth.GET("/hatchery", batchHandler.ResolveGrowthHatcheryPage)
+		growth.GET("/hatchery/:hatcheryId", batchHandler.ResolveGrowthHatcheryByID)
+		growth.POST("/hatchery", batchHandler.StoreGrowthHatchery)
+		growth.PUT("/hatchery/:hatcheryId", batchHandler.StoreGrowthHatchery)
+		growth.DELETE("/hatchery/:hatcheryId", batchHandler.RemoveGrowthHatcheryByID)
+		//seed source
+		growth.GET("/seed-source", batchHandler.ResolveGrowthSeedSourcePage)
+		growth.GET("/seed-source/:seedSourceId", batchHandler.ResolveGrowthSeedSourceByID)
+		growth.POST("/seed-source", batchHandler.StoreGrowthSeedSource)
+		growth.PUT("/seed-source/:seedSourceId", batchHandler.StoreGrowthSeedSource)
+		//pool device
+		growth.GET("/pool/:poolId/device", batchHandler.ResolveGrowthPoolDeviceByPoolID)
+		growth.POST("/pool/:poolId/device", batchHandler.StoreGrowthPoolDevice)
+		growth.DELETE("/pool/:poolId/device/:deviceId", batchHandler.RemoveGrowthPoolDeviceByID)
+		//pool water quality
+		growth.GET("/pool/:poolId/water-quality", batchHandler.ResolveGrowthWaterQualityByPoolID)
+		growth.POST("/pool/:poolId/water-quality", batchHandler.StoreGrowthWaterQuality)
+		growth.GET("/water-quality/range", batchHandler.ResolveGrowthWaterQualityRange)
+		growth.PUT("/water-quality/range", batchHandler.StoreGrowthWaterQualityRange)
 		//batch cycle
 		growth.GET("/batch/:batchId/cycle", batchHandler.ResolveGrowthBatchCyclePage)
 		growth.GET("/batch/:batchId/cycle/:cycleId", batchHandler.ResolveGrowthBatchCycleByID)
 		growth.POST("/batch/:batchId/cycle", batchHandler.StoreGrowthBatchCycle)
 		growth.PUT("/batch/:batchId/cycle/:cycleId", batchHandler.StoreGrowthBatchCycle)
+		//batch cycle sampling
+		growth.GET("/batch/:batchId/cycle/:cycleId/sampling", batchHandler.ResolveGrowthSamplingByBatchCycleID)
+		growth.POST("/batch/:batchId/cycle/:cycleId/sampling", batchHandler.StoreGrowthSampling)
 		//batch cycle death
 		growth.POST("/batch/:batchId/cycle/:cycleId/death", batchHandler.StoreGrowthDeath)
+		//death cause
+		growth.GET("/death-cause", batchHandler.ResolveGrowthDeathCause)
+		growth.GET("/death-cause/:causeId", batchHandler.ResolveGrowthDeathCauseByID)
+		growth.POST("/death-cause", batchHandler.StoreGrowthDeathCause)
+		growth.PUT("/death-cause/:causeId", batchHandler.StoreGrowthDeathCause)
+		growth.DELETE("/death-cause/:causeId", batchHandler.RemoveGrowthDeathCauseByID)
+		//reports
+		growth.GET("/reports/mortality", batchHandler.ResolveGrowthMortalityReport)
+		growth.GET("/reports/hatchery", batchHandler.ResolveGrowthHatcheryPerformance)
+		growth.GET("/reports/cycles", batchHandler.ResolveGrowthCycleReport)
+		//forecast
+		growth.GET("/forecast", batchHandler.ResolveGrowthForecast)
 		//batch cycle feeding
 		growth.POST("/batch/:batchId/cycle/:cycleId/feeding", batchHandler.StoreGrowthFeeding)
 		//batch cycle cut off
 		growth.POST("/batch/:batchId/cycle/:cycleId/cutoff", batchHandler.StoreGrowthCutOff)
+		//batch cycle cost
+		growth.GET("/batch/:batchId/cycle/:cycleId/cost", batchHandler.ResolveGrowthCostByBatchCycleID)
+		growth.POST("/batch/:batchId/cycle/:cycleId/cost", batchHandler.StoreGrowthCost)
+		//batch cycle treatment
+		growth.GET("/batch/:batchId/cycle/:cycleId/treatment", batchHandler.ResolveGrowthTreatmentByBatchCycleID)
+		growth.POST("/batch/:batchId/cycle/:cycleId/treatment", batchHandler.StoreGrowthTreatment)
+		growth.PUT("/batch/:batchId/cycle/:cycleId/treatment/:treatmentId", batchHandler.StoreGrowthTreatment)
+		growth.POST("/batch/:batchId/cycle/:cycleId/treatment/:treatmentId/override", batchHandler.StoreGrowthTreatmentOverride)
+		//batch cycle profit and loss
+		growth.GET("/batch/:batchId/cycle/:cycleId/pnl", batchHandler.ResolveGrowthBatchCycleProfitAndLoss)
 		//batch cycle sales
+		growth.GET("/sales", batchHandler.ResolveGrowthSales)
 		growth.GET("/sales/:salesId", batchHandler.ResolveGrowthSalesByID)
+		growth.GET("/sales/:salesId/trace", batchHandler.ResolveGrowthSalesTraceBySalesID)
 		growth.POST("/sales", batchHandler.StoreGrowthSales)
 		growth.PUT("/sales/:salesId", batchHandler.StoreGrowthSales)
 		//batch cycle sales detail
@@ -90,16 +205,45 @@ func main() {
 		feed.PUT("/feed-type/:id", feedHandler.StoreFeedType)
 		feed.DELETE("/feed-type", feedHandler.RemoveFeedTypeByIDs)
 		feed.DELETE("/feed-type/:id", feedHandler.RemoveFeedTypeByID)
+		feed.GET("/feed-type/:id/cost-history", feedHandler.ResolveFeedTypeCostByFeedTypeID)
+		feed.GET("/feed-type/:id/unit", feedHandler.ResolveFeedTypeUnitByFeedTypeID)
+		feed.POST("/feed-type/:id/unit", feedHandler.StoreFeedTypeUnit)
+		feed.DELETE("/feed-type/:id/unit/:unitId", feedHandler.RemoveFeedTypeUnitByID)
+		//supplier
+		feed.GET("/supplier", feedHandler.ResolveSupplierPage)
+		feed.GET("/supplier/:id", feedHandler.ResolveSupplierByID)
+		feed.POST("/supplier", feedHandler.StoreSupplier)
+		feed.PUT("/supplier/:id", feedHandler.StoreSupplier)
+		feed.DELETE("/supplier", feedHandler.RemoveSupplierByIDs)
+		feed.DELETE("/supplier/:id", feedHandler.RemoveSupplierByID)
+		feed.GET("/reports/supplier-purchase", feedHandler.ResolveSupplierPurchase)
+		feed.GET("/reports/supplier-performance", feedHandler.ResolveSupplierPerformance)
 		//feed incoming
 		feed.GET("/incoming", feedHandler.ResolveFeedIncomingPage)
 		feed.GET("/incoming/:id", feedHandler.ResolveFeedIncomingByID)
 		feed.POST("/incoming", feedHandler.StoreFeedIncoming)
+		//lot
+		feed.GET("/lots", feedHandler.ResolveFeedLot)
+		//alert
+		feed.GET("/alerts", feedHandler.ResolveFeedAlert)
 		//adjustment
 		feed.GET("/adjustment", feedHandler.ResolveFeedAdjustmentPage)
 		feed.GET("/adjustment/:id", feedHandler.ResolveFeedAdjustmentByID)
 		feed.POST("/adjustment", feedHandler.StoreFeedAdjustment)
+		//stocktake
+		feed.GET("/stocktake", feedHandler.ResolveStocktakePage)
+		feed.GET("/stocktake/:id", feedHandler.ResolveStocktakeByID)
+		feed.POST("/stocktake", feedHandler.StoreStocktake)
+		feed.PUT("/stocktake/:id", feedHandler.StoreStocktake)
+		feed.POST("/stocktake/:id/count", feedHandler.StoreStocktakeCount)
+		feed.GET("/stocktake/:id/variance", feedHandler.PreviewStocktakeVariance)
+		feed.POST("/stocktake/:id/approve", feedHandler.ApproveStocktake)
 	}
 
+	if db != nil {
+		r.GET("/dashboard", dashboardHandler.ResolveDashboard)
+		r.POST("/import/:entity", importHandler.Import)
+	}
 	r.GET("/health", batchHandler.HealthHandler)
 	r.Run(":9090")
 }
@@ -0,0 +1,244 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/livestockz/api/config"
+	"github.com/livestockz/api/domain/batch"
+	uuid "github.com/satori/go.uuid"
+)
+
+//Store is the part of batch.Service the ingestor writes readings through
+type Store interface {
+	ResolveGrowthPoolByDeviceID(deviceId string) (*batch.Pool, error)
+	StoreGrowthWaterQualityBatch([]batch.WaterQuality) (*[]batch.WaterQuality, error)
+}
+
+//Reading is a sensor message, device_id may be left out when the topic carries it
+type Reading struct {
+	DeviceID string `json:"device_id"`
+	batch.WaterQuality
+}
+
+//Ingestor subscribes to sensor topics and stores the readings as pool water quality
+type Ingestor struct {
+	Config config.Config `inject:"config"`
+	Store  Store         `inject:"batchService"`
+
+	client   mqtt.Client
+	readings chan batch.WaterQuality
+	stop     chan struct{}
+	done     chan struct{}
+	mu       sync.Mutex
+	pools    map[string]uuid.UUID
+	dropped  uint64
+}
+
+const (
+	qos = 1
+	//readings kept while the store is failing, in batches
+	maxPendingBatches = 20
+)
+
+//errBufferFull is answered when the flush falls behind, the paho callback must
+//not block or the client stops reading from the broker
+var errBufferFull = fmt.Errorf("reading buffer is full")
+
+//Start connects to the broker and begins flushing readings, the client keeps
+//retrying and resubscribes on its own whenever the broker goes away
+func (i *Ingestor) Start() error {
+	if len(i.Config.MQTTTopics) < 1 {
+		return fmt.Errorf("no mqtt topic configured")
+	}
+	if i.Config.MQTTBatchSize < 1 || i.Config.MQTTFlushInterval <= 0 {
+		return fmt.Errorf("mqtt batch size and flush interval must be greater than zero")
+	}
+	i.readings = make(chan batch.WaterQuality, i.Config.MQTTBatchSize*2)
+	i.stop = make(chan struct{})
+	i.done = make(chan struct{})
+	i.pools = make(map[string]uuid.UUID)
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(i.Config.MQTTBroker).
+		SetClientID(i.Config.MQTTClientID).
+		SetUsername(i.Config.MQTTUsername).
+		SetPassword(i.Config.MQTTPassword).
+		SetCleanSession(false).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(5 * time.Second).
+		SetMaxReconnectInterval(time.Minute).
+		SetOnConnectHandler(i.subscribe).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			log.Printf("telemetry: connection to %s lost: %s", i.Config.MQTTBroker, err)
+		})
+	i.client = mqtt.NewClient(opts)
+
+	go i.run()
+	//with connect retry the token only completes once connected, so do not wait on it
+	i.client.Connect()
+	return nil
+}
+
+//Stop disconnects from the broker and flushes what is left in the buffer
+func (i *Ingestor) Stop() {
+	if i.client == nil {
+		return
+	}
+	i.client.Disconnect(250)
+	close(i.stop)
+	<-i.done
+}
+
+func (i *Ingestor) subscribe(c mqtt.Client) {
+	filters := make(map[string]byte)
+	for _, topic := range i.Config.MQTTTopics {
+		filters[topic] = qos
+	}
+	if token := c.SubscribeMultiple(filters, i.receive); token.Wait() && token.Error() != nil {
+		log.Printf("telemetry: failed to subscribe %s: %s", strings.Join(i.Config.MQTTTopics, ","), token.Error())
+		return
+	}
+	log.Printf("telemetry: subscribed to %s", strings.Join(i.Config.MQTTTopics, ","))
+}
+
+func (i *Ingestor) receive(_ mqtt.Client, msg mqtt.Message) {
+	//dropped readings are counted and reported on the next tick instead
+	if err := i.Handle(msg.Topic(), msg.Payload()); err != nil && err != errBufferFull {
+		log.Printf("telemetry: dropped message on %s: %s", msg.Topic(), err)
+	}
+}
+
+//Handle parses one message and queues it for the next flush, the reading is
+//dropped and counted when the buffer is full
+func (i *Ingestor) Handle(topic string, payload []byte) error {
+	var reading Reading
+	if err := json.Unmarshal(payload, &reading); err != nil {
+		return err
+	}
+	if reading.DeviceID == "" {
+		reading.DeviceID = i.deviceFromTopic(topic)
+	}
+	if reading.DeviceID == "" {
+		return fmt.Errorf("no device id in message or topic")
+	}
+	poolId, err := i.resolvePool(reading.DeviceID)
+	if err != nil {
+		return err
+	}
+	waterQuality := reading.WaterQuality
+	waterQuality.PoolID = poolId
+	if waterQuality.MeasuredAt.IsZero() {
+		waterQuality.MeasuredAt = time.Now()
+	}
+	if waterQuality.Remarks == "" {
+		waterQuality.Remarks = "device " + reading.DeviceID
+	}
+	select {
+	case i.readings <- waterQuality:
+		return nil
+	case <-i.stop:
+		return fmt.Errorf("ingestor is stopping")
+	default:
+		atomic.AddUint64(&i.dropped, 1)
+		return errBufferFull
+	}
+}
+
+//Dropped tells how many readings were dropped on a full buffer since start
+func (i *Ingestor) Dropped() uint64 {
+	return atomic.LoadUint64(&i.dropped)
+}
+
+//deviceFromTopic takes the level matched by a single level wildcard
+//of the subscribed filter, e.g. livestock/+/telemetry
+func (i *Ingestor) deviceFromTopic(topic string) string {
+	levels := strings.Split(topic, "/")
+	for _, filter := range i.Config.MQTTTopics {
+		parts := strings.Split(filter, "/")
+		for n, part := range parts {
+			if part == "+" && n < len(levels) {
+				return levels[n]
+			}
+		}
+	}
+	return ""
+}
+
+func (i *Ingestor) resolvePool(deviceId string) (uuid.UUID, error) {
+	i.mu.Lock()
+	poolId, ok := i.pools[deviceId]
+	i.mu.Unlock()
+	if ok {
+		return poolId, nil
+	}
+	pool, err := i.Store.ResolveGrowthPoolByDeviceID(deviceId)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	i.mu.Lock()
+	i.pools[deviceId] = pool.ID
+	i.mu.Unlock()
+	return pool.ID, nil
+}
+
+func (i *Ingestor) run() {
+	defer close(i.done)
+	ticker := time.NewTicker(i.Config.MQTTFlushInterval)
+	defer ticker.Stop()
+	buffer := make([]batch.WaterQuality, 0, i.Config.MQTTBatchSize)
+	var reported uint64
+	for {
+		select {
+		case reading := <-i.readings:
+			buffer = append(buffer, reading)
+			if len(buffer) >= i.Config.MQTTBatchSize {
+				buffer = i.flush(buffer)
+			}
+		case <-ticker.C:
+			buffer = i.flush(buffer)
+			if dropped := i.Dropped(); dropped > reported {
+				log.Printf("telemetry: buffer full, dropped %d readings", dropped-reported)
+				reported = dropped
+			}
+			//device assignments may have changed, resolve them again
+			i.mu.Lock()
+			i.pools = make(map[string]uuid.UUID)
+			i.mu.Unlock()
+		case <-i.stop:
+			for {
+				select {
+				case reading := <-i.readings:
+					buffer = append(buffer, reading)
+				default:
+					i.flush(buffer)
+					return
+				}
+			}
+		}
+	}
+}
+
+//flush stores the buffer and returns what is still pending, on failure the
+//readings are kept for the next attempt and the oldest are dropped past the cap
+func (i *Ingestor) flush(buffer []batch.WaterQuality) []batch.WaterQuality {
+	if len(buffer) < 1 {
+		return buffer
+	}
+	if _, err := i.Store.StoreGrowthWaterQualityBatch(buffer); err != nil {
+		log.Printf("telemetry: failed to store %d readings: %s", len(buffer), err)
+		if max := i.Config.MQTTBatchSize * maxPendingBatches; len(buffer) > max {
+			log.Printf("telemetry: dropping %d oldest readings", len(buffer)-max)
+			buffer = buffer[len(buffer)-max:]
+		}
+		return buffer
+	}
+	return make([]batch.WaterQuality, 0, i.Config.MQTTBatchSize)
+}
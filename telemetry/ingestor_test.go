@@ -0,0 +1,189 @@
+package telemetry
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/livestockz/api/config"
+	"github.com/livestockz/api/domain/batch"
+	uuid "github.com/satori/go.uuid"
+)
+
+type testStore struct {
+	mu       sync.Mutex
+	device   string
+	pool     batch.Pool
+	readings []batch.WaterQuality
+}
+
+func (s *testStore) ResolveGrowthPoolByDeviceID(deviceId string) (*batch.Pool, error) {
+	if deviceId != s.device {
+		return nil, fmt.Errorf("Pool with device %s not found", deviceId)
+	}
+	return &s.pool, nil
+}
+
+func (s *testStore) StoreGrowthWaterQualityBatch(readings []batch.WaterQuality) (*[]batch.WaterQuality, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.readings = append(s.readings, readings...)
+	return &readings, nil
+}
+
+//testBroker is a local mqtt broker serving one client, it acknowledges the
+//connect and subscribe then publishes the messages handed to it
+type testBroker struct {
+	listener   net.Listener
+	subscribed chan struct{}
+	publish    chan *packets.PublishPacket
+	acked      chan uint16
+}
+
+func newTestBroker(t *testing.T) *testBroker {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &testBroker{listener: listener, subscribed: make(chan struct{}), publish: make(chan *packets.PublishPacket), acked: make(chan uint16, 10)}
+	go b.serve()
+	return b
+}
+
+func (b *testBroker) url() string {
+	return "tcp://" + b.listener.Addr().String()
+}
+
+func (b *testBroker) serve() {
+	conn, err := b.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	var mu sync.Mutex
+	write := func(p packets.ControlPacket) {
+		mu.Lock()
+		defer mu.Unlock()
+		p.Write(conn)
+	}
+	go func() {
+		for p := range b.publish {
+			write(p)
+		}
+	}()
+	for {
+		packet, err := packets.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+		switch p := packet.(type) {
+		case *packets.ConnectPacket:
+			write(packets.NewControlPacket(packets.Connack))
+		case *packets.SubscribePacket:
+			suback := packets.NewControlPacket(packets.Suback).(*packets.SubackPacket)
+			suback.MessageID = p.MessageID
+			suback.ReturnCodes = p.Qoss
+			write(suback)
+			close(b.subscribed)
+		case *packets.PingreqPacket:
+			write(packets.NewControlPacket(packets.Pingresp))
+		case *packets.PubackPacket:
+			b.acked <- p.MessageID
+		case *packets.DisconnectPacket:
+			return
+		}
+	}
+}
+
+func (b *testBroker) send(t *testing.T, id uint16, topic string, payload string) {
+	publish := packets.NewControlPacket(packets.Publish).(*packets.PublishPacket)
+	publish.Qos = qos
+	publish.MessageID = id
+	publish.TopicName = topic
+	publish.Payload = []byte(payload)
+	select {
+	case <-b.subscribed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("client did not subscribe")
+	}
+	b.publish <- publish
+	select {
+	case acked := <-b.acked:
+		if acked != id {
+			t.Fatalf("broker got ack %d for message %d", acked, id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("message %d was not acknowledged", id)
+	}
+}
+
+func newTestStore() *testStore {
+	return &testStore{device: "sensor-1", pool: batch.Pool{ID: uuid.Must(uuid.NewV4()), Name: "Pool 1"}}
+}
+
+func TestIngestorFlushesOnStop(t *testing.T) {
+	broker := newTestBroker(t)
+	defer broker.listener.Close()
+	store := newTestStore()
+	ingestor := &Ingestor{
+		Config: config.Config{
+			MQTTBroker:        broker.url(),
+			MQTTClientID:      "livestock-test",
+			MQTTTopics:        []string{"livestock/+/telemetry"},
+			MQTTBatchSize:     10,
+			MQTTFlushInterval: time.Hour,
+		},
+		Store: store,
+	}
+	if err := ingestor.Start(); err != nil {
+		t.Fatal(err)
+	}
+	//the ack follows the handler, so both readings are buffered once acknowledged
+	broker.send(t, 1, "livestock/sensor-1/telemetry", `{"measured_at":"2024-01-01T06:00:00Z","ph":7.2}`)
+	broker.send(t, 2, "livestock/sensor-1/telemetry", `{"measured_at":"2024-01-01T07:00:00Z","ph":7.4}`)
+	store.mu.Lock()
+	stored := len(store.readings)
+	store.mu.Unlock()
+	if stored != 0 {
+		t.Fatalf("stored %d readings before the batch was full", stored)
+	}
+
+	ingestor.Stop()
+	close(broker.publish)
+	if len(store.readings) != 2 {
+		t.Fatalf("stop flushed %d readings", len(store.readings))
+	}
+	if r := store.readings[1]; r.PoolID != store.pool.ID || r.PH.Float64 != 7.4 || r.Remarks != "device sensor-1" {
+		t.Fatalf("reading stored as %+v", r)
+	}
+}
+
+func TestHandleDropsOnFullBuffer(t *testing.T) {
+	store := newTestStore()
+	ingestor := &Ingestor{
+		Config:   config.Config{MQTTTopics: []string{"livestock/+/telemetry"}},
+		Store:    store,
+		readings: make(chan batch.WaterQuality, 1),
+		stop:     make(chan struct{}),
+		pools:    make(map[string]uuid.UUID),
+	}
+	payload := []byte(`{"ph":7.2}`)
+	if err := ingestor.Handle("livestock/sensor-1/telemetry", payload); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error)
+	go func() {
+		done <- ingestor.Handle("livestock/sensor-1/telemetry", payload)
+	}()
+	select {
+	case err := <-done:
+		if err != errBufferFull || ingestor.Dropped() != 1 {
+			t.Fatalf("full buffer answered %v with %d dropped", err, ingestor.Dropped())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handle blocked on a full buffer")
+	}
+}
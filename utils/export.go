@@ -0,0 +1,296 @@
+package utils
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guregu/null"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	Format_CSV  string = "csv"
+	Format_XLSX string = "xlsx"
+	//exported dates, spreadsheets read this layout whatever the locale
+	exportTimeLayout = "2006-01-02 15:04:05"
+)
+
+//locales writing numbers with a decimal comma, their csv uses ; as separator
+var decimalCommaLocales = []string{"id", "de", "fr", "es", "it", "pt", "nl", "ru", "tr", "vi"}
+
+//Exporter writes a table row by row straight to the response, nothing is sent
+//until the first row so an early error can still be answered with json
+type Exporter interface {
+	Write(values ...interface{}) error
+	Started() bool
+	Close() error
+}
+
+//ExportFormat tells which file a list endpoint should answer with, ?format=csv|xlsx
+//wins over `Accept: text/csv`, empty means json
+func ExportFormat(c *gin.Context) string {
+	if f := strings.ToLower(c.Request.URL.Query().Get("format")); f == Format_CSV || f == Format_XLSX {
+		return f
+	}
+	accept := c.Request.Header.Get("Accept")
+	if strings.Contains(accept, "text/csv") {
+		return Format_CSV
+	} else if strings.Contains(accept, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet") {
+		return Format_XLSX
+	}
+	return ""
+}
+
+//Export prepares a file named after the collection with the given header
+func Export(c *gin.Context, format string, name string, header []string) Exporter {
+	locale := c.Request.URL.Query().Get("locale")
+	if locale == "" {
+		locale = c.Request.Header.Get("Accept-Language")
+	}
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102"), format)
+	if format == Format_XLSX {
+		return &xlsxExporter{c: c, filename: filename, header: header}
+	}
+	return &csvExporter{c: c, filename: filename, header: header, decimalComma: decimalComma(locale)}
+}
+
+//decimalComma reads the primary language of a locale like id-ID or de;q=0.9
+func decimalComma(locale string) bool {
+	language := strings.ToLower(strings.TrimSpace(strings.SplitN(locale, ",", 2)[0]))
+	language = strings.SplitN(strings.SplitN(strings.SplitN(language, ";", 2)[0], "-", 2)[0], "_", 2)[0]
+	for _, l := range decimalCommaLocales {
+		if l == language {
+			return true
+		}
+	}
+	return false
+}
+
+type csvExporter struct {
+	c            *gin.Context
+	filename     string
+	header       []string
+	decimalComma bool
+	writer       *csv.Writer
+	rows         int
+}
+
+func (e *csvExporter) start() error {
+	e.c.Header("Content-Type", "text/csv; charset=utf-8")
+	e.c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", e.filename))
+	e.c.Status(200)
+	e.writer = csv.NewWriter(e.c.Writer)
+	if e.decimalComma {
+		e.writer.Comma = ';'
+	}
+	return e.writer.Write(e.header)
+}
+
+func (e *csvExporter) Write(values ...interface{}) error {
+	if e.writer == nil {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+	record := make([]string, len(values))
+	for i, value := range values {
+		text, number := exportValue(value)
+		if number && e.decimalComma {
+			text = strings.Replace(text, ".", ",", 1)
+		}
+		record[i] = text
+	}
+	if err := e.writer.Write(record); err != nil {
+		return err
+	}
+	//flush every few hundred rows so big exports do not sit in memory
+	e.rows++
+	if e.rows%500 == 0 {
+		e.writer.Flush()
+		e.c.Writer.Flush()
+	}
+	return e.writer.Error()
+}
+
+func (e *csvExporter) Started() bool {
+	return e.writer != nil
+}
+
+func (e *csvExporter) Close() error {
+	if e.writer == nil {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+//xlsxExporter writes a single sheet workbook, the sheet is the last entry of the
+//zip so rows go out as they come without keeping the workbook in memory
+type xlsxExporter struct {
+	c        *gin.Context
+	filename string
+	header   []string
+	archive  *zip.Writer
+	sheet    io.Writer
+	rows     int
+}
+
+var xlsxParts = []struct{ name, content string }{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`},
+}
+
+func (e *xlsxExporter) start() error {
+	e.c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	e.c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", e.filename))
+	e.c.Status(200)
+	e.archive = zip.NewWriter(e.c.Writer)
+	for _, part := range xlsxParts {
+		if w, err := e.archive.Create(part.name); err != nil {
+			return err
+		} else if _, err := io.WriteString(w, part.content); err != nil {
+			return err
+		}
+	}
+	sheet, err := e.archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	e.sheet = sheet
+	if _, err := io.WriteString(e.sheet, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return err
+	}
+	header := make([]interface{}, len(e.header))
+	for i, h := range e.header {
+		header[i] = h
+	}
+	return e.row(header)
+}
+
+func (e *xlsxExporter) row(values []interface{}) error {
+	e.rows++
+	var b strings.Builder
+	fmt.Fprintf(&b, `<row r="%d">`, e.rows)
+	for _, value := range values {
+		text, number := exportValue(value)
+		if text == "" {
+			b.WriteString(`<c/>`)
+		} else if number {
+			fmt.Fprintf(&b, `<c><v>%s</v></c>`, text)
+		} else {
+			b.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+			xml.EscapeText(&b, []byte(text))
+			b.WriteString(`</t></is></c>`)
+		}
+	}
+	b.WriteString(`</row>`)
+	_, err := io.WriteString(e.sheet, b.String())
+	return err
+}
+
+func (e *xlsxExporter) Write(values ...interface{}) error {
+	if e.archive == nil {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+	if err := e.row(values); err != nil {
+		return err
+	}
+	if e.rows%500 == 0 {
+		if err := e.archive.Flush(); err != nil {
+			return err
+		}
+		e.c.Writer.Flush()
+	}
+	return nil
+}
+
+func (e *xlsxExporter) Started() bool {
+	return e.archive != nil
+}
+
+func (e *xlsxExporter) Close() error {
+	if e.archive == nil {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+	if _, err := io.WriteString(e.sheet, `</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	return e.archive.Close()
+}
+
+//exportValue renders a cell and tells whether it is a number, numbers keep
+//a dot as decimal separator here
+func exportValue(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case string:
+		return v, false
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), true
+	case int:
+		return strconv.Itoa(v), true
+	case int32:
+		return strconv.FormatInt(int64(v), 10), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case bool:
+		return strconv.FormatBool(v), false
+	case time.Time:
+		if v.IsZero() {
+			return "", false
+		}
+		return v.Format(exportTimeLayout), false
+	case null.Time:
+		if !v.Valid {
+			return "", false
+		}
+		return v.Time.Format(exportTimeLayout), false
+	case null.Float:
+		if !v.Valid {
+			return "", false
+		}
+		return strconv.FormatFloat(v.Float64, 'f', -1, 64), true
+	case null.String:
+		return v.String, false
+	case uuid.UUID:
+		if v == uuid.Nil {
+			return "", false
+		}
+		return v.String(), false
+	case uuid.NullUUID:
+		if !v.Valid {
+			return "", false
+		}
+		return v.UUID.String(), false
+	case *uuid.UUID:
+		if v == nil {
+			return "", false
+		}
+		return v.String(), false
+	default:
+		return fmt.Sprint(v), false
+	}
+}
@@ -41,6 +41,12 @@ func NoContent(c *gin.Context) {
 	c.JSON(204, nil)
 }
 
+//UnprocessableEntity writes http response with status code 422 and json object with `data` property,
+//for a request that was read but rejected with details
+func UnprocessableEntity(c *gin.Context, data interface{}) {
+	c.JSON(422, SuccessResponse{data})
+}
+
 //BadRequest writes http response with status code 400 and json object with `error` property
 func BadRequest(c *gin.Context, errors ...error) {
 	msg := make([]string, len(errors))